@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// CachedAnalysis holds the results stored in the cache for one file, one package or the clones of
+// a whole run. File entries carry function metrics and suppressions, package entries carry type
+// metrics and package smells, and clone entries carry duplication
+type CachedAnalysis struct {
+	PackageName     string
+	Findings        []entities.AnalysisFinding
+	FunctionMetrics []valueobjects.FunctionMetrics
+	Suppressions    []valueobjects.Suppression
+	TypeMetrics     []valueobjects.TypeMetrics
+	Duplication     []valueobjects.PackageDuplication
+	FunctionCount   int
+	TotalCyclomatic int
+	TotalCognitive  int
+}
+
+// hashFile returns the content hash of a file, or "" when the file's results are not cached
+func (uc *analyzeCodeUseCaseImpl) hashFile(filePath string) string {
+	if uc.cache == nil {
+		return ""
+	}
+
+	// An unreadable file is not cached; parsing it reports the error
+	hash, err := uc.cache.HashFile(filePath)
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
+// loadCached returns the entry stored under key; the empty key is never cached
+func (uc *analyzeCodeUseCaseImpl) loadCached(key string) (*CachedAnalysis, bool) {
+	if uc.cache == nil || key == "" {
+		return nil, false
+	}
+	return uc.cache.Load(key)
+}
+
+// storeCached stores an entry under key. A failed write only costs the next run its speed, so
+// it does not fail the analysis
+func (uc *analyzeCodeUseCaseImpl) storeCached(key string, entry *CachedAnalysis) {
+	if uc.cache == nil || key == "" {
+		return
+	}
+	_ = uc.cache.Store(key, entry)
+}
+
+// fileCacheKey returns the key of a file's function metrics and findings, which depend only on
+// the file's content and the configuration
+func fileCacheKey(filePath, hash string, config valueobjects.AnalysisConfiguration) string {
+	if hash == "" {
+		return ""
+	}
+	return cacheKey("file", config.Fingerprint(), filePath, hash)
+}
+
+// packageCacheKey returns the key of a package's type metrics and package-level findings. Smell
+// detectors see the type information of module-local imports, so with smell detection the key
+// covers the source of every such dependency and a change to a dependency invalidates its dependents
+func (uc *analyzeCodeUseCaseImpl) packageCacheKey(pkg *parsedPackage, config valueobjects.AnalysisConfiguration, detectSmells bool) string {
+	parts := []string{"package", config.Fingerprint(), strconv.FormatBool(detectSmells)}
+	for i, filePath := range pkg.filePaths {
+		if pkg.hashes[i] == "" {
+			return ""
+		}
+		parts = append(parts, filePath, pkg.hashes[i])
+	}
+
+	if detectSmells && uc.packageLoader != nil {
+		dependencies, err := uc.packageLoader.DependencyFiles(pkg.dir)
+		if err != nil {
+			return ""
+		}
+		for _, dependency := range dependencies {
+			hash := uc.hashFile(dependency)
+			if hash == "" {
+				return ""
+			}
+			parts = append(parts, dependency, hash)
+		}
+	}
+
+	return cacheKey(parts...)
+}
+
+// cloneCacheKey returns the key of the clones found across all packages, which change with any file
+func cloneCacheKey(packages []*parsedPackage, config valueobjects.AnalysisConfiguration) string {
+	parts := []string{"clones", config.Fingerprint()}
+	for _, pkg := range packages {
+		for i, filePath := range pkg.filePaths {
+			if pkg.hashes[i] == "" {
+				return ""
+			}
+			parts = append(parts, filePath, pkg.hashes[i])
+		}
+	}
+	return cacheKey(parts...)
+}
+
+// cacheKey hashes the parts of a key, separated so that no two part lists produce the same input
+func cacheKey(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(strconv.Itoa(len(part))))
+		hash.Write([]byte{':'})
+		hash.Write([]byte(part))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,242 @@
+package usecases
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+// testCache keeps entries in memory, hashes files by content and counts the entries it served
+type testCache struct {
+	entries map[string]*CachedAnalysis
+	hits    int
+}
+
+func newTestCache() *testCache {
+	return &testCache{entries: make(map[string]*CachedAnalysis)}
+}
+
+func (c *testCache) HashFile(filePath string) (string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *testCache) Load(key string) (*CachedAnalysis, bool) {
+	entry, ok := c.entries[key]
+	if ok {
+		c.hits++
+	}
+	return entry, ok
+}
+
+func (c *testCache) Store(key string, entry *CachedAnalysis) error {
+	c.entries[key] = entry
+	return nil
+}
+
+func TestFileCacheKey(t *testing.T) {
+	config := valueobjects.DefaultAnalysisConfiguration()
+	base := fileCacheKey("a.go", "hash", config)
+
+	tests := []struct {
+		name     string
+		key      string
+		expected bool
+	}{
+		{"same file, content and configuration", fileCacheKey("a.go", "hash", config), true},
+		{"different path", fileCacheKey("b.go", "hash", config), false},
+		{"different content", fileCacheKey("a.go", "other", config), false},
+		{"different configuration", fileCacheKey("a.go", "hash", config.WithMaxCyclomaticComplexity(5)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := tt.key == base; same != tt.expected {
+				t.Errorf("Expected equal keys to be %v", tt.expected)
+			}
+		})
+	}
+
+	if key := fileCacheKey("a.go", "", config); key != "" {
+		t.Errorf("Expected no key for a file without a hash, got %q", key)
+	}
+}
+
+func TestPackageCacheKey(t *testing.T) {
+	dir := t.TempDir()
+	dependency := writeSource(t, dir, "dep/dep.go", "package dep\n")
+	cache := newTestCache()
+	uc := newTestUseCase(&testParser{fset: token.NewFileSet()}, &testLoader{dependencies: map[string][]string{"p": {dependency}}}, cache)
+	config := valueobjects.DefaultAnalysisConfiguration()
+
+	pkg := func(hashes ...string) *parsedPackage {
+		return &parsedPackage{dir: "p", name: "p", filePaths: []string{"p/a.go", "p/b.go"}, hashes: hashes}
+	}
+	base := uc.packageCacheKey(pkg("a1", "b1"), config, true)
+
+	tests := []struct {
+		name     string
+		key      func() string
+		expected bool
+	}{
+		{"same files", func() string { return uc.packageCacheKey(pkg("a1", "b1"), config, true) }, true},
+		{"another file of the package changed", func() string { return uc.packageCacheKey(pkg("a1", "b2"), config, true) }, false},
+		{"smell detection disabled", func() string { return uc.packageCacheKey(pkg("a1", "b1"), config, false) }, false},
+		{"different configuration", func() string { return uc.packageCacheKey(pkg("a1", "b1"), config.WithMaxStructFields(3), true) }, false},
+		{"module-local dependency changed", func() string {
+			writeSource(t, dir, "dep/dep.go", "package dep\n\nfunc F() {}\n")
+			defer writeSource(t, dir, "dep/dep.go", "package dep\n")
+			return uc.packageCacheKey(pkg("a1", "b1"), config, true)
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := tt.key() == base; same != tt.expected {
+				t.Errorf("Expected equal keys to be %v", tt.expected)
+			}
+		})
+	}
+
+	// Without smell detection no rule sees the dependencies, so they are not part of the key
+	withoutSmells := uc.packageCacheKey(pkg("a1", "b1"), config, false)
+	writeSource(t, dir, "dep/dep.go", "package dep\n\nfunc G() {}\n")
+	if key := uc.packageCacheKey(pkg("a1", "b1"), config, false); key != withoutSmells {
+		t.Errorf("Expected dependencies to be ignored without smell detection")
+	}
+
+	if key := uc.packageCacheKey(pkg("a1", ""), config, true); key != "" {
+		t.Errorf("Expected no key for a package with an unhashed file, got %q", key)
+	}
+	os.Remove(dependency)
+	if key := uc.packageCacheKey(pkg("a1", "b1"), config, true); key != "" {
+		t.Errorf("Expected no key for a package with an unreadable dependency, got %q", key)
+	}
+}
+
+func TestCloneCacheKey(t *testing.T) {
+	config := valueobjects.DefaultAnalysisConfiguration()
+	packages := func(hash string) []*parsedPackage {
+		return []*parsedPackage{
+			{filePaths: []string{"p/a.go"}, hashes: []string{"a1"}},
+			{filePaths: []string{"q/b.go"}, hashes: []string{hash}},
+		}
+	}
+
+	if cloneCacheKey(packages("b1"), config) != cloneCacheKey(packages("b1"), config) {
+		t.Errorf("Expected equal keys for the same files")
+	}
+	if cloneCacheKey(packages("b1"), config) == cloneCacheKey(packages("b2"), config) {
+		t.Errorf("Expected a change in any package to change the key")
+	}
+	if cloneCacheKey(packages("b1"), config) == cloneCacheKey(packages("b1"), config.WithMinCloneTokens(20)) {
+		t.Errorf("Expected the configuration to be part of the key")
+	}
+	if key := cloneCacheKey(packages(""), config); key != "" {
+		t.Errorf("Expected no key with an unhashed file, got %q", key)
+	}
+}
+
+func TestCacheKey_PartBoundaries(t *testing.T) {
+	if cacheKey("ab", "c") == cacheKey("a", "bc") {
+		t.Errorf("Expected parts to be separated in the key")
+	}
+}
+
+func TestAnalyzeCode_Cache(t *testing.T) {
+	dir := t.TempDir()
+	typeFile := writeSource(t, dir, "p/a.go", `package p
+
+type server struct {
+	name string
+	port int
+}
+
+func (s *server) Name() string { return s.name }
+`)
+	methodFile := writeSource(t, dir, "p/b.go", `package p
+
+func (s *server) Port() int { return s.port }
+`)
+	dependency := writeSource(t, dir, "dep/dep.go", "package dep\n")
+
+	cache := newTestCache()
+	parser := &testParser{fset: token.NewFileSet()}
+	loader := &testLoader{dependencies: map[string][]string{filepath.Dir(typeFile): {dependency}}}
+	request := AnalyzeCodeRequest{
+		FilePaths:             []string{typeFile, methodFile},
+		Configuration:         valueobjects.DefaultAnalysisConfiguration(),
+		IncludeSmellDetection: true,
+	}
+	run := func() *AnalyzeCodeResponse {
+		t.Helper()
+		parser.parsed = nil
+		cache.hits = 0
+		response, err := newTestUseCase(parser, loader, cache).Execute(request)
+		if err != nil || !response.Success {
+			t.Fatalf("Execute failed: %v, %v", err, response.Error)
+		}
+		return response
+	}
+	methods := func(response *AnalyzeCodeResponse) int {
+		t.Helper()
+		metrics := response.AnalysisResult.TypeMetrics()
+		if len(metrics) != 1 {
+			t.Fatalf("Expected 1 type, got %d", len(metrics))
+		}
+		return metrics[0].Methods()
+	}
+
+	cold := run()
+	if cache.hits != 0 || len(parser.parsed) != 2 {
+		t.Fatalf("Expected a cold run to parse every file, got %d hits and %v parsed", cache.hits, parser.parsed)
+	}
+
+	warm := run()
+	if len(parser.parsed) != 0 {
+		t.Errorf("Expected a warm run to parse nothing, parsed %v", parser.parsed)
+	}
+	if len(warm.AnalysisResult.FunctionMetrics()) != len(cold.AnalysisResult.FunctionMetrics()) || methods(warm) != methods(cold) {
+		t.Errorf("Expected cached results to match the analysis")
+	}
+
+	// A method added in another file of the package changes the type's metrics, although the
+	// file declaring the type is unchanged
+	writeSource(t, dir, "p/b.go", `package p
+
+func (s *server) Port() int { return s.port }
+
+func (s *server) Addr() string { return s.name }
+`)
+	changed := run()
+	if methods(changed) != 3 {
+		t.Errorf("Expected the package entry to be invalidated by the other file, got %d methods", methods(changed))
+	}
+	if cache.hits == 0 {
+		t.Errorf("Expected the unchanged file's results to come from the cache")
+	}
+
+	// A changed module-local dependency runs the package-level rules again
+	run()
+	writeSource(t, dir, "dep/dep.go", "package dep\n\nfunc F() {}\n")
+	run()
+	if len(parser.parsed) == 0 {
+		t.Errorf("Expected the package to be analyzed again after its dependency changed")
+	}
+
+	// Another configuration never reuses results
+	request.Configuration = request.Configuration.WithMaxCyclomaticComplexity(5)
+	run()
+	if cache.hits != 0 {
+		t.Errorf("Expected no cache hits under another configuration, got %d", cache.hits)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"goastanalyzer/domain/aggregates"
 	"goastanalyzer/domain/entities"
@@ -21,6 +24,8 @@ type AnalyzeCodeRequest struct {
 	FilePaths        []string
 	Configuration    valueobjects.AnalysisConfiguration
 	IncludeSmellDetection bool
+	// ChangedLines restricts findings to changed code; nil reports every finding
+	ChangedLines *valueobjects.ChangeSet
 }
 
 // AnalyzeCodeResponse represents the output of code analysis
@@ -35,8 +40,12 @@ type AnalyzeCodeResponse struct {
 type analyzeCodeUseCaseImpl struct {
 	complexityCalculator services.ComplexityCalculator
 	smellDetector        services.SmellDetector
+	cloneDetector        services.CloneDetector
+	suppressionScanner   services.SuppressionScanner
 	fileParser           FileParser
+	packageLoader        PackageLoader
 	idGenerator          IDGenerator
+	cache                AnalysisCache
 }
 
 // FileParser defines the interface for parsing Go files
@@ -44,6 +53,24 @@ type FileParser interface {
 	ParseFile(filePath string) (*ast.File, *token.FileSet, error)
 }
 
+// PackageLoader defines the interface for building the package-wide context
+// (type information, cross-package declarations) of files in one package
+type PackageLoader interface {
+	LoadPackage(fset *token.FileSet, files []*ast.File) (*services.PackageContext, error)
+	// DependencyFiles returns the source files of the module-local packages imported, directly
+	// or through each other, by the package in dir
+	DependencyFiles(dir string) ([]string, error)
+}
+
+// AnalysisCache defines the interface for storing analysis results between runs. Entries are
+// keyed by the content they were computed from; the cache adds the analyzer version to every key
+type AnalysisCache interface {
+	// HashFile returns a hash of the file's content, or "" when results of the file are not cached
+	HashFile(filePath string) (string, error)
+	Load(key string) (*CachedAnalysis, bool)
+	Store(key string, entry *CachedAnalysis) error
+}
+
 // IDGenerator defines the interface for generating unique IDs
 type IDGenerator interface {
 	GenerateID() string
@@ -53,14 +80,22 @@ type IDGenerator interface {
 func NewAnalyzeCodeUseCase(
 	complexityCalculator services.ComplexityCalculator,
 	smellDetector services.SmellDetector,
+	cloneDetector services.CloneDetector,
+	suppressionScanner services.SuppressionScanner,
 	fileParser FileParser,
+	packageLoader PackageLoader,
 	idGenerator IDGenerator,
+	cache AnalysisCache,
 ) AnalyzeCodeUseCase {
 	return &analyzeCodeUseCaseImpl{
 		complexityCalculator: complexityCalculator,
 		smellDetector:        smellDetector,
+		cloneDetector:        cloneDetector,
+		suppressionScanner:   suppressionScanner,
 		fileParser:           fileParser,
+		packageLoader:        packageLoader,
 		idGenerator:          idGenerator,
+		cache:                cache,
 	}
 }
 
@@ -80,27 +115,84 @@ func (uc *analyzeCodeUseCaseImpl) Execute(request AnalyzeCodeRequest) (*AnalyzeC
 	totalCyclomatic := 0
 	totalCognitive := 0
 
-	// Analyze each file
-	for _, filePath := range request.FilePaths {
-		fileResult, err := uc.analyzeFile(filePath, request.Configuration, request.IncludeSmellDetection)
+	// Group every file by package up front so that files of the same package are analyzed together;
+	// files with cached results are only parsed if a package-level rule has to run again
+	packages, err := uc.parsePackages(request.FilePaths, request.Configuration)
+	if err != nil {
+		return &AnalyzeCodeResponse{
+			Success: false,
+			Error:   err,
+		}, nil
+	}
+
+	// Function spans let findings reported at a function's first line match changes anywhere in its body
+	spans := make(map[string]map[int]int)
+	suppressions := make(map[string][]valueobjects.Suppression)
+	addFinding := func(finding entities.AnalysisFinding) {
+		if services.IsSuppressed(finding, suppressions[finding.Location().FilePath()]) {
+			return
+		}
+		if inChangedLines(request.ChangedLines, spans, finding) {
+			analysisResult.AddFinding(finding)
+		}
+	}
+
+	// Analyze each package
+	for _, pkg := range packages {
+		for i, filePath := range pkg.filePaths {
+			fileResult := uc.fileResult(pkg, i, request.Configuration)
+			for _, suppression := range fileResult.Suppressions {
+				suppressions[suppression.FilePath()] = append(suppressions[suppression.FilePath()], suppression)
+			}
+
+			spans[filePath] = make(map[int]int, len(fileResult.Metrics))
+			for _, metrics := range fileResult.Metrics {
+				analysisResult.AddFunctionMetrics(metrics)
+				spans[filePath][metrics.Location().Line()] = metrics.Location().Line() + metrics.Lines().Physical() - 1
+			}
+
+			// Add findings to result
+			for _, finding := range fileResult.Findings {
+				addFinding(finding)
+			}
+
+			// Update totals
+			totalFunctions += fileResult.FunctionCount
+			totalCyclomatic += fileResult.TotalCyclomatic
+			totalCognitive += fileResult.TotalCognitive
+
+			analysisResult.AddAnalyzedFile(filePath)
+		}
+
+		// Type metrics and smells need the whole package, since declarations may be in any of its files
+		packageResult, err := uc.packageResult(pkg, request.Configuration, request.IncludeSmellDetection)
 		if err != nil {
 			return &AnalyzeCodeResponse{
 				Success: false,
-				Error:   fmt.Errorf("failed to analyze file %s: %w", filePath, err),
+				Error:   err,
 			}, nil
 		}
-
-		// Add findings to result
-		for _, finding := range fileResult.Findings {
-			analysisResult.AddFinding(finding)
+		for _, metrics := range packageResult.TypeMetrics {
+			analysisResult.AddTypeMetrics(metrics)
 		}
+		for _, finding := range packageResult.Findings {
+			addFinding(finding)
+		}
+	}
 
-		// Update totals
-		totalFunctions += fileResult.FunctionCount
-		totalCyclomatic += fileResult.TotalCyclomatic
-		totalCognitive += fileResult.TotalCognitive
-
-		analysisResult.AddAnalyzedFile(filePath)
+	// Clones are matched across packages, so they are detected once all files are grouped
+	if request.IncludeSmellDetection {
+		cloneResult, err := uc.cloneResult(packages, request.Configuration)
+		if err != nil {
+			return &AnalyzeCodeResponse{
+				Success: false,
+				Error:   fmt.Errorf("failed to detect duplicated code: %w", err),
+			}, nil
+		}
+		analysisResult.SetPackageDuplication(cloneResult.Duplication)
+		for _, finding := range cloneResult.Findings {
+			addFinding(finding)
+		}
 	}
 
 	// Set aggregate metrics
@@ -124,20 +216,190 @@ func (uc *analyzeCodeUseCaseImpl) Execute(request AnalyzeCodeRequest) (*AnalyzeC
 	}, nil
 }
 
-// analyzeFile analyzes a single Go file
-func (uc *analyzeCodeUseCaseImpl) analyzeFile(
-	filePath string,
-	config valueobjects.AnalysisConfiguration,
-	includeSmells bool,
-) (*FileAnalysisResult, error) {
+// parsedPackage groups the files that belong to one package. Files whose results were found in
+// the cache stay unparsed, with a nil syntax tree, until a package-level rule needs them
+type parsedPackage struct {
+	dir       string
+	name      string
+	filePaths []string
+	hashes    []string
+	cached    []*CachedAnalysis
+	files     []*ast.File
+	fset      *token.FileSet
+}
+
+// parsePackages groups the given files by directory and package name, parsing the files without
+// cached results
+func (uc *analyzeCodeUseCaseImpl) parsePackages(filePaths []string, config valueobjects.AnalysisConfiguration) ([]*parsedPackage, error) {
+	var packages []*parsedPackage
+	index := make(map[string]*parsedPackage)
+
+	for _, filePath := range filePaths {
+		hash := uc.hashFile(filePath)
+		entry, cached := uc.loadCached(fileCacheKey(filePath, hash, config))
+
+		var astFile *ast.File
+		var fset *token.FileSet
+		name := ""
+		if cached {
+			name = entry.PackageName
+		} else {
+			parsed, parsedSet, err := uc.fileParser.ParseFile(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to analyze file %s: failed to parse file: %w", filePath, err)
+			}
+			astFile, fset, name = parsed, parsedSet, parsed.Name.Name
+		}
+
+		dir := filepath.Dir(filePath)
+		key := dir + "|" + name
+		pkg, ok := index[key]
+		if !ok {
+			pkg = &parsedPackage{dir: dir, name: name}
+			index[key] = pkg
+			packages = append(packages, pkg)
+		}
+		if fset != nil {
+			pkg.fset = fset
+		}
+
+		pkg.filePaths = append(pkg.filePaths, filePath)
+		pkg.hashes = append(pkg.hashes, hash)
+		pkg.cached = append(pkg.cached, entry)
+		pkg.files = append(pkg.files, astFile)
+	}
+
+	return packages, nil
+}
+
+// parseCached parses the files of a package whose results came from the cache
+func (uc *analyzeCodeUseCaseImpl) parseCached(pkg *parsedPackage) error {
+	for i, file := range pkg.files {
+		if file != nil {
+			continue
+		}
+
+		astFile, fset, err := uc.fileParser.ParseFile(pkg.filePaths[i])
+		if err != nil {
+			return fmt.Errorf("failed to analyze file %s: failed to parse file: %w", pkg.filePaths[i], err)
+		}
+		pkg.files[i] = astFile
+		pkg.fset = fset
+	}
+	return nil
+}
+
+// fileResult returns the cached results of the i-th file of a package, analyzing the file on a miss
+func (uc *analyzeCodeUseCaseImpl) fileResult(pkg *parsedPackage, i int, config valueobjects.AnalysisConfiguration) *FileAnalysisResult {
+	filePath := pkg.filePaths[i]
+	if entry := pkg.cached[i]; entry != nil {
+		return &FileAnalysisResult{
+			FilePath:        filePath,
+			Findings:        entry.Findings,
+			Metrics:         entry.FunctionMetrics,
+			FunctionCount:   entry.FunctionCount,
+			TotalCyclomatic: entry.TotalCyclomatic,
+			TotalCognitive:  entry.TotalCognitive,
+			Suppressions:    entry.Suppressions,
+		}
+	}
+
+	fileResult := uc.analyzeFile(filePath, pkg.files[i], pkg.fset, config)
+	fileResult.Suppressions = uc.suppressionScanner.ScanSuppressions(pkg.files[i], pkg.fset)
+	uc.storeCached(fileCacheKey(filePath, pkg.hashes[i], config), &CachedAnalysis{
+		PackageName:     pkg.name,
+		Findings:        fileResult.Findings,
+		FunctionMetrics: fileResult.Metrics,
+		Suppressions:    fileResult.Suppressions,
+		FunctionCount:   fileResult.FunctionCount,
+		TotalCyclomatic: fileResult.TotalCyclomatic,
+		TotalCognitive:  fileResult.TotalCognitive,
+	})
+	return fileResult
+}
 
-	// Parse the file
-	astFile, fset, err := uc.fileParser.ParseFile(filePath)
+// packageResult returns the type metrics and package-level findings of a package, running the
+// package-level rules again when any of its files or their module-local dependencies changed
+func (uc *analyzeCodeUseCaseImpl) packageResult(pkg *parsedPackage, config valueobjects.AnalysisConfiguration, detectSmells bool) (*CachedAnalysis, error) {
+	key := uc.packageCacheKey(pkg, config, detectSmells)
+	if entry, ok := uc.loadCached(key); ok {
+		return entry, nil
+	}
+	if err := uc.parseCached(pkg); err != nil {
+		return nil, err
+	}
+
+	typeMetrics, findings, err := uc.analyzeTypes(pkg, config, detectSmells)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze types of package %s: %w", pkg.dir, err)
+	}
+
+	// Detect smells if requested
+	if detectSmells {
+		smellFindings, err := uc.detectPackageSmells(pkg, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze package %s: %w", pkg.dir, err)
+		}
+		findings = append(findings, smellFindings...)
+	}
+
+	entry := &CachedAnalysis{PackageName: pkg.name, Findings: findings, TypeMetrics: typeMetrics}
+	uc.storeCached(key, entry)
+	return entry, nil
+}
+
+// cloneResult returns the duplicated code across all packages, detecting it again when any file changed
+func (uc *analyzeCodeUseCaseImpl) cloneResult(packages []*parsedPackage, config valueobjects.AnalysisConfiguration) (*CachedAnalysis, error) {
+	key := cloneCacheKey(packages, config)
+	if entry, ok := uc.loadCached(key); ok {
+		return entry, nil
+	}
+	for _, pkg := range packages {
+		if err := uc.parseCached(pkg); err != nil {
+			return nil, err
+		}
+	}
+
+	findings, duplication, err := uc.detectClones(packages, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse file: %w", err)
+		return nil, err
 	}
 
+	entry := &CachedAnalysis{Findings: findings, Duplication: duplication}
+	uc.storeCached(key, entry)
+	return entry, nil
+}
+
+// detectPackageSmells runs smell detection over all files of a package
+func (uc *analyzeCodeUseCaseImpl) detectPackageSmells(pkg *parsedPackage, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var context *services.PackageContext
+	if uc.packageLoader != nil {
+		loaded, err := uc.packageLoader.LoadPackage(pkg.fset, pkg.files)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load package: %w", err)
+		}
+		context = loaded
+	} else {
+		context = services.NewPackageContext(pkg.fset, pkg.files, nil)
+	}
+
+	smellFindings, err := uc.smellDetector.DetectPackageSmells(context, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect smells: %w", err)
+	}
+	return smellFindings, nil
+}
+
+// analyzeFile analyzes the functions of a single parsed Go file
+func (uc *analyzeCodeUseCaseImpl) analyzeFile(
+	filePath string,
+	astFile *ast.File,
+	fset *token.FileSet,
+	config valueobjects.AnalysisConfiguration,
+) *FileAnalysisResult {
+
 	var findings []entities.AnalysisFinding
+	var functionMetrics []valueobjects.FunctionMetrics
 	functionCount := 0
 	totalCyclomatic := 0
 	totalCognitive := 0
@@ -147,20 +409,22 @@ func (uc *analyzeCodeUseCaseImpl) analyzeFile(
 		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
 			functionCount++
 
-			// Calculate complexity
-			complexity, err := uc.complexityCalculator.CalculateComplexity(funcDecl, fset)
+			// Calculate complexity, Halstead and size metrics
+			metrics, err := uc.complexityCalculator.CalculateFunctionMetrics(funcDecl, astFile, fset)
 			if err != nil {
 				continue // Skip functions that can't be analyzed
 			}
+			functionMetrics = append(functionMetrics, metrics)
 
+			complexity := metrics.Complexity()
 			totalCyclomatic += complexity.Cyclomatic()
 			totalCognitive += complexity.Cognitive()
 
+			pos := fset.Position(funcDecl.Pos())
+			location, _ := valueobjects.NewSourceLocation(filePath, pos.Line, pos.Column)
+
 			// Check complexity thresholds
 			if complexity.IsHighComplexity() {
-				pos := fset.Position(funcDecl.Pos())
-				location, _ := valueobjects.NewSourceLocation(filePath, pos.Line, pos.Column)
-
 				var severity valueobjects.SeverityLevel
 				if complexity.Cyclomatic() > 20 || complexity.Cognitive() > 30 {
 					severity = valueobjects.SeverityError
@@ -177,32 +441,200 @@ func (uc *analyzeCodeUseCaseImpl) analyzeFile(
 				)
 				findings = append(findings, finding)
 			}
-		}
-	}
 
-	// Detect smells if requested
-	if includeSmells {
-		smellFindings, err := uc.smellDetector.DetectSmells(astFile, fset, config)
-		if err != nil {
-			return nil, fmt.Errorf("failed to detect smells: %w", err)
+			findings = append(findings, uc.checkFunctionMetrics(funcDecl, metrics, location, config)...)
 		}
-		findings = append(findings, smellFindings...)
 	}
 
 	return &FileAnalysisResult{
 		FilePath:       filePath,
 		Findings:       findings,
+		Metrics:        functionMetrics,
 		FunctionCount:  functionCount,
 		TotalCyclomatic: totalCyclomatic,
 		TotalCognitive:  totalCognitive,
-	}, nil
+	}
+}
+
+// checkFunctionMetrics reports functions whose NPath, essential complexity, Halstead volume or effort
+// exceed the configured maximum, or whose maintainability index falls below the configured minimum
+func (uc *analyzeCodeUseCaseImpl) checkFunctionMetrics(
+	funcDecl *ast.FuncDecl,
+	metrics valueobjects.FunctionMetrics,
+	location valueobjects.SourceLocation,
+	config valueobjects.AnalysisConfiguration,
+) []entities.AnalysisFinding {
+	var findings []entities.AnalysisFinding
+	complexity := metrics.Complexity()
+	halstead := metrics.Halstead()
+
+	if maxNPath := config.MaxNPath(); maxNPath > 0 && complexity.NPath() > int64(maxNPath) {
+		severity := valueobjects.SeverityWarning
+		if complexity.NPath() > 10*int64(maxNPath) {
+			severity = valueobjects.SeverityError
+		}
+
+		paths := fmt.Sprintf("%d", complexity.NPath())
+		if complexity.IsNPathSaturated() {
+			paths = "more than " + paths
+		}
+
+		finding, _ := entities.NewAnalysisFinding(
+			fmt.Sprintf("npath_%s_%d", funcDecl.Name.Name, location.Line()),
+			entities.FindingTypeComplexity,
+			location,
+			fmt.Sprintf("Function %s: NPath complexity %s exceeds %d (acyclic execution paths to cover)", funcDecl.Name.Name, paths, maxNPath),
+			severity,
+		)
+		finding.AddMetadata("npath", complexity.NPath())
+		findings = append(findings, finding)
+	}
+
+	if maxEssential := config.MaxEssentialComplexity(); maxEssential > 0 && complexity.Essential() > maxEssential {
+		finding, _ := entities.NewAnalysisFinding(
+			fmt.Sprintf("essential_%s_%d", funcDecl.Name.Name, location.Line()),
+			entities.FindingTypeComplexity,
+			location,
+			fmt.Sprintf("Function %s: essential complexity %d exceeds %d (loops with several exits or goto statements)", funcDecl.Name.Name, complexity.Essential(), maxEssential),
+			valueobjects.SeverityWarning,
+		)
+		finding.AddMetadata("essential_complexity", complexity.Essential())
+		findings = append(findings, finding)
+	}
+
+	var exceeded []string
+	severity := valueobjects.SeverityWarning
+	if maxVolume := config.MaxHalsteadVolume(); maxVolume > 0 && halstead.Volume() > float64(maxVolume) {
+		exceeded = append(exceeded, fmt.Sprintf("volume %.0f exceeds %d", halstead.Volume(), maxVolume))
+		if halstead.Volume() > 2*float64(maxVolume) {
+			severity = valueobjects.SeverityError
+		}
+	}
+	if maxEffort := config.MaxHalsteadEffort(); maxEffort > 0 && halstead.Effort() > float64(maxEffort) {
+		exceeded = append(exceeded, fmt.Sprintf("effort %.0f exceeds %d", halstead.Effort(), maxEffort))
+		if halstead.Effort() > 2*float64(maxEffort) {
+			severity = valueobjects.SeverityError
+		}
+	}
+	if len(exceeded) > 0 {
+		finding, _ := entities.NewAnalysisFinding(
+			fmt.Sprintf("halstead_%s_%d", funcDecl.Name.Name, location.Line()),
+			entities.FindingTypeComplexity,
+			location,
+			fmt.Sprintf("Function %s: Halstead %s (difficulty=%.1f)", funcDecl.Name.Name, strings.Join(exceeded, ", "), halstead.Difficulty()),
+			severity,
+		)
+		finding.AddMetadata("halstead_volume", halstead.Volume())
+		finding.AddMetadata("halstead_difficulty", halstead.Difficulty())
+		finding.AddMetadata("halstead_effort", halstead.Effort())
+		findings = append(findings, finding)
+	}
+
+	index := metrics.MaintainabilityIndex()
+	if minIndex := config.MinMaintainabilityIndex(); minIndex > 0 && index < float64(minIndex) {
+		severity := valueobjects.SeverityWarning
+		if index < float64(minIndex)/2 {
+			severity = valueobjects.SeverityError
+		}
+
+		finding, _ := entities.NewAnalysisFinding(
+			fmt.Sprintf("maintainability_%s_%d", funcDecl.Name.Name, location.Line()),
+			entities.FindingTypeComplexity,
+			location,
+			fmt.Sprintf("Function %s: maintainability index %.1f is below %d (volume=%.1f, cyclomatic=%d, loc=%d)",
+				funcDecl.Name.Name, index, minIndex, halstead.Volume(), complexity.Cyclomatic(), metrics.Lines().Physical()),
+			severity,
+		)
+		finding.AddMetadata("maintainability_index", index)
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// minCohesionMethods is the number of field-using methods a type needs before its cohesion is judged
+const minCohesionMethods = 4
+
+// analyzeTypes returns the design metrics of the package's struct types and, when smell detection
+// is enabled, reports the types whose methods split into groups sharing no fields
+func (uc *analyzeCodeUseCaseImpl) analyzeTypes(pkg *parsedPackage, config valueobjects.AnalysisConfiguration, detectSmells bool) ([]valueobjects.TypeMetrics, []entities.AnalysisFinding, error) {
+	typeMetrics, err := uc.complexityCalculator.CalculateTypeMetrics(services.NewPackageContext(pkg.fset, pkg.files, nil))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var findings []entities.AnalysisFinding
+	for _, metrics := range typeMetrics {
+		if !detectSmells || metrics.LCOM4() < 2 {
+			continue
+		}
+
+		groups := metrics.Groups()
+		cohesive := 0
+		for _, group := range groups {
+			cohesive += len(group.Methods())
+		}
+		if cohesive < minCohesionMethods {
+			continue
+		}
+
+		described := make([]string, 0, len(groups))
+		for _, group := range groups {
+			described = append(described, group.String())
+		}
+
+		kind := "Struct"
+		if metrics.Fields() > config.MaxStructFields() {
+			kind = "God struct"
+		}
+
+		finding, _ := entities.NewAnalysisFinding(
+			fmt.Sprintf("%s_%s_%d", services.SmellTypeLowCohesion.String(), metrics.Name(), metrics.Location().Line()),
+			entities.FindingTypeSmell,
+			metrics.Location(),
+			fmt.Sprintf("%s %s has low cohesion: LCOM4=%d, the %d methods using its fields split into groups sharing no fields: %s; consider splitting %s along these groups",
+				kind, metrics.Name(), metrics.LCOM4(), cohesive, strings.Join(described, " and "), metrics.Name()),
+			valueobjects.SeverityWarning,
+		)
+		finding.AddMetadata("fields", metrics.Fields())
+		finding.AddMetadata("methods", metrics.Methods())
+		finding.AddMetadata("lcom4", metrics.LCOM4())
+		findings = append(findings, finding)
+	}
+
+	return typeMetrics, findings, nil
+}
+
+// detectClones returns duplicated code across all packages and the duplication of each package
+func (uc *analyzeCodeUseCaseImpl) detectClones(packages []*parsedPackage, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, []valueobjects.PackageDuplication, error) {
+	contexts := make([]*services.PackageContext, 0, len(packages))
+	for _, pkg := range packages {
+		contexts = append(contexts, services.NewPackageContext(pkg.fset, pkg.files, nil))
+	}
+
+	return uc.cloneDetector.DetectClones(contexts, config)
+}
+
+// inChangedLines reports whether a finding lies in changed code. A finding at the first line of a
+// function covers the whole function, so a change anywhere in its body keeps it
+func inChangedLines(changes *valueobjects.ChangeSet, spans map[string]map[int]int, finding entities.AnalysisFinding) bool {
+	if changes == nil {
+		return true
+	}
+
+	location := finding.Location()
+	end := location.Line()
+	if functionEnd, ok := spans[location.FilePath()][location.Line()]; ok {
+		end = functionEnd
+	}
+	return changes.Overlaps(location.FilePath(), location.Line(), end)
 }
 
 // createSummary creates a human-readable summary of the analysis
 func (uc *analyzeCodeUseCaseImpl) createSummary(result aggregates.AnalysisResult) string {
 	summary := result.Summary()
 
-	return fmt.Sprintf(
+	text := fmt.Sprintf(
 		"Analysis complete: %d files, %d functions analyzed. Found %d issues (%d high severity) in %v. Complexity: %s",
 		summary.TotalFiles,
 		summary.TotalFunctions,
@@ -211,13 +643,44 @@ func (uc *analyzeCodeUseCaseImpl) createSummary(result aggregates.AnalysisResult
 		summary.Duration,
 		result.TotalComplexity().String(),
 	)
+
+	if len(summary.Duplication) > 0 {
+		text += fmt.Sprintf(". Duplication: %.1f%% overall", summary.DuplicationPercentage)
+		if packages := describeDuplication(summary.Duplication); packages != "" {
+			text += " (" + packages + ")"
+		}
+	}
+
+	return text
+}
+
+// describeDuplication lists the packages containing duplicated code, most duplicated first
+func describeDuplication(duplication []valueobjects.PackageDuplication) string {
+	var duplicated []valueobjects.PackageDuplication
+	for _, pkg := range duplication {
+		if pkg.DuplicatedTokens() > 0 {
+			duplicated = append(duplicated, pkg)
+		}
+	}
+	sort.SliceStable(duplicated, func(i, j int) bool {
+		return duplicated[i].Percentage() > duplicated[j].Percentage()
+	})
+
+	parts := make([]string, 0, len(duplicated))
+	for _, pkg := range duplicated {
+		parts = append(parts, pkg.String())
+	}
+	return strings.Join(parts, ", ")
 }
 
 // FileAnalysisResult represents the analysis result for a single file
 type FileAnalysisResult struct {
 	FilePath        string
 	Findings        []entities.AnalysisFinding
+	Metrics         []valueobjects.FunctionMetrics
 	FunctionCount   int
 	TotalCyclomatic int
 	TotalCognitive  int
+	// Suppressions are the //goast:ignore comments of the file
+	Suppressions []valueobjects.Suppression
 }
@@ -0,0 +1,156 @@
+package usecases
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"goastanalyzer/domain/services"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// testParser parses every file into one file set, like the real parser, and records the files
+// it parsed
+type testParser struct {
+	fset   *token.FileSet
+	parsed []string
+}
+
+func (p *testParser) ParseFile(filePath string) (*ast.File, *token.FileSet, error) {
+	file, err := parser.ParseFile(p.fset, filePath, nil, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	p.parsed = append(p.parsed, filePath)
+	return file, p.fset, nil
+}
+
+// testLoader builds package contexts without type information; dependencies maps a package
+// directory to the files of its module-local imports
+type testLoader struct {
+	dependencies map[string][]string
+}
+
+func (l *testLoader) LoadPackage(fset *token.FileSet, files []*ast.File) (*services.PackageContext, error) {
+	return services.NewPackageContext(fset, files, nil), nil
+}
+
+func (l *testLoader) DependencyFiles(dir string) ([]string, error) {
+	return l.dependencies[dir], nil
+}
+
+// testIDGenerator returns a fixed result ID
+type testIDGenerator struct{}
+
+func (testIDGenerator) GenerateID() string {
+	return "test"
+}
+
+func TestAnalyzeCode_ChangedLines(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSource(t, dir, "p/a.go", `package p
+
+func first(a int) int {
+	if a > 0 {
+		return 1
+	}
+	return 0
+}
+
+func second(a int) int {
+	ch := make(chan int)
+	go func() {
+		ch <- a
+	}()
+	if a > 0 {
+		return 1
+	}
+	return 0
+}
+`)
+
+	// Both functions exceed the NPath limit, reported at their first line, and second leaks a
+	// goroutine, reported at line 12
+	changes := func(file string, start, lines int) *valueobjects.ChangeSet {
+		hunk, err := valueobjects.NewDiffHunk(start, 0, start, lines)
+		if err != nil {
+			t.Fatalf("Failed to create hunk: %v", err)
+		}
+		changeSet := valueobjects.NewChangeSet(map[string][]valueobjects.DiffHunk{file: {hunk}})
+		return &changeSet
+	}
+	empty := valueobjects.NewChangeSet(nil)
+
+	tests := []struct {
+		name     string
+		changes  *valueobjects.ChangeSet
+		expected []string
+	}{
+		{name: "No change set keeps every finding", changes: nil, expected: []string{"complexity:10", "complexity:3", "smell:12"}},
+		{name: "Change in a function body keeps the function's finding", changes: changes(path, 5, 1), expected: []string{"complexity:3"}},
+		{name: "Change at a reported line keeps its findings", changes: changes(path, 12, 1), expected: []string{"complexity:10", "smell:12"}},
+		{name: "Change below a statement finding drops it", changes: changes(path, 13, 2), expected: []string{"complexity:10"}},
+		{name: "Change spanning both functions", changes: changes(path, 7, 4), expected: []string{"complexity:10", "complexity:3"}},
+		{name: "Change outside any function", changes: changes(path, 1, 1), expected: nil},
+		{name: "Change in another file", changes: changes(filepath.Join(dir, "p/b.go"), 5, 1), expected: nil},
+		{name: "Empty change set", changes: &empty, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := newTestUseCase(&testParser{fset: token.NewFileSet()}, &testLoader{}, nil).Execute(AnalyzeCodeRequest{
+				FilePaths:             []string{path},
+				Configuration:         valueobjects.DefaultAnalysisConfiguration().WithMaxNPath(1),
+				IncludeSmellDetection: true,
+				ChangedLines:          tt.changes,
+			})
+			if err != nil || !response.Success {
+				t.Fatalf("Execute failed: %v, %v", err, response.Error)
+			}
+
+			var findings []string
+			for _, finding := range response.AnalysisResult.Findings() {
+				findings = append(findings, fmt.Sprintf("%s:%d", finding.Type(), finding.Location().Line()))
+			}
+			sort.Strings(findings)
+			if !reflect.DeepEqual(findings, tt.expected) {
+				t.Errorf("Expected findings %v, got %v", tt.expected, findings)
+			}
+		})
+	}
+}
+
+// newTestUseCase wires the analysis with the real detectors around the given parser, loader and
+// cache; cache may be nil to disable caching
+func newTestUseCase(parser *testParser, loader *testLoader, cache AnalysisCache) *analyzeCodeUseCaseImpl {
+	return NewAnalyzeCodeUseCase(
+		services.NewASTComplexityCalculator(),
+		services.NewASTSmellDetector(),
+		services.NewASTCloneDetector(),
+		services.NewCommentSuppressionScanner(),
+		parser,
+		loader,
+		testIDGenerator{},
+		cache,
+	).(*analyzeCodeUseCaseImpl)
+}
+
+// writeSource writes a source file into dir and returns its path
+func writeSource(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	return path
+}
@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"fmt"
+
+	"goastanalyzer/domain/services"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// FindHotspotsUseCase defines the contract for ranking code by change frequency and complexity
+type FindHotspotsUseCase interface {
+	Execute(request FindHotspotsRequest) (*FindHotspotsResponse, error)
+}
+
+// FindHotspotsRequest represents the input for hotspot analysis
+type FindHotspotsRequest struct {
+	FilePaths     []string
+	Configuration valueobjects.AnalysisConfiguration
+	Since         string
+	Limit         int
+}
+
+// FindHotspotsResponse represents the ranked file and function hotspots
+type FindHotspotsResponse struct {
+	Files     []valueobjects.Hotspot
+	Functions []valueobjects.Hotspot
+	Summary   string
+	Success   bool
+	Error     error
+}
+
+// ChangeHistoryReader defines the interface for reading the version control history of files
+type ChangeHistoryReader interface {
+	// ReadHistory returns the revisions of each file since the given time, newest first
+	ReadHistory(filePaths []string, since string) (map[string][]valueobjects.FileRevision, error)
+}
+
+// findHotspotsUseCaseImpl implements FindHotspotsUseCase
+type findHotspotsUseCaseImpl struct {
+	analyzer      AnalyzeCodeUseCase
+	historyReader ChangeHistoryReader
+	ranker        services.HotspotRanker
+}
+
+// NewFindHotspotsUseCase creates a new find hotspots use case
+func NewFindHotspotsUseCase(
+	analyzer AnalyzeCodeUseCase,
+	historyReader ChangeHistoryReader,
+	ranker services.HotspotRanker,
+) FindHotspotsUseCase {
+	return &findHotspotsUseCaseImpl{
+		analyzer:      analyzer,
+		historyReader: historyReader,
+		ranker:        ranker,
+	}
+}
+
+// Execute measures complexity, reads the change history and ranks the hotspots
+func (uc *findHotspotsUseCaseImpl) Execute(request FindHotspotsRequest) (*FindHotspotsResponse, error) {
+	analysis, err := uc.analyzer.Execute(AnalyzeCodeRequest{
+		FilePaths:     request.FilePaths,
+		Configuration: request.Configuration,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !analysis.Success {
+		return &FindHotspotsResponse{
+			Success: false,
+			Error:   analysis.Error,
+		}, nil
+	}
+
+	history, err := uc.historyReader.ReadHistory(analysis.AnalysisResult.AnalyzedFiles(), request.Since)
+	if err != nil {
+		return &FindHotspotsResponse{
+			Success: false,
+			Error:   fmt.Errorf("failed to read change history: %w", err),
+		}, nil
+	}
+
+	hotspots, err := uc.ranker.RankHotspots(history, analysis.AnalysisResult.FunctionMetrics())
+	if err != nil {
+		return &FindHotspotsResponse{
+			Success: false,
+			Error:   fmt.Errorf("failed to rank hotspots: %w", err),
+		}, nil
+	}
+
+	response := &FindHotspotsResponse{Success: true}
+	for _, hotspot := range hotspots {
+		switch hotspot.Kind() {
+		case valueobjects.HotspotKindFile:
+			response.Files = append(response.Files, hotspot)
+		case valueobjects.HotspotKindFunction:
+			response.Functions = append(response.Functions, hotspot)
+		}
+	}
+
+	commits := make(map[string]bool)
+	for _, revisions := range history {
+		for _, revision := range revisions {
+			commits[revision.Commit()] = true
+		}
+	}
+	response.Summary = fmt.Sprintf("Hotspots since %s: %d commits changed %d of %d files; %d changed functions ranked by revisions × cyclomatic complexity",
+		request.Since, len(commits), len(response.Files), len(analysis.AnalysisResult.AnalyzedFiles()), len(response.Functions))
+
+	if request.Limit > 0 {
+		response.Files = response.Files[:min(request.Limit, len(response.Files))]
+		response.Functions = response.Functions[:min(request.Limit, len(response.Functions))]
+	}
+
+	return response, nil
+}
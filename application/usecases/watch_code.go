@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	"fmt"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/services"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// WatchCodeUseCase defines the contract for re-analyzing code whenever it changes
+type WatchCodeUseCase interface {
+	Execute(request WatchCodeRequest, stop <-chan struct{}) error
+}
+
+// WatchCodeRequest represents the input for watching code
+type WatchCodeRequest struct {
+	Configuration         valueobjects.AnalysisConfiguration
+	IncludeSmellDetection bool
+	// OnUpdate receives the result of the first analysis and of every analysis after a change
+	OnUpdate func(WatchUpdate)
+}
+
+// WatchUpdate reports one analysis of the watched files
+type WatchUpdate struct {
+	// Changed lists the files whose change triggered the analysis; it is empty for the first one
+	Changed  []string
+	Response *AnalyzeCodeResponse
+	// Diff compares the findings with the last successful analysis
+	Diff services.FindingDiff
+}
+
+// FileWatcher defines the interface for observing the Go files of a tree
+type FileWatcher interface {
+	// Files returns the Go files currently watched and remembers their state
+	Files() ([]string, error)
+	// Wait blocks until watched files are added, modified or removed since the last call to
+	// Files and returns them, or returns nil once stop is closed
+	Wait(stop <-chan struct{}) ([]string, error)
+}
+
+// watchCodeUseCaseImpl implements WatchCodeUseCase
+type watchCodeUseCaseImpl struct {
+	newAnalyzer func() AnalyzeCodeUseCase
+	watcher     FileWatcher
+	differ      services.FindingDiffer
+}
+
+// NewWatchCodeUseCase creates a new watch code use case. Every analysis runs on a fresh analyzer
+// from newAnalyzer so that no parsed or type-checked state outlives the edit it was read before;
+// analyzers sharing one AnalysisCache keep each run incremental
+func NewWatchCodeUseCase(
+	newAnalyzer func() AnalyzeCodeUseCase,
+	watcher FileWatcher,
+	differ services.FindingDiffer,
+) WatchCodeUseCase {
+	return &watchCodeUseCaseImpl{
+		newAnalyzer: newAnalyzer,
+		watcher:     watcher,
+		differ:      differ,
+	}
+}
+
+// Execute analyzes the watched files, then again after every change until stop is closed.
+// A failed analysis, such as one of a file saved mid-edit, is reported and the next diff is
+// taken against the last successful one
+func (uc *watchCodeUseCaseImpl) Execute(request WatchCodeRequest, stop <-chan struct{}) error {
+	var previous []entities.AnalysisFinding
+	var changed []string
+
+	for {
+		files, err := uc.watcher.Files()
+		if err != nil {
+			return fmt.Errorf("failed to list watched files: %w", err)
+		}
+
+		response, err := uc.newAnalyzer().Execute(AnalyzeCodeRequest{
+			FilePaths:             files,
+			Configuration:         request.Configuration,
+			IncludeSmellDetection: request.IncludeSmellDetection,
+		})
+		if err != nil {
+			return err
+		}
+
+		update := WatchUpdate{Changed: changed, Response: response}
+		if response.Success {
+			findings := response.AnalysisResult.Findings()
+			update.Diff = uc.differ.DiffFindings(previous, findings)
+			previous = findings
+		}
+		request.OnUpdate(update)
+
+		changed, err = uc.watcher.Wait(stop)
+		if err != nil {
+			return fmt.Errorf("failed to watch files: %w", err)
+		}
+		if changed == nil {
+			return nil
+		}
+	}
+}
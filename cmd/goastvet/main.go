@@ -0,0 +1,15 @@
+// Command goastvet runs the goastanalyzer detectors as go/analysis analyzers. It works standalone
+// on package patterns or as a vet tool:
+//
+//	go vet -vettool=$(which goastvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"goastanalyzer/presentation/analyzers"
+)
+
+func main() {
+	multichecker.Main(analyzers.All()...)
+}
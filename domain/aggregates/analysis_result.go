@@ -19,6 +19,9 @@ type AnalysisResult struct {
 	totalFiles       int
 	totalFunctions   int
 	totalComplexity  valueobjects.ComplexityScore
+	duplication      []valueobjects.PackageDuplication
+	functionMetrics  []valueobjects.FunctionMetrics
+	typeMetrics      []valueobjects.TypeMetrics
 }
 
 // NewAnalysisResult creates a new analysis result
@@ -98,6 +101,40 @@ func (ar AnalysisResult) TotalComplexity() valueobjects.ComplexityScore {
 	return ar.totalComplexity
 }
 
+// FunctionMetrics returns the complexity, Halstead and size metrics of every analyzed function
+func (ar AnalysisResult) FunctionMetrics() []valueobjects.FunctionMetrics {
+	metrics := make([]valueobjects.FunctionMetrics, len(ar.functionMetrics))
+	copy(metrics, ar.functionMetrics)
+	return metrics
+}
+
+// TypeMetrics returns the field, method and cohesion metrics of every analyzed struct type
+func (ar AnalysisResult) TypeMetrics() []valueobjects.TypeMetrics {
+	metrics := make([]valueobjects.TypeMetrics, len(ar.typeMetrics))
+	copy(metrics, ar.typeMetrics)
+	return metrics
+}
+
+// PackageDuplication returns the share of duplicated code measured in each package
+func (ar AnalysisResult) PackageDuplication() []valueobjects.PackageDuplication {
+	duplication := make([]valueobjects.PackageDuplication, len(ar.duplication))
+	copy(duplication, ar.duplication)
+	return duplication
+}
+
+// DuplicationPercentage returns the share of duplicated tokens across all packages
+func (ar AnalysisResult) DuplicationPercentage() float64 {
+	total, duplicated := 0, 0
+	for _, pkg := range ar.duplication {
+		total += pkg.TotalTokens()
+		duplicated += pkg.DuplicatedTokens()
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(duplicated) * 100 / float64(total)
+}
+
 // HighSeverityFindings returns only findings with high severity
 func (ar AnalysisResult) HighSeverityFindings() []entities.AnalysisFinding {
 	var highSeverity []entities.AnalysisFinding
@@ -153,6 +190,21 @@ func (ar *AnalysisResult) SetTotalComplexity(complexity valueobjects.ComplexityS
 	ar.totalComplexity = complexity
 }
 
+// AddFunctionMetrics records the metrics of an analyzed function
+func (ar *AnalysisResult) AddFunctionMetrics(metrics valueobjects.FunctionMetrics) {
+	ar.functionMetrics = append(ar.functionMetrics, metrics)
+}
+
+// AddTypeMetrics records the metrics of an analyzed struct type
+func (ar *AnalysisResult) AddTypeMetrics(metrics valueobjects.TypeMetrics) {
+	ar.typeMetrics = append(ar.typeMetrics, metrics)
+}
+
+// SetPackageDuplication sets the per-package duplication measured by clone detection
+func (ar *AnalysisResult) SetPackageDuplication(duplication []valueobjects.PackageDuplication) {
+	ar.duplication = append([]valueobjects.PackageDuplication(nil), duplication...)
+}
+
 // Complete marks the analysis as complete
 func (ar *AnalysisResult) Complete() {
 	ar.endTime = time.Now()
@@ -178,6 +230,8 @@ func (ar AnalysisResult) Summary() AnalysisSummary {
 		SecurityFindings:   len(findingsByType[entities.FindingTypeSecurity]),
 		PerformanceFindings: len(findingsByType[entities.FindingTypePerformance]),
 		Duration:           ar.Duration(),
+		Duplication:        ar.PackageDuplication(),
+		DuplicationPercentage: ar.DuplicationPercentage(),
 	}
 }
 
@@ -192,4 +246,6 @@ type AnalysisSummary struct {
 	SecurityFindings    int
 	PerformanceFindings int
 	Duration            time.Duration
+	Duplication         []valueobjects.PackageDuplication
+	DuplicationPercentage float64
 }
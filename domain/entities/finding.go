@@ -45,6 +45,8 @@ type AnalysisFinding struct {
 	severity    valueobjects.SeverityLevel
 	timestamp   time.Time
 	metadata    map[string]interface{}
+	trace       []valueobjects.SourceLocation
+	fixes       []valueobjects.SuggestedFix
 }
 
 // NewAnalysisFinding creates a new analysis finding
@@ -122,6 +124,28 @@ func (f *AnalysisFinding) AddMetadata(key string, value interface{}) {
 	f.metadata[key] = value
 }
 
+// Trace returns the source-to-sink steps that lead to this finding, if any
+func (f AnalysisFinding) Trace() []valueobjects.SourceLocation {
+	// Return a copy to prevent external modification
+	return append([]valueobjects.SourceLocation(nil), f.trace...)
+}
+
+// AddTraceStep appends a step to the data flow trace of this finding
+func (f *AnalysisFinding) AddTraceStep(location valueobjects.SourceLocation) {
+	f.trace = append(f.trace, location)
+}
+
+// Fixes returns the suggested fixes that resolve this finding, if any
+func (f AnalysisFinding) Fixes() []valueobjects.SuggestedFix {
+	// Return a copy to prevent external modification
+	return append([]valueobjects.SuggestedFix(nil), f.fixes...)
+}
+
+// AddFix attaches a suggested fix to this finding
+func (f *AnalysisFinding) AddFix(fix valueobjects.SuggestedFix) {
+	f.fixes = append(f.fixes, fix)
+}
+
 // IsHighSeverity checks if this finding has high severity
 func (f AnalysisFinding) IsHighSeverity() bool {
 	return f.severity >= valueobjects.SeverityError
@@ -0,0 +1,407 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// AtomicAccessIssue represents the plain accesses of a value that is also accessed atomically
+type AtomicAccessIssue int
+
+const (
+	AtomicMixedRead AtomicAccessIssue = iota
+	AtomicMixedWrite
+)
+
+// String returns a string representation of the atomic access issue
+func (aai AtomicAccessIssue) String() string {
+	switch aai {
+	case AtomicMixedRead:
+		return "non_atomic_read"
+	case AtomicMixedWrite:
+		return "non_atomic_write"
+	default:
+		return "unknown"
+	}
+}
+
+// atomicOperations are the sync/atomic function prefixes that take the address of a value
+var atomicOperations = []string{"CompareAndSwap", "Add", "Load", "Store", "Swap", "And", "Or"}
+
+// atomicTypes maps the operand suffix of a sync/atomic function to its typed replacement
+var atomicTypes = map[string]string{
+	"Int32":   "atomic.Int32",
+	"Int64":   "atomic.Int64",
+	"Uint32":  "atomic.Uint32",
+	"Uint64":  "atomic.Uint64",
+	"Uintptr": "atomic.Uintptr",
+	"Pointer": "atomic.Pointer[T]",
+}
+
+// AtomicAccessDetector detects struct fields and package variables accessed both atomically and plainly
+type AtomicAccessDetector interface {
+	DetectAtomicAccess(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageAtomicAccess(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTAtomicAccessDetector implements AtomicAccessDetector using AST analysis
+type ASTAtomicAccessDetector struct{}
+
+// NewASTAtomicAccessDetector creates a new AST-based mixed atomic access detector
+func NewASTAtomicAccessDetector() *ASTAtomicAccessDetector {
+	return &ASTAtomicAccessDetector{}
+}
+
+// atomicKey identifies a field or package variable, by object when typed and by name otherwise
+type atomicKey struct {
+	obj  types.Object
+	name string
+}
+
+// atomicTarget records the first atomic operation applied to a value
+type atomicTarget struct {
+	expr      string
+	operation string
+	pos       token.Pos
+}
+
+// atomicAccessScan holds the atomically accessed values of a package and the findings
+type atomicAccessScan struct {
+	pkg         *PackageContext
+	fieldOwners map[string][]string
+	packageVars map[*ast.ValueSpec]bool
+	imports     map[string]bool
+	targets     map[atomicKey]*atomicTarget
+	findings    []entities.AnalysisFinding
+}
+
+// DetectAtomicAccess analyzes code for values accessed both with sync/atomic and plainly
+func (aad *ASTAtomicAccessDetector) DetectAtomicAccess(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	return aad.detect(newNodePackageContext(node, fset), []ast.Node{node}), nil
+}
+
+// DetectPackageAtomicAccess analyzes every file of a package, so atomic operations in one file
+// are matched against plain accesses in the others
+func (aad *ASTAtomicAccessDetector) DetectPackageAtomicAccess(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var nodes []ast.Node
+	for _, file := range pkg.Files() {
+		nodes = append(nodes, file)
+	}
+	return aad.detect(pkg, nodes), nil
+}
+
+// detect collects the atomically accessed values under nodes, then reports plain accesses to them
+func (aad *ASTAtomicAccessDetector) detect(pkg *PackageContext, nodes []ast.Node) []entities.AnalysisFinding {
+	scan := &atomicAccessScan{
+		pkg:         pkg,
+		fieldOwners: make(map[string][]string),
+		packageVars: make(map[*ast.ValueSpec]bool),
+		imports:     make(map[string]bool),
+		targets:     make(map[atomicKey]*atomicTarget),
+	}
+
+	for _, node := range nodes {
+		scan.collectDeclarations(node)
+	}
+	for _, node := range nodes {
+		scan.collectTargets(node)
+	}
+	if len(scan.targets) == 0 {
+		return nil
+	}
+
+	for _, node := range nodes {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if funcDecl, ok := n.(*ast.FuncDecl); ok {
+				if funcDecl.Body != nil {
+					scan.checkFunction(funcDecl)
+				}
+				return false
+			}
+			return true
+		})
+	}
+	return scan.findings
+}
+
+// collectDeclarations indexes struct fields by name, the package-level variables and the import names
+func (scan *atomicAccessScan) collectDeclarations(node ast.Node) {
+	if file, ok := node.(*ast.File); ok {
+		for _, spec := range file.Imports {
+			name := path.Base(strings.Trim(spec.Path.Value, `"`))
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			scan.imports[name] = true
+		}
+		for _, decl := range file.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.VAR {
+				for _, spec := range genDecl.Specs {
+					scan.packageVars[spec.(*ast.ValueSpec)] = true
+				}
+			}
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		typeSpec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+			for _, field := range structType.Fields.List {
+				for _, name := range field.Names {
+					scan.fieldOwners[name.Name] = append(scan.fieldOwners[name.Name], typeSpec.Name.Name)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// collectTargets records every value whose address is passed to a sync/atomic function
+func (scan *atomicAccessScan) collectTargets(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		operation := scan.atomicOperation(call)
+		if operation == "" {
+			return true
+		}
+		address, ok := ast.Unparen(call.Args[0]).(*ast.UnaryExpr)
+		if !ok || address.Op != token.AND {
+			return true
+		}
+		if key, ok := scan.keyOf(address.X); ok {
+			if _, known := scan.targets[key]; !known {
+				scan.targets[key] = &atomicTarget{expr: types.ExprString(address.X), operation: operation, pos: call.Pos()}
+			}
+		}
+		return true
+	})
+}
+
+// atomicOperation returns the name of the sync/atomic function invoked by call, or ""
+func (scan *atomicAccessScan) atomicOperation(call *ast.CallExpr) string {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	isAtomic := ident.Name == "atomic"
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if pkgName, ok := info.Uses[ident].(*types.PkgName); ok {
+			isAtomic = pkgName.Imported().Path() == "sync/atomic"
+		}
+	}
+	if !isAtomic || atomicSuffix(selector.Sel.Name) == "" {
+		return ""
+	}
+	return selector.Sel.Name
+}
+
+// atomicSuffix returns the operand type suffix of a sync/atomic function name, or ""
+func atomicSuffix(name string) string {
+	for _, prefix := range atomicOperations {
+		if suffix, ok := strings.CutPrefix(name, prefix); ok {
+			if _, known := atomicTypes[suffix]; known {
+				return suffix
+			}
+		}
+	}
+	return ""
+}
+
+// keyOf identifies the struct field or package variable denoted by expr
+func (scan *atomicAccessScan) keyOf(expr ast.Expr) (atomicKey, bool) {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		var ident *ast.Ident
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.SelectorExpr:
+			ident = e.Sel
+		case *ast.Ident:
+			ident = e
+		}
+		// Fall back to syntax only where type checking left the name unresolved
+		if obj := info.Uses[ident]; ident != nil && obj != nil {
+			variable, ok := obj.(*types.Var)
+			if !ok {
+				return atomicKey{}, false
+			}
+			if variable.IsField() {
+				return atomicKey{obj: variable}, true
+			}
+			return atomicKey{obj: variable}, variable.Pkg() != nil && variable.Parent() == variable.Pkg().Scope()
+		}
+	}
+
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		// Without types a field name is only trusted when a single struct declares it
+		if base, ok := ast.Unparen(e.X).(*ast.Ident); ok && scan.imports[base.Name] {
+			return atomicKey{}, false
+		}
+		if owners := scan.fieldOwners[e.Sel.Name]; len(owners) == 1 {
+			return atomicKey{name: owners[0] + "." + e.Sel.Name}, true
+		}
+	case *ast.Ident:
+		if e.Obj == nil || e.Obj.Kind != ast.Var {
+			return atomicKey{}, false
+		}
+		if spec, ok := e.Obj.Decl.(*ast.ValueSpec); ok && scan.packageVars[spec] {
+			return atomicKey{name: e.Name}, true
+		}
+	}
+	return atomicKey{}, false
+}
+
+// checkFunction reports the first plain read and write of each atomic value in a function
+func (scan *atomicAccessScan) checkFunction(funcDecl *ast.FuncDecl) {
+	writes := make(map[ast.Expr]bool)
+	skip := make(map[ast.Node]bool)
+	fresh := freshValues(funcDecl.Body)
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range stmt.Lhs {
+				writes[ast.Unparen(lhs)] = true
+			}
+		case *ast.IncDecStmt:
+			writes[ast.Unparen(stmt.X)] = true
+		}
+		return true
+	})
+
+	reported := make(map[atomicKey]map[AtomicAccessIssue]bool)
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		var expr ast.Expr
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			// Atomic calls are the sanctioned accesses
+			return scan.atomicOperation(node) == ""
+		case *ast.UnaryExpr:
+			// Taking the address hands the value to code we cannot see
+			return node.Op != token.AND
+		case *ast.KeyValueExpr:
+			skip[node.Key] = true
+			return true
+		case *ast.SelectorExpr:
+			skip[node.Sel] = true
+			if base, ok := ast.Unparen(node.X).(*ast.Ident); ok && fresh[base.Name] {
+				return false
+			}
+			expr = node
+		case *ast.Ident:
+			if skip[node] {
+				return false
+			}
+			expr = node
+		default:
+			return true
+		}
+
+		key, ok := scan.keyOf(expr)
+		if !ok {
+			return true
+		}
+		target := scan.targets[key]
+		if target == nil {
+			return true
+		}
+
+		issue := AtomicMixedRead
+		if writes[expr] {
+			issue = AtomicMixedWrite
+		}
+		if reported[key] == nil {
+			reported[key] = make(map[AtomicAccessIssue]bool)
+		}
+		if !reported[key][issue] {
+			reported[key][issue] = true
+			scan.report(issue, expr, funcDecl.Name.Name, target)
+		}
+		return true
+	})
+}
+
+// freshValues returns the local variables a function initializes with a new value, whose
+// fields cannot be shared with other goroutines until the value escapes
+func freshValues(body *ast.BlockStmt) map[string]bool {
+	fresh := make(map[string]bool)
+	isFresh := func(value ast.Expr) bool {
+		switch v := ast.Unparen(value).(type) {
+		case *ast.CompositeLit:
+			return true
+		case *ast.UnaryExpr:
+			_, ok := ast.Unparen(v.X).(*ast.CompositeLit)
+			return v.Op == token.AND && ok
+		case *ast.CallExpr:
+			fun, ok := v.Fun.(*ast.Ident)
+			return ok && fun.Name == "new"
+		}
+		return false
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch decl := n.(type) {
+		case *ast.AssignStmt:
+			if decl.Tok != token.DEFINE || len(decl.Lhs) != len(decl.Rhs) {
+				return true
+			}
+			for i, lhs := range decl.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && isFresh(decl.Rhs[i]) {
+					fresh[ident.Name] = true
+				}
+			}
+		case *ast.ValueSpec:
+			for i, ident := range decl.Names {
+				if len(decl.Values) == 0 || (i < len(decl.Values) && isFresh(decl.Values[i])) {
+					fresh[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	return fresh
+}
+
+// report records a plain access to a value that is also accessed atomically
+func (scan *atomicAccessScan) report(issue AtomicAccessIssue, expr ast.Expr, funcName string, target *atomicTarget) {
+	position := scan.pkg.FileSet().Position(expr.Pos())
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+	atomicPosition := scan.pkg.FileSet().Position(target.pos)
+
+	access, severity := "read", valueobjects.SeverityWarning
+	if issue == AtomicMixedWrite {
+		access, severity = "written", valueobjects.SeverityError
+	}
+	replacement := atomicTypes[atomicSuffix(target.operation)]
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue.String(), funcName, position.Line, position.Column),
+		entities.FindingTypeBug,
+		location,
+		fmt.Sprintf("Mixed atomic access in %s: %s detected - '%s' is %s without sync/atomic although atomic.%s accesses it at %s:%d; declare it as %s so every access is atomic",
+			funcName, issue.String(), types.ExprString(expr), access, target.operation, atomicPosition.Filename, atomicPosition.Line, replacement),
+		severity,
+	)
+	finding.AddMetadata("value", target.expr)
+	finding.AddMetadata("atomic_operation", target.operation)
+	finding.AddMetadata("suggestion", replacement)
+
+	scan.findings = append(scan.findings, finding)
+}
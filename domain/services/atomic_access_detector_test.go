@@ -0,0 +1,219 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestAtomicAccessDetector_DetectAtomicAccess(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		expectedIssues int
+		expectedTypes  []string
+	}{
+		{
+			name: "Field incremented atomically and read plainly",
+			code: `
+package main
+import "sync/atomic"
+type stats struct {
+	hits int64
+}
+func (s *stats) record() {
+	atomic.AddInt64(&s.hits, 1)
+}
+func (s *stats) report() int64 {
+	return s.hits
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"non_atomic_read", "'s.hits' is read", "atomic.AddInt64", "atomic.Int64"},
+		},
+		{
+			name: "Field reset with plain store",
+			code: `
+package main
+import "sync/atomic"
+type gate struct {
+	open uint32
+}
+func (g *gate) isOpen() bool {
+	return atomic.LoadUint32(&g.open) == 1
+}
+func (g *gate) reset() {
+	g.open = 0
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"non_atomic_write", "'g.open' is written", "atomic.Uint32"},
+		},
+		{
+			name: "Package variable mixed access",
+			code: `
+package main
+import (
+	"fmt"
+	"sync/atomic"
+)
+var requests int32
+func handle() {
+	atomic.AddInt32(&requests, 1)
+}
+func dump() {
+	fmt.Println(requests)
+	requests++
+}`,
+			expectedIssues: 2,
+			expectedTypes:  []string{"non_atomic_read", "non_atomic_write", "'requests'", "atomic.Int32"},
+		},
+		{
+			name: "Only atomic accesses - no issue",
+			code: `
+package main
+import "sync/atomic"
+type stats struct {
+	hits int64
+}
+func (s *stats) record() {
+	atomic.AddInt64(&s.hits, 1)
+}
+func (s *stats) report() int64 {
+	return atomic.LoadInt64(&s.hits)
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Initialization before publication - no issue",
+			code: `
+package main
+import "sync/atomic"
+type stats struct {
+	hits int64
+}
+func newStats(start int64) *stats {
+	s := &stats{}
+	s.hits = start
+	return s
+}
+func (s *stats) record() {
+	atomic.AddInt64(&s.hits, 1)
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Local shadowing package variable - no issue",
+			code: `
+package main
+import "sync/atomic"
+var requests int32
+func handle() {
+	atomic.AddInt32(&requests, 1)
+}
+func local() int32 {
+	requests := int32(3)
+	return requests
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Field name shared by several structs - no issue without types",
+			code: `
+package main
+import "sync/atomic"
+type a struct {
+	n int64
+}
+type b struct {
+	n int64
+}
+func (x *a) inc() {
+	atomic.AddInt64(&x.n, 1)
+}
+func (y *b) get() int64 {
+	return y.n
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			detector := NewASTAtomicAccessDetector()
+			config := valueobjects.DefaultAnalysisConfiguration()
+			findings, err := detector.DetectAtomicAccess(node, fset, config)
+			if err != nil {
+				t.Fatalf("DetectAtomicAccess failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+		})
+	}
+}
+
+func TestAtomicAccessDetector_DetectPackageAtomicAccess(t *testing.T) {
+	sources := []string{`
+package metrics
+import "sync/atomic"
+type a struct {
+	n int64
+}
+type b struct {
+	n int64
+}
+func (x *a) inc() {
+	atomic.AddInt64(&x.n, 1)
+}`, `
+package metrics
+func (x *a) get() int64 {
+	return x.n
+}
+func (y *b) get() int64 {
+	return y.n
+}`}
+
+	pkg := checkPackage(t, sources...)
+
+	detector := NewASTAtomicAccessDetector()
+	findings, err := detector.DetectPackageAtomicAccess(pkg, valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("DetectPackageAtomicAccess failed: %v", err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 issue, got %d", len(findings))
+	}
+	if !strings.Contains(findings[0].Message(), "'x.n' is read without sync/atomic") {
+		t.Errorf("Unexpected finding: %s", findings[0].Message())
+	}
+}
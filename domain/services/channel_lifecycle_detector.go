@@ -0,0 +1,1112 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// ChannelLifecycleIssue represents different kinds of channel lifecycle violations
+type ChannelLifecycleIssue int
+
+const (
+	ChannelIssueDoubleClose ChannelLifecycleIssue = iota
+	ChannelIssueSendAfterClose
+	ChannelIssueCloseByReceiver
+	ChannelIssueDirectionViolation
+)
+
+// String returns a string representation of the channel lifecycle issue
+func (cli ChannelLifecycleIssue) String() string {
+	switch cli {
+	case ChannelIssueDoubleClose:
+		return "double_close"
+	case ChannelIssueSendAfterClose:
+		return "send_after_close"
+	case ChannelIssueCloseByReceiver:
+		return "close_by_receiver"
+	case ChannelIssueDirectionViolation:
+		return "direction_violation"
+	default:
+		return "unknown"
+	}
+}
+
+// ChannelLifecycleDetector detects misuse of channel close and direction semantics
+type ChannelLifecycleDetector interface {
+	DetectChannelIssues(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageChannelIssues(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTChannelLifecycleDetector implements ChannelLifecycleDetector using AST analysis
+type ASTChannelLifecycleDetector struct{}
+
+// NewASTChannelLifecycleDetector creates a new AST-based channel lifecycle detector
+func NewASTChannelLifecycleDetector() *ASTChannelLifecycleDetector {
+	return &ASTChannelLifecycleDetector{}
+}
+
+// channelOp represents an operation performed on a channel
+type channelOp int
+
+const (
+	channelOpClose channelOp = iota
+	channelOpSend
+	channelOpReceive
+)
+
+// pathStep locates a statement inside one level of nested statement lists.
+// owner is the statement (or function literal) that introduced the list and
+// arm distinguishes mutually exclusive lists of the same owner (if/else, cases).
+type pathStep struct {
+	owner ast.Node
+	arm   int
+	list  []ast.Stmt
+	index int
+}
+
+// channelEvent records a single channel operation with the path leading to it
+type channelEvent struct {
+	op          channelOp
+	channel     string
+	display     string
+	pos         token.Pos
+	origin      token.Pos
+	path        []pathStep
+	scope       int
+	deferred    bool
+	conditional bool
+	viaCall     string
+}
+
+// channelFlow holds the state of the lifecycle analysis of a single function
+type channelFlow struct {
+	pkg        *PackageContext
+	aliases    map[string]string
+	versions   map[string]int
+	directions map[string]ast.ChanDir
+	holders    map[string]ast.ChanDir
+	origins    map[string]token.Pos
+	events     []*channelEvent
+	violations []channelViolation
+	scopes     int
+}
+
+// channelViolation records a direction violation hidden from the compiler
+type channelViolation struct {
+	pos         token.Pos
+	channel     string
+	description string
+}
+
+// DetectChannelIssues analyzes code for channel lifecycle bugs
+func (cld *ASTChannelLifecycleDetector) DetectChannelIssues(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	return cld.detectChannelIssues(node, newNodePackageContext(node, fset)), nil
+}
+
+// DetectPackageChannelIssues analyzes every file of a package, following channels into callees
+func (cld *ASTChannelLifecycleDetector) DetectPackageChannelIssues(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var findings []entities.AnalysisFinding
+
+	for _, file := range pkg.Files() {
+		findings = append(findings, cld.detectChannelIssues(file, pkg)...)
+	}
+
+	return findings, nil
+}
+
+// detectChannelIssues analyzes each function declaration under node
+func (cld *ASTChannelLifecycleDetector) detectChannelIssues(node ast.Node, pkg *PackageContext) []entities.AnalysisFinding {
+	var findings []entities.AnalysisFinding
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if funcDecl, ok := n.(*ast.FuncDecl); ok && funcDecl.Body != nil {
+			findings = append(findings, cld.analyzeFunction(funcDecl, pkg)...)
+			return false
+		}
+		return true
+	})
+
+	return findings
+}
+
+// analyzeFunction tracks the channels of a function and reports lifecycle violations
+func (cld *ASTChannelLifecycleDetector) analyzeFunction(funcDecl *ast.FuncDecl, pkg *PackageContext) []entities.AnalysisFinding {
+	flow := &channelFlow{
+		pkg:        pkg,
+		aliases:    make(map[string]string),
+		versions:   make(map[string]int),
+		directions: make(map[string]ast.ChanDir),
+		holders:    make(map[string]ast.ChanDir),
+		origins:    make(map[string]token.Pos),
+	}
+
+	flow.declareFields(funcDecl.Type.Params)
+	flow.declareFields(funcDecl.Type.Results)
+	flow.walkStmts(funcDecl.Body.List, nil, funcDecl.Body, 0, 0, false, false)
+
+	var findings []entities.AnalysisFinding
+	findings = append(findings, cld.reportDoubleCloses(flow, funcDecl)...)
+	findings = append(findings, cld.reportSendsAfterClose(flow, funcDecl)...)
+	findings = append(findings, cld.reportClosesByReceiver(flow, funcDecl)...)
+	findings = append(findings, cld.reportDirectionViolations(flow, funcDecl)...)
+
+	return findings
+}
+
+// reportDoubleCloses reports channels closed more than once on a reachable path
+func (cld *ASTChannelLifecycleDetector) reportDoubleCloses(flow *channelFlow, funcDecl *ast.FuncDecl) []entities.AnalysisFinding {
+	var findings []entities.AnalysisFinding
+	closes := flow.eventsOf(channelOpClose)
+
+	for i, second := range closes {
+		if second.conditional {
+			continue
+		}
+
+		if flow.repeatsInLoop(second) {
+			findings = append(findings, cld.newFinding(flow, funcDecl, ChannelIssueDoubleClose, second,
+				fmt.Sprintf("channel '%s' is closed inside a loop and will be closed again on the next iteration", second.display),
+				valueobjects.SeverityCritical))
+			continue
+		}
+
+		for _, first := range closes[:i] {
+			if first.conditional || first.channel != second.channel {
+				continue
+			}
+			if flow.reachableAfter(first, second) {
+				findings = append(findings, cld.newFinding(flow, funcDecl, ChannelIssueDoubleClose, second,
+					fmt.Sprintf("channel '%s' may be closed twice (first close at line %d)%s",
+						second.display, flow.pkg.FileSet().Position(first.pos).Line, viaCallSuffix(second)),
+					valueobjects.SeverityCritical))
+				break
+			}
+		}
+	}
+
+	return findings
+}
+
+// reportSendsAfterClose reports sends that can execute after the channel was closed
+func (cld *ASTChannelLifecycleDetector) reportSendsAfterClose(flow *channelFlow, funcDecl *ast.FuncDecl) []entities.AnalysisFinding {
+	var findings []entities.AnalysisFinding
+	closes := flow.eventsOf(channelOpClose)
+
+	for _, send := range flow.eventsOf(channelOpSend) {
+		if send.conditional {
+			continue
+		}
+		for _, closeEvent := range closes {
+			// Deferred closes run last and closes in other goroutines are unordered
+			if closeEvent.conditional || closeEvent.deferred || closeEvent.channel != send.channel {
+				continue
+			}
+			if closeEvent.scope != 0 && closeEvent.scope != send.scope {
+				continue
+			}
+			if closeEvent.pos < send.pos && flow.reachableAfter(closeEvent, send) {
+				findings = append(findings, cld.newFinding(flow, funcDecl, ChannelIssueSendAfterClose, send,
+					fmt.Sprintf("send on channel '%s' is reachable after it was closed at line %d%s",
+						send.display, flow.pkg.FileSet().Position(closeEvent.pos).Line, viaCallSuffix(send)),
+					valueobjects.SeverityCritical))
+				break
+			}
+		}
+	}
+
+	return findings
+}
+
+// reportClosesByReceiver reports channels closed by a goroutine that only receives from them
+func (cld *ASTChannelLifecycleDetector) reportClosesByReceiver(flow *channelFlow, funcDecl *ast.FuncDecl) []entities.AnalysisFinding {
+	var findings []entities.AnalysisFinding
+	reported := make(map[string]bool)
+
+	for _, closeEvent := range flow.eventsOf(channelOpClose) {
+		// The function body may close after draining its workers; only spawned receivers are suspect
+		key := fmt.Sprintf("%s/%d", closeEvent.channel, closeEvent.scope)
+		if closeEvent.scope == 0 || reported[key] {
+			continue
+		}
+
+		receivesHere, sendsHere, sendsElsewhere := false, false, false
+		for _, event := range flow.events {
+			if event.channel != closeEvent.channel {
+				continue
+			}
+			switch {
+			case event.op == channelOpReceive && event.scope == closeEvent.scope:
+				receivesHere = true
+			case event.op == channelOpSend && event.scope == closeEvent.scope:
+				sendsHere = true
+			case event.op == channelOpSend:
+				sendsElsewhere = true
+			}
+		}
+
+		if receivesHere && !sendsHere && sendsElsewhere {
+			reported[key] = true
+			findings = append(findings, cld.newFinding(flow, funcDecl, ChannelIssueCloseByReceiver, closeEvent,
+				fmt.Sprintf("channel '%s' is closed by its receiver while another goroutine sends on it; only the sender should close a channel", closeEvent.display),
+				valueobjects.SeverityError))
+		}
+	}
+
+	return findings
+}
+
+// reportDirectionViolations reports direction violations hidden behind conversions
+func (cld *ASTChannelLifecycleDetector) reportDirectionViolations(flow *channelFlow, funcDecl *ast.FuncDecl) []entities.AnalysisFinding {
+	var findings []entities.AnalysisFinding
+
+	for _, violation := range flow.violations {
+		event := &channelEvent{channel: violation.channel, display: violation.channel, pos: violation.pos}
+		findings = append(findings, cld.newFinding(flow, funcDecl, ChannelIssueDirectionViolation, event,
+			fmt.Sprintf("channel '%s' %s", violation.channel, violation.description),
+			valueobjects.SeverityError))
+	}
+
+	return findings
+}
+
+// newFinding creates a bug finding located at the offending channel operation
+func (cld *ASTChannelLifecycleDetector) newFinding(flow *channelFlow, funcDecl *ast.FuncDecl, issue ChannelLifecycleIssue, event *channelEvent, description string, severity valueobjects.SeverityLevel) entities.AnalysisFinding {
+	pos := flow.pkg.FileSet().Position(event.pos)
+	location, _ := valueobjects.NewSourceLocation(pos.Filename, pos.Line, pos.Column)
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue.String(), funcDecl.Name.Name, pos.Line, pos.Column),
+		entities.FindingTypeBug,
+		location,
+		fmt.Sprintf("Channel lifecycle bug in %s: %s detected - %s", funcDecl.Name.Name, issue.String(), description),
+		severity,
+	)
+	finding.AddMetadata("channel", event.display)
+	if event.viaCall != "" {
+		finding.AddMetadata("callee", event.viaCall)
+	}
+
+	return finding
+}
+
+// viaCallSuffix describes the callee through which an operation happens
+func viaCallSuffix(event *channelEvent) string {
+	if event.viaCall == "" {
+		return ""
+	}
+	return fmt.Sprintf(" via call to %s", event.viaCall)
+}
+
+// eventsOf returns the recorded events of one operation kind in source order
+func (flow *channelFlow) eventsOf(op channelOp) []*channelEvent {
+	var events []*channelEvent
+	for _, event := range flow.events {
+		if event.op == op {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// declareFields records the directions of channel-typed parameters and results
+func (flow *channelFlow) declareFields(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		chanType, ok := field.Type.(*ast.ChanType)
+		if !ok {
+			continue
+		}
+		for _, name := range field.Names {
+			flow.directions[name.Name] = chanType.Dir
+		}
+	}
+}
+
+// walkStmts walks a statement list that forms one level of the path
+func (flow *channelFlow) walkStmts(list []ast.Stmt, path []pathStep, owner ast.Node, arm int, scope int, deferred, conditional bool) {
+	for i, stmt := range list {
+		stepPath := append(append([]pathStep(nil), path...), pathStep{owner: owner, arm: arm, list: list, index: i})
+		flow.walkStmt(stmt, stepPath, scope, deferred, conditional)
+	}
+}
+
+// walkStmt records the channel events of a single statement
+func (flow *channelFlow) walkStmt(stmt ast.Stmt, path []pathStep, scope int, deferred, conditional bool) {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		flow.walkStmts(s.List, path, s, 0, scope, deferred, conditional)
+	case *ast.LabeledStmt:
+		flow.walkStmt(s.Stmt, path, scope, deferred, conditional)
+	case *ast.IfStmt:
+		if s.Init != nil {
+			flow.walkStmt(s.Init, path, scope, deferred, conditional)
+		}
+		flow.walkExpr(s.Cond, path, scope, deferred, conditional)
+		flow.walkStmts(s.Body.List, path, s, 0, scope, deferred, conditional)
+		switch elseStmt := s.Else.(type) {
+		case *ast.BlockStmt:
+			flow.walkStmts(elseStmt.List, path, s, 1, scope, deferred, conditional)
+		case *ast.IfStmt:
+			flow.walkStmts([]ast.Stmt{elseStmt}, path, s, 1, scope, deferred, conditional)
+		}
+	case *ast.ForStmt:
+		if s.Init != nil {
+			flow.walkStmt(s.Init, path, scope, deferred, conditional)
+		}
+		flow.walkExpr(s.Cond, path, scope, deferred, conditional)
+		body := s.Body.List
+		if s.Post != nil {
+			body = append(append([]ast.Stmt(nil), body...), s.Post)
+		}
+		flow.walkStmts(body, path, s, 0, scope, deferred, conditional)
+	case *ast.RangeStmt:
+		flow.walkExpr(s.X, path, scope, deferred, conditional)
+		for _, target := range []ast.Expr{s.Key, s.Value} {
+			if ident := identOf(target); ident != nil {
+				flow.origins[ident.Name] = ident.Pos()
+			}
+		}
+		if flow.channelName(s.X) != "" && flow.isChannelExpr(s.X) {
+			flow.record(channelOpReceive, s.X, s.X.Pos(), path, scope, deferred, conditional, "")
+		}
+		flow.walkStmts(s.Body.List, path, s, 0, scope, deferred, conditional)
+	case *ast.SwitchStmt:
+		if s.Init != nil {
+			flow.walkStmt(s.Init, path, scope, deferred, conditional)
+		}
+		flow.walkExpr(s.Tag, path, scope, deferred, conditional)
+		for i, clause := range s.Body.List {
+			if caseClause, ok := clause.(*ast.CaseClause); ok {
+				for _, expr := range caseClause.List {
+					flow.walkExpr(expr, path, scope, deferred, conditional)
+				}
+				flow.walkStmts(caseClause.Body, path, s, i, scope, deferred, conditional)
+			}
+		}
+	case *ast.TypeSwitchStmt:
+		if s.Init != nil {
+			flow.walkStmt(s.Init, path, scope, deferred, conditional)
+		}
+		flow.walkStmt(s.Assign, path, scope, deferred, conditional)
+		for i, clause := range s.Body.List {
+			if caseClause, ok := clause.(*ast.CaseClause); ok {
+				flow.walkStmts(caseClause.Body, path, s, i, scope, deferred, conditional)
+			}
+		}
+	case *ast.SelectStmt:
+		for i, clause := range s.Body.List {
+			if commClause, ok := clause.(*ast.CommClause); ok {
+				body := commClause.Body
+				if commClause.Comm != nil {
+					body = append([]ast.Stmt{commClause.Comm}, body...)
+				}
+				flow.walkStmts(body, path, s, i, scope, deferred, conditional)
+			}
+		}
+	case *ast.GoStmt:
+		flow.walkCall(s.Call, path, scope, deferred, conditional, true)
+	case *ast.DeferStmt:
+		flow.walkCall(s.Call, path, scope, true, conditional, false)
+	case *ast.SendStmt:
+		flow.walkExpr(s.Value, path, scope, deferred, conditional)
+		flow.record(channelOpSend, s.Chan, s.Arrow, path, scope, deferred, conditional, "")
+	case *ast.AssignStmt:
+		for _, rhs := range s.Rhs {
+			flow.walkExpr(rhs, path, scope, deferred, conditional)
+		}
+		flow.trackAssignment(s.Lhs, s.Rhs, nil)
+	case *ast.DeclStmt:
+		if genDecl, ok := s.Decl.(*ast.GenDecl); ok {
+			for _, spec := range genDecl.Specs {
+				if valueSpec, ok := spec.(*ast.ValueSpec); ok {
+					for _, value := range valueSpec.Values {
+						flow.walkExpr(value, path, scope, deferred, conditional)
+					}
+					lhs := make([]ast.Expr, len(valueSpec.Names))
+					for i, name := range valueSpec.Names {
+						lhs[i] = name
+					}
+					flow.trackAssignment(lhs, valueSpec.Values, valueSpec.Type)
+				}
+			}
+		}
+	case *ast.ExprStmt:
+		flow.walkExpr(s.X, path, scope, deferred, conditional)
+	case *ast.ReturnStmt:
+		for _, result := range s.Results {
+			flow.walkExpr(result, path, scope, deferred, conditional)
+		}
+	case *ast.IncDecStmt:
+		flow.walkExpr(s.X, path, scope, deferred, conditional)
+	}
+}
+
+// walkCall handles go and defer statements, which start a new scope or postpone execution
+func (flow *channelFlow) walkCall(call *ast.CallExpr, path []pathStep, scope int, deferred, conditional, spawn bool) {
+	for _, arg := range call.Args {
+		flow.walkExpr(arg, path, scope, deferred, conditional)
+	}
+
+	targetScope := scope
+	if spawn {
+		flow.scopes++
+		targetScope = flow.scopes
+	}
+
+	if funcLit, ok := call.Fun.(*ast.FuncLit); ok {
+		restore := flow.bindParams(funcLit.Type, call.Args)
+		flow.walkStmts(funcLit.Body.List, path, funcLit, 0, targetScope, deferred, conditional)
+		restore()
+		return
+	}
+
+	if flow.isBuiltinClose(call) {
+		flow.record(channelOpClose, call.Args[0], call.Pos(), path, targetScope, deferred, conditional, "")
+		return
+	}
+
+	flow.walkExpr(call.Fun, path, targetScope, deferred, conditional)
+	flow.summarizeCall(call, path, targetScope, deferred, conditional)
+}
+
+// walkExpr records the channel events of an expression
+func (flow *channelFlow) walkExpr(expr ast.Expr, path []pathStep, scope int, deferred, conditional bool) {
+	if expr == nil {
+		return
+	}
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.FuncLit:
+			// A literal that is neither spawned nor deferred may run any number of times
+			flow.walkStmts(e.Body.List, path, e, 0, scope, deferred, true)
+			return false
+		case *ast.UnaryExpr:
+			if e.Op == token.ARROW {
+				flow.record(channelOpReceive, e.X, e.OpPos, path, scope, deferred, conditional, "")
+			}
+		case *ast.CallExpr:
+			if flow.isBuiltinClose(e) {
+				flow.record(channelOpClose, e.Args[0], e.Pos(), path, scope, deferred, conditional, "")
+				return true
+			}
+			flow.checkConversion(e)
+			flow.summarizeCall(e, path, scope, deferred, conditional)
+		case *ast.TypeAssertExpr:
+			flow.checkTypeAssertion(e)
+		}
+		return true
+	})
+}
+
+// record appends a channel event for the channel denoted by expr
+func (flow *channelFlow) record(op channelOp, expr ast.Expr, pos token.Pos, path []pathStep, scope int, deferred, conditional bool, viaCall string) {
+	name := flow.channelName(expr)
+	if name == "" {
+		return
+	}
+
+	channel := flow.canonical(name)
+	origin, ok := flow.origins[channel]
+	if !ok {
+		origin = flow.origins[strings.SplitN(channel, ".", 2)[0]]
+	}
+
+	flow.events = append(flow.events, &channelEvent{
+		op:          op,
+		channel:     channel,
+		display:     name,
+		pos:         pos,
+		origin:      origin,
+		path:        path,
+		scope:       scope,
+		deferred:    deferred,
+		conditional: conditional,
+		viaCall:     viaCall,
+	})
+}
+
+// summarizeCall records the channel operations a resolved callee performs on channel arguments
+func (flow *channelFlow) summarizeCall(call *ast.CallExpr, path []pathStep, scope int, deferred, conditional bool) {
+	funcDecl := flow.pkg.ResolveCall(call)
+	if funcDecl == nil || funcDecl.Body == nil {
+		return
+	}
+
+	params := flattenParams(funcDecl.Type.Params)
+	for i, arg := range call.Args {
+		if i >= len(params) || params[i] == nil || flow.channelName(arg) == "" || !flow.isChannelExpr(arg) {
+			continue
+		}
+		param := params[i].Name
+
+		for _, op := range calleeChannelOps(funcDecl.Body, param) {
+			flow.record(op, arg, call.Pos(), path, scope, deferred, conditional, funcDecl.Name.Name)
+		}
+	}
+}
+
+// calleeChannelOps lists the operations a function body performs on a parameter, once per kind
+func calleeChannelOps(body *ast.BlockStmt, param string) []channelOp {
+	seen := make(map[channelOp]bool)
+	var ops []channelOp
+	add := func(op channelOp) {
+		if !seen[op] {
+			seen[op] = true
+			ops = append(ops, op)
+		}
+	}
+
+	isParam := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && ident.Name == param
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.CallExpr:
+			if ident, ok := e.Fun.(*ast.Ident); ok && ident.Name == "close" && len(e.Args) == 1 && isParam(e.Args[0]) {
+				add(channelOpClose)
+			}
+		case *ast.SendStmt:
+			if isParam(e.Chan) {
+				add(channelOpSend)
+			}
+		case *ast.UnaryExpr:
+			if e.Op == token.ARROW && isParam(e.X) {
+				add(channelOpReceive)
+			}
+		case *ast.RangeStmt:
+			if isParam(e.X) {
+				add(channelOpReceive)
+			}
+		}
+		return true
+	})
+
+	return ops
+}
+
+// flattenParams returns one entry per positional parameter; unnamed parameters are nil
+func flattenParams(fields *ast.FieldList) []*ast.Ident {
+	var params []*ast.Ident
+	if fields == nil {
+		return params
+	}
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			params = append(params, nil)
+			continue
+		}
+		params = append(params, field.Names...)
+	}
+	return params
+}
+
+// bindParams aliases the parameters of a spawned literal to the channels passed in
+func (flow *channelFlow) bindParams(funcType *ast.FuncType, args []ast.Expr) func() {
+	savedAliases := make(map[string]string)
+	savedDirections := make(map[string]ast.ChanDir)
+	params := flattenParams(funcType.Params)
+
+	for _, param := range params {
+		if param == nil {
+			continue
+		}
+		if previous, ok := flow.aliases[param.Name]; ok {
+			savedAliases[param.Name] = previous
+		}
+		if previous, ok := flow.directions[param.Name]; ok {
+			savedDirections[param.Name] = previous
+		}
+	}
+
+	for i, param := range params {
+		if param == nil || i >= len(args) {
+			continue
+		}
+		if name := flow.channelName(args[i]); name != "" {
+			flow.aliases[param.Name] = flow.canonical(name)
+		}
+	}
+	flow.declareFields(funcType.Params)
+
+	return func() {
+		for _, param := range params {
+			if param == nil {
+				continue
+			}
+			delete(flow.aliases, param.Name)
+			delete(flow.directions, param.Name)
+			if previous, ok := savedAliases[param.Name]; ok {
+				flow.aliases[param.Name] = previous
+			}
+			if previous, ok := savedDirections[param.Name]; ok {
+				flow.directions[param.Name] = previous
+			}
+		}
+	}
+}
+
+// trackAssignment follows channel values through assignments and records directions
+func (flow *channelFlow) trackAssignment(lhs, rhs []ast.Expr, declaredType ast.Expr) {
+	if chanType, ok := declaredType.(*ast.ChanType); ok {
+		for _, target := range lhs {
+			if name := flow.channelName(target); name != "" {
+				flow.directions[name] = chanType.Dir
+			}
+		}
+	}
+
+	if len(lhs) != len(rhs) {
+		for _, target := range lhs {
+			if name := flow.channelName(target); name != "" {
+				flow.origins[name] = target.Pos()
+			}
+		}
+		return
+	}
+
+	for i, target := range lhs {
+		name := flow.channelName(target)
+		if name == "" || name == "_" {
+			continue
+		}
+		value := ast.Unparen(rhs[i])
+		flow.origins[name] = target.Pos()
+
+		if flow.isMakeChan(value) {
+			// A fresh channel value: later operations refer to a new version of the name
+			flow.versions[name]++
+			flow.aliases[name] = fmt.Sprintf("%s#%d", name, flow.versions[name])
+			flow.origins[flow.aliases[name]] = value.Pos()
+			if chanType, ok := value.(*ast.CallExpr).Args[0].(*ast.ChanType); ok {
+				flow.directions[name] = chanType.Dir
+			}
+			continue
+		}
+
+		if source := flow.channelName(value); source != "" && flow.isChannelExpr(value) {
+			flow.aliases[name] = flow.canonical(source)
+			if dir, ok := flow.directionOf(value); ok {
+				flow.directions[name] = dir
+			}
+			if flow.isInterfaceTarget(target, declaredType) {
+				if dir, ok := flow.directionOf(value); ok && dir != ast.SEND|ast.RECV {
+					flow.holders[name] = dir
+				}
+			}
+			continue
+		}
+
+		// Channel stored in an interface through an explicit conversion: any(ch), interface{}(ch)
+		if call, ok := value.(*ast.CallExpr); ok && len(call.Args) == 1 && isInterfaceTypeExpr(call.Fun) {
+			if dir, ok := flow.directionOf(call.Args[0]); ok && dir != ast.SEND|ast.RECV {
+				flow.holders[name] = dir
+			}
+		}
+	}
+}
+
+// checkTypeAssertion reports assertions recovering a channel with a different direction than stored
+func (flow *channelFlow) checkTypeAssertion(assert *ast.TypeAssertExpr) {
+	chanType, ok := assert.Type.(*ast.ChanType)
+	if !ok {
+		return
+	}
+	name := flow.channelName(assert.X)
+	stored, ok := flow.holders[name]
+	if !ok || stored == chanType.Dir {
+		return
+	}
+
+	flow.violations = append(flow.violations, channelViolation{
+		pos:     assert.Pos(),
+		channel: name,
+		description: fmt.Sprintf("holds a %s channel but is asserted to %s; the assertion always fails at runtime",
+			directionName(stored), directionName(chanType.Dir)),
+	})
+}
+
+// checkConversion reports unsafe and reflect based circumventions of channel direction
+func (flow *channelFlow) checkConversion(call *ast.CallExpr) {
+	// (*chan T)(unsafe.Pointer(&roCh))
+	if chanType := pointerToChanType(call.Fun); chanType != nil && len(call.Args) == 1 {
+		if inner, ok := ast.Unparen(call.Args[0]).(*ast.CallExpr); ok && isSelectorCall(inner, "unsafe", "Pointer") && len(inner.Args) == 1 {
+			if addr, ok := ast.Unparen(inner.Args[0]).(*ast.UnaryExpr); ok && addr.Op == token.AND {
+				if dir, ok := flow.directionOf(addr.X); ok && dir != chanType.Dir && dir != ast.SEND|ast.RECV {
+					flow.violations = append(flow.violations, channelViolation{
+						pos:     call.Pos(),
+						channel: flow.channelName(addr.X),
+						description: fmt.Sprintf("is a %s channel converted through unsafe.Pointer to %s, bypassing the compiler's direction check",
+							directionName(dir), directionName(chanType.Dir)),
+					})
+				}
+			}
+		}
+	}
+
+	// reflect.ValueOf(roCh).Send(...) and friends panic at runtime
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	valueOf, ok := ast.Unparen(selector.X).(*ast.CallExpr)
+	if !ok || !isSelectorCall(valueOf, "reflect", "ValueOf") || len(valueOf.Args) != 1 {
+		return
+	}
+	dir, ok := flow.directionOf(valueOf.Args[0])
+	if !ok {
+		return
+	}
+
+	var required ast.ChanDir
+	switch selector.Sel.Name {
+	case "Send", "TrySend", "Close":
+		required = ast.SEND
+	case "Recv", "TryRecv":
+		required = ast.RECV
+	default:
+		return
+	}
+	if dir&required == 0 {
+		flow.violations = append(flow.violations, channelViolation{
+			pos:     call.Pos(),
+			channel: flow.channelName(valueOf.Args[0]),
+			description: fmt.Sprintf("is a %s channel used with reflect.Value.%s, which panics at runtime",
+				directionName(dir), selector.Sel.Name),
+		})
+	}
+}
+
+// directionOf returns the direction of a channel expression from types or declarations
+func (flow *channelFlow) directionOf(expr ast.Expr) (ast.ChanDir, bool) {
+	if info := flow.pkg.TypesInfo(); info != nil {
+		if typ := info.TypeOf(expr); typ != nil {
+			if ch, ok := typ.Underlying().(*types.Chan); ok {
+				switch ch.Dir() {
+				case types.SendOnly:
+					return ast.SEND, true
+				case types.RecvOnly:
+					return ast.RECV, true
+				default:
+					return ast.SEND | ast.RECV, true
+				}
+			}
+		}
+	}
+
+	name := flow.channelName(expr)
+	dir, ok := flow.directions[name]
+	return dir, ok
+}
+
+// isChannelExpr reports whether expr is known, or plausibly, a channel
+func (flow *channelFlow) isChannelExpr(expr ast.Expr) bool {
+	if info := flow.pkg.TypesInfo(); info != nil {
+		if typ := info.TypeOf(expr); typ != nil {
+			_, isChan := typ.Underlying().(*types.Chan)
+			return isChan
+		}
+	}
+
+	name := flow.channelName(expr)
+	if _, ok := flow.aliases[name]; ok {
+		return true
+	}
+	_, ok := flow.directions[name]
+	return ok
+}
+
+// isInterfaceTarget reports whether an assignment target has interface type
+func (flow *channelFlow) isInterfaceTarget(target ast.Expr, declaredType ast.Expr) bool {
+	if declaredType != nil {
+		return isInterfaceTypeExpr(declaredType)
+	}
+	if ident := identOf(target); ident != nil && flow.pkg.TypesInfo() != nil {
+		if obj := flow.pkg.TypesInfo().ObjectOf(ident); obj != nil {
+			return types.IsInterface(obj.Type())
+		}
+	}
+	return false
+}
+
+// isMakeChan reports whether expr is make(chan T[, size])
+func (flow *channelFlow) isMakeChan(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || ident.Name != "make" {
+		return false
+	}
+	_, isChan := call.Args[0].(*ast.ChanType)
+	return isChan
+}
+
+// isBuiltinClose reports whether call is the builtin close(ch)
+func (flow *channelFlow) isBuiltinClose(call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || ident.Name != "close" || len(call.Args) != 1 {
+		return false
+	}
+	if info := flow.pkg.TypesInfo(); info != nil {
+		if obj, ok := info.Uses[ident]; ok {
+			_, isBuiltin := obj.(*types.Builtin)
+			return isBuiltin
+		}
+	}
+	return true
+}
+
+// canonical resolves a channel name through aliases to the value it refers to
+func (flow *channelFlow) canonical(name string) string {
+	seen := make(map[string]bool)
+	for {
+		target, ok := flow.aliases[name]
+		if !ok || target == name || seen[name] {
+			return name
+		}
+		seen[name] = true
+		name = target
+	}
+}
+
+// channelName returns the textual name of an identifier or field selector chain
+func (flow *channelFlow) channelName(expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		if x := flow.channelName(e.X); x != "" {
+			return x + "." + e.Sel.Name
+		}
+	case *ast.StarExpr:
+		return flow.channelName(e.X)
+	}
+	return ""
+}
+
+// reachableAfter reports whether b can execute after a on some path through the function
+func (flow *channelFlow) reachableAfter(a, b *channelEvent) bool {
+	depth := len(a.path)
+	if len(b.path) < depth {
+		depth = len(b.path)
+	}
+
+	for k := 0; k < depth; k++ {
+		stepA, stepB := a.path[k], b.path[k]
+		if stepA.owner != stepB.owner || stepA.arm != stepB.arm {
+			// Different arms of the same branch never both execute
+			return false
+		}
+		if stepA.index != stepB.index {
+			if stepA.index > stepB.index {
+				return false
+			}
+			return !flow.terminatesAfter(a, k, stepB.index)
+		}
+	}
+
+	return true
+}
+
+// terminatesAfter reports whether every path from a leaves the function, or the
+// current loop iteration, before reaching index limit of the statement list at level k
+func (flow *channelFlow) terminatesAfter(a *channelEvent, k int, limit int) bool {
+	// Both events share the loop bodies above level k, so break and continue skip b
+	loopLevel := -1
+	for j := k; j >= 0 && loopLevel < 0; j-- {
+		switch a.path[j].owner.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			loopLevel = j
+		case *ast.FuncLit:
+			j = -1
+		}
+	}
+
+	for j := k; j < len(a.path); j++ {
+		// A return inside a function literal does not leave the enclosing function
+		if _, isLit := a.path[j].owner.(*ast.FuncLit); isLit && j > k {
+			break
+		}
+
+		end := len(a.path[j].list)
+		if j == k {
+			end = limit
+		}
+		for _, stmt := range a.path[j].list[a.path[j].index+1 : end] {
+			if isTerminatingStmt(stmt) {
+				return true
+			}
+			if loopLevel >= 0 && flow.leavesIteration(stmt, a.path[loopLevel+1:j+1]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// leavesIteration reports whether stmt is an unlabeled break or continue that applies
+// to the loop enclosing the given path levels
+func (flow *channelFlow) leavesIteration(stmt ast.Stmt, levels []pathStep) bool {
+	branch, ok := stmt.(*ast.BranchStmt)
+	if !ok || branch.Label != nil || (branch.Tok != token.BREAK && branch.Tok != token.CONTINUE) {
+		return false
+	}
+
+	for _, level := range levels {
+		switch level.owner.(type) {
+		case *ast.ForStmt, *ast.RangeStmt, *ast.FuncLit:
+			return false
+		case *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+			if branch.Tok == token.BREAK {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// repeatsInLoop reports whether an event inside a loop body unconditionally
+// executes again on the next iteration for the same channel value
+func (flow *channelFlow) repeatsInLoop(event *channelEvent) bool {
+	loopLevel := -1
+	for j := len(event.path) - 1; j >= 0 && loopLevel < 0; j-- {
+		switch event.path[j].owner.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			loopLevel = j
+		}
+	}
+	if loopLevel < 0 {
+		return false
+	}
+
+	// A channel created or reassigned inside the loop is a different value on every iteration
+	loop := event.path[loopLevel].owner
+	if event.origin.IsValid() && event.origin >= loop.Pos() && event.origin < loop.End() {
+		return false
+	}
+
+	for j := loopLevel; j < len(event.path); j++ {
+		switch event.path[j].owner.(type) {
+		case *ast.IfStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+			// Closes guarded by a condition are usually guarded by a closed flag
+			return false
+		}
+	}
+
+	for j := loopLevel; j < len(event.path); j++ {
+		if _, isLit := event.path[j].owner.(*ast.FuncLit); isLit {
+			// Statements in a spawned or deferred literal cannot leave the loop
+			return true
+		}
+		for _, stmt := range event.path[j].list[event.path[j].index+1:] {
+			if isTerminatingStmt(stmt) {
+				return false
+			}
+			if branch, ok := stmt.(*ast.BranchStmt); ok && branch.Tok == token.BREAK && branch.Label == nil {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// isTerminatingStmt reports whether stmt always leaves the enclosing function
+func isTerminatingStmt(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return s.Tok == token.GOTO
+	case *ast.BlockStmt:
+		return len(s.List) > 0 && isTerminatingStmt(s.List[len(s.List)-1])
+	case *ast.LabeledStmt:
+		return isTerminatingStmt(s.Stmt)
+	case *ast.IfStmt:
+		return s.Else != nil && isTerminatingStmt(s.Body) && isTerminatingStmt(s.Else)
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		if ident, ok := call.Fun.(*ast.Ident); ok {
+			return ident.Name == "panic"
+		}
+		if selector, ok := call.Fun.(*ast.SelectorExpr); ok {
+			if pkg, ok := selector.X.(*ast.Ident); ok {
+				switch pkg.Name + "." + selector.Sel.Name {
+				case "os.Exit", "log.Fatal", "log.Fatalf", "log.Fatalln", "log.Panic", "log.Panicf", "log.Panicln", "runtime.Goexit":
+					return true
+				}
+			}
+			// testing.TB methods that stop the test goroutine
+			switch selector.Sel.Name {
+			case "Fatal", "Fatalf", "FailNow", "Skip", "Skipf", "SkipNow":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pointerToChanType returns the channel type of a (*chan T) conversion target
+func pointerToChanType(expr ast.Expr) *ast.ChanType {
+	star, ok := ast.Unparen(expr).(*ast.StarExpr)
+	if !ok {
+		return nil
+	}
+	chanType, _ := ast.Unparen(star.X).(*ast.ChanType)
+	return chanType
+}
+
+// isSelectorCall reports whether call invokes pkg.name
+func isSelectorCall(call *ast.CallExpr, pkg, name string) bool {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != name {
+		return false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	return ok && ident.Name == pkg
+}
+
+// isInterfaceTypeExpr reports whether expr denotes an empty interface type
+func isInterfaceTypeExpr(expr ast.Expr) bool {
+	switch t := ast.Unparen(expr).(type) {
+	case *ast.InterfaceType:
+		return true
+	case *ast.Ident:
+		return t.Name == "any"
+	}
+	return false
+}
+
+// identOf returns the identifier of a plain name expression
+func identOf(expr ast.Expr) *ast.Ident {
+	ident, _ := ast.Unparen(expr).(*ast.Ident)
+	return ident
+}
+
+// directionName returns the Go spelling of a channel direction
+func directionName(dir ast.ChanDir) string {
+	switch dir {
+	case ast.SEND:
+		return "send-only (chan<-)"
+	case ast.RECV:
+		return "receive-only (<-chan)"
+	default:
+		return "bidirectional (chan)"
+	}
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestChannelLifecycleDetector_DetectChannelIssues(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		expectedIssues int
+		expectedTypes  []string
+	}{
+		{
+			name: "Double close on sequential path",
+			code: `
+package main
+func test() {
+	ch := make(chan int)
+	close(ch)
+	close(ch)
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"double_close"},
+		},
+		{
+			name: "Closes in exclusive branches - no issue",
+			code: `
+package main
+func test(ok bool) {
+	ch := make(chan int)
+	if ok {
+		close(ch)
+	} else {
+		close(ch)
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Close followed by return guards later close - no issue",
+			code: `
+package main
+func test(err error) {
+	ch := make(chan int)
+	if err != nil {
+		close(ch)
+		return
+	}
+	close(ch)
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Deferred close plus explicit close through alias",
+			code: `
+package main
+func test() {
+	ch := make(chan int)
+	defer close(ch)
+	out := ch
+	close(out)
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"double_close"},
+		},
+		{
+			name: "Reassigned channel is a new value - no issue",
+			code: `
+package main
+func test() {
+	ch := make(chan int)
+	close(ch)
+	ch = make(chan int)
+	close(ch)
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Close inside loop",
+			code: `
+package main
+func test(items []int) {
+	ch := make(chan int)
+	for range items {
+		close(ch)
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"closed inside a loop"},
+		},
+		{
+			name: "Close in goroutine and in parent",
+			code: `
+package main
+func test() {
+	ch := make(chan int)
+	go func(c chan int) {
+		close(c)
+	}(ch)
+	close(ch)
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"double_close"},
+		},
+		{
+			name: "Close through sync.Once - no issue",
+			code: `
+package main
+import "sync"
+func test() {
+	var once sync.Once
+	ch := make(chan int)
+	once.Do(func() { close(ch) })
+	once.Do(func() { close(ch) })
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Double close through callee parameter",
+			code: `
+package main
+func shutdown(c chan struct{}) {
+	close(c)
+}
+func test() {
+	done := make(chan struct{})
+	shutdown(done)
+	close(done)
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"double_close"},
+		},
+		{
+			name: "Send after close",
+			code: `
+package main
+func test() {
+	ch := make(chan int, 1)
+	close(ch)
+	ch <- 1
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"send_after_close"},
+		},
+		{
+			name: "Close followed by break skips later send - no issue",
+			code: `
+package main
+func test(limit int) {
+	for i := 0; ; i++ {
+		next := make(chan bool, 1)
+		if i >= limit {
+			close(next)
+			break
+		}
+		next <- true
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Send in goroutine started after close",
+			code: `
+package main
+func test() {
+	ch := make(chan int, 1)
+	close(ch)
+	go func() {
+		ch <- 1
+	}()
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"send_after_close"},
+		},
+		{
+			name: "Deferred close after sends - no issue",
+			code: `
+package main
+func test(out chan int) {
+	defer close(out)
+	for i := 0; i < 3; i++ {
+		out <- i
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Receiver closes channel while parent sends",
+			code: `
+package main
+func test() {
+	ch := make(chan int)
+	go func() {
+		for v := range ch {
+			_ = v
+		}
+		close(ch)
+	}()
+	ch <- 1
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"close_by_receiver"},
+		},
+		{
+			name: "Producer closes its own channel - no issue",
+			code: `
+package main
+func test() {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		ch <- 1
+	}()
+	for v := range ch {
+		_ = v
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Direction lost through interface assertion",
+			code: `
+package main
+func test(events <-chan int) {
+	var holder any = events
+	ch := holder.(chan int)
+	_ = ch
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"direction_violation"},
+		},
+		{
+			name: "Send through reflect on receive-only channel",
+			code: `
+package main
+import "reflect"
+func test(events <-chan int) {
+	reflect.ValueOf(events).Send(reflect.ValueOf(1))
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"reflect.Value.Send"},
+		},
+		{
+			name: "Direction bypassed through unsafe.Pointer",
+			code: `
+package main
+import "unsafe"
+func test(events <-chan int) {
+	rw := *(*chan int)(unsafe.Pointer(&events))
+	close(rw)
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"unsafe.Pointer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			detector := NewASTChannelLifecycleDetector()
+			config := valueobjects.DefaultAnalysisConfiguration()
+			findings, err := detector.DetectChannelIssues(node, fset, config)
+			if err != nil {
+				t.Fatalf("DetectChannelIssues failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+		})
+	}
+}
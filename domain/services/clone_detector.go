@@ -0,0 +1,507 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"hash/fnv"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// maxSequenceBucket bounds how many statements sharing one shape are paired when
+// looking for duplicated statement sequences; larger buckets are idioms such as
+// "if err != nil { return err }" that only matter as part of a larger clone
+const maxSequenceBucket = 64
+
+// CloneDetector finds duplicated code across all analyzed packages
+type CloneDetector interface {
+	DetectClones(pkgs []*PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, []valueobjects.PackageDuplication, error)
+}
+
+// ASTCloneDetector implements CloneDetector by hashing normalized AST subtrees. Identifiers
+// and literals are abstracted, so renamed copies (Type-2 clones) hash like exact copies
+// (Type-1 clones); the exact hash then tells the two apart
+type ASTCloneDetector struct{}
+
+// NewASTCloneDetector creates a new AST-based clone detector
+func NewASTCloneDetector() *ASTCloneDetector {
+	return &ASTCloneDetector{}
+}
+
+// cloneToken is one element of the pre-order serialization of a file
+type cloneToken struct {
+	normalized uint64
+	exact      uint64
+	weight     int
+}
+
+// cloneFile is the serialized form of one file
+type cloneFile struct {
+	fset    *token.FileSet
+	pkg     string
+	tokens  []cloneToken
+	weights []int
+	covered []bool
+}
+
+// cloneSpan is the token range and source extent of a serialized node or statement sequence
+type cloneSpan struct {
+	start, end int
+	pos, last  token.Pos
+}
+
+// cloneOccurrence is one copy of a duplicated fragment
+type cloneOccurrence struct {
+	file *cloneFile
+	span cloneSpan
+}
+
+// cloneKey identifies fragments with the same normalized shape
+type cloneKey struct {
+	hash   uint64
+	length int
+}
+
+// cloneGroup collects the occurrences of one normalized fragment
+type cloneGroup struct {
+	key         cloneKey
+	tokens      int
+	occurrences []cloneOccurrence
+}
+
+// statementList is a block body with the span and shape of each statement
+type statementList struct {
+	file  *cloneFile
+	spans []cloneSpan
+	hash  []uint64
+}
+
+// statementRef points at one statement of a statement list
+type statementRef struct {
+	list  *statementList
+	index int
+}
+
+// DetectClones reports duplicated fragments of at least MinCloneTokens tokens and
+// measures the share of duplicated tokens in each package
+func (cd *ASTCloneDetector) DetectClones(pkgs []*PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, []valueobjects.PackageDuplication, error) {
+	minTokens := config.MinCloneTokens()
+	if minTokens < 1 {
+		minTokens = 1
+	}
+
+	groups := make(map[cloneKey]*cloneGroup)
+	var files []*cloneFile
+	var lists []*statementList
+	var packageOrder []string
+	packageNames := make(map[string]string)
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files() {
+			if ast.IsGenerated(file) {
+				continue
+			}
+			dir := filepath.Dir(pkg.FileSet().Position(file.Pos()).Filename)
+			if !containsString(packageOrder, dir) {
+				packageOrder = append(packageOrder, dir)
+				packageNames[dir] = file.Name.Name
+			}
+
+			serialized, candidates, fileLists := serializeCloneFile(file, pkg.FileSet(), dir)
+			files = append(files, serialized)
+			lists = append(lists, fileLists...)
+
+			for _, span := range candidates {
+				addCloneOccurrence(groups, serialized, span, minTokens)
+			}
+		}
+	}
+
+	findStatementSequences(groups, lists, minTokens)
+
+	findings := cd.reportGroups(groups)
+
+	// Measure duplication per package directory, labelled by package name
+	total := make(map[string]int)
+	duplicated := make(map[string]int)
+	for _, file := range files {
+		for i, tok := range file.tokens {
+			total[file.pkg] += tok.weight
+			if file.covered[i] {
+				duplicated[file.pkg] += tok.weight
+			}
+		}
+	}
+
+	var duplication []valueobjects.PackageDuplication
+	for _, dir := range packageOrder {
+		if measured, err := valueobjects.NewPackageDuplication(packageLabel(dir, packageNames), total[dir], duplicated[dir]); err == nil {
+			duplication = append(duplication, measured)
+		}
+	}
+
+	return findings, duplication, nil
+}
+
+// packageLabel names the package in dir by its package name, adding the directory when
+// another measured package has the same name
+func packageLabel(dir string, names map[string]string) string {
+	name := names[dir]
+	for other, otherName := range names {
+		if other != dir && otherName == name {
+			return name + " (" + filepath.ToSlash(dir) + ")"
+		}
+	}
+	return name
+}
+
+// reportGroups turns clone groups into findings, largest first, skipping groups whose
+// occurrences all lie inside already reported clones
+func (cd *ASTCloneDetector) reportGroups(groups map[cloneKey]*cloneGroup) []entities.AnalysisFinding {
+	ordered := make([]*cloneGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Slice(group.occurrences, func(i, j int) bool {
+			return group.occurrences[i].span.pos < group.occurrences[j].span.pos
+		})
+		group.occurrences = distinctOccurrences(group.occurrences)
+		if len(group.occurrences) >= 2 {
+			ordered = append(ordered, group)
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].tokens != ordered[j].tokens {
+			return ordered[i].tokens > ordered[j].tokens
+		}
+		return ordered[i].occurrences[0].span.pos < ordered[j].occurrences[0].span.pos
+	})
+
+	var findings []entities.AnalysisFinding
+	for _, group := range ordered {
+		uncovered := false
+		for _, occurrence := range group.occurrences {
+			if !occurrence.covered() {
+				uncovered = true
+				break
+			}
+		}
+		if !uncovered {
+			continue
+		}
+
+		for _, occurrence := range group.occurrences {
+			for i := occurrence.span.start; i < occurrence.span.end; i++ {
+				occurrence.file.covered[i] = true
+			}
+		}
+		findings = append(findings, cd.createFinding(group))
+	}
+
+	return findings
+}
+
+// createFinding builds the finding for a clone group, listing every occurrence
+func (cd *ASTCloneDetector) createFinding(group *cloneGroup) entities.AnalysisFinding {
+	first := group.occurrences[0]
+	position := first.file.fset.Position(first.span.pos)
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+
+	identical := true
+	exact := first.exactHash()
+	var places []string
+	for _, occurrence := range group.occurrences {
+		start := occurrence.file.fset.Position(occurrence.span.pos)
+		end := occurrence.file.fset.Position(occurrence.span.last)
+		places = append(places, fmt.Sprintf("%s:%d-%d", start.Filename, start.Line, end.Line))
+		if occurrence.exactHash() != exact {
+			identical = false
+		}
+	}
+
+	cloneType, description := "type-2", "Type-2 clone (renamed identifiers or literals)"
+	if identical {
+		cloneType, description = "type-1", "Type-1 clone (identical code)"
+	}
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%016x_%d", SmellTypeDuplicateCode.String(), group.key.hash, group.key.length),
+		entities.FindingTypeSmell,
+		location,
+		fmt.Sprintf("Duplicate code: %d-token %s occurs %d times: %s",
+			group.tokens, description, len(group.occurrences), strings.Join(places, ", ")),
+		valueobjects.SeverityWarning,
+	)
+	finding.AddMetadata("clone_type", cloneType)
+	finding.AddMetadata("tokens", group.tokens)
+	finding.AddMetadata("occurrences", places)
+
+	return finding
+}
+
+// serializeCloneFile flattens a file into tokens in pre-order, with a closing marker after
+// each node so that the token range of every subtree is contiguous and unambiguous
+func serializeCloneFile(file *ast.File, fset *token.FileSet, pkg string) (*cloneFile, []cloneSpan, []*statementList) {
+	serialized := &cloneFile{fset: fset, pkg: pkg}
+	spans := make(map[ast.Node]cloneSpan)
+	var candidates []cloneSpan
+	var stack []ast.Node
+	var starts []int
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			node := stack[len(stack)-1]
+			start := starts[len(starts)-1]
+			stack, starts = stack[:len(stack)-1], starts[:len(starts)-1]
+
+			serialized.tokens = append(serialized.tokens, cloneToken{normalized: 1, exact: 1})
+			span := cloneSpan{start: start, end: len(serialized.tokens), pos: node.Pos(), last: node.End() - 1}
+			if _, ok := node.(ast.Stmt); ok {
+				spans[node] = span
+			}
+			if isCloneCandidate(node) {
+				candidates = append(candidates, span)
+			}
+			return true
+		}
+
+		switch node := n.(type) {
+		case *ast.CommentGroup, *ast.Comment:
+			return false
+		case *ast.GenDecl:
+			if node.Tok == token.IMPORT {
+				return false
+			}
+		}
+
+		normalized, exact, weight := cloneSymbol(n)
+		stack = append(stack, n)
+		starts = append(starts, len(serialized.tokens))
+		serialized.tokens = append(serialized.tokens, cloneToken{
+			normalized: hashSymbol(normalized),
+			exact:      hashSymbol(exact),
+			weight:     weight,
+		})
+		return true
+	})
+
+	serialized.weights = make([]int, len(serialized.tokens)+1)
+	for i, tok := range serialized.tokens {
+		serialized.weights[i+1] = serialized.weights[i] + tok.weight
+	}
+	serialized.covered = make([]bool, len(serialized.tokens))
+
+	// Collect statement lists for sequence matching
+	var lists []*statementList
+	addList := func(stmts []ast.Stmt) {
+		if len(stmts) < 2 {
+			return
+		}
+		list := &statementList{file: serialized}
+		for _, stmt := range stmts {
+			span, ok := spans[stmt]
+			if !ok {
+				return
+			}
+			list.spans = append(list.spans, span)
+			list.hash = append(list.hash, serialized.hash(span.start, span.end, false))
+		}
+		lists = append(lists, list)
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.BlockStmt:
+			addList(node.List)
+		case *ast.CaseClause:
+			addList(node.Body)
+		case *ast.CommClause:
+			addList(node.Body)
+		}
+		return true
+	})
+
+	return serialized, candidates, lists
+}
+
+// cloneSymbol returns the normalized and exact symbols of a node and the number of lexical
+// tokens it stands for; nodes without syntax of their own weigh nothing
+func cloneSymbol(n ast.Node) (string, string, int) {
+	switch node := n.(type) {
+	case *ast.Ident:
+		return "ident", "ident:" + node.Name, 1
+	case *ast.BasicLit:
+		return "literal:" + node.Kind.String(), "literal:" + node.Value, 1
+	case *ast.BinaryExpr:
+		return withSymbol("binary:" + node.Op.String())
+	case *ast.UnaryExpr:
+		return withSymbol("unary:" + node.Op.String())
+	case *ast.AssignStmt:
+		return withSymbol("assign:" + node.Tok.String())
+	case *ast.IncDecStmt:
+		return withSymbol("incdec:" + node.Tok.String())
+	case *ast.BranchStmt:
+		return withSymbol("branch:" + node.Tok.String())
+	case *ast.GenDecl:
+		return withSymbol("decl:" + node.Tok.String())
+	case *ast.RangeStmt:
+		return withSymbol("range:" + node.Tok.String())
+	case *ast.ChanType:
+		return withSymbol(fmt.Sprintf("chan:%d", node.Dir))
+	case *ast.File, *ast.ExprStmt, *ast.DeclStmt, *ast.FieldList, *ast.Field, *ast.ValueSpec,
+		*ast.TypeSpec, *ast.FuncType, *ast.EmptyStmt:
+		symbol := fmt.Sprintf("%T", n)
+		return symbol, symbol, 0
+	default:
+		return withSymbol(fmt.Sprintf("%T", n))
+	}
+}
+
+// withSymbol returns a symbol that is the same in normalized and exact form
+func withSymbol(symbol string) (string, string, int) {
+	return symbol, symbol, 1
+}
+
+// hashSymbol hashes a token symbol
+func hashSymbol(symbol string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(symbol))
+	return h.Sum64()
+}
+
+// isCloneCandidate reports whether a node is a unit worth reporting as a clone on its own
+func isCloneCandidate(n ast.Node) bool {
+	switch n.(type) {
+	case *ast.FuncDecl, *ast.FuncLit, *ast.BlockStmt, *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt,
+		*ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.CaseClause, *ast.CommClause, *ast.CompositeLit:
+		return true
+	}
+	return false
+}
+
+// hash combines the token hashes of a range
+func (f *cloneFile) hash(start, end int, exact bool) uint64 {
+	h := uint64(14695981039346656037)
+	for _, tok := range f.tokens[start:end] {
+		value := tok.normalized
+		if exact {
+			value = tok.exact
+		}
+		h = (h ^ value) * 1099511628211
+	}
+	return h
+}
+
+// size returns the lexical token count of a range
+func (f *cloneFile) size(start, end int) int {
+	return f.weights[end] - f.weights[start]
+}
+
+// exactHash returns the hash of an occurrence including names and literal values
+func (o cloneOccurrence) exactHash() uint64 {
+	return o.file.hash(o.span.start, o.span.end, true)
+}
+
+// covered reports whether an occurrence lies entirely inside reported clones
+func (o cloneOccurrence) covered() bool {
+	for i := o.span.start; i < o.span.end; i++ {
+		if !o.file.covered[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// addCloneOccurrence records a fragment in the group of its normalized shape
+func addCloneOccurrence(groups map[cloneKey]*cloneGroup, file *cloneFile, span cloneSpan, minTokens int) {
+	tokens := file.size(span.start, span.end)
+	if tokens < minTokens {
+		return
+	}
+
+	key := cloneKey{hash: file.hash(span.start, span.end, false), length: span.end - span.start}
+	group, ok := groups[key]
+	if !ok {
+		group = &cloneGroup{key: key, tokens: tokens}
+		groups[key] = group
+	}
+	group.occurrences = append(group.occurrences, cloneOccurrence{file: file, span: span})
+}
+
+// findStatementSequences pairs statements of the same shape and extends each pair into the
+// longest run of matching statements, recording runs that start where the lists diverge
+func findStatementSequences(groups map[cloneKey]*cloneGroup, lists []*statementList, minTokens int) {
+	buckets := make(map[uint64][]statementRef)
+	for _, list := range lists {
+		for i, hash := range list.hash {
+			buckets[hash] = append(buckets[hash], statementRef{list: list, index: i})
+		}
+	}
+
+	for _, bucket := range buckets {
+		if len(bucket) < 2 || len(bucket) > maxSequenceBucket {
+			continue
+		}
+		for i := 0; i < len(bucket); i++ {
+			for j := i + 1; j < len(bucket); j++ {
+				a, b := bucket[i], bucket[j]
+
+				// Only start where the preceding statements differ, so each run is found once
+				if a.index > 0 && b.index > 0 && a.list.hash[a.index-1] == b.list.hash[b.index-1] {
+					continue
+				}
+
+				length := 0
+				for a.index+length < len(a.list.hash) && b.index+length < len(b.list.hash) &&
+					a.list.hash[a.index+length] == b.list.hash[b.index+length] {
+					if a.list == b.list && a.index+length >= b.index {
+						break
+					}
+					length++
+				}
+				if length < 2 {
+					continue
+				}
+
+				addCloneOccurrence(groups, a.list.file, a.list.sequence(a.index, length), minTokens)
+				addCloneOccurrence(groups, b.list.file, b.list.sequence(b.index, length), minTokens)
+			}
+		}
+	}
+}
+
+// sequence returns the span of length statements starting at index
+func (l *statementList) sequence(index, length int) cloneSpan {
+	first, last := l.spans[index], l.spans[index+length-1]
+	return cloneSpan{start: first.start, end: last.end, pos: first.pos, last: last.last}
+}
+
+// distinctOccurrences drops occurrences that overlap an earlier one in the same file
+func distinctOccurrences(occurrences []cloneOccurrence) []cloneOccurrence {
+	var distinct []cloneOccurrence
+	for _, occurrence := range occurrences {
+		overlaps := false
+		for _, kept := range distinct {
+			if kept.file == occurrence.file && occurrence.span.start < kept.span.end && kept.span.start < occurrence.span.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			distinct = append(distinct, occurrence)
+		}
+	}
+	return distinct
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
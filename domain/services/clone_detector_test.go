@@ -0,0 +1,268 @@
+package services
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestCloneDetector_DetectClones(t *testing.T) {
+	tests := []struct {
+		name           string
+		sources        []string
+		minTokens      int
+		expectedIssues int
+		expectedTypes  []string
+	}{
+		{
+			name: "Identical function bodies across files",
+			sources: []string{
+				`
+package pkg
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		if v > 0 {
+			total += v
+		}
+	}
+	return total
+}`,
+				`
+package pkg
+
+// addAll copies the body of sum
+func addAll(values []int, _ bool) int {
+	total := 0
+	for _, v := range values {
+		if v > 0 {
+			total += v
+		}
+	}
+	return total
+}`,
+			},
+			minTokens:      15,
+			expectedIssues: 1,
+			expectedTypes:  []string{"Type-1 clone", "occurs 2 times", "pkg/a.go:3-11", "pkg/b.go:5-13"},
+		},
+		{
+			name: "Renamed identifiers and literals",
+			sources: []string{
+				`
+package pkg
+func positive(values []int) int {
+	count := 0
+	for _, v := range values {
+		if v > 0 {
+			count++
+		}
+	}
+	return count
+}`,
+				`
+package pkg
+func large(items []int) int {
+	n := 0
+	for _, item := range items {
+		if item > 100 {
+			n++
+		}
+	}
+	return n
+}`,
+			},
+			minTokens:      20,
+			expectedIssues: 1,
+			expectedTypes:  []string{"Type-2 clone", "pkg/a.go:3-11", "pkg/b.go:3-11"},
+		},
+		{
+			name: "Clone below the token threshold - no issue",
+			sources: []string{
+				`
+package pkg
+func first(values []int) int {
+	return values[0]
+}
+func head(values []int) int {
+	return values[0]
+}`,
+			},
+			minTokens:      50,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Duplicated statement sequence inside different functions",
+			sources: []string{
+				`
+package pkg
+import "strings"
+func render(name string, tags []string) string {
+	var b strings.Builder
+	b.WriteString("<")
+	b.WriteString(name)
+	b.WriteString(" class=\"")
+	b.WriteString(strings.Join(tags, " "))
+	b.WriteString("\">")
+	return b.String()
+}
+func renderItem(label string, classes []string, closed bool) string {
+	if closed {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("<")
+	b.WriteString(label)
+	b.WriteString(" class=\"")
+	b.WriteString(strings.Join(classes, " "))
+	b.WriteString("\">")
+	return b.String()
+}`,
+			},
+			minTokens:      30,
+			expectedIssues: 1,
+			expectedTypes:  []string{"Type-2 clone", "pkg/a.go:5-11", "pkg/a.go:17-23"},
+		},
+		{
+			name: "Different control flow - no issue",
+			sources: []string{
+				`
+package pkg
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}`,
+				`
+package pkg
+func max(values []int) int {
+	best := 0
+	for _, v := range values {
+		if v > best {
+			best = v
+		}
+	}
+	return best
+}`,
+			},
+			minTokens:      10,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := parsePackage(t, tt.sources...)
+			config := valueobjects.DefaultAnalysisConfiguration().WithMinCloneTokens(tt.minTokens)
+
+			detector := NewASTCloneDetector()
+			findings, _, err := detector.DetectClones([]*PackageContext{pkg}, config)
+			if err != nil {
+				t.Fatalf("DetectClones failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+		})
+	}
+}
+
+func TestCloneDetector_PackageDuplication(t *testing.T) {
+	duplicated := parsePackage(t, `
+package pkg
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+func add(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}`)
+	clean := parsePackage(t, `
+package other
+func double(v int) int {
+	return v * 2
+}`)
+
+	config := valueobjects.DefaultAnalysisConfiguration().WithMinCloneTokens(10)
+	findings, duplication, err := NewASTCloneDetector().DetectClones([]*PackageContext{duplicated, clean}, config)
+	if err != nil {
+		t.Fatalf("DetectClones failed: %v", err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %d", len(findings))
+	}
+	if len(duplication) != 2 {
+		t.Fatalf("Expected duplication for 2 packages, got %d", len(duplication))
+	}
+
+	if duplication[0].Package() != "pkg" || duplication[0].Percentage() < 80 {
+		t.Errorf("Expected package pkg to be mostly duplicated, got %s", duplication[0])
+	}
+	if duplication[1].Package() != "other" || duplication[1].DuplicatedTokens() != 0 {
+		t.Errorf("Expected package other to have no duplication, got %s", duplication[1])
+	}
+}
+
+func TestCloneDetector_DuplicationLabels(t *testing.T) {
+	sources := map[string]string{
+		"main.go":          "package main\nfunc main() {}\n",
+		"cmd/tool/main.go": "package main\nfunc main() {}\n",
+		"store/store.go":   "package store\nfunc open() {}\n",
+	}
+
+	var pkgs []*PackageContext
+	for _, name := range []string{"main.go", "cmd/tool/main.go", "store/store.go"} {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, name, sources[name], 0)
+		if err != nil {
+			t.Fatalf("Failed to parse code: %v", err)
+		}
+		pkgs = append(pkgs, NewPackageContext(fset, []*ast.File{file}, nil))
+	}
+
+	_, duplication, err := NewASTCloneDetector().DetectClones(pkgs, valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("DetectClones failed: %v", err)
+	}
+
+	var labels []string
+	for _, pkg := range duplication {
+		labels = append(labels, pkg.Package())
+	}
+	expected := []string{"main (.)", "main (cmd/tool)", "store"}
+	if strings.Join(labels, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected package labels %q, got %q", expected, labels)
+	}
+}
@@ -10,6 +10,8 @@ import (
 // ComplexityCalculator calculates complexity metrics for Go code constructs
 type ComplexityCalculator interface {
 	CalculateComplexity(node ast.Node, fset *token.FileSet) (valueobjects.ComplexityScore, error)
+	CalculateFunctionMetrics(funcDecl *ast.FuncDecl, file *ast.File, fset *token.FileSet) (valueobjects.FunctionMetrics, error)
+	CalculateTypeMetrics(pkg *PackageContext) ([]valueobjects.TypeMetrics, error)
 }
 
 // ASTComplexityCalculator implements ComplexityCalculator using AST analysis
@@ -20,12 +22,17 @@ func NewASTComplexityCalculator() *ASTComplexityCalculator {
 	return &ASTComplexityCalculator{}
 }
 
-// CalculateComplexity calculates both cyclomatic and cognitive complexity
+// CalculateComplexity calculates cyclomatic, cognitive, NPath and essential complexity
 func (c *ASTComplexityCalculator) CalculateComplexity(node ast.Node, fset *token.FileSet) (valueobjects.ComplexityScore, error) {
 	cyclomatic := c.calculateCyclomaticComplexity(node)
 	cognitive := c.calculateCognitiveComplexity(node, 0)
 
-	return valueobjects.NewComplexityScore(cyclomatic, cognitive)
+	score, err := valueobjects.NewComplexityScore(cyclomatic, cognitive)
+	if err != nil {
+		return valueobjects.ComplexityScore{}, err
+	}
+
+	return score.WithPathComplexity(c.calculateNPath(node), c.calculateEssentialComplexity(node))
 }
 
 // calculateCyclomaticComplexity implements McCabe's cyclomatic complexity
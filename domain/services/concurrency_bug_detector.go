@@ -138,6 +138,7 @@ func (cbd *ASTConcurrencyBugDetector) detectBlockingBugs(funcDecl *ast.FuncDecl,
 			message,
 			severity,
 		)
+		finding.AddMetadata("rule", pattern.cause.String())
 		findings = append(findings, finding)
 	}
 
@@ -518,46 +519,22 @@ func (cbd *ASTConcurrencyBugDetector) findSynchronizationPrimitives(block *ast.B
 		case *ast.GenDecl:
 			// Find mutex variable declarations
 			for _, spec := range stmt.Specs {
-				if valueSpec, ok := spec.(*ast.ValueSpec); ok {
-					for _, name := range valueSpec.Names {
-						if valueSpec.Type != nil {
-							if selector, ok := valueSpec.Type.(*ast.SelectorExpr); ok {
-								if ident, ok := selector.X.(*ast.Ident); ok {
-									if ident.Name == "sync" {
-										switch selector.Sel.Name {
-										case "Mutex", "RWMutex":
-											syncPrimitives[name.Name] = "mutex"
-										case "WaitGroup":
-											syncPrimitives[name.Name] = "waitgroup"
-										case "Once":
-											syncPrimitives[name.Name] = "once"
-										}
-									}
-								}
-							}
+				if valueSpec, ok := spec.(*ast.ValueSpec); ok && valueSpec.Type != nil {
+					if kind := syncPrimitiveKind(valueSpec.Type); kind != "" {
+						for _, name := range valueSpec.Names {
+							syncPrimitives[name.Name] = kind
 						}
 					}
 				}
 			}
 		case *ast.AssignStmt:
-			// Also check for mutex assignments like: var mu sync.Mutex
+			// Also check for mutex assignments like: mu := sync.Mutex{}
 			for _, rhs := range stmt.Rhs {
-				if composite, ok := rhs.(*ast.CompositeLit); ok {
-					if selector, ok := composite.Type.(*ast.SelectorExpr); ok {
-						if ident, ok := selector.X.(*ast.Ident); ok {
-							if ident.Name == "sync" {
-								for _, lhs := range stmt.Lhs {
-									if lhsIdent, ok := lhs.(*ast.Ident); ok {
-										switch selector.Sel.Name {
-										case "Mutex", "RWMutex":
-											syncPrimitives[lhsIdent.Name] = "mutex"
-										case "WaitGroup":
-											syncPrimitives[lhsIdent.Name] = "waitgroup"
-										case "Once":
-											syncPrimitives[lhsIdent.Name] = "once"
-										}
-									}
-								}
+				if composite, ok := rhs.(*ast.CompositeLit); ok && composite.Type != nil {
+					if kind := syncPrimitiveKind(composite.Type); kind != "" {
+						for _, lhs := range stmt.Lhs {
+							if lhsIdent, ok := lhs.(*ast.Ident); ok {
+								syncPrimitives[lhsIdent.Name] = kind
 							}
 						}
 					}
@@ -568,6 +545,50 @@ func (cbd *ASTConcurrencyBugDetector) findSynchronizationPrimitives(block *ast.B
 	})
 }
 
+// syncPrimitiveKind classifies a type expression naming a sync or sync/atomic type that must not be copied
+func syncPrimitiveKind(expr ast.Expr) string {
+	// Generic atomics such as atomic.Pointer[T] are instantiated through index expressions
+	switch generic := expr.(type) {
+	case *ast.IndexExpr:
+		expr = generic.X
+	case *ast.IndexListExpr:
+		expr = generic.X
+	}
+
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return syncTypeKind(pkg.Name, selector.Sel.Name)
+}
+
+// syncTypeKind classifies a type of the sync or sync/atomic packages by package and type name
+func syncTypeKind(pkg, name string) string {
+	switch pkg {
+	case "sync":
+		switch name {
+		case "Mutex", "RWMutex":
+			return "mutex"
+		case "WaitGroup":
+			return "waitgroup"
+		case "Once":
+			return "once"
+		case "Cond", "Map", "Pool":
+			return strings.ToLower(name)
+		}
+	case "atomic":
+		switch name {
+		case "Bool", "Int32", "Int64", "Uint32", "Uint64", "Uintptr", "Pointer", "Value":
+			return "atomic"
+		}
+	}
+	return ""
+}
+
 // analyzeBlockForRaces analyzes a block of code for variable accesses that could cause races
 func (cbd *ASTConcurrencyBugDetector) analyzeBlockForRaces(block *ast.BlockStmt, variableAccesses map[string][]accessInfo, goroutineID string, syncPrimitives map[string]string) {
 	// Track if we're inside a locked section
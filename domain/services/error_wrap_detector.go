@@ -0,0 +1,194 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// unwrappedErrorRule names findings of errors formatted into new errors without %w
+const unwrappedErrorRule = "unwrapped_error"
+
+// ErrorWrapDetector detects errors that are formatted into new errors without being wrapped
+type ErrorWrapDetector interface {
+	DetectErrorWrapping(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageErrorWrapping(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTErrorWrapDetector implements ErrorWrapDetector using AST analysis
+type ASTErrorWrapDetector struct{}
+
+// NewASTErrorWrapDetector creates a new AST-based error wrapping detector
+func NewASTErrorWrapDetector() *ASTErrorWrapDetector {
+	return &ASTErrorWrapDetector{}
+}
+
+// formatVerb is a verb of a format string literal and the argument it formats
+type formatVerb struct {
+	offset int
+	verb   byte
+	arg    ast.Expr
+}
+
+// DetectErrorWrapping analyzes code for fmt.Errorf calls that format errors with %v or %s
+func (ewd *ASTErrorWrapDetector) DetectErrorWrapping(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	return ewd.detectErrorWrapping(node, newNodePackageContext(node, fset)), nil
+}
+
+// DetectPackageErrorWrapping analyzes every file of a package, using type information to recognize errors
+func (ewd *ASTErrorWrapDetector) DetectPackageErrorWrapping(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var findings []entities.AnalysisFinding
+
+	for _, file := range pkg.Files() {
+		findings = append(findings, ewd.detectErrorWrapping(file, pkg)...)
+	}
+
+	return findings, nil
+}
+
+// detectErrorWrapping reports each fmt.Errorf call in the function declarations under node that
+// formats an error without %w
+func (ewd *ASTErrorWrapDetector) detectErrorWrapping(node ast.Node, pkg *PackageContext) []entities.AnalysisFinding {
+	var findings []entities.AnalysisFinding
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		funcDecl, ok := n.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			return true
+		}
+
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			verbs := unwrappedErrorVerbs(call, pkg)
+			if len(verbs) == 0 {
+				return true
+			}
+
+			position := pkg.FileSet().Position(call.Pos())
+			location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+
+			var names []string
+			for _, verb := range verbs {
+				names = append(names, fmt.Sprintf("'%s' with %%%c", types.ExprString(verb.arg), verb.verb))
+			}
+			finding, _ := entities.NewAnalysisFinding(
+				fmt.Sprintf("%s_%s_%d_%d", unwrappedErrorRule, funcDecl.Name.Name, position.Line, position.Column),
+				entities.FindingTypeSmell,
+				location,
+				fmt.Sprintf("Error wrapping in %s: %s detected - fmt.Errorf formats %s, so errors.Is and errors.As cannot see the cause; use %%w",
+					funcDecl.Name.Name, unwrappedErrorRule, strings.Join(names, ", ")),
+				valueobjects.SeverityWarning,
+			)
+			finding.AddMetadata("rule", unwrappedErrorRule)
+			finding.AddMetadata("suggestion", "%w")
+			findings = append(findings, finding)
+			return true
+		})
+		return false
+	})
+
+	return findings
+}
+
+// unwrappedErrorVerbs returns the %v and %s verbs of a fmt.Errorf call that format an error
+func unwrappedErrorVerbs(call *ast.CallExpr, pkg *PackageContext) []formatVerb {
+	if !isFmtErrorf(call, pkg) || len(call.Args) < 2 {
+		return nil
+	}
+	format, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || format.Kind != token.STRING {
+		return nil
+	}
+
+	verbs, ok := parseFormatVerbs(format.Value, call.Args[1:])
+	if !ok {
+		return nil
+	}
+
+	var unwrapped []formatVerb
+	for _, verb := range verbs {
+		if (verb.verb == 'v' || verb.verb == 's') && isErrorExpr(verb.arg, pkg) {
+			unwrapped = append(unwrapped, verb)
+		}
+	}
+	return unwrapped
+}
+
+// parseFormatVerbs pairs the verbs of a quoted format string with the arguments they consume,
+// giving each verb's offset within the quoted literal. Formats with explicit argument indexes or
+// * widths are not paired, since their verbs do not consume arguments in order
+func parseFormatVerbs(literal string, args []ast.Expr) ([]formatVerb, bool) {
+	var verbs []formatVerb
+	next := 0
+
+	for i := 0; i < len(literal); i++ {
+		if literal[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(literal) && strings.IndexByte("+-# 0123456789.", literal[i]) >= 0 {
+			i++
+		}
+		if i >= len(literal) {
+			break
+		}
+		switch literal[i] {
+		case '%':
+			continue
+		case '[', '*':
+			return nil, false
+		}
+
+		if next >= len(args) {
+			return nil, false
+		}
+		verbs = append(verbs, formatVerb{offset: i, verb: literal[i], arg: args[next]})
+		next++
+	}
+
+	return verbs, true
+}
+
+// isFmtErrorf reports whether a call invokes fmt.Errorf
+func isFmtErrorf(call *ast.CallExpr, pkg *PackageContext) bool {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "Errorf" {
+		return false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	if info := pkg.TypesInfo(); info != nil {
+		if pkgName, ok := info.Uses[ident].(*types.PkgName); ok {
+			return pkgName.Imported().Path() == "fmt"
+		}
+	}
+	return ident.Name == "fmt"
+}
+
+// isErrorExpr reports whether an expression is an error. Without type information, identifiers
+// named err or ending in Err or err are taken to be errors
+func isErrorExpr(expr ast.Expr, pkg *PackageContext) bool {
+	if info := pkg.TypesInfo(); info != nil {
+		if t := info.TypeOf(expr); t != nil {
+			errorType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+			return types.Implements(t, errorType)
+		}
+	}
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	return ident.Name == "err" || strings.HasSuffix(ident.Name, "Err") || strings.HasSuffix(ident.Name, "err")
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestErrorWrapDetector_DetectErrorWrapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected []string
+	}{
+		{
+			name: "Error formatted with %v",
+			code: `package main
+
+import "fmt"
+
+func load(path string, err error) error {
+	return fmt.Errorf("load %s: %v", path, err)
+}
+`,
+			expected: []string{"unwrapped_error_load_6_9"},
+		},
+		{
+			name: "Error formatted with %s after an escaped percent",
+			code: `package main
+
+import "fmt"
+
+func load(loadErr error) error {
+	return fmt.Errorf("100%% failed: %s", loadErr)
+}
+`,
+			expected: []string{"unwrapped_error_load_6_9"},
+		},
+		{
+			name: "Error already wrapped",
+			code: `package main
+
+import "fmt"
+
+func load(err error) error {
+	return fmt.Errorf("load: %w", err)
+}
+`,
+		},
+		{
+			name: "Formatted value is not an error",
+			code: `package main
+
+import "fmt"
+
+func load(path string) error {
+	return fmt.Errorf("load %v", path)
+}
+`,
+		},
+		{
+			name: "Explicit argument indexes are not paired",
+			code: `package main
+
+import "fmt"
+
+func load(err error) error {
+	return fmt.Errorf("%[1]v", err)
+}
+`,
+		},
+	}
+
+	detector := NewASTErrorWrapDetector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "main.go", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			findings, err := detector.DetectErrorWrapping(file, fset, valueobjects.DefaultAnalysisConfiguration())
+			if err != nil {
+				t.Fatalf("DetectErrorWrapping failed: %v", err)
+			}
+
+			var ids []string
+			for _, finding := range findings {
+				ids = append(ids, finding.ID())
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected findings %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestErrorWrapDetector_DetectPackageErrorWrapping(t *testing.T) {
+	code := `package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errMissing = errors.New("missing")
+
+type notFound struct{}
+
+func (notFound) Error() string { return "not found" }
+
+func lookup(key string, cause notFound) error {
+	if key == "" {
+		return fmt.Errorf("lookup: %v", errMissing)
+	}
+	return fmt.Errorf("lookup %v: %v", key, cause)
+}
+`
+	pkg := checkPackage(t, code)
+
+	findings, err := NewASTErrorWrapDetector().DetectPackageErrorWrapping(pkg, valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("DetectPackageErrorWrapping failed: %v", err)
+	}
+
+	// Types recognize errors whatever their names, and the string key is left alone
+	if len(findings) != 2 {
+		t.Fatalf("Expected 2 findings, got %d", len(findings))
+	}
+	if !strings.Contains(findings[0].Message(), "'errMissing' with %v") {
+		t.Errorf("Unexpected message %s", findings[0].Message())
+	}
+	if !strings.Contains(findings[1].Message(), "'cause' with %v") || strings.Contains(findings[1].Message(), "'key'") {
+		t.Errorf("Unexpected message %s", findings[1].Message())
+	}
+}
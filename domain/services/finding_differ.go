@@ -0,0 +1,96 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+
+	"goastanalyzer/domain/entities"
+)
+
+// FindingDiff lists how the findings of one analysis differ from those of an earlier one
+type FindingDiff struct {
+	Appeared    []entities.AnalysisFinding
+	Disappeared []entities.AnalysisFinding
+	// Changed holds the new version of findings whose numbers changed, such as a complexity score
+	Changed []entities.AnalysisFinding
+}
+
+// IsEmpty reports whether both analyses found the same issues
+func (d FindingDiff) IsEmpty() bool {
+	return len(d.Appeared) == 0 && len(d.Disappeared) == 0 && len(d.Changed) == 0
+}
+
+// FindingDiffer compares the findings of two analyses of the same code
+type FindingDiffer interface {
+	DiffFindings(before, after []entities.AnalysisFinding) FindingDiff
+}
+
+// EditFindingDiffer implements FindingDiffer for code being edited. Lines move with every edit,
+// so findings are matched in three passes: exactly, then ignoring the line, then ignoring every
+// number in their message, which pairs up findings whose measured values changed
+type EditFindingDiffer struct{}
+
+// NewEditFindingDiffer creates a new edit-tolerant finding differ
+func NewEditFindingDiffer() *EditFindingDiffer {
+	return &EditFindingDiffer{}
+}
+
+// numbers matches the numbers masked by the last matching pass
+var numbers = regexp.MustCompile(`[0-9]+(\.[0-9]+)?`)
+
+// DiffFindings returns the findings only in after, only in before, and changed between them
+func (d *EditFindingDiffer) DiffFindings(before, after []entities.AnalysisFinding) FindingDiff {
+	unmatchedBefore := append([]entities.AnalysisFinding(nil), before...)
+	unmatchedAfter := append([]entities.AnalysisFinding(nil), after...)
+
+	exact := func(f entities.AnalysisFinding) string {
+		return fmt.Sprintf("%s|%s|%s|%d|%s", f.Location().FilePath(), f.Type(), f.Severity(), f.Location().Line(), f.Message())
+	}
+	moved := func(f entities.AnalysisFinding) string {
+		return fmt.Sprintf("%s|%s|%s|%s", f.Location().FilePath(), f.Type(), f.Severity(), f.Message())
+	}
+	measured := func(f entities.AnalysisFinding) string {
+		return fmt.Sprintf("%s|%s|%s", f.Location().FilePath(), f.Type(), numbers.ReplaceAllString(f.Message(), "#"))
+	}
+
+	unmatchedBefore, unmatchedAfter, _ = matchFindings(unmatchedBefore, unmatchedAfter, exact)
+	unmatchedBefore, unmatchedAfter, _ = matchFindings(unmatchedBefore, unmatchedAfter, moved)
+	unmatchedBefore, unmatchedAfter, changed := matchFindings(unmatchedBefore, unmatchedAfter, measured)
+
+	return FindingDiff{
+		Appeared:    unmatchedAfter,
+		Disappeared: unmatchedBefore,
+		Changed:     changed,
+	}
+}
+
+// matchFindings pairs findings with equal keys in order and returns the unpaired findings of each
+// side along with the paired findings of after
+func matchFindings(before, after []entities.AnalysisFinding, key func(entities.AnalysisFinding) string) ([]entities.AnalysisFinding, []entities.AnalysisFinding, []entities.AnalysisFinding) {
+	waiting := make(map[string][]int)
+	for i, finding := range before {
+		k := key(finding)
+		waiting[k] = append(waiting[k], i)
+	}
+
+	matched := make([]bool, len(before))
+	var unmatchedAfter, paired []entities.AnalysisFinding
+	for _, finding := range after {
+		k := key(finding)
+		if queue := waiting[k]; len(queue) > 0 {
+			matched[queue[0]] = true
+			waiting[k] = queue[1:]
+			paired = append(paired, finding)
+			continue
+		}
+		unmatchedAfter = append(unmatchedAfter, finding)
+	}
+
+	var unmatchedBefore []entities.AnalysisFinding
+	for i, finding := range before {
+		if !matched[i] {
+			unmatchedBefore = append(unmatchedBefore, finding)
+		}
+	}
+	return unmatchedBefore, unmatchedAfter, paired
+}
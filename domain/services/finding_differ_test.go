@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestFindingDiffer_DiffFindings(t *testing.T) {
+	tests := []struct {
+		name                string
+		before              []entities.AnalysisFinding
+		after               []entities.AnalysisFinding
+		expectedAppeared    []string
+		expectedDisappeared []string
+		expectedChanged     []string
+	}{
+		{
+			name:   "Unchanged findings",
+			before: []entities.AnalysisFinding{testFinding(t, "main.go", 10, "Function parse is too long: 90 lines (max: 80)")},
+			after:  []entities.AnalysisFinding{testFinding(t, "main.go", 10, "Function parse is too long: 90 lines (max: 80)")},
+		},
+		{
+			name:   "Findings moved by an edit above them",
+			before: []entities.AnalysisFinding{testFinding(t, "main.go", 10, "Function parse is too long: 90 lines (max: 80)")},
+			after:  []entities.AnalysisFinding{testFinding(t, "main.go", 14, "Function parse is too long: 90 lines (max: 80)")},
+		},
+		{
+			name:            "Measured value changed",
+			before:          []entities.AnalysisFinding{testFinding(t, "main.go", 10, "Function parse is too long: 90 lines (max: 80)")},
+			after:           []entities.AnalysisFinding{testFinding(t, "main.go", 10, "Function parse is too long: 95 lines (max: 80)")},
+			expectedChanged: []string{"Function parse is too long: 95 lines (max: 80)"},
+		},
+		{
+			name: "Findings fixed and introduced",
+			before: []entities.AnalysisFinding{
+				testFinding(t, "main.go", 10, "Function parse is too long: 90 lines (max: 80)"),
+				testFinding(t, "main.go", 40, "Potential goroutine leak in run"),
+			},
+			after: []entities.AnalysisFinding{
+				testFinding(t, "main.go", 40, "Potential goroutine leak in run"),
+				testFinding(t, "util.go", 3, "Function helper is too long: 81 lines (max: 80)"),
+			},
+			expectedAppeared:    []string{"Function helper is too long: 81 lines (max: 80)"},
+			expectedDisappeared: []string{"Function parse is too long: 90 lines (max: 80)"},
+		},
+		{
+			name: "Duplicate messages are matched one to one",
+			before: []entities.AnalysisFinding{
+				testFinding(t, "main.go", 10, "Potential goroutine leak in run"),
+			},
+			after: []entities.AnalysisFinding{
+				testFinding(t, "main.go", 10, "Potential goroutine leak in run"),
+				testFinding(t, "main.go", 20, "Potential goroutine leak in run"),
+			},
+			expectedAppeared: []string{"Potential goroutine leak in run"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := NewEditFindingDiffer().DiffFindings(tt.before, tt.after)
+
+			checkMessages(t, "appeared", diff.Appeared, tt.expectedAppeared)
+			checkMessages(t, "disappeared", diff.Disappeared, tt.expectedDisappeared)
+			checkMessages(t, "changed", diff.Changed, tt.expectedChanged)
+			expectEmpty := len(tt.expectedAppeared)+len(tt.expectedDisappeared)+len(tt.expectedChanged) == 0
+			if diff.IsEmpty() != expectEmpty {
+				t.Errorf("Expected IsEmpty to be %v", expectEmpty)
+			}
+		})
+	}
+}
+
+// testFinding creates a smell finding at the given place
+func testFinding(t *testing.T, file string, line int, message string) entities.AnalysisFinding {
+	t.Helper()
+
+	location, _ := valueobjects.NewSourceLocation(file, line, 1)
+	finding, err := entities.NewAnalysisFinding("finding", entities.FindingTypeSmell, location, message, valueobjects.SeverityWarning)
+	if err != nil {
+		t.Fatalf("Failed to create finding: %v", err)
+	}
+	return finding
+}
+
+// checkMessages compares the messages of findings with the expected ones in order
+func checkMessages(t *testing.T, kind string, findings []entities.AnalysisFinding, expected []string) {
+	t.Helper()
+
+	if len(findings) != len(expected) {
+		t.Errorf("Expected %d %s findings, got %v", len(expected), kind, findings)
+		return
+	}
+	for i, finding := range findings {
+		if finding.Message() != expected[i] {
+			t.Errorf("Expected %s finding %q, got %q", kind, expected[i], finding.Message())
+		}
+	}
+}
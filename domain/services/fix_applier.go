@@ -0,0 +1,115 @@
+package services
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+// FixApplier applies suggested fixes to Go source files
+type FixApplier interface {
+	ApplyFixes(sources map[string][]byte, fixes []valueobjects.SuggestedFix) (FixResult, error)
+}
+
+// FixResult holds the fixed files and which fixes went into them
+type FixResult struct {
+	// Files maps each changed file to its fixed, gofmt-formatted source
+	Files map[string][]byte
+	// Applied are the fixes whose edits were made
+	Applied []valueobjects.SuggestedFix
+	// Skipped are the fixes refused because they overlap an applied fix or fall outside their file
+	Skipped []valueobjects.SuggestedFix
+}
+
+// GoFixApplier implements FixApplier, formatting every fixed file with go/format
+type GoFixApplier struct{}
+
+// NewGoFixApplier creates a new fix applier
+func NewGoFixApplier() *GoFixApplier {
+	return &GoFixApplier{}
+}
+
+// ApplyFixes applies fixes in order to the sources, keyed by file path. A fix is applied whole or
+// not at all: one that overlaps an already applied fix is skipped, and a fix repeated by several
+// findings is applied once
+func (fa *GoFixApplier) ApplyFixes(sources map[string][]byte, fixes []valueobjects.SuggestedFix) (FixResult, error) {
+	result := FixResult{Files: make(map[string][]byte)}
+	accepted := make(map[string][]valueobjects.TextEdit)
+	seen := make(map[string]bool)
+
+	for _, fix := range fixes {
+		key := fixKey(fix)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if !fa.fits(fix, sources, accepted) {
+			result.Skipped = append(result.Skipped, fix)
+			continue
+		}
+		for _, edit := range fix.Edits() {
+			accepted[edit.FilePath()] = append(accepted[edit.FilePath()], edit)
+		}
+		result.Applied = append(result.Applied, fix)
+	}
+
+	paths := make([]string, 0, len(accepted))
+	for path := range accepted {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fixed, err := applyEdits(sources[path], accepted[path])
+		if err != nil {
+			return FixResult{}, fmt.Errorf("fixes to %s do not produce valid Go: %w", path, err)
+		}
+		result.Files[path] = fixed
+	}
+
+	return result, nil
+}
+
+// fits reports whether every edit of a fix lies within its file and clear of accepted edits
+func (fa *GoFixApplier) fits(fix valueobjects.SuggestedFix, sources map[string][]byte, accepted map[string][]valueobjects.TextEdit) bool {
+	for _, edit := range fix.Edits() {
+		source, ok := sources[edit.FilePath()]
+		if !ok || edit.End() > len(source) {
+			return false
+		}
+		for _, other := range accepted[edit.FilePath()] {
+			if edit.Overlaps(other) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// applyEdits applies non-overlapping edits to a source, from the last to the first so earlier
+// offsets stay valid, and formats the result
+func applyEdits(source []byte, edits []valueobjects.TextEdit) ([]byte, error) {
+	sorted := valueobjects.SortTextEdits(edits)
+	fixed := append([]byte(nil), source...)
+
+	for i := len(sorted) - 1; i >= 0; i-- {
+		edit := sorted[i]
+		tail := append([]byte(edit.NewText()), fixed[edit.End():]...)
+		fixed = append(fixed[:edit.Offset()], tail...)
+	}
+
+	return format.Source(fixed)
+}
+
+// fixKey identifies a fix by its edits
+func fixKey(fix valueobjects.SuggestedFix) string {
+	var parts []string
+	for _, edit := range fix.Edits() {
+		parts = append(parts, edit.String())
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestFixApplier_ApplyFixes(t *testing.T) {
+	source := "package main\n\nfunc f() {\n\tx := 1\n\t_ = x\n}\n"
+	edit := func(offset, end int, text string) valueobjects.TextEdit {
+		e, err := valueobjects.NewTextEdit("main.go", offset, end, text)
+		if err != nil {
+			t.Fatalf("NewTextEdit failed: %v", err)
+		}
+		return e
+	}
+	fix := func(edits ...valueobjects.TextEdit) valueobjects.SuggestedFix {
+		f, err := valueobjects.NewSuggestedFix("fix", edits...)
+		if err != nil {
+			t.Fatalf("NewSuggestedFix failed: %v", err)
+		}
+		return f
+	}
+	value := strings.Index(source, "1")
+
+	tests := []struct {
+		name            string
+		fixes           []valueobjects.SuggestedFix
+		expected        string
+		expectedApplied int
+		expectedSkipped int
+	}{
+		{
+			name:            "Edits are applied and formatted",
+			fixes:           []valueobjects.SuggestedFix{fix(edit(value, value+1, "2"), edit(len(source), len(source), "func g(){}\n"))},
+			expected:        "package main\n\nfunc f() {\n\tx := 2\n\t_ = x\n}\nfunc g() {}\n",
+			expectedApplied: 1,
+		},
+		{
+			name:            "Overlapping fix is skipped",
+			fixes:           []valueobjects.SuggestedFix{fix(edit(value, value+1, "2")), fix(edit(value, value+1, "3"))},
+			expected:        "package main\n\nfunc f() {\n\tx := 2\n\t_ = x\n}\n",
+			expectedApplied: 1,
+			expectedSkipped: 1,
+		},
+		{
+			name:            "Repeated fix is applied once",
+			fixes:           []valueobjects.SuggestedFix{fix(edit(value, value+1, "2")), fix(edit(value, value+1, "2"))},
+			expected:        "package main\n\nfunc f() {\n\tx := 2\n\t_ = x\n}\n",
+			expectedApplied: 1,
+		},
+		{
+			name:            "Fix beyond the end of the file is skipped",
+			fixes:           []valueobjects.SuggestedFix{fix(edit(len(source)+1, len(source)+1, "x"))},
+			expectedSkipped: 1,
+		},
+	}
+
+	applier := NewGoFixApplier()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := applier.ApplyFixes(map[string][]byte{"main.go": []byte(source)}, tt.fixes)
+			if err != nil {
+				t.Fatalf("ApplyFixes failed: %v", err)
+			}
+			if got := string(result.Files["main.go"]); got != tt.expected {
+				t.Errorf("Expected fixed source %q, got %q", tt.expected, got)
+			}
+			if len(result.Applied) != tt.expectedApplied || len(result.Skipped) != tt.expectedSkipped {
+				t.Errorf("Expected %d applied and %d skipped fixes, got %d and %d",
+					tt.expectedApplied, tt.expectedSkipped, len(result.Applied), len(result.Skipped))
+			}
+		})
+	}
+}
+
+func TestFixApplier_InvalidResult(t *testing.T) {
+	source := "package main\n"
+	edit, _ := valueobjects.NewTextEdit("main.go", len(source), len(source), "func {\n")
+	fix, _ := valueobjects.NewSuggestedFix("break the file", edit)
+
+	if _, err := NewGoFixApplier().ApplyFixes(map[string][]byte{"main.go": []byte(source)}, []valueobjects.SuggestedFix{fix}); err == nil {
+		t.Error("Expected fixes producing invalid Go to be refused")
+	}
+}
@@ -0,0 +1,608 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/version"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// FixSuggester attaches mechanical fixes to the findings they resolve
+type FixSuggester interface {
+	SuggestFixes(findings []entities.AnalysisFinding, pkg *PackageContext) []entities.AnalysisFinding
+}
+
+// ASTFixSuggester implements FixSuggester by rewriting the syntax a finding points at. It fixes
+// single-send result channels, receivers that wait for a close, selects without cancellation,
+// locks without unlocks and errors formatted without %w, and only when the rewrite is safe
+type ASTFixSuggester struct{}
+
+// NewASTFixSuggester creates a new AST-based fix suggester
+func NewASTFixSuggester() *ASTFixSuggester {
+	return &ASTFixSuggester{}
+}
+
+// fixTarget is the syntax around a finding's location
+type fixTarget struct {
+	pkg       *PackageContext
+	file      *ast.File
+	tokenFile *token.File
+	pos       token.Pos
+	path      []ast.Node
+}
+
+// SuggestFixes attaches a fix to each finding whose rule metadata names a fixable rule, returning
+// the findings
+func (fs *ASTFixSuggester) SuggestFixes(findings []entities.AnalysisFinding, pkg *PackageContext) []entities.AnalysisFinding {
+	for i := range findings {
+		target := fs.locate(findings[i].Location(), pkg)
+		if target == nil {
+			continue
+		}
+
+		var fix *valueobjects.SuggestedFix
+		rule, _ := findings[i].Metadata()["rule"].(string)
+		switch rule {
+		case LeakPatternChannelSend.String():
+			fix = target.bufferChannel(findings[i])
+		case LeakPatternChannelReceive.String():
+			fix = target.closeChannel()
+		case LeakPatternSelectStatement.String():
+			fix = target.cancelSelect()
+		case BlockingCauseDeadlock.String():
+			fix = target.deferUnlock()
+		case unwrappedErrorRule:
+			fix = target.wrapError()
+		}
+		if fix != nil {
+			findings[i].AddFix(*fix)
+		}
+	}
+	return findings
+}
+
+// locate finds the syntax at a location in the package's files
+func (fs *ASTFixSuggester) locate(location valueobjects.SourceLocation, pkg *PackageContext) *fixTarget {
+	for _, file := range pkg.Files() {
+		tokenFile := pkg.FileSet().File(file.Pos())
+		if tokenFile == nil || tokenFile.Name() != location.FilePath() {
+			continue
+		}
+		if location.Line() < 1 || location.Line() > tokenFile.LineCount() || location.Column() < 1 {
+			return nil
+		}
+
+		offset := tokenFile.Offset(tokenFile.LineStart(location.Line())) + location.Column() - 1
+		if offset >= tokenFile.Size() {
+			return nil
+		}
+		pos := tokenFile.Pos(offset)
+		path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+		return &fixTarget{pkg: pkg, file: file, tokenFile: tokenFile, pos: pos, path: path}
+	}
+	return nil
+}
+
+// bufferChannel gives the unbuffered channel a goroutine sends its only result on a buffer of one,
+// so the send completes even when nobody receives
+func (t *fixTarget) bufferChannel(finding entities.AnalysisFinding) *valueobjects.SuggestedFix {
+	channel, _ := finding.Metadata()["channel"].(string)
+	_, body := t.goStatement()
+	if channel == "" || body == nil {
+		return nil
+	}
+
+	var makes []*ast.CallExpr
+	forEachDefinition(body, channel, func(value ast.Expr) {
+		if call := makeChanCall(value); call != nil && len(call.Args) == 1 {
+			makes = append(makes, call)
+		}
+	})
+	if len(makes) != 1 {
+		return nil
+	}
+
+	// A trailing comma or line break before ) would end up before the inserted size
+	call := makes[0]
+	if t.tokenFile.Offset(call.Args[0].End()) != t.tokenFile.Offset(call.Rparen) {
+		return nil
+	}
+	return t.fix(fmt.Sprintf("buffer channel '%s' so the send cannot block", channel), t.insert(call.Rparen, ", 1"))
+}
+
+// closeChannel closes the channel a goroutine waits on when the function creating it returns,
+// provided that function is the only sender
+func (t *fixTarget) closeChannel() *valueobjects.SuggestedFix {
+	goStmt, body := t.goStatement()
+	if goStmt == nil || body == nil {
+		return nil
+	}
+	literal, ok := goStmt.Call.Fun.(*ast.FuncLit)
+	if !ok {
+		return nil
+	}
+
+	received := receivedChannels(literal.Body)
+	if len(received) != 1 || !endsOnClose(literal.Body, received[0]) {
+		return nil
+	}
+	channel := received[0]
+
+	var definition ast.Stmt
+	definitions := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok != token.DEFINE {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == channel {
+					definitions++
+					if len(node.Lhs) == 1 && len(node.Rhs) == 1 && makeChanCall(node.Rhs[i]) != nil {
+						definition = node
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, name := range node.Names {
+				if name.Name == channel {
+					definitions++
+				}
+			}
+		}
+		return true
+	})
+	if definitions != 1 || definition == nil || !onlySentBy(body, channel, definition) {
+		return nil
+	}
+
+	directive := fmt.Sprintf("defer close(%s)", channel)
+	return t.fix(fmt.Sprintf("%s so the receiving goroutine returns", directive), t.insertLineAfter(definition, directive))
+}
+
+// cancelSelect adds a case returning on context cancellation to the selects of a goroutine
+func (t *fixTarget) cancelSelect() *valueobjects.SuggestedFix {
+	goStmt, _ := t.goStatement()
+	if goStmt == nil {
+		return nil
+	}
+	literal, ok := goStmt.Call.Fun.(*ast.FuncLit)
+	if !ok || literal.Type.Results != nil && len(literal.Type.Results.List) > 0 {
+		return nil
+	}
+
+	ctx := contextParam(literal.Type)
+	if ctx == "" {
+		ctx = t.contextInScope(goStmt)
+	}
+	if ctx == "" {
+		return nil
+	}
+
+	var edits []valueobjects.TextEdit
+	ast.Inspect(literal.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.SelectStmt:
+			if selectCanExit(node) {
+				return true
+			}
+			indent := strings.Repeat("\t", t.tokenFile.Position(node.Pos()).Column-1)
+			edits = append(edits, t.insert(node.Body.Rbrace,
+				fmt.Sprintf("case <-%s.Done():\n%s\treturn\n%s", ctx, indent, indent)))
+		}
+		return true
+	})
+	return t.fix(fmt.Sprintf("return from the goroutine when %s is cancelled", ctx), edits...)
+}
+
+// deferUnlock unlocks each mutex that a function locks once and never unlocks when it returns
+func (t *fixTarget) deferUnlock() *valueobjects.SuggestedFix {
+	var funcDecl *ast.FuncDecl
+	for _, node := range t.path {
+		if decl, ok := node.(*ast.FuncDecl); ok {
+			funcDecl = decl
+			break
+		}
+	}
+	if funcDecl == nil || funcDecl.Body == nil {
+		return nil
+	}
+
+	locks := make(map[string][]*ast.ExprStmt)
+	unlocked := make(map[string]bool)
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ExprStmt:
+			if name, method := mutexCall(node.X); method == "Lock" || method == "RLock" {
+				locks[name] = append(locks[name], node)
+			}
+		case *ast.CallExpr:
+			if name, method := mutexCall(node); method == "Unlock" || method == "RUnlock" {
+				unlocked[name] = true
+			}
+		}
+		return true
+	})
+
+	var names []string
+	for name, stmts := range locks {
+		if len(stmts) == 1 && !unlocked[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var edits []valueobjects.TextEdit
+	var directives []string
+	for _, name := range names {
+		stmt := locks[name][0]
+		_, method := mutexCall(stmt.X)
+		directive := fmt.Sprintf("defer %s.%s()", name, strings.Replace(method, "Lock", "Unlock", 1))
+		edits = append(edits, t.insertLineAfter(stmt, directive))
+		directives = append(directives, directive)
+	}
+	return t.fix(strings.Join(directives, ", ")+" after locking", edits...)
+}
+
+// wrapError formats the errors of a fmt.Errorf call with %w
+func (t *fixTarget) wrapError() *valueobjects.SuggestedFix {
+	var call *ast.CallExpr
+	for _, node := range t.path {
+		if expr, ok := node.(*ast.CallExpr); ok && expr.Pos() == t.pos {
+			call = expr
+			break
+		}
+	}
+	if call == nil {
+		return nil
+	}
+	verbs := unwrappedErrorVerbs(call, t.pkg)
+	if len(verbs) == 0 {
+		return nil
+	}
+
+	// Before Go 1.20 a format may wrap only one error
+	format := call.Args[0].(*ast.BasicLit)
+	wrapped := strings.Count(format.Value, "%w") + len(verbs)
+	if goVersion := t.pkg.GoVersion(); wrapped > 1 && goVersion != "" && version.Compare(goVersion, "go1.20") < 0 {
+		return nil
+	}
+
+	start := t.tokenFile.Offset(format.Pos())
+	var edits []valueobjects.TextEdit
+	for _, verb := range verbs {
+		edit, err := valueobjects.NewTextEdit(t.tokenFile.Name(), start+verb.offset, start+verb.offset+1, "w")
+		if err != nil {
+			return nil
+		}
+		edits = append(edits, edit)
+	}
+	return t.fix("wrap the error with %w", edits...)
+}
+
+// goStatement returns the go statement at the target and the body of the function running it
+func (t *fixTarget) goStatement() (*ast.GoStmt, *ast.BlockStmt) {
+	var goStmt *ast.GoStmt
+	for _, node := range t.path {
+		switch node := node.(type) {
+		case *ast.GoStmt:
+			if goStmt == nil && node.Pos() == t.pos {
+				goStmt = node
+			}
+		case *ast.FuncLit:
+			if goStmt != nil {
+				return goStmt, node.Body
+			}
+		case *ast.FuncDecl:
+			if goStmt != nil {
+				return goStmt, node.Body
+			}
+		}
+	}
+	return goStmt, nil
+}
+
+// contextInScope returns a context declared by the functions enclosing a go statement, either
+// as a parameter or assigned from the context package before the statement
+func (t *fixTarget) contextInScope(goStmt *ast.GoStmt) string {
+	for _, node := range t.path {
+		var funcType *ast.FuncType
+		var body *ast.BlockStmt
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			funcType, body = node.Type, node.Body
+		case *ast.FuncDecl:
+			funcType, body = node.Type, node.Body
+		default:
+			continue
+		}
+
+		if name := contextParam(funcType); name != "" {
+			return name
+		}
+		name := ""
+		ast.Inspect(body, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok || name != "" {
+				return false
+			}
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || assign.Pos() >= goStmt.Pos() || len(assign.Rhs) != 1 {
+				return true
+			}
+			if call, ok := assign.Rhs[0].(*ast.CallExpr); ok && isPackageCall(call, "context") {
+				if ident, ok := assign.Lhs[0].(*ast.Ident); ok && ident.Name != "_" {
+					name = ident.Name
+				}
+			}
+			return true
+		})
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// insert returns an edit inserting text at pos
+func (t *fixTarget) insert(pos token.Pos, text string) valueobjects.TextEdit {
+	offset := t.tokenFile.Offset(pos)
+	edit, _ := valueobjects.NewTextEdit(t.tokenFile.Name(), offset, offset, text)
+	return edit
+}
+
+// insertLineAfter returns an edit adding a line holding text after a statement and any comment
+// trailing it. The line is indented like the statement
+func (t *fixTarget) insertLineAfter(stmt ast.Stmt, text string) valueobjects.TextEdit {
+	end := stmt.End()
+	line := t.tokenFile.Position(end).Line
+	for _, group := range t.file.Comments {
+		for _, comment := range group.List {
+			if comment.Pos() >= end && t.tokenFile.Position(comment.Pos()).Line == line {
+				end = comment.End()
+			}
+		}
+	}
+
+	indent := strings.Repeat("\t", t.tokenFile.Position(stmt.Pos()).Column-1)
+	return t.insert(end, "\n"+indent+text)
+}
+
+// fix bundles edits into a suggested fix, or returns nil without edits
+func (t *fixTarget) fix(description string, edits ...valueobjects.TextEdit) *valueobjects.SuggestedFix {
+	if len(edits) == 0 {
+		return nil
+	}
+	fix, err := valueobjects.NewSuggestedFix(description, edits...)
+	if err != nil {
+		return nil
+	}
+	return &fix
+}
+
+// forEachDefinition calls visit with each value assigned to or declared for name in body
+func forEachDefinition(body *ast.BlockStmt, name string, visit func(value ast.Expr)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name {
+					visit(node.Rhs[i])
+				}
+			}
+		case *ast.ValueSpec:
+			if len(node.Names) != len(node.Values) {
+				return true
+			}
+			for i, ident := range node.Names {
+				if ident.Name == name {
+					visit(node.Values[i])
+				}
+			}
+		}
+		return true
+	})
+}
+
+// makeChanCall returns expr if it is a make(chan T, ...) call
+func makeChanCall(expr ast.Expr) *ast.CallExpr {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+	if ident, ok := call.Fun.(*ast.Ident); !ok || ident.Name != "make" {
+		return nil
+	}
+	if _, ok := call.Args[0].(*ast.ChanType); !ok {
+		return nil
+	}
+	return call
+}
+
+// receivedChannels returns the named channels received from or ranged over in body, outside
+// nested function literals
+func receivedChannels(body *ast.BlockStmt) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(expr ast.Expr) {
+		if ident, ok := expr.(*ast.Ident); ok && !seen[ident.Name] {
+			seen[ident.Name] = true
+			names = append(names, ident.Name)
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				add(node.X)
+			}
+		case *ast.RangeStmt:
+			add(node.X)
+		}
+		return true
+	})
+	return names
+}
+
+// endsOnClose reports whether closing a channel lets a goroutine body finish: it ranges over the
+// channel or receives from it outside loops. A receive in a loop would spin on the closed channel
+func endsOnClose(body *ast.BlockStmt, channel string) bool {
+	ends := true
+	loops := 0
+
+	astutil.Apply(body, func(c *astutil.Cursor) bool {
+		switch node := c.Node().(type) {
+		case *ast.ForStmt:
+			loops++
+		case *ast.RangeStmt:
+			if ident, ok := node.X.(*ast.Ident); !ok || ident.Name != channel {
+				loops++
+			}
+		case *ast.UnaryExpr:
+			if ident, ok := node.X.(*ast.Ident); ok && ident.Name == channel && node.Op == token.ARROW && loops > 0 {
+				ends = false
+			}
+		}
+		return ends
+	}, func(c *astutil.Cursor) bool {
+		switch node := c.Node().(type) {
+		case *ast.ForStmt:
+			loops--
+		case *ast.RangeStmt:
+			if ident, ok := node.X.(*ast.Ident); !ok || ident.Name != channel {
+				loops--
+			}
+		}
+		return true
+	})
+	return ends
+}
+
+// onlySentBy reports whether a channel is only received from, ranged over, or sent on directly
+// by the function owning body, so closing it when that function returns cannot panic. Any other
+// use, such as passing the channel on or closing it already, counts as escaping
+func onlySentBy(body *ast.BlockStmt, channel string, definition ast.Stmt) bool {
+	safe := true
+	literals := 0
+
+	astutil.Apply(body, func(c *astutil.Cursor) bool {
+		if _, ok := c.Node().(*ast.FuncLit); ok {
+			literals++
+		}
+		ident, ok := c.Node().(*ast.Ident)
+		if !ok || ident.Name != channel {
+			return safe
+		}
+
+		switch parent := c.Parent().(type) {
+		case *ast.AssignStmt:
+			safe = safe && parent == definition && c.Name() == "Lhs"
+		case *ast.SendStmt:
+			safe = safe && c.Name() == "Chan" && literals == 0
+		case *ast.UnaryExpr:
+			safe = safe && parent.Op == token.ARROW
+		case *ast.RangeStmt:
+			safe = safe && c.Name() == "X"
+		default:
+			safe = false
+		}
+		return safe
+	}, func(c *astutil.Cursor) bool {
+		if _, ok := c.Node().(*ast.FuncLit); ok {
+			literals--
+		}
+		return true
+	})
+	return safe
+}
+
+// contextParam returns the name of a context.Context parameter of a function type
+func contextParam(funcType *ast.FuncType) string {
+	if funcType == nil || funcType.Params == nil {
+		return ""
+	}
+	for _, field := range funcType.Params.List {
+		selector, ok := field.Type.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != "Context" {
+			continue
+		}
+		if ident, ok := selector.X.(*ast.Ident); !ok || ident.Name != "context" {
+			continue
+		}
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				return name.Name
+			}
+		}
+	}
+	return ""
+}
+
+// isPackageCall reports whether a call invokes a function of the package imported under name
+func isPackageCall(call *ast.CallExpr, name string) bool {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// selectCanExit reports whether a select has a default case or waits on a Done channel
+func selectCanExit(stmt *ast.SelectStmt) bool {
+	for _, clause := range stmt.Body.List {
+		comm, ok := clause.(*ast.CommClause)
+		if !ok {
+			continue
+		}
+		if comm.Comm == nil {
+			return true
+		}
+
+		exits := false
+		ast.Inspect(comm.Comm, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Done" {
+					exits = true
+				}
+			}
+			return !exits
+		})
+		if exits {
+			return true
+		}
+	}
+	return false
+}
+
+// mutexCall returns the receiver name and method of a call such as mu.Lock()
+func mutexCall(expr ast.Expr) (string, string) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return "", ""
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", ""
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+	return ident.Name, selector.Sel.Name
+}
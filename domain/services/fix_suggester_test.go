@@ -0,0 +1,299 @@
+package services
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// applySuggestedFixes detects smells in code and returns it with every suggested fix applied
+func applySuggestedFixes(t *testing.T, code, goVersion string) string {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", code, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse code: %v", err)
+	}
+	pkg := NewPackageContext(fset, []*ast.File{file}, nil)
+	pkg.SetGoVersion(goVersion)
+
+	findings, err := NewASTSmellDetector().DetectPackageSmells(pkg, valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("DetectPackageSmells failed: %v", err)
+	}
+
+	var fixes []valueobjects.SuggestedFix
+	for _, finding := range findings {
+		fixes = append(fixes, finding.Fixes()...)
+	}
+	result, err := NewGoFixApplier().ApplyFixes(map[string][]byte{"main.go": []byte(code)}, fixes)
+	if err != nil {
+		t.Fatalf("ApplyFixes failed: %v", err)
+	}
+	if fixed, ok := result.Files["main.go"]; ok {
+		return string(fixed)
+	}
+	return code
+}
+
+func TestFixSuggester_SuggestFixes(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		goVersion string
+		expected  string
+	}{
+		{
+			name: "Buffer a single-send result channel",
+			code: `package main
+
+func compute() int {
+	result := make(chan int)
+	go func() {
+		result <- 42
+	}()
+	return 0
+}
+`,
+			expected: `package main
+
+func compute() int {
+	result := make(chan int, 1)
+	go func() {
+		result <- 42
+	}()
+	return 0
+}
+`,
+		},
+		{
+			name: "Channel received from in a loop is not closed",
+			code: `package main
+
+import "fmt"
+
+func drain(values []int) {
+	ch := make(chan int)
+	go func() {
+		for {
+			fmt.Println(<-ch)
+		}
+	}()
+	for _, v := range values {
+		ch <- v
+	}
+}
+`,
+			// Receiving in a loop would spin once the channel is closed
+			expected: `package main
+
+import "fmt"
+
+func drain(values []int) {
+	ch := make(chan int)
+	go func() {
+		for {
+			fmt.Println(<-ch)
+		}
+	}()
+	for _, v := range values {
+		ch <- v
+	}
+}
+`,
+		},
+		{
+			name: "Close a channel received from once",
+			code: `package main
+
+import "fmt"
+
+func wait() {
+	done := make(chan struct{})
+	go func() {
+		<-done
+		fmt.Println("done")
+	}()
+}
+`,
+			expected: `package main
+
+import "fmt"
+
+func wait() {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		<-done
+		fmt.Println("done")
+	}()
+}
+`,
+		},
+		{
+			name: "Return from a select on context cancellation",
+			code: `package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func poll(ctx context.Context, events chan int) {
+	go func() {
+		for {
+			select {
+			case e := <-events:
+				fmt.Println(e)
+			}
+		}
+	}()
+}
+`,
+			expected: `package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func poll(ctx context.Context, events chan int) {
+	go func() {
+		for {
+			select {
+			case e := <-events:
+				fmt.Println(e)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+`,
+		},
+		{
+			name: "Defer the unlock after a trailing comment",
+			code: `package main
+
+import "sync"
+
+var mu sync.RWMutex
+var counter int
+
+func read() int {
+	mu.RLock() // guards counter
+	return counter
+}
+`,
+			expected: `package main
+
+import "sync"
+
+var mu sync.RWMutex
+var counter int
+
+func read() int {
+	mu.RLock() // guards counter
+	defer mu.RUnlock()
+	return counter
+}
+`,
+		},
+		{
+			name: "Wrap errors with %w",
+			code: `package main
+
+import "fmt"
+
+func load(path string, err error) error {
+	return fmt.Errorf("load %s: %v", path, err)
+}
+`,
+			expected: `package main
+
+import "fmt"
+
+func load(path string, err error) error {
+	return fmt.Errorf("load %s: %w", path, err)
+}
+`,
+		},
+		{
+			name: "Several wrapped errors need Go 1.20",
+			code: `package main
+
+import "fmt"
+
+func join(err, closeErr error) error {
+	return fmt.Errorf("%v; %v", err, closeErr)
+}
+`,
+			goVersion: "go1.19",
+			expected: `package main
+
+import "fmt"
+
+func join(err, closeErr error) error {
+	return fmt.Errorf("%v; %v", err, closeErr)
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applySuggestedFixes(t, tt.code, tt.goVersion); got != tt.expected {
+				t.Errorf("Unexpected fixed code:\n%s\nexpected:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFixSuggester_RuleMetadata(t *testing.T) {
+	code := `package main
+
+import "sync"
+
+var mu sync.Mutex
+
+func read() {
+	mu.Lock()
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", code, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse code: %v", err)
+	}
+	pkg := NewPackageContext(fset, []*ast.File{file}, nil)
+	location, _ := valueobjects.NewSourceLocation("main.go", 7, 1)
+
+	tests := []struct {
+		name        string
+		rule        string
+		expectedFix bool
+	}{
+		{name: "Rule metadata selects the fix", rule: BlockingCauseDeadlock.String(), expectedFix: true},
+		{name: "ID prefix alone does not select a fix"},
+		{name: "Unknown rule has no fix", rule: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding, _ := entities.NewAnalysisFinding("deadlock_read_7", entities.FindingTypeBug, location, "deadlock", valueobjects.SeverityCritical)
+			if tt.rule != "" {
+				finding.AddMetadata("rule", tt.rule)
+			}
+
+			findings := NewASTFixSuggester().SuggestFixes([]entities.AnalysisFinding{finding}, pkg)
+			if hasFix := len(findings[0].Fixes()) > 0; hasFix != tt.expectedFix {
+				t.Errorf("Expected fix %v, got %v", tt.expectedFix, findings[0].Fixes())
+			}
+		})
+	}
+}
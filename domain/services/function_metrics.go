@@ -0,0 +1,190 @@
+package services
+
+import (
+	"go/ast"
+	"go/token"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+// CalculateFunctionMetrics calculates complexity, Halstead metrics and line counts of a function;
+// file supplies the comments, which are not attached to the function node
+func (c *ASTComplexityCalculator) CalculateFunctionMetrics(funcDecl *ast.FuncDecl, file *ast.File, fset *token.FileSet) (valueobjects.FunctionMetrics, error) {
+	complexity, err := c.CalculateComplexity(funcDecl, fset)
+	if err != nil {
+		return valueobjects.FunctionMetrics{}, err
+	}
+
+	halstead, err := c.calculateHalstead(funcDecl)
+	if err != nil {
+		return valueobjects.FunctionMetrics{}, err
+	}
+
+	lines, err := c.countLines(funcDecl, file, fset)
+	if err != nil {
+		return valueobjects.FunctionMetrics{}, err
+	}
+
+	pos := fset.Position(funcDecl.Pos())
+	location, err := valueobjects.NewSourceLocation(pos.Filename, pos.Line, pos.Column)
+	if err != nil {
+		return valueobjects.FunctionMetrics{}, err
+	}
+
+	name := funcDecl.Name.Name
+	if recv := receiverTypeName(funcDecl); recv != "" {
+		name = recv + "." + name
+	}
+
+	return valueobjects.NewFunctionMetrics(name, location, complexity, halstead, lines)
+}
+
+// calculateHalstead counts operators and operands; identifiers and literals are operands,
+// while operator tokens, keywords and punctuation pairs such as calls and indexing are operators
+func (c *ASTComplexityCalculator) calculateHalstead(node ast.Node) (valueobjects.HalsteadMetrics, error) {
+	operators := make(map[string]int)
+	operands := make(map[string]int)
+	totalOperators, totalOperands := 0, 0
+
+	operator := func(symbol string) {
+		operators[symbol]++
+		totalOperators++
+	}
+	operand := func(symbol string) {
+		operands[symbol]++
+		totalOperands++
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch expr := n.(type) {
+		case *ast.Ident:
+			operand(expr.Name)
+		case *ast.BasicLit:
+			operand(expr.Value)
+		case *ast.BinaryExpr:
+			operator(expr.Op.String())
+		case *ast.UnaryExpr:
+			operator(expr.Op.String())
+		case *ast.StarExpr:
+			operator("*")
+		case *ast.AssignStmt:
+			operator(expr.Tok.String())
+		case *ast.IncDecStmt:
+			operator(expr.Tok.String())
+		case *ast.SendStmt:
+			operator("<-")
+		case *ast.BranchStmt:
+			operator(expr.Tok.String())
+		case *ast.CallExpr:
+			operator("()")
+			if expr.Ellipsis.IsValid() {
+				operator("...")
+			}
+		case *ast.ParenExpr:
+			operator("()")
+		case *ast.IndexExpr, *ast.IndexListExpr:
+			operator("[]")
+		case *ast.SliceExpr:
+			operator("[:]")
+		case *ast.SelectorExpr:
+			operator(".")
+		case *ast.TypeAssertExpr:
+			operator(".()")
+		case *ast.CompositeLit:
+			operator("{}")
+		case *ast.KeyValueExpr:
+			operator(":")
+		case *ast.FuncDecl, *ast.FuncLit:
+			operator("func")
+		case *ast.IfStmt:
+			operator("if")
+			if expr.Else != nil {
+				operator("else")
+			}
+		case *ast.ForStmt:
+			operator("for")
+		case *ast.RangeStmt:
+			operator("for")
+			operator("range")
+		case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+			operator("switch")
+		case *ast.SelectStmt:
+			operator("select")
+		case *ast.CaseClause:
+			if expr.List == nil {
+				operator("default")
+			} else {
+				operator("case")
+			}
+		case *ast.CommClause:
+			if expr.Comm == nil {
+				operator("default")
+			} else {
+				operator("case")
+			}
+		case *ast.ReturnStmt:
+			operator("return")
+		case *ast.GoStmt:
+			operator("go")
+		case *ast.DeferStmt:
+			operator("defer")
+		case *ast.GenDecl:
+			operator(expr.Tok.String())
+		case *ast.ArrayType:
+			operator("[]")
+		case *ast.MapType:
+			operator("map")
+		case *ast.ChanType:
+			operator("chan")
+		case *ast.StructType:
+			operator("struct")
+		case *ast.InterfaceType:
+			operator("interface")
+		case *ast.Ellipsis:
+			operator("...")
+		}
+		return true
+	})
+
+	return valueobjects.NewHalsteadMetrics(len(operators), len(operands), totalOperators, totalOperands)
+}
+
+// countLines counts the physical lines a function spans, its statements and its comment lines,
+// including the doc comment
+func (c *ASTComplexityCalculator) countLines(funcDecl *ast.FuncDecl, file *ast.File, fset *token.FileSet) (valueobjects.LinesOfCode, error) {
+	start := fset.Position(funcDecl.Pos()).Line
+	end := fset.Position(funcDecl.End()).Line
+
+	logical := 0
+	if funcDecl.Body != nil {
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			switch n.(type) {
+			case *ast.BlockStmt, *ast.EmptyStmt, *ast.LabeledStmt, *ast.CaseClause, *ast.CommClause:
+			case ast.Stmt:
+				logical++
+			}
+			return true
+		})
+	}
+
+	commentLines := make(map[int]bool)
+	addComments := func(group *ast.CommentGroup) {
+		for _, comment := range group.List {
+			for line := fset.Position(comment.Pos()).Line; line <= fset.Position(comment.End()).Line; line++ {
+				commentLines[line] = true
+			}
+		}
+	}
+	if funcDecl.Doc != nil {
+		addComments(funcDecl.Doc)
+	}
+	if file != nil {
+		for _, group := range file.Comments {
+			if group.Pos() >= funcDecl.Pos() && group.End() <= funcDecl.End() {
+				addComments(group)
+			}
+		}
+	}
+
+	return valueobjects.NewLinesOfCode(end-start+1, logical, len(commentLines))
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestComplexityCalculator_CalculateFunctionMetrics(t *testing.T) {
+	tests := []struct {
+		name              string
+		code              string
+		expectedName      string
+		distinctOperators int
+		distinctOperands  int
+		totalOperators    int
+		totalOperands     int
+		physical          int
+		logical           int
+		comment           int
+	}{
+		{
+			name: "Single expression",
+			code: `
+package main
+func add(a, b int) int {
+	return a + b
+}`,
+			expectedName:      "add",
+			distinctOperators: 3, // func, return, +
+			distinctOperands:  4, // add, a, b, int
+			totalOperators:    3,
+			totalOperands:     7,
+			physical:          3,
+			logical:           1,
+			comment:           0,
+		},
+		{
+			name: "Method with comments and control flow",
+			code: `
+package main
+type Counter struct{ n int }
+
+// Inc adds delta when positive
+func (c *Counter) Inc(delta int) {
+	// ignore non-positive deltas
+	if delta > 0 {
+		c.n += delta
+	}
+}`,
+			expectedName:      "Counter.Inc",
+			distinctOperators: 6, // func, *, if, >, +=, .
+			distinctOperands:  7, // c, Counter, Inc, delta, int, 0, n
+			totalOperators:    6,
+			totalOperands:     10,
+			physical:          6,
+			logical:           2,
+			comment:           2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "test.go", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			var funcDecl *ast.FuncDecl
+			for _, decl := range file.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok {
+					funcDecl = fd
+				}
+			}
+
+			calculator := NewASTComplexityCalculator()
+			metrics, err := calculator.CalculateFunctionMetrics(funcDecl, file, fset)
+			if err != nil {
+				t.Fatalf("CalculateFunctionMetrics failed: %v", err)
+			}
+
+			if metrics.Name() != tt.expectedName {
+				t.Errorf("Expected name %s, got %s", tt.expectedName, metrics.Name())
+			}
+
+			halstead := metrics.Halstead()
+			if halstead.DistinctOperators() != tt.distinctOperators || halstead.DistinctOperands() != tt.distinctOperands {
+				t.Errorf("Expected %d/%d distinct operators/operands, got %d/%d",
+					tt.distinctOperators, tt.distinctOperands, halstead.DistinctOperators(), halstead.DistinctOperands())
+			}
+			if halstead.TotalOperators() != tt.totalOperators || halstead.TotalOperands() != tt.totalOperands {
+				t.Errorf("Expected %d/%d total operators/operands, got %d/%d",
+					tt.totalOperators, tt.totalOperands, halstead.TotalOperators(), halstead.TotalOperands())
+			}
+
+			lines := metrics.Lines()
+			if lines.Physical() != tt.physical || lines.Logical() != tt.logical || lines.Comment() != tt.comment {
+				t.Errorf("Expected physical=%d, logical=%d, comment=%d, got %s", tt.physical, tt.logical, tt.comment, lines)
+			}
+
+			if index := metrics.MaintainabilityIndex(); index <= 0 || index > 100 {
+				t.Errorf("Expected maintainability index in (0, 100], got %.1f", index)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
 	"strings"
 
 	"goastanalyzer/domain/entities"
@@ -36,6 +37,7 @@ func (lpt LeakPatternType) String() string {
 // GoroutineLeakDetector detects goroutine leak patterns in Go code
 type GoroutineLeakDetector interface {
 	DetectLeaks(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageLeaks(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
 }
 
 // ASTGoroutineLeakDetector implements GoroutineLeakDetector using AST analysis
@@ -53,38 +55,95 @@ type goroutineContext struct {
 	hasSelectStmt     bool
 	hasContextCancel  bool
 	hasTimeout        bool
-	hasPrematureReturn bool
 	hasChannelSend    bool
 	hasDoubleSend     bool
-	hasDeferClose     bool
 	functionName      string
 	position          token.Position
+	calleePosition    token.Position
+	bindings          map[string]string
+	sendLeak          *unbufferedSendLeak
+}
+
+// goroutineSite is a go statement together with the function that starts it
+type goroutineSite struct {
+	goStmt     *ast.GoStmt
+	parentName string
+	parentBody *ast.BlockStmt
+}
+
+// unbufferedSendLeak describes a goroutine blocked forever sending on an unbuffered
+// channel because its parent can return without receiving (Tu et al., ASPLOS'19)
+type unbufferedSendLeak struct {
+	channel    string
+	elemType   string
+	parentName string
+	exit       token.Position
 }
 
 // DetectLeaks analyzes code for goroutine leak patterns
 func (gld *ASTGoroutineLeakDetector) DetectLeaks(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	return gld.detectLeaks(node, newNodePackageContext(node, fset), config), nil
+}
+
+// DetectPackageLeaks analyzes every file of a package, resolving goroutine targets across files
+func (gld *ASTGoroutineLeakDetector) DetectPackageLeaks(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var findings []entities.AnalysisFinding
+
+	for _, file := range pkg.Files() {
+		findings = append(findings, gld.detectLeaks(file, pkg, config)...)
+	}
+
+	return findings, nil
+}
+
+// detectLeaks analyzes the goroutines under node using pkg to resolve named targets
+func (gld *ASTGoroutineLeakDetector) detectLeaks(node ast.Node, pkg *PackageContext, config valueobjects.AnalysisConfiguration) []entities.AnalysisFinding {
 	var findings []entities.AnalysisFinding
 
 	// First pass: collect all goroutines and their contexts
 	goroutines := gld.collectGoroutines(node)
 
 	// Second pass: analyze each goroutine for leak patterns
-	for _, goStmt := range goroutines {
-		if leakFindings := gld.analyzeGoroutine(goStmt, fset, config); len(leakFindings) > 0 {
+	for _, site := range goroutines {
+		if leakFindings := gld.analyzeGoroutine(site, pkg, config); len(leakFindings) > 0 {
 			findings = append(findings, leakFindings...)
 		}
 	}
 
-	return findings, nil
+	return findings
 }
 
-// collectGoroutines collects all goroutine statements from the AST
-func (gld *ASTGoroutineLeakDetector) collectGoroutines(node ast.Node) []*ast.GoStmt {
-	var goroutines []*ast.GoStmt
+// collectGoroutines collects all goroutine statements from the AST along with their starting function
+func (gld *ASTGoroutineLeakDetector) collectGoroutines(node ast.Node) []goroutineSite {
+	var goroutines []goroutineSite
+	enclosing := ""
 
 	ast.Inspect(node, func(n ast.Node) bool {
-		if goStmt, ok := n.(*ast.GoStmt); ok {
-			goroutines = append(goroutines, goStmt)
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			enclosing = gld.calleeName(fn)
+			goroutines = gld.collectGoStmts(fn.Body, enclosing, goroutines)
+		case *ast.FuncLit:
+			goroutines = gld.collectGoStmts(fn.Body, enclosing, goroutines)
+		}
+		return true
+	})
+
+	return goroutines
+}
+
+// collectGoStmts appends the go statements of one function body, leaving nested literals to their own pass
+func (gld *ASTGoroutineLeakDetector) collectGoStmts(body *ast.BlockStmt, parentName string, goroutines []goroutineSite) []goroutineSite {
+	if body == nil {
+		return goroutines
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.GoStmt:
+			goroutines = append(goroutines, goroutineSite{goStmt: stmt, parentName: parentName, parentBody: body})
 		}
 		return true
 	})
@@ -93,24 +152,28 @@ func (gld *ASTGoroutineLeakDetector) collectGoroutines(node ast.Node) []*ast.GoS
 }
 
 // analyzeGoroutine analyzes a single goroutine for leak patterns
-func (gld *ASTGoroutineLeakDetector) analyzeGoroutine(goStmt *ast.GoStmt, fset *token.FileSet, config valueobjects.AnalysisConfiguration) []entities.AnalysisFinding {
+func (gld *ASTGoroutineLeakDetector) analyzeGoroutine(site goroutineSite, pkg *PackageContext, config valueobjects.AnalysisConfiguration) []entities.AnalysisFinding {
 	var findings []entities.AnalysisFinding
+	fset := pkg.FileSet()
+	goStmt := site.goStmt
 
 	context := &goroutineContext{
 		functionName: "anonymous_goroutine",
 		position:     fset.Position(goStmt.Pos()),
 	}
 
-	// Check if it's a function call (named function)
-	if callExpr, ok := goStmt.Call.Fun.(*ast.CallExpr); ok {
-		if ident, ok := callExpr.Fun.(*ast.Ident); ok {
-			context.functionName = ident.Name
-		}
-	}
-
 	// Check if it's a function literal (anonymous function)
 	if funcLit, ok := goStmt.Call.Fun.(*ast.FuncLit); ok {
+		context.bindings = gld.bindParameters(funcLit.Type, goStmt.Call.Args)
 		gld.analyzeFunctionBody(funcLit.Body, context)
+	} else if funcDecl := pkg.ResolveCall(goStmt.Call); funcDecl != nil {
+		// Named function or method: analyze the body of its declaration
+		context.functionName = gld.calleeName(funcDecl)
+		context.calleePosition = fset.Position(funcDecl.Pos())
+		context.bindings = gld.bindParameters(funcDecl.Type, goStmt.Call.Args)
+		gld.analyzeFunctionBody(funcDecl.Body, context)
+	} else if name := gld.callTargetName(goStmt.Call.Fun); name != "" {
+		context.functionName = name
 	}
 
 	// Check if it's a function call with function literal as argument
@@ -122,8 +185,8 @@ func (gld *ASTGoroutineLeakDetector) analyzeGoroutine(goStmt *ast.GoStmt, fset *
 		}
 	}
 
-	// If no function body found (e.g., named function call), still check for leaks
-	// based on the goroutine statement itself
+	// Check whether the parent can return while the goroutine is still sending
+	context.sendLeak = gld.findUnbufferedSendLeak(site, pkg)
 
 	// Check for leak patterns
 	findings = append(findings, gld.checkLeakPatterns(context, fset)...)
@@ -131,6 +194,77 @@ func (gld *ASTGoroutineLeakDetector) analyzeGoroutine(goStmt *ast.GoStmt, fset *
 	return findings
 }
 
+// bindParameters maps the parameter names of a goroutine target to the names of the arguments passed
+func (gld *ASTGoroutineLeakDetector) bindParameters(funcType *ast.FuncType, args []ast.Expr) map[string]string {
+	bindings := make(map[string]string)
+	if funcType == nil || funcType.Params == nil {
+		return bindings
+	}
+
+	index := 0
+	for _, field := range funcType.Params.List {
+		if len(field.Names) == 0 {
+			index++
+			continue
+		}
+		for _, name := range field.Names {
+			if index >= len(args) {
+				return bindings
+			}
+			if argName := gld.callTargetName(args[index]); argName != "" && name.Name != "_" {
+				bindings[name.Name] = argName
+			}
+			index++
+		}
+	}
+
+	return bindings
+}
+
+// boundName returns the caller-side name of an identifier bound to a goroutine argument
+func (gld *ASTGoroutineLeakDetector) boundName(ident *ast.Ident, context *goroutineContext) string {
+	if bound, ok := context.bindings[ident.Name]; ok {
+		return bound
+	}
+	return ident.Name
+}
+
+// nameMatches reports whether match accepts an identifier under its name in the goroutine or the
+// caller-side name bound to it, so a ctx parameter called with parent still reads as a context
+func (gld *ASTGoroutineLeakDetector) nameMatches(ident *ast.Ident, context *goroutineContext, match func(name string) bool) bool {
+	return match(ident.Name) || match(gld.boundName(ident, context))
+}
+
+// calleeName returns a readable name for a resolved goroutine target
+func (gld *ASTGoroutineLeakDetector) calleeName(funcDecl *ast.FuncDecl) string {
+	if recv := receiverTypeName(funcDecl); recv != "" {
+		return recv + "." + funcDecl.Name.Name
+	}
+	return funcDecl.Name.Name
+}
+
+// callTargetName returns the textual name of an identifier or selector expression
+func (gld *ASTGoroutineLeakDetector) callTargetName(expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		if x := gld.callTargetName(e.X); x != "" {
+			return x + "." + e.Sel.Name
+		}
+		return e.Sel.Name
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return gld.callTargetName(e.X)
+		}
+	case *ast.IndexExpr:
+		return gld.callTargetName(e.X)
+	case *ast.IndexListExpr:
+		return gld.callTargetName(e.X)
+	}
+	return ""
+}
+
 // analyzeFunctionBody analyzes the body of a goroutine function
 func (gld *ASTGoroutineLeakDetector) analyzeFunctionBody(block *ast.BlockStmt, context *goroutineContext) {
 	ast.Inspect(block, func(n ast.Node) bool {
@@ -156,12 +290,6 @@ func (gld *ASTGoroutineLeakDetector) analyzeFunctionBody(block *ast.BlockStmt, c
 		case *ast.SelectStmt:
 			context.hasSelectStmt = true
 			gld.analyzeSelectStatement(stmt, context)
-		case *ast.DeferStmt:
-			// Check for defer close operations
-			gld.checkDeferClose(stmt, context)
-		case *ast.ReturnStmt:
-			// Check if return happens before proper channel operations
-			gld.checkPrematureReturn(block, stmt, context)
 		}
 		return true
 	})
@@ -179,7 +307,7 @@ func (gld *ASTGoroutineLeakDetector) analyzeCallExpression(call *ast.CallExpr, c
 	// Check for context operations
 	if selector, ok := call.Fun.(*ast.SelectorExpr); ok {
 		if ident, ok := selector.X.(*ast.Ident); ok {
-			if ident.Name == "ctx" || ident.Name == "context" {
+			if gld.nameMatches(ident, context, func(name string) bool { return name == "ctx" || name == "context" }) {
 				switch selector.Sel.Name {
 				case "Done":
 					context.hasContextCancel = true
@@ -208,11 +336,11 @@ func (gld *ASTGoroutineLeakDetector) analyzeSelectStatement(selectStmt *ast.Sele
 				if exprStmt, ok := commClause.Comm.(*ast.ExprStmt); ok {
 					comm := exprStmt.X
 					if unary, ok := comm.(*ast.UnaryExpr); ok && unary.Op == token.ARROW {
-						if gld.isContextDoneCall(unary.X) {
+						if gld.isContextDoneCall(unary.X, context) {
 							hasContextDone = true
 						}
 						// Check for potential done/quit channels
-						if gld.isDoneChannel(unary.X) {
+						if gld.isDoneChannel(unary.X, context) {
 							hasContextDone = true
 						}
 						// Check for timeout patterns in channel receives (e.g., <-time.After(...))
@@ -244,13 +372,16 @@ func (gld *ASTGoroutineLeakDetector) analyzeSelectStatement(selectStmt *ast.Sele
 }
 
 // isContextDoneCall checks if an expression is ctx.Done() or similar
-func (gld *ASTGoroutineLeakDetector) isContextDoneCall(expr ast.Expr) bool {
+func (gld *ASTGoroutineLeakDetector) isContextDoneCall(expr ast.Expr, context *goroutineContext) bool {
 	if selector, ok := expr.(*ast.SelectorExpr); ok {
 		if selector.Sel.Name == "Done" {
 			if ident, ok := selector.X.(*ast.Ident); ok {
 				// Check if variable name suggests context (ctx, context, etc.)
-				return strings.Contains(strings.ToLower(ident.Name), "ctx") ||
-					   strings.Contains(strings.ToLower(ident.Name), "context")
+				return gld.nameMatches(ident, context, func(name string) bool {
+					name = strings.ToLower(name)
+					return strings.Contains(name, "ctx") ||
+						   strings.Contains(name, "context")
+				})
 			}
 		}
 	}
@@ -258,14 +389,16 @@ func (gld *ASTGoroutineLeakDetector) isContextDoneCall(expr ast.Expr) bool {
 }
 
 // isDoneChannel checks if an expression refers to a done/quit/cancel channel
-func (gld *ASTGoroutineLeakDetector) isDoneChannel(expr ast.Expr) bool {
+func (gld *ASTGoroutineLeakDetector) isDoneChannel(expr ast.Expr, context *goroutineContext) bool {
 	if ident, ok := expr.(*ast.Ident); ok {
-		name := strings.ToLower(ident.Name)
-		return strings.Contains(name, "done") ||
-			   strings.Contains(name, "quit") ||
-			   strings.Contains(name, "stop") ||
-			   strings.Contains(name, "cancel") ||
-			   strings.Contains(name, "exit")
+		return gld.nameMatches(ident, context, func(name string) bool {
+			name = strings.ToLower(name)
+			return strings.Contains(name, "done") ||
+				   strings.Contains(name, "quit") ||
+				   strings.Contains(name, "stop") ||
+				   strings.Contains(name, "cancel") ||
+				   strings.Contains(name, "exit")
+		})
 	}
 	return false
 }
@@ -305,22 +438,6 @@ func (gld *ASTGoroutineLeakDetector) checkForDoubleSend(block *ast.BlockStmt, se
 	}
 }
 
-// checkDeferClose checks for defer close operations
-func (gld *ASTGoroutineLeakDetector) checkDeferClose(deferStmt *ast.DeferStmt, context *goroutineContext) {
-	if call, ok := deferStmt.Call.Fun.(*ast.Ident); ok && call.Name == "close" {
-		context.hasDeferClose = true
-	}
-}
-
-// checkPrematureReturn checks if return happens before proper cleanup
-func (gld *ASTGoroutineLeakDetector) checkPrematureReturn(block *ast.BlockStmt, returnStmt *ast.ReturnStmt, context *goroutineContext) {
-	// If there's a return statement and we have channel operations, mark as premature return
-	// This is a simple heuristic - any return in a function with channel operations could be problematic
-	if context.hasChannelReceive || context.hasChannelSend {
-		context.hasPrematureReturn = true
-	}
-}
-
 // sameChannel checks if two channel expressions refer to the same channel
 func (gld *ASTGoroutineLeakDetector) sameChannel(chan1, chan2 ast.Expr) bool {
 	if ident1, ok := chan1.(*ast.Ident); ok {
@@ -354,6 +471,7 @@ func (gld *ASTGoroutineLeakDetector) checkLeakPatterns(context *goroutineContext
 			fmt.Sprintf("Potential goroutine leak in %s: channel_receive_leak detected - channel receive without close operation (confidence: 0.42)", context.functionName),
 			valueobjects.SeverityWarning,
 		)
+		finding.AddMetadata("rule", LeakPatternChannelReceive.String())
 		findings = append(findings, finding)
 	}
 
@@ -366,20 +484,586 @@ func (gld *ASTGoroutineLeakDetector) checkLeakPatterns(context *goroutineContext
 			fmt.Sprintf("Potential goroutine leak in %s: select_statement_leak detected - select statement without escape hatch (context cancel or timeout) (confidence: 0.86)", context.functionName),
 			valueobjects.SeverityError,
 		)
+		finding.AddMetadata("rule", LeakPatternSelectStatement.String())
 		findings = append(findings, finding)
 	}
 
-	// Channel send leak pattern - if there's send operation with premature return and no defer close
-	if context.hasChannelSend && context.hasPrematureReturn && !context.hasDeferClose {
+	// Channel send leak pattern - the parent can return without receiving from an unbuffered channel
+	if leak := context.sendLeak; leak != nil {
 		finding, _ := entities.NewAnalysisFinding(
 			fmt.Sprintf("channel_send_leak_%s_%d", context.functionName, context.position.Line),
 			entities.FindingTypeSmell,
 			location,
-			fmt.Sprintf("Potential goroutine leak in %s: channel_send_leak detected - channel send with premature return (confidence: 0.57)", context.functionName),
+			fmt.Sprintf("Potential goroutine leak in %s: channel_send_leak detected - send on unbuffered channel '%s' blocks forever when %s exits at line %d without receiving; use make(chan %s, 1) (confidence: 0.57)",
+				context.functionName, leak.channel, leak.parentName, leak.exit.Line, leak.elemType),
 			valueobjects.SeverityWarning,
 		)
+		finding.AddMetadata("rule", LeakPatternChannelSend.String())
+		finding.AddMetadata("channel", leak.channel)
+		finding.AddMetadata("exit_location", leak.exit.String())
+		finding.AddMetadata("suggestion", fmt.Sprintf("make(chan %s, 1)", leak.elemType))
 		findings = append(findings, finding)
 	}
 
+	// Point findings for named targets at the analyzed declaration as well
+	if context.calleePosition.IsValid() {
+		for i := range findings {
+			findings[i].AddMetadata("callee", context.functionName)
+			findings[i].AddMetadata("callee_location", context.calleePosition.String())
+			if len(context.bindings) > 0 {
+				findings[i].AddMetadata("bindings", context.bindings)
+			}
+		}
+	}
+
 	return findings
-}
\ No newline at end of file
+}
+
+// sendLeakStep is one statement list on the path from a function body to a go statement
+type sendLeakStep struct {
+	list  []ast.Stmt
+	index int
+}
+
+// findUnbufferedSendLeak reports when the goroutine sends once on an unbuffered channel made by
+// its parent and the parent has a path that leaves the channel's scope without receiving
+func (gld *ASTGoroutineLeakDetector) findUnbufferedSendLeak(site goroutineSite, pkg *PackageContext) *unbufferedSendLeak {
+	path := gld.pathToStmt(site.parentBody.List, site.goStmt)
+	if path == nil {
+		return nil
+	}
+
+	// The channel must be made earlier on the path, with no loop in between
+	channel, chanType, makeLevel := "", (*ast.ChanType)(nil), -1
+	for level := len(path) - 1; level >= 0 && makeLevel < 0; level-- {
+		step := path[level]
+		if level < len(path)-1 {
+			switch step.list[step.index].(type) {
+			case *ast.ForStmt, *ast.RangeStmt:
+				return nil
+			}
+		}
+		for _, stmt := range step.list[:step.index] {
+			for name, candidate := range gld.unbufferedChannels(stmt) {
+				if gld.goroutineSendsOnce(site.goStmt, name, pkg) {
+					channel, chanType, makeLevel = name, candidate, level
+				}
+			}
+		}
+	}
+	if makeLevel < 0 || !gld.onlyReceivedByParent(site, channel) {
+		return nil
+	}
+
+	scan := &sendLeakScan{channel: channel}
+	for _, step := range path[makeLevel:] {
+		for _, stmt := range step.list[:step.index] {
+			if deferStmt, ok := stmt.(*ast.DeferStmt); ok && scan.receivesDeep(deferStmt.Call) {
+				return nil
+			}
+		}
+	}
+
+	received, live := false, true
+	for level := len(path) - 1; level >= makeLevel && live && !received; level-- {
+		step := path[level]
+		received, live = scan.stmts(step.list[step.index+1:], false)
+		if scan.unknown {
+			return nil
+		}
+	}
+
+	exit := scan.exit
+	if !exit.IsValid() && live && !received {
+		// Falling off the end of the scope that declared the channel
+		exit = site.parentBody.Rbrace
+		if makeLevel > 0 {
+			outer := path[makeLevel-1]
+			exit = outer.list[outer.index].End()
+		}
+	}
+	if !exit.IsValid() {
+		return nil
+	}
+
+	return &unbufferedSendLeak{
+		channel:    channel,
+		elemType:   types.ExprString(chanType.Value),
+		parentName: site.parentName,
+		exit:       pkg.FileSet().Position(exit),
+	}
+}
+
+// pathToStmt returns the statement lists enclosing target, outermost first
+func (gld *ASTGoroutineLeakDetector) pathToStmt(list []ast.Stmt, target ast.Stmt) []sendLeakStep {
+	for i, stmt := range list {
+		if stmt == target {
+			return []sendLeakStep{{list: list, index: i}}
+		}
+		if target.Pos() < stmt.Pos() || target.End() > stmt.End() {
+			continue
+		}
+		for _, child := range childStmtLists(stmt) {
+			if rest := gld.pathToStmt(child, target); rest != nil {
+				return append([]sendLeakStep{{list: list, index: i}}, rest...)
+			}
+		}
+	}
+	return nil
+}
+
+// childStmtLists returns the statement lists nested directly in stmt
+func childStmtLists(stmt ast.Stmt) [][]ast.Stmt {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		return [][]ast.Stmt{s.List}
+	case *ast.LabeledStmt:
+		return [][]ast.Stmt{{s.Stmt}}
+	case *ast.IfStmt:
+		lists := [][]ast.Stmt{s.Body.List}
+		if s.Else != nil {
+			lists = append(lists, []ast.Stmt{s.Else})
+		}
+		return lists
+	case *ast.ForStmt:
+		return [][]ast.Stmt{s.Body.List}
+	case *ast.RangeStmt:
+		return [][]ast.Stmt{s.Body.List}
+	case *ast.SwitchStmt:
+		return clauseBodies(s.Body)
+	case *ast.TypeSwitchStmt:
+		return clauseBodies(s.Body)
+	case *ast.SelectStmt:
+		return clauseBodies(s.Body)
+	}
+	return nil
+}
+
+// clauseBodies returns the bodies of the case or comm clauses of a switch or select
+func clauseBodies(body *ast.BlockStmt) [][]ast.Stmt {
+	var lists [][]ast.Stmt
+	for _, clause := range body.List {
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			lists = append(lists, c.Body)
+		case *ast.CommClause:
+			lists = append(lists, c.Body)
+		}
+	}
+	return lists
+}
+
+// unbufferedChannels returns the channels declared by stmt with make(chan T) or make(chan T, 0)
+func (gld *ASTGoroutineLeakDetector) unbufferedChannels(stmt ast.Stmt) map[string]*ast.ChanType {
+	channels := make(map[string]*ast.ChanType)
+
+	var names []*ast.Ident
+	var values []ast.Expr
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if s.Tok != token.DEFINE || len(s.Lhs) != len(s.Rhs) {
+			return channels
+		}
+		for _, lhs := range s.Lhs {
+			ident, _ := lhs.(*ast.Ident)
+			names = append(names, ident)
+		}
+		values = s.Rhs
+	case *ast.DeclStmt:
+		genDecl, ok := s.Decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			return channels
+		}
+		for _, spec := range genDecl.Specs {
+			if valueSpec, ok := spec.(*ast.ValueSpec); ok && len(valueSpec.Names) == len(valueSpec.Values) {
+				names = append(names, valueSpec.Names...)
+				values = append(values, valueSpec.Values...)
+			}
+		}
+	}
+
+	for i, value := range values {
+		call, ok := value.(*ast.CallExpr)
+		if !ok || names[i] == nil || names[i].Name == "_" {
+			continue
+		}
+		if fun, ok := call.Fun.(*ast.Ident); !ok || fun.Name != "make" || len(call.Args) == 0 {
+			continue
+		}
+		chanType, ok := call.Args[0].(*ast.ChanType)
+		if !ok {
+			continue
+		}
+		if len(call.Args) > 1 {
+			if size, ok := call.Args[1].(*ast.BasicLit); !ok || size.Value != "0" {
+				continue
+			}
+		}
+		channels[names[i].Name] = chanType
+	}
+
+	return channels
+}
+
+// goroutineSendsOnce reports whether the goroutine target sends on the named channel exactly once,
+// outside loops and select statements, and otherwise only closes it
+func (gld *ASTGoroutineLeakDetector) goroutineSendsOnce(goStmt *ast.GoStmt, channel string, pkg *PackageContext) bool {
+	var funcType *ast.FuncType
+	var body *ast.BlockStmt
+	captured := false
+	if funcLit, ok := goStmt.Call.Fun.(*ast.FuncLit); ok {
+		funcType, body, captured = funcLit.Type, funcLit.Body, true
+	} else if funcDecl := pkg.ResolveCall(goStmt.Call); funcDecl != nil && funcDecl.Body != nil {
+		funcType, body = funcDecl.Type, funcDecl.Body
+	} else {
+		return false
+	}
+
+	// Names under which the goroutine sees the channel
+	names := make(map[string]bool)
+	for param, arg := range gld.bindParameters(funcType, goStmt.Call.Args) {
+		if arg == channel {
+			names[param] = true
+		}
+	}
+	if captured {
+		shadowed := false
+		for _, param := range flattenParams(funcType.Params) {
+			shadowed = shadowed || param.Name == channel
+		}
+		if !shadowed {
+			names[channel] = true
+		}
+	}
+	if len(names) == 0 {
+		return false
+	}
+
+	sends, valid := 0, true
+	var inspect func(n ast.Node, inLoop bool)
+	inspect = func(n ast.Node, inLoop bool) {
+		ast.Inspect(n, func(node ast.Node) bool {
+			if !valid {
+				return false
+			}
+			switch stmt := node.(type) {
+			case *ast.ForStmt, *ast.RangeStmt, *ast.FuncLit:
+				if node != n {
+					inspect(node, true)
+					return false
+				}
+			case *ast.SelectStmt:
+				// A send guarded by other cases does not block forever
+				for _, clause := range stmt.Body.List {
+					if comm, ok := clause.(*ast.CommClause); ok {
+						if send, ok := comm.Comm.(*ast.SendStmt); ok && identOf(send.Chan) != nil && names[identOf(send.Chan).Name] {
+							valid = false
+						}
+					}
+				}
+			case *ast.SendStmt:
+				if ident := identOf(stmt.Chan); ident != nil && names[ident.Name] {
+					if inLoop {
+						valid = false
+					}
+					sends++
+					ast.Inspect(stmt.Value, func(v ast.Node) bool {
+						if ident, ok := v.(*ast.Ident); ok && names[ident.Name] {
+							valid = false
+						}
+						return valid
+					})
+					return false
+				}
+			case *ast.CallExpr:
+				if fun, ok := stmt.Fun.(*ast.Ident); ok && fun.Name == "close" && len(stmt.Args) == 1 {
+					if ident := identOf(stmt.Args[0]); ident != nil && names[ident.Name] {
+						return false
+					}
+				}
+			case *ast.SelectorExpr:
+				inspect(stmt.X, inLoop)
+				return false
+			case *ast.Ident:
+				if names[stmt.Name] {
+					valid = false
+				}
+			}
+			return true
+		})
+	}
+	inspect(body, false)
+
+	return valid && sends == 1
+}
+
+// onlyReceivedByParent reports whether the parent uses the channel for nothing but starting
+// the goroutine and receiving, so no other party can drain it
+func (gld *ASTGoroutineLeakDetector) onlyReceivedByParent(site goroutineSite, channel string) bool {
+	allowed := make(map[*ast.Ident]bool)
+	valid := true
+
+	ast.Inspect(site.parentBody, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			if node != site.goStmt {
+				// Another goroutine touching the channel may drain it
+				ast.Inspect(node, func(inner ast.Node) bool {
+					if ident, ok := inner.(*ast.Ident); ok && ident.Name == channel {
+						valid = false
+					}
+					return valid
+				})
+				return false
+			}
+
+			// The goroutine's own uses were checked against its body
+			for _, arg := range node.Call.Args {
+				if ident := identOf(arg); ident != nil {
+					allowed[ident] = true
+				}
+			}
+			if funcLit, ok := node.Call.Fun.(*ast.FuncLit); ok {
+				ast.Inspect(funcLit, func(inner ast.Node) bool {
+					if ident, ok := inner.(*ast.Ident); ok {
+						allowed[ident] = true
+					}
+					return true
+				})
+			}
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				for _, lhs := range node.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && ident.Name == channel && ident.Obj != nil && ident.Obj.Decl == node {
+						allowed[ident] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, ident := range node.Names {
+				allowed[ident] = true
+			}
+		case *ast.UnaryExpr:
+			if ident := identOf(node.X); node.Op == token.ARROW && ident != nil {
+				allowed[ident] = true
+			}
+		case *ast.RangeStmt:
+			if ident := identOf(node.X); ident != nil {
+				allowed[ident] = true
+			}
+		case *ast.CallExpr:
+			if fun, ok := node.Fun.(*ast.Ident); ok && (fun.Name == "len" || fun.Name == "cap") && len(node.Args) == 1 {
+				if ident := identOf(node.Args[0]); ident != nil {
+					allowed[ident] = true
+				}
+			}
+		case *ast.SelectorExpr:
+			allowed[node.Sel] = true
+		case *ast.Ident:
+			if node.Name == channel && !allowed[node] {
+				valid = false
+			}
+		}
+		return valid
+	})
+
+	return valid
+}
+
+// sendLeakScan follows the parent's statements after a go statement looking for a path
+// that exits without receiving from the channel
+type sendLeakScan struct {
+	channel   string
+	exit      token.Pos
+	unknown   bool
+	breaks    []*branchState
+	continues []*branchState
+}
+
+// branchState merges the receive state of the break or continue statements targeting one construct
+type branchState struct {
+	taken    bool
+	received bool
+}
+
+// merge records one more path reaching the construct
+func (bs *branchState) merge(received bool) {
+	if !bs.taken {
+		bs.taken, bs.received = true, received
+		return
+	}
+	bs.received = bs.received && received
+}
+
+// stmts scans a statement list and returns whether every path completing it has received
+// and whether any path completes it at all
+func (scan *sendLeakScan) stmts(list []ast.Stmt, received bool) (bool, bool) {
+	live := true
+	for _, stmt := range list {
+		if received || !live || scan.unknown || scan.exit.IsValid() {
+			break
+		}
+		received, live = scan.stmt(stmt)
+	}
+	return received, live
+}
+
+// stmt scans one statement reached without a receive
+func (scan *sendLeakScan) stmt(stmt ast.Stmt) (bool, bool) {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		for _, result := range s.Results {
+			if scan.receives(result) {
+				return true, false
+			}
+		}
+		scan.exit = s.Pos()
+		return false, false
+	case *ast.BranchStmt:
+		targets := scan.breaks
+		if s.Tok == token.CONTINUE {
+			targets = scan.continues
+		}
+		if s.Label != nil || s.Tok == token.GOTO || s.Tok == token.FALLTHROUGH || len(targets) == 0 {
+			scan.unknown = true
+			return false, false
+		}
+		targets[len(targets)-1].merge(false)
+		return false, false
+	case *ast.BlockStmt:
+		return scan.stmts(s.List, false)
+	case *ast.LabeledStmt:
+		return scan.stmt(s.Stmt)
+	case *ast.DeferStmt:
+		// A deferred receive runs on every exit
+		return scan.receivesDeep(s.Call), true
+	case *ast.ExprStmt:
+		if scan.receives(s.X) {
+			return true, true
+		}
+		return false, !isTerminatingStmt(s)
+	case *ast.IfStmt:
+		if s.Init != nil && scan.receives(s.Init) || scan.receives(s.Cond) {
+			return true, true
+		}
+		bodyReceived, bodyLive := scan.stmts(s.Body.List, false)
+		elseReceived, elseLive := false, true
+		if s.Else != nil {
+			elseReceived, elseLive = scan.stmt(s.Else)
+		}
+		return mergeBranches([]bool{bodyReceived, elseReceived}, []bool{bodyLive, elseLive})
+	case *ast.SwitchStmt:
+		if s.Init != nil && scan.receives(s.Init) || s.Tag != nil && scan.receives(s.Tag) {
+			return true, true
+		}
+		return scan.clauses(s.Body, false)
+	case *ast.TypeSwitchStmt:
+		if s.Init != nil && scan.receives(s.Init) || scan.receives(s.Assign) {
+			return true, true
+		}
+		return scan.clauses(s.Body, false)
+	case *ast.SelectStmt:
+		return scan.clauses(s.Body, true)
+	case *ast.ForStmt:
+		if s.Init != nil && scan.receives(s.Init) {
+			return true, true
+		}
+		return scan.loop(s.Body, s.Cond == nil)
+	case *ast.RangeStmt:
+		if ident := identOf(s.X); ident != nil && ident.Name == scan.channel {
+			return true, true
+		}
+		return scan.loop(s.Body, false)
+	}
+	return scan.receives(stmt), true
+}
+
+// clauses scans the cases of a switch or select; a select always takes one of its cases
+func (scan *sendLeakScan) clauses(body *ast.BlockStmt, isSelect bool) (bool, bool) {
+	breaks := &branchState{}
+	scan.breaks = append(scan.breaks, breaks)
+	defer func() { scan.breaks = scan.breaks[:len(scan.breaks)-1] }()
+
+	var received, live []bool
+	hasDefault := false
+	for _, clause := range body.List {
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			hasDefault = hasDefault || c.List == nil
+			r, l := scan.stmts(c.Body, false)
+			received, live = append(received, r), append(live, l)
+		case *ast.CommClause:
+			hasDefault = hasDefault || c.Comm == nil
+			r, l := scan.stmts(c.Body, c.Comm != nil && scan.receives(c.Comm))
+			received, live = append(received, r), append(live, l)
+		}
+	}
+	if !isSelect && !hasDefault {
+		received, live = append(received, false), append(live, true)
+	}
+	if breaks.taken {
+		received, live = append(received, breaks.received), append(live, true)
+	}
+
+	return mergeBranches(received, live)
+}
+
+// loop scans a loop body; only an infinite loop guarantees its body ran before it completes
+func (scan *sendLeakScan) loop(body *ast.BlockStmt, infinite bool) (bool, bool) {
+	breaks, continues := &branchState{}, &branchState{}
+	scan.breaks = append(scan.breaks, breaks)
+	scan.continues = append(scan.continues, continues)
+	defer func() {
+		scan.breaks = scan.breaks[:len(scan.breaks)-1]
+		scan.continues = scan.continues[:len(scan.continues)-1]
+	}()
+
+	scan.stmts(body.List, false)
+	if infinite {
+		return breaks.taken && breaks.received, breaks.taken
+	}
+	return false, true
+}
+
+// mergeBranches combines alternative paths: all completing paths must have received
+func mergeBranches(received, live []bool) (bool, bool) {
+	anyLive, allReceived := false, true
+	for i := range received {
+		if live[i] {
+			anyLive = true
+			allReceived = allReceived && received[i]
+		}
+	}
+	return anyLive && allReceived, anyLive
+}
+
+// receives reports whether node receives from the channel outside nested function literals
+func (scan *sendLeakScan) receives(node ast.Node) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch expr := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.UnaryExpr:
+			if ident := identOf(expr.X); expr.Op == token.ARROW && ident != nil && ident.Name == scan.channel {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// receivesDeep reports whether node receives from the channel, including inside function literals
+func (scan *sendLeakScan) receivesDeep(node ast.Node) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if expr, ok := n.(*ast.UnaryExpr); ok && expr.Op == token.ARROW {
+			if ident := identOf(expr.X); ident != nil && ident.Name == scan.channel {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
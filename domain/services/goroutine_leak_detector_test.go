@@ -133,12 +133,222 @@ func test() {
 			expectedTypes: []string{},
 		},
 		{
-			name: "Named function goroutine - no body analysis",
+			name: "Named function goroutine - empty body",
 			code: `
 package main
 func worker() {}
 func test() {
-	go worker() // Named function - no leak detection
+	go worker() // Named function with nothing to leak
+}`,
+			expectedLeaks: 0,
+			expectedTypes: []string{},
+		},
+		{
+			name: "Named function goroutine - receive leak in callee",
+			code: `
+package main
+func worker(ch chan int) {
+	for {
+		<-ch
+	}
+}
+func test() {
+	ch := make(chan int)
+	go worker(ch)
+}`,
+			expectedLeaks: 1,
+			expectedTypes: []string{"leak in worker: channel_receive_leak"},
+		},
+		{
+			name: "Method goroutine - select leak in callee",
+			code: `
+package main
+type server struct {
+	jobs    chan int
+	results chan int
+}
+func (s *server) run() {
+	select {
+	case <-s.jobs:
+	case <-s.results:
+	}
+}
+func test(s *server) {
+	go s.run()
+}`,
+			expectedLeaks: 2,
+			expectedTypes: []string{"channel_receive_leak", "leak in server.run: select_statement_leak"},
+		},
+		{
+			name: "Named function goroutine - done channel bound through parameter",
+			code: `
+package main
+func worker(jobs chan int, c chan struct{}) {
+	select {
+	case <-jobs:
+	case <-c:
+		return
+	}
+}
+func test() {
+	jobs := make(chan int)
+	done := make(chan struct{})
+	go worker(jobs, done)
+	close(done)
+}`,
+			expectedLeaks: 0,
+			expectedTypes: []string{},
+		},
+		{
+			name: "Function literal goroutine - context passed under another name",
+			code: `
+package main
+import "context"
+func test(parent context.Context, ch chan int) {
+	go func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v := <-ch:
+				_ = v
+			}
+		}
+	}(parent)
+}`,
+			expectedLeaks: 0,
+			expectedTypes: []string{},
+		},
+		{
+			name: "Named function goroutine - context passed under another name",
+			code: `
+package main
+import "context"
+func worker(ctx context.Context, ch chan int) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case v := <-ch:
+			_ = v
+		}
+	}
+}
+func test(c context.Context, ch chan int) {
+	go worker(c, ch)
+}`,
+			expectedLeaks: 0,
+			expectedTypes: []string{},
+		},
+		{
+			name: "Unbuffered result channel - parent returns on timeout",
+			code: `
+package main
+import "time"
+func fetch() string { return "" }
+func test() (string, error) {
+	ch := make(chan string)
+	go func() {
+		ch <- fetch()
+	}()
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-time.After(time.Second):
+		return "", nil
+	}
+}`,
+			expectedLeaks: 1,
+			expectedTypes: []string{"channel_send_leak", "test exits at line 14", "make(chan string, 1)"},
+		},
+		{
+			name: "Buffered result channel - no leak",
+			code: `
+package main
+import "time"
+func fetch() string { return "" }
+func test() (string, error) {
+	ch := make(chan string, 1)
+	go func() {
+		ch <- fetch()
+	}()
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-time.After(time.Second):
+		return "", nil
+	}
+}`,
+			expectedLeaks: 0,
+			expectedTypes: []string{},
+		},
+		{
+			name: "Unbuffered channel - early error return before receive",
+			code: `
+package main
+func load() int { return 0 }
+func test(err error) int {
+	results := make(chan int)
+	go worker(results)
+	if err != nil {
+		return 0
+	}
+	return <-results
+}
+func worker(out chan int) {
+	out <- load()
+}`,
+			expectedLeaks: 1,
+			expectedTypes: []string{"leak in worker: channel_send_leak", "'results'"},
+		},
+		{
+			name: "Unbuffered channel - received on every path",
+			code: `
+package main
+func load() int { return 0 }
+func test(err error) int {
+	results := make(chan int)
+	go func() {
+		results <- load()
+	}()
+	if err != nil {
+		<-results
+		return 0
+	}
+	return <-results
+}`,
+			expectedLeaks: 0,
+			expectedTypes: []string{},
+		},
+		{
+			name: "Unbuffered channel - deferred drain",
+			code: `
+package main
+func load() int { return 0 }
+func test(err error) {
+	results := make(chan int)
+	defer func() { <-results }()
+	go func() {
+		results <- load()
+	}()
+	if err != nil {
+		return
+	}
+}`,
+			expectedLeaks: 0,
+			expectedTypes: []string{},
+		},
+		{
+			name: "Unbuffered channel handed to caller - no leak",
+			code: `
+package main
+func load() int { return 0 }
+func test() chan int {
+	results := make(chan int)
+	go func() {
+		results <- load()
+	}()
+	return results
 }`,
 			expectedLeaks: 0,
 			expectedTypes: []string{},
@@ -222,6 +432,42 @@ func test() {
 	}
 }
 
+func TestGoroutineLeakDetector_DetectPackageLeaks(t *testing.T) {
+	sources := []string{`
+package workers
+func (p *pool) drain() {
+	for {
+		<-p.queue
+	}
+}`, `
+package workers
+type pool struct {
+	queue chan int
+}
+func start(p *pool) {
+	go p.drain()
+}`}
+
+	pkg := checkPackage(t, sources...)
+
+	detector := NewASTGoroutineLeakDetector()
+	config, _ := valueobjects.NewAnalysisConfiguration(10, 15, 50, true, valueobjects.SeverityWarning)
+	findings, err := detector.DetectPackageLeaks(pkg, config)
+	if err != nil {
+		t.Fatalf("DetectPackageLeaks failed: %v", err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 leak, got %d", len(findings))
+	}
+	if !stringContains(findings[0].Message(), "leak in pool.drain: channel_receive_leak") {
+		t.Errorf("Unexpected finding: %s", findings[0].Message())
+	}
+	if _, ok := findings[0].Metadata()["callee_location"]; !ok {
+		t.Errorf("Expected callee_location metadata on finding")
+	}
+}
+
 func stringContains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"sort"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+// HotspotRanker combines change history with complexity to rank hotspots
+type HotspotRanker interface {
+	RankHotspots(history map[string][]valueobjects.FileRevision, metrics []valueobjects.FunctionMetrics) ([]valueobjects.Hotspot, error)
+}
+
+// ChurnHotspotRanker implements HotspotRanker by scoring revisions times cyclomatic complexity.
+// Function revisions are found by tracing each function's current line range back through
+// the hunks of every older revision of its file
+type ChurnHotspotRanker struct{}
+
+// NewChurnHotspotRanker creates a new churn-based hotspot ranker
+func NewChurnHotspotRanker() *ChurnHotspotRanker {
+	return &ChurnHotspotRanker{}
+}
+
+// RankHotspots ranks files and functions by score, highest first; history maps each file to its
+// revisions newest first. Code without revisions in the history is not a hotspot
+func (r *ChurnHotspotRanker) RankHotspots(history map[string][]valueobjects.FileRevision, metrics []valueobjects.FunctionMetrics) ([]valueobjects.Hotspot, error) {
+	var files []string
+	functionsByFile := make(map[string][]valueobjects.FunctionMetrics)
+	for _, function := range metrics {
+		path := function.Location().FilePath()
+		if _, ok := functionsByFile[path]; !ok {
+			files = append(files, path)
+		}
+		functionsByFile[path] = append(functionsByFile[path], function)
+	}
+
+	var hotspots []valueobjects.Hotspot
+	for _, path := range files {
+		revisions := history[path]
+		if len(revisions) == 0 {
+			continue
+		}
+		functions := functionsByFile[path]
+
+		complexity := 0
+		for _, function := range functions {
+			complexity += function.Complexity().Cyclomatic()
+		}
+		location, err := valueobjects.NewSourceLocation(path, 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		fileHotspot, err := valueobjects.NewHotspot(valueobjects.HotspotKindFile, path, location,
+			len(revisions), countAuthors(revisions), complexity)
+		if err != nil {
+			return nil, err
+		}
+		hotspots = append(hotspots, fileHotspot)
+
+		for i, touching := range functionRevisions(revisions, functions) {
+			if len(touching) == 0 {
+				continue
+			}
+			function := functions[i]
+			hotspot, err := valueobjects.NewHotspot(valueobjects.HotspotKindFunction, function.Name(), function.Location(),
+				len(touching), countAuthors(touching), function.Complexity().Cyclomatic())
+			if err != nil {
+				return nil, err
+			}
+			hotspots = append(hotspots, hotspot)
+		}
+	}
+
+	sort.SliceStable(hotspots, func(i, j int) bool {
+		return hotspots[i].Score() > hotspots[j].Score()
+	})
+
+	return hotspots, nil
+}
+
+// lineRange is an inclusive line range; it is empty once start passes end
+type lineRange struct {
+	start int
+	end   int
+}
+
+// functionRevisions returns, for each function, the revisions whose hunks touch its lines
+func functionRevisions(revisions []valueobjects.FileRevision, functions []valueobjects.FunctionMetrics) [][]valueobjects.FileRevision {
+	ranges := make([]lineRange, len(functions))
+	for i, function := range functions {
+		start := function.Location().Line()
+		ranges[i] = lineRange{start: start, end: start + function.Lines().Physical() - 1}
+	}
+
+	touching := make([][]valueobjects.FileRevision, len(functions))
+	for _, revision := range revisions {
+		hunks := revision.Hunks()
+		for i, lines := range ranges {
+			if lines.start <= lines.end && hunksTouch(hunks, lines) {
+				touching[i] = append(touching[i], revision)
+			}
+		}
+
+		// Older revisions belong to code that no longer exists
+		if revision.Created() {
+			break
+		}
+		for i := range ranges {
+			ranges[i] = lineRange{start: parentLine(hunks, ranges[i].start, true), end: parentLine(hunks, ranges[i].end, false)}
+		}
+	}
+
+	return touching
+}
+
+// hunksTouch reports whether any hunk changes the range
+func hunksTouch(hunks []valueobjects.DiffHunk, lines lineRange) bool {
+	for _, hunk := range hunks {
+		if hunk.Touches(lines.start, lines.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// parentLine maps a line of a revision to the file before it. A line added by a hunk maps to the
+// start or end of the lines it replaced, so a range made entirely of added lines becomes empty
+func parentLine(hunks []valueobjects.DiffHunk, line int, start bool) int {
+	offset := 0
+	for _, hunk := range hunks {
+		if hunk.NewLines() > 0 && line >= hunk.NewStart() && line < hunk.NewStart()+hunk.NewLines() {
+			switch {
+			case hunk.OldLines() == 0 && start:
+				return hunk.OldStart() + 1
+			case hunk.OldLines() == 0:
+				return hunk.OldStart()
+			case start:
+				return hunk.OldStart()
+			default:
+				return hunk.OldStart() + hunk.OldLines() - 1
+			}
+		}
+
+		last := hunk.NewStart() + hunk.NewLines() - 1
+		if hunk.NewLines() == 0 {
+			last = hunk.NewStart()
+		}
+		if line <= last {
+			break
+		}
+		offset += hunk.OldLines() - hunk.NewLines()
+	}
+	return line + offset
+}
+
+// countAuthors counts the distinct authors of revisions
+func countAuthors(revisions []valueobjects.FileRevision) int {
+	authors := make(map[string]bool)
+	for _, revision := range revisions {
+		authors[revision.Author()] = true
+	}
+	return len(authors)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestHotspotRanker_RankHotspots(t *testing.T) {
+	// parse spans lines 10-20 and format spans lines 30-40 of main.go at the newest revision
+	functions := []valueobjects.FunctionMetrics{
+		testFunctionMetrics(t, "parse", "main.go", 10, 11, 5),
+		testFunctionMetrics(t, "format", "main.go", 30, 11, 2),
+		testFunctionMetrics(t, "helper", "util.go", 1, 5, 3),
+	}
+
+	tests := []struct {
+		name              string
+		revisions         []valueobjects.FileRevision
+		expectedRevisions map[string]int
+	}{
+		{
+			name: "Hunks inside and outside functions",
+			revisions: []valueobjects.FileRevision{
+				testRevision(t, "alice", false, [4]int{12, 1, 12, 2}),
+				testRevision(t, "bob", false, [4]int{1, 0, 1, 3}, [4]int{35, 1, 38, 1}),
+			},
+			expectedRevisions: map[string]int{"main.go": 2, "parse": 1, "format": 1},
+		},
+		{
+			name: "Pure deletions count only strictly inside a function",
+			revisions: []valueobjects.FileRevision{
+				testRevision(t, "alice", false, [4]int{21, 4, 20, 0}),
+				testRevision(t, "bob", false, [4]int{16, 2, 15, 0}),
+			},
+			expectedRevisions: map[string]int{"main.go": 2, "parse": 1},
+		},
+		{
+			name: "Line ranges are traced back through earlier hunks",
+			revisions: []valueobjects.FileRevision{
+				// Removes two lines above both functions, which sat two lines lower before:
+				// line 31 was above format and line 21 was inside parse
+				testRevision(t, "alice", false, [4]int{3, 2, 2, 0}),
+				testRevision(t, "bob", false, [4]int{31, 1, 31, 1}),
+				testRevision(t, "carol", false, [4]int{21, 1, 21, 1}),
+			},
+			expectedRevisions: map[string]int{"main.go": 3, "parse": 1, "format": 0},
+		},
+		{
+			name: "A function added by a revision has no older history",
+			revisions: []valueobjects.FileRevision{
+				testRevision(t, "alice", false, [4]int{28, 0, 29, 12}),
+				testRevision(t, "bob", false, [4]int{30, 1, 30, 1}),
+			},
+			expectedRevisions: map[string]int{"main.go": 2, "format": 1, "parse": 0},
+		},
+		{
+			name: "Revisions before the file was created are ignored",
+			revisions: []valueobjects.FileRevision{
+				testRevision(t, "alice", true, [4]int{0, 0, 1, 40}),
+				testRevision(t, "bob", false, [4]int{12, 1, 12, 1}),
+			},
+			expectedRevisions: map[string]int{"main.go": 2, "parse": 1, "format": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := map[string][]valueobjects.FileRevision{"main.go": tt.revisions}
+
+			hotspots, err := NewChurnHotspotRanker().RankHotspots(history, functions)
+			if err != nil {
+				t.Fatalf("RankHotspots failed: %v", err)
+			}
+
+			revisions := make(map[string]int)
+			for i, hotspot := range hotspots {
+				revisions[hotspot.Name()] = hotspot.Revisions()
+				if i > 0 && hotspot.Score() > hotspots[i-1].Score() {
+					t.Errorf("Hotspots not sorted by score: %v", hotspots)
+				}
+			}
+
+			if _, ok := revisions["helper"]; ok {
+				t.Errorf("Expected no hotspot for a file without history, got %v", hotspots)
+			}
+			for name, expected := range tt.expectedRevisions {
+				if revisions[name] != expected {
+					t.Errorf("Expected %d revisions for %s, got %d (%v)", expected, name, revisions[name], hotspots)
+				}
+			}
+		})
+	}
+}
+
+func TestHotspotRanker_ScoresAndAuthors(t *testing.T) {
+	functions := []valueobjects.FunctionMetrics{
+		testFunctionMetrics(t, "parse", "main.go", 10, 11, 5),
+		testFunctionMetrics(t, "format", "main.go", 30, 11, 2),
+	}
+	history := map[string][]valueobjects.FileRevision{
+		"main.go": {
+			testRevision(t, "alice", false, [4]int{12, 1, 12, 1}),
+			testRevision(t, "bob", false, [4]int{15, 1, 15, 1}, [4]int{32, 1, 32, 1}),
+			testRevision(t, "alice", false, [4]int{18, 1, 18, 1}),
+		},
+	}
+
+	hotspots, err := NewChurnHotspotRanker().RankHotspots(history, functions)
+	if err != nil {
+		t.Fatalf("RankHotspots failed: %v", err)
+	}
+
+	expected := []string{
+		"file main.go: score=21 (revisions=3, authors=2, complexity=7)",
+		"function parse: score=15 (revisions=3, authors=2, complexity=5)",
+		"function format: score=2 (revisions=1, authors=1, complexity=2)",
+	}
+	if len(hotspots) != len(expected) {
+		t.Fatalf("Expected %d hotspots, got %v", len(expected), hotspots)
+	}
+	for i, hotspot := range hotspots {
+		if hotspot.String() != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], hotspot.String())
+		}
+	}
+}
+
+// testFunctionMetrics creates metrics for a function spanning lines from start
+func testFunctionMetrics(t *testing.T, name, file string, start, lines, cyclomatic int) valueobjects.FunctionMetrics {
+	t.Helper()
+
+	location, _ := valueobjects.NewSourceLocation(file, start, 1)
+	complexity, _ := valueobjects.NewComplexityScore(cyclomatic, 0)
+	halstead, _ := valueobjects.NewHalsteadMetrics(1, 1, 1, 1)
+	loc, _ := valueobjects.NewLinesOfCode(lines, 1, 0)
+
+	metrics, err := valueobjects.NewFunctionMetrics(name, location, complexity, halstead, loc)
+	if err != nil {
+		t.Fatalf("Failed to create function metrics: %v", err)
+	}
+	return metrics
+}
+
+// testRevision creates a revision from hunk headers given as {oldStart, oldLines, newStart, newLines}
+func testRevision(t *testing.T, author string, created bool, headers ...[4]int) valueobjects.FileRevision {
+	t.Helper()
+
+	var hunks []valueobjects.DiffHunk
+	for _, header := range headers {
+		hunk, err := valueobjects.NewDiffHunk(header[0], header[1], header[2], header[3])
+		if err != nil {
+			t.Fatalf("Failed to create hunk: %v", err)
+		}
+		hunks = append(hunks, hunk)
+	}
+
+	revision, err := valueobjects.NewFileRevision(author+"-commit", author, time.Unix(0, 0), hunks, created)
+	if err != nil {
+		t.Fatalf("Failed to create revision: %v", err)
+	}
+	return revision
+}
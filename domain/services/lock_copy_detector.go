@@ -0,0 +1,376 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// LockCopyIssue represents the different ways a value holding a sync primitive gets copied
+type LockCopyIssue int
+
+const (
+	LockCopyValueReceiver LockCopyIssue = iota
+	LockCopyParameter
+	LockCopyRange
+	LockCopyReturn
+	LockCopyAssignment
+)
+
+// String returns a string representation of the lock copy issue
+func (lci LockCopyIssue) String() string {
+	switch lci {
+	case LockCopyValueReceiver:
+		return "value_receiver"
+	case LockCopyParameter:
+		return "copied_parameter"
+	case LockCopyRange:
+		return "range_copy"
+	case LockCopyReturn:
+		return "returned_by_value"
+	case LockCopyAssignment:
+		return "assignment_copy"
+	default:
+		return "unknown"
+	}
+}
+
+// LockCopyDetector detects values containing sync primitives that are copied
+type LockCopyDetector interface {
+	DetectLockCopies(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageLockCopies(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTLockCopyDetector implements LockCopyDetector using AST analysis
+type ASTLockCopyDetector struct{}
+
+// NewASTLockCopyDetector creates a new AST-based lock copy detector
+func NewASTLockCopyDetector() *ASTLockCopyDetector {
+	return &ASTLockCopyDetector{}
+}
+
+// lockCopyScan holds the lock-holding types of a package and the findings of one file
+type lockCopyScan struct {
+	pkg       *PackageContext
+	lockTypes map[string]string
+	findings  []entities.AnalysisFinding
+}
+
+// DetectLockCopies analyzes code for copied sync primitives
+func (lcd *ASTLockCopyDetector) DetectLockCopies(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	pkg := newNodePackageContext(node, fset)
+	scan := &lockCopyScan{pkg: pkg, lockTypes: lcd.discoverLockTypes([]ast.Node{node})}
+	scan.analyze(node)
+	return scan.findings, nil
+}
+
+// DetectPackageLockCopies analyzes every file of a package, so types declared in one file are known in the others
+func (lcd *ASTLockCopyDetector) DetectPackageLockCopies(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var nodes []ast.Node
+	for _, file := range pkg.Files() {
+		nodes = append(nodes, file)
+	}
+
+	scan := &lockCopyScan{pkg: pkg, lockTypes: lcd.discoverLockTypes(nodes)}
+	for _, node := range nodes {
+		scan.analyze(node)
+	}
+	return scan.findings, nil
+}
+
+// discoverLockTypes maps the struct types declared under nodes to the sync primitive they
+// hold by value, following fields of other local types until nothing changes
+func (lcd *ASTLockCopyDetector) discoverLockTypes(nodes []ast.Node) map[string]string {
+	structs := make(map[string]*ast.StructType)
+	for _, node := range nodes {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if typeSpec, ok := n.(*ast.TypeSpec); ok {
+				if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+					structs[typeSpec.Name.Name] = structType
+				}
+			}
+			return true
+		})
+	}
+
+	lockTypes := make(map[string]string)
+	for changed := true; changed; {
+		changed = false
+		for name, structType := range structs {
+			if _, known := lockTypes[name]; known {
+				continue
+			}
+			for _, field := range structType.Fields.List {
+				if lock := lockInTypeExpr(field.Type, lockTypes); lock != "" {
+					lockTypes[name] = lock
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	return lockTypes
+}
+
+// lockInTypeExpr names the sync primitive held by value in a type expression, using the
+// local lock-holding types discovered so far
+func lockInTypeExpr(expr ast.Expr, lockTypes map[string]string) string {
+	switch t := expr.(type) {
+	case *ast.ParenExpr:
+		return lockInTypeExpr(t.X, lockTypes)
+	case *ast.Ident:
+		return lockTypes[t.Name]
+	case *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		if syncPrimitiveKind(t) != "" {
+			return types.ExprString(primitiveTypeName(t))
+		}
+		if index, ok := t.(*ast.IndexExpr); ok {
+			return lockInTypeExpr(index.X, lockTypes)
+		}
+	case *ast.ArrayType:
+		// Slices share their elements; only fixed-size arrays copy them
+		if t.Len != nil {
+			return lockInTypeExpr(t.Elt, lockTypes)
+		}
+	case *ast.StructType:
+		for _, field := range t.Fields.List {
+			if lock := lockInTypeExpr(field.Type, lockTypes); lock != "" {
+				return lock
+			}
+		}
+	}
+	return ""
+}
+
+// primitiveTypeName strips type arguments from a generic sync type expression
+func primitiveTypeName(expr ast.Expr) ast.Expr {
+	switch generic := expr.(type) {
+	case *ast.IndexExpr:
+		return generic.X
+	case *ast.IndexListExpr:
+		return generic.X
+	}
+	return expr
+}
+
+// lockInType names the sync primitive held by value in a type checked by go/types
+func lockInType(t types.Type, visited map[types.Type]bool) string {
+	if visited[t] {
+		return ""
+	}
+	visited[t] = true
+
+	switch typ := t.(type) {
+	case *types.Named:
+		if obj := typ.Obj(); obj.Pkg() != nil && (obj.Pkg().Path() == "sync" || obj.Pkg().Path() == "sync/atomic") {
+			if syncTypeKind(obj.Pkg().Name(), obj.Name()) != "" {
+				return obj.Pkg().Name() + "." + obj.Name()
+			}
+		}
+		return lockInType(typ.Underlying(), visited)
+	case *types.Alias:
+		return lockInType(types.Unalias(typ), visited)
+	case *types.Struct:
+		for i := 0; i < typ.NumFields(); i++ {
+			if lock := lockInType(typ.Field(i).Type(), visited); lock != "" {
+				return lock
+			}
+		}
+	case *types.Array:
+		return lockInType(typ.Elem(), visited)
+	}
+	return ""
+}
+
+// lockOf names the sync primitive held by a value of the given type expression
+func (scan *lockCopyScan) lockOf(typeExpr ast.Expr) string {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if t := info.TypeOf(typeExpr); t != nil {
+			return lockInType(t, make(map[types.Type]bool))
+		}
+	}
+	return lockInTypeExpr(typeExpr, scan.lockTypes)
+}
+
+// analyze reports copies in every function under node
+func (scan *lockCopyScan) analyze(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			if fn.Recv != nil {
+				scan.checkReceiver(fn)
+			}
+			scan.checkSignature(fn.Type, fn.Name.Name)
+			if fn.Body != nil {
+				scan.checkBody(fn.Body, fn)
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// checkReceiver reports methods whose value receiver copies a lock on every call
+func (scan *lockCopyScan) checkReceiver(funcDecl *ast.FuncDecl) {
+	for _, field := range funcDecl.Recv.List {
+		if lock := scan.lockOf(field.Type); lock != "" {
+			scan.report(LockCopyValueReceiver, field.Pos(), funcDecl.Name.Name, types.ExprString(field.Type), lock,
+				fmt.Sprintf("method %s has a value receiver of type %s, so every call copies its %s; use a pointer receiver",
+					funcDecl.Name.Name, types.ExprString(field.Type), lock))
+		}
+	}
+}
+
+// checkSignature reports parameters and results that pass lock-holding values by value
+func (scan *lockCopyScan) checkSignature(funcType *ast.FuncType, funcName string) {
+	if funcType.Params != nil {
+		for _, field := range funcType.Params.List {
+			// A WaitGroup parameter by value is reported by the WaitGroup detector
+			if syncPrimitiveKind(field.Type) == "waitgroup" {
+				continue
+			}
+			if lock := scan.lockOf(field.Type); lock != "" {
+				scan.report(LockCopyParameter, field.Pos(), funcName, types.ExprString(field.Type), lock,
+					fmt.Sprintf("parameter of type %s is passed by value, copying its %s; pass a pointer", types.ExprString(field.Type), lock))
+			}
+		}
+	}
+
+	if funcType.Results != nil {
+		for _, field := range funcType.Results.List {
+			if lock := scan.lockOf(field.Type); lock != "" {
+				scan.report(LockCopyReturn, field.Pos(), funcName, types.ExprString(field.Type), lock,
+					fmt.Sprintf("result of type %s is returned by value, copying its %s; return a pointer", types.ExprString(field.Type), lock))
+			}
+		}
+	}
+}
+
+// checkBody reports range loops, assignments and function literals that copy lock-holding values
+func (scan *lockCopyScan) checkBody(body *ast.BlockStmt, funcDecl *ast.FuncDecl) {
+	name := funcDecl.Name.Name
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			scan.checkSignature(stmt.Type, name)
+		case *ast.RangeStmt:
+			if ident, ok := stmt.Value.(*ast.Ident); ok && ident.Name != "_" {
+				if lock := scan.rangeElementLock(stmt, funcDecl); lock != "" {
+					scan.report(LockCopyRange, stmt.Value.Pos(), name, ident.Name, lock,
+						fmt.Sprintf("range variable '%s' copies each element and its %s; range over indexes or store pointers", ident.Name, lock))
+				}
+			}
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) == len(stmt.Rhs) {
+				scan.checkAssignment(stmt.Lhs, stmt.Rhs, name)
+			}
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(stmt.Names))
+			for i, ident := range stmt.Names {
+				lhs[i] = ident
+			}
+			if len(lhs) == len(stmt.Values) {
+				scan.checkAssignment(lhs, stmt.Values, name)
+			}
+		}
+		return true
+	})
+}
+
+// rangeElementLock names the sync primitive copied into the value variable of a range loop
+func (scan *lockCopyScan) rangeElementLock(stmt *ast.RangeStmt, funcDecl *ast.FuncDecl) string {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if t := info.TypeOf(stmt.Value); t != nil {
+			return lockInType(t, make(map[types.Type]bool))
+		}
+	}
+
+	// Without types, follow the declared type of a ranged parameter or variable
+	ident, ok := stmt.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	declared := declaredType(funcDecl, ident.Name)
+	switch t := declared.(type) {
+	case *ast.ArrayType:
+		return lockInTypeExpr(t.Elt, scan.lockTypes)
+	case *ast.MapType:
+		return lockInTypeExpr(t.Value, scan.lockTypes)
+	}
+	return ""
+}
+
+// declaredType finds the explicit type of a parameter or variable declared in a function
+func declaredType(funcDecl *ast.FuncDecl, name string) ast.Expr {
+	var found ast.Expr
+	ast.Inspect(funcDecl, func(n ast.Node) bool {
+		switch decl := n.(type) {
+		case *ast.Field:
+			for _, ident := range decl.Names {
+				if ident.Name == name {
+					found = decl.Type
+				}
+			}
+		case *ast.ValueSpec:
+			for _, ident := range decl.Names {
+				if ident.Name == name && decl.Type != nil {
+					found = decl.Type
+				}
+			}
+		}
+		return found == nil
+	})
+	return found
+}
+
+// checkAssignment reports assignments copying an existing lock-holding value; needs type information
+func (scan *lockCopyScan) checkAssignment(lhs, rhs []ast.Expr, funcName string) {
+	info := scan.pkg.TypesInfo()
+	if info == nil {
+		return
+	}
+
+	for i, value := range rhs {
+		if ident, ok := lhs[i].(*ast.Ident); ok && ident.Name == "_" {
+			continue
+		}
+		// Composite literals, calls and conversions produce fresh values
+		switch ast.Unparen(value).(type) {
+		case *ast.CompositeLit, *ast.CallExpr, *ast.FuncLit:
+			continue
+		}
+		t := info.TypeOf(value)
+		if t == nil {
+			continue
+		}
+		if lock := lockInType(t, make(map[types.Type]bool)); lock != "" {
+			target := types.ExprString(lhs[i])
+			scan.report(LockCopyAssignment, value.Pos(), funcName, target, lock,
+				fmt.Sprintf("assignment to '%s' copies %s and its %s; copy a pointer instead", target, types.ExprString(value), lock))
+		}
+	}
+}
+
+// report records a finding located at pos
+func (scan *lockCopyScan) report(issue LockCopyIssue, pos token.Pos, funcName, value, lock, description string) {
+	position := scan.pkg.FileSet().Position(pos)
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue.String(), funcName, position.Line, position.Column),
+		entities.FindingTypeBug,
+		location,
+		fmt.Sprintf("Copied sync primitive in %s: %s detected - %s", funcName, issue.String(), description),
+		valueobjects.SeverityError,
+	)
+	finding.AddMetadata("value", value)
+	finding.AddMetadata("primitive", lock)
+
+	scan.findings = append(scan.findings, finding)
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestLockCopyDetector_DetectLockCopies(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		expectedIssues int
+		expectedTypes  []string
+	}{
+		{
+			name: "Value receiver on type with mutex",
+			code: `
+package main
+import "sync"
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+func (c counter) Value() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"value_receiver", "sync.Mutex"},
+		},
+		{
+			name: "Pointer receiver - no issue",
+			code: `
+package main
+import "sync"
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+func (c *counter) Value() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Lock held through nested struct passed by value",
+			code: `
+package main
+import "sync"
+type guard struct {
+	sync.RWMutex
+}
+type registry struct {
+	guard
+	items map[string]int
+}
+func inspect(r registry) int {
+	return len(r.items)
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"copied_parameter", "sync.RWMutex"},
+		},
+		{
+			name: "Range over slice of structs holding atomics",
+			code: `
+package main
+import "sync/atomic"
+type stat struct {
+	hits atomic.Int64
+}
+func total(stats []stat) int64 {
+	var sum int64
+	for _, s := range stats {
+		sum += s.hits.Load()
+	}
+	return sum
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"range_copy", "atomic.Int64"},
+		},
+		{
+			name: "Range by index - no issue",
+			code: `
+package main
+import "sync/atomic"
+type stat struct {
+	hits atomic.Int64
+}
+func total(stats []stat) int64 {
+	var sum int64
+	for i := range stats {
+		sum += stats[i].hits.Load()
+	}
+	return sum
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Returning struct with sync.Once by value",
+			code: `
+package main
+import "sync"
+type lazy struct {
+	once  sync.Once
+	value int
+}
+func newLazy() lazy {
+	return lazy{}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"returned_by_value", "sync.Once"},
+		},
+		{
+			name: "Slice and pointer parameters - no issue",
+			code: `
+package main
+import "sync"
+type cache struct {
+	mu sync.Mutex
+}
+func flush(all []cache, one *cache, byName map[string]*cache) {}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "WaitGroup parameter left to the WaitGroup detector",
+			code: `
+package main
+import "sync"
+func worker(wg sync.WaitGroup) {}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			detector := NewASTLockCopyDetector()
+			config := valueobjects.DefaultAnalysisConfiguration()
+			findings, err := detector.DetectLockCopies(node, fset, config)
+			if err != nil {
+				t.Fatalf("DetectLockCopies failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+		})
+	}
+}
+
+func TestLockCopyDetector_DetectPackageLockCopies(t *testing.T) {
+	sources := []string{`
+package store
+import "sync"
+type Store struct {
+	mu   sync.Mutex
+	data map[string]string
+}`, `
+package store
+func snapshot(s *Store) map[string]string {
+	copied := *s
+	return copied.data
+}`}
+
+	pkg := checkPackage(t, sources...)
+
+	detector := NewASTLockCopyDetector()
+	findings, err := detector.DetectPackageLockCopies(pkg, valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("DetectPackageLockCopies failed: %v", err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 issue, got %d", len(findings))
+	}
+	if !strings.Contains(findings[0].Message(), "assignment to 'copied' copies *s and its sync.Mutex") {
+		t.Errorf("Unexpected finding: %s", findings[0].Message())
+	}
+}
@@ -0,0 +1,553 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"go/version"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// perIterationLoopVersion is the first language version giving each loop iteration its own variables
+const perIterationLoopVersion = "go1.22"
+
+// LoopHazardIssue represents the different hazards of goroutines and closures started in loops
+type LoopHazardIssue int
+
+const (
+	LoopVariableCapture LoopHazardIssue = iota
+	LoopSharedWrite
+	LoopUnboundedGoroutines
+)
+
+// String returns a string representation of the loop hazard issue
+func (lhi LoopHazardIssue) String() string {
+	switch lhi {
+	case LoopVariableCapture:
+		return "loop_variable_capture"
+	case LoopSharedWrite:
+		return "shared_collection_write"
+	case LoopUnboundedGoroutines:
+		return "unbounded_goroutines"
+	default:
+		return "unknown"
+	}
+}
+
+// LoopHazardDetector detects goroutines and deferred closures in loops that race on shared state
+type LoopHazardDetector interface {
+	DetectLoopHazards(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageLoopHazards(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTLoopHazardDetector implements LoopHazardDetector using AST analysis
+type ASTLoopHazardDetector struct{}
+
+// NewASTLoopHazardDetector creates a new AST-based loop hazard detector
+func NewASTLoopHazardDetector() *ASTLoopHazardDetector {
+	return &ASTLoopHazardDetector{}
+}
+
+// loopHazardScan holds the language version that applies to one file and its findings
+type loopHazardScan struct {
+	pkg           *PackageContext
+	goVersion     string
+	versionSource string
+	funcName      string
+	findings      []entities.AnalysisFinding
+}
+
+// DetectLoopHazards analyzes code for goroutines and closures started in loops
+func (lhd *ASTLoopHazardDetector) DetectLoopHazards(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	scan := newLoopHazardScan(newNodePackageContext(node, fset), node)
+	scan.analyze(node)
+	return scan.findings, nil
+}
+
+// DetectPackageLoopHazards analyzes every file of a package with the language version of its go.mod
+func (lhd *ASTLoopHazardDetector) DetectPackageLoopHazards(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var findings []entities.AnalysisFinding
+	for _, file := range pkg.Files() {
+		scan := newLoopHazardScan(pkg, file)
+		scan.analyze(file)
+		findings = append(findings, scan.findings...)
+	}
+	return findings, nil
+}
+
+// newLoopHazardScan resolves the language version of node: a //go:build version line
+// overrides the go.mod directive from go1.21 on, when file versions took effect
+func newLoopHazardScan(pkg *PackageContext, node ast.Node) *loopHazardScan {
+	scan := &loopHazardScan{pkg: pkg, goVersion: pkg.GoVersion(), versionSource: "go.mod"}
+
+	if file, ok := node.(*ast.File); ok && file.GoVersion != "" {
+		if scan.goVersion == "" || version.Compare(scan.goVersion, "go1.21") >= 0 {
+			scan.goVersion = version.Lang(file.GoVersion)
+			scan.versionSource = "//go:build"
+		}
+	}
+	return scan
+}
+
+// sharedLoopVariables reports whether loop variables are shared by all iterations;
+// unknown versions are not assumed either way
+func (scan *loopHazardScan) sharedLoopVariables() bool {
+	return version.IsValid(scan.goVersion) && version.Compare(scan.goVersion, perIterationLoopVersion) < 0
+}
+
+// versionNote names the language version that applies to the findings
+func (scan *loopHazardScan) versionNote() string {
+	if !version.IsValid(scan.goVersion) {
+		return "language version unknown"
+	}
+	return fmt.Sprintf("language version %s from %s", version.Lang(scan.goVersion), scan.versionSource)
+}
+
+// analyze checks every loop of the functions declared under node
+func (scan *loopHazardScan) analyze(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		funcDecl, ok := n.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			return true
+		}
+
+		scan.funcName = funcDecl.Name.Name
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			switch loop := n.(type) {
+			case *ast.RangeStmt:
+				scan.checkLoop(loop, loop.Body, rangeVariables(loop))
+			case *ast.ForStmt:
+				scan.checkLoop(loop, loop.Body, forVariables(loop))
+			}
+			return true
+		})
+		return false
+	})
+}
+
+// rangeVariables returns the variables declared by a range clause
+func rangeVariables(loop *ast.RangeStmt) []*ast.Ident {
+	if loop.Tok != token.DEFINE {
+		return nil
+	}
+	return declaredIdents([]ast.Expr{loop.Key, loop.Value})
+}
+
+// forVariables returns the variables declared by the init statement of a three-clause loop
+func forVariables(loop *ast.ForStmt) []*ast.Ident {
+	if assign, ok := loop.Init.(*ast.AssignStmt); ok && assign.Tok == token.DEFINE {
+		return declaredIdents(assign.Lhs)
+	}
+	return nil
+}
+
+// declaredIdents returns the named identifiers among exprs
+func declaredIdents(exprs []ast.Expr) []*ast.Ident {
+	var idents []*ast.Ident
+	for _, expr := range exprs {
+		if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+			idents = append(idents, ident)
+		}
+	}
+	return idents
+}
+
+// checkLoop inspects the go and defer statements started by one loop
+func (scan *loopHazardScan) checkLoop(loop ast.Node, body *ast.BlockStmt, vars []*ast.Ident) {
+	var firstGo *ast.GoStmt
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt, *ast.RangeStmt:
+			// Nested loops check their own statements; only captures of our variables matter here
+			scan.checkNestedCaptures(stmt, vars)
+			return false
+		case *ast.GoStmt:
+			if firstGo == nil {
+				firstGo = stmt
+			}
+			if lit, ok := ast.Unparen(stmt.Call.Fun).(*ast.FuncLit); ok {
+				scan.checkCaptures(lit, vars, "goroutine")
+				scan.checkSharedWrites(lit, loop)
+			}
+		case *ast.DeferStmt:
+			if lit, ok := ast.Unparen(stmt.Call.Fun).(*ast.FuncLit); ok {
+				scan.checkCaptures(lit, vars, "deferred closure")
+			}
+		}
+		return true
+	})
+
+	if firstGo != nil && scan.unbounded(loop) && !scan.throttled(body) {
+		scan.report(LoopUnboundedGoroutines, firstGo.Pos(), "", entities.FindingTypePerformance, valueobjects.SeverityWarning,
+			"the loop starts a goroutine per iteration with no bound on how many run at once; acquire a buffered channel semaphore before each go statement, use errgroup.SetLimit or feed a fixed pool of workers")
+	}
+}
+
+// checkNestedCaptures reports closures in a nested loop that capture the variables of an outer one
+func (scan *loopHazardScan) checkNestedCaptures(loop ast.Node, vars []*ast.Ident) {
+	if len(vars) == 0 {
+		return
+	}
+	ast.Inspect(loop, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.GoStmt:
+			if lit, ok := ast.Unparen(stmt.Call.Fun).(*ast.FuncLit); ok {
+				scan.checkCaptures(lit, vars, "goroutine")
+			}
+		case *ast.DeferStmt:
+			if lit, ok := ast.Unparen(stmt.Call.Fun).(*ast.FuncLit); ok {
+				scan.checkCaptures(lit, vars, "deferred closure")
+			}
+		}
+		return true
+	})
+}
+
+// checkCaptures reports loop variables referenced by a closure while they are shared by all iterations
+func (scan *loopHazardScan) checkCaptures(lit *ast.FuncLit, vars []*ast.Ident, kind string) {
+	if !scan.sharedLoopVariables() {
+		return
+	}
+
+	for _, loopVar := range vars {
+		declared := scan.declarationPos(loopVar)
+		if !declared.IsValid() {
+			continue
+		}
+
+		var use *ast.Ident
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && use == nil && ident.Name == loopVar.Name && scan.declarationPos(ident) == declared {
+				use = ident
+			}
+			return use == nil
+		})
+		if use == nil {
+			continue
+		}
+
+		consequence := "may observe a later iteration's value"
+		if kind == "deferred closure" {
+			consequence = "sees only the final value when it runs"
+		}
+		scan.report(LoopVariableCapture, use.Pos(), loopVar.Name, entities.FindingTypeBug, valueobjects.SeverityError,
+			fmt.Sprintf("%s started in the loop captures loop variable '%s', which every iteration shares before %s, and %s; pass it as an argument or copy it with %s := %s",
+				kind, loopVar.Name, perIterationLoopVersion, consequence, loopVar.Name, loopVar.Name))
+	}
+}
+
+// checkSharedWrites reports a goroutine writing a map or slice declared outside the loop without holding a lock
+func (scan *loopHazardScan) checkSharedWrites(lit *ast.FuncLit, loop ast.Node) {
+	if containsLockCall(lit.Body) {
+		return
+	}
+
+	reported := make(map[string]bool)
+	check := func(target ast.Expr, appended bool) {
+		var ident *ast.Ident
+		var index ast.Expr
+		switch t := ast.Unparen(target).(type) {
+		case *ast.IndexExpr:
+			ident, _ = ast.Unparen(t.X).(*ast.Ident)
+			index = t.Index
+		case *ast.Ident:
+			if !appended {
+				return
+			}
+			ident = t
+		}
+		if ident == nil || reported[ident.Name] || !scan.declaredOutside(ident, loop) {
+			return
+		}
+
+		var description string
+		switch kind := scan.collectionKind(ident); {
+		case appended && kind == "slice":
+			description = fmt.Sprintf("goroutines started in the loop append to shared slice '%s' concurrently; guard it with a mutex or collect results over a channel", ident.Name)
+		case index != nil && kind == "map":
+			description = fmt.Sprintf("goroutines started in the loop write shared map '%s' concurrently, which is a data race; guard it with a mutex or use sync.Map", ident.Name)
+		case index != nil && kind == "slice" && !scan.perIterationIndex(index, loop):
+			description = fmt.Sprintf("goroutines started in the loop write shared slice '%s' at an index that is the same for every goroutine; index it by a per-iteration value or guard it with a mutex", ident.Name)
+		default:
+			return
+		}
+		reported[ident.Name] = true
+		scan.report(LoopSharedWrite, target.Pos(), ident.Name, entities.FindingTypeBug, valueobjects.SeverityError, description)
+	}
+
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			if stmt.Tok == token.DEFINE {
+				return true
+			}
+			for i, lhs := range stmt.Lhs {
+				appended := false
+				if len(stmt.Lhs) == len(stmt.Rhs) {
+					appended = isSelfAppend(lhs, stmt.Rhs[i])
+				}
+				check(lhs, appended)
+			}
+		case *ast.IncDecStmt:
+			check(stmt.X, false)
+		}
+		return true
+	})
+}
+
+// isSelfAppend reports whether value is append(target, ...)
+func isSelfAppend(target, value ast.Expr) bool {
+	call, ok := ast.Unparen(value).(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	fun, ok := call.Fun.(*ast.Ident)
+	if !ok || fun.Name != "append" {
+		return false
+	}
+	targetIdent, ok := ast.Unparen(target).(*ast.Ident)
+	argIdent, argOk := ast.Unparen(call.Args[0]).(*ast.Ident)
+	return ok && argOk && targetIdent.Name == argIdent.Name
+}
+
+// containsLockCall reports whether body acquires a mutex
+func containsLockCall(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			switch calledMethod(call) {
+			case "Lock", "RLock":
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// calledMethod returns the selected name of a method or qualified call, or "" for other calls
+func calledMethod(call *ast.CallExpr) string {
+	if selector, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		return selector.Sel.Name
+	}
+	return ""
+}
+
+// declarationPos returns where the variable referenced by ident is declared, or NoPos if unresolved
+func (scan *loopHazardScan) declarationPos(ident *ast.Ident) token.Pos {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if obj := info.ObjectOf(ident); obj != nil {
+			if _, ok := obj.(*types.Var); ok {
+				return obj.Pos()
+			}
+			return token.NoPos
+		}
+	}
+	if ident.Obj != nil && ident.Obj.Kind == ast.Var {
+		return ident.Obj.Pos()
+	}
+	return token.NoPos
+}
+
+// declaredOutside reports whether ident names a variable declared before the loop started
+func (scan *loopHazardScan) declaredOutside(ident *ast.Ident, loop ast.Node) bool {
+	declared := scan.declarationPos(ident)
+	return declared.IsValid() && (declared < loop.Pos() || declared >= loop.End())
+}
+
+// perIterationIndex reports whether an index expression depends on a variable declared inside
+// the loop, so each goroutine addresses its own element
+func (scan *loopHazardScan) perIterationIndex(index ast.Expr, loop ast.Node) bool {
+	perIteration := false
+	ast.Inspect(index, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			declared := scan.declarationPos(ident)
+			if declared.IsValid() && declared >= loop.Pos() && declared < loop.End() {
+				perIteration = true
+			}
+		}
+		return !perIteration
+	})
+	return perIteration
+}
+
+// collectionKind classifies a variable as "map" or "slice", using types when available
+func (scan *loopHazardScan) collectionKind(ident *ast.Ident) string {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if obj := info.ObjectOf(ident); obj != nil {
+			switch obj.Type().Underlying().(type) {
+			case *types.Map:
+				return "map"
+			case *types.Slice:
+				return "slice"
+			}
+			return ""
+		}
+	}
+
+	if ident.Obj == nil {
+		return ""
+	}
+	switch decl := ident.Obj.Decl.(type) {
+	case *ast.ValueSpec:
+		if decl.Type != nil {
+			return typeExprKind(decl.Type)
+		}
+		for i, name := range decl.Names {
+			if name.Name == ident.Name && i < len(decl.Values) {
+				return valueExprKind(decl.Values[i])
+			}
+		}
+	case *ast.AssignStmt:
+		for i, lhs := range decl.Lhs {
+			if name, ok := lhs.(*ast.Ident); ok && name.Name == ident.Name && len(decl.Lhs) == len(decl.Rhs) {
+				return valueExprKind(decl.Rhs[i])
+			}
+		}
+	case *ast.Field:
+		return typeExprKind(decl.Type)
+	}
+	return ""
+}
+
+// typeExprKind classifies a type expression as "map" or "slice"
+func typeExprKind(expr ast.Expr) string {
+	switch t := ast.Unparen(expr).(type) {
+	case *ast.MapType:
+		return "map"
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "slice"
+		}
+	}
+	return ""
+}
+
+// valueExprKind classifies the value a variable is initialized with as "map" or "slice"
+func valueExprKind(expr ast.Expr) string {
+	switch v := ast.Unparen(expr).(type) {
+	case *ast.CompositeLit:
+		if v.Type != nil {
+			return typeExprKind(v.Type)
+		}
+	case *ast.CallExpr:
+		if fun, ok := v.Fun.(*ast.Ident); ok && fun.Name == "make" && len(v.Args) > 0 {
+			return typeExprKind(v.Args[0])
+		}
+	}
+	return ""
+}
+
+// unbounded reports whether the number of iterations depends on data rather than a constant
+func (scan *loopHazardScan) unbounded(loop ast.Node) bool {
+	switch l := loop.(type) {
+	case *ast.RangeStmt:
+		switch x := ast.Unparen(l.X).(type) {
+		case *ast.BasicLit, *ast.CompositeLit:
+			return false
+		case *ast.Ident:
+			if x.Obj == nil {
+				break
+			}
+			if x.Obj.Kind == ast.Con {
+				return false
+			}
+			if field, ok := x.Obj.Decl.(*ast.Field); ok {
+				if array, ok := field.Type.(*ast.ArrayType); ok && array.Len != nil {
+					return false
+				}
+			}
+		}
+		if info := scan.pkg.TypesInfo(); info != nil {
+			if tv, ok := info.Types[l.X]; ok {
+				if tv.Value != nil {
+					return false
+				}
+				t := tv.Type.Underlying()
+				if pointer, ok := t.(*types.Pointer); ok {
+					t = pointer.Elem().Underlying()
+				}
+				switch t := t.(type) {
+				case *types.Array:
+					return false
+				case *types.Basic:
+					// Ranging over an integer starts a counted set of workers
+					return t.Info()&types.IsInteger == 0
+				}
+			}
+		}
+		return true
+	case *ast.ForStmt:
+		// Counted loops start a fixed set of workers and endless loops are accept or event
+		// loops bounded elsewhere; only loops walking the length of a collection fan out per item
+		cond, ok := l.Cond.(*ast.BinaryExpr)
+		if !ok {
+			return false
+		}
+		switch cond.Op {
+		case token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return isLenCall(cond.X) || isLenCall(cond.Y)
+		}
+	}
+	return false
+}
+
+// isLenCall reports whether expr is a call to the len builtin
+func isLenCall(expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	fun, ok := call.Fun.(*ast.Ident)
+	return ok && fun.Name == "len"
+}
+
+// throttled reports whether the loop body blocks on a channel, semaphore, limiter or
+// wait group outside the goroutines, which bounds how many goroutines are in flight
+func (scan *loopHazardScan) throttled(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.SendStmt:
+			found = true
+		case *ast.UnaryExpr:
+			found = found || node.Op == token.ARROW
+		case *ast.CallExpr:
+			switch calledMethod(node) {
+			case "Acquire", "TryAcquire", "Wait":
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// report records a finding located at pos
+func (scan *loopHazardScan) report(issue LoopHazardIssue, pos token.Pos, variable string, findingType entities.FindingType, severity valueobjects.SeverityLevel, description string) {
+	position := scan.pkg.FileSet().Position(pos)
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue.String(), scan.funcName, position.Line, position.Column),
+		findingType,
+		location,
+		fmt.Sprintf("Loop hazard in %s: %s detected - %s (%s)", scan.funcName, issue.String(), description, scan.versionNote()),
+		severity,
+	)
+	if variable != "" {
+		finding.AddMetadata("variable", variable)
+	}
+	finding.AddMetadata("go_version", scan.goVersion)
+
+	scan.findings = append(scan.findings, finding)
+}
@@ -0,0 +1,356 @@
+package services
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestLoopHazardDetector_DetectPackageLoopHazards(t *testing.T) {
+	tests := []struct {
+		name           string
+		goVersion      string
+		code           string
+		expectedIssues int
+		expectedTypes  []string
+	}{
+		{
+			name:      "Goroutine captures range variable before go1.22",
+			goVersion: "1.21",
+			code: `
+package main
+func process(items []string, results chan<- string) {
+	for _, item := range items {
+		go func() {
+			results <- item
+		}()
+	}
+}`,
+			expectedIssues: 2,
+			expectedTypes:  []string{"loop_variable_capture", "loop variable 'item'", "language version go1.21 from go.mod", "unbounded_goroutines"},
+		},
+		{
+			name:      "Same capture with per-iteration variables - only unbounded",
+			goVersion: "1.22",
+			code: `
+package main
+func process(items []string, results chan<- string) {
+	for _, item := range items {
+		go func() {
+			results <- item
+		}()
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"unbounded_goroutines", "language version go1.22 from go.mod"},
+		},
+		{
+			name:      "Loop variable copied before capture - no capture",
+			goVersion: "1.20",
+			code: `
+package main
+func process(results chan<- int) {
+	for i := 0; i < 10; i++ {
+		i := i
+		go func() {
+			results <- i
+		}()
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name:      "Loop variable passed as argument - no capture",
+			goVersion: "1.20",
+			code: `
+package main
+func process(results chan<- int) {
+	for i := 0; i < 10; i++ {
+		go func(n int) {
+			results <- n
+		}(i)
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name:      "Deferred closure captures loop variable",
+			goVersion: "1.18",
+			code: `
+package main
+import "os"
+func closeAll(files []*os.File) {
+	for _, f := range files {
+		defer func() {
+			f.Close()
+		}()
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"deferred closure", "sees only the final value", "go1.18"},
+		},
+		{
+			name:      "Build constraint raises file version",
+			goVersion: "1.21",
+			code: `//go:build go1.22
+
+package main
+func process(results chan<- int) {
+	for i := 0; i < 10; i++ {
+		go func() {
+			results <- i
+		}()
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name:      "Goroutines write shared map",
+			goVersion: "1.22",
+			code: `
+package main
+import "sync"
+func count(words []string) map[string]int {
+	counts := make(map[string]int)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, w := range words {
+				counts[w]++
+			}
+		}()
+	}
+	wg.Wait()
+	return counts
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"shared_collection_write", "shared map 'counts'", "sync.Map"},
+		},
+		{
+			name:      "Shared map guarded by mutex - no issue",
+			goVersion: "1.22",
+			code: `
+package main
+import "sync"
+func count(words []string) map[string]int {
+	counts := make(map[string]int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			counts[words[i]]++
+		}()
+	}
+	wg.Wait()
+	return counts
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name:      "Slice written at per-iteration index - no issue",
+			goVersion: "1.22",
+			code: `
+package main
+import "sync"
+func square(n int) []int {
+	out := make([]int, 8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i] = i * i
+		}()
+	}
+	wg.Wait()
+	return out
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name:      "Goroutines append to shared slice",
+			goVersion: "1.22",
+			code: `
+package main
+import "sync"
+func collect(parts [4]string) []string {
+	var out []string
+	var wg sync.WaitGroup
+	for _, p := range parts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out = append(out, p)
+		}()
+	}
+	wg.Wait()
+	return out
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"append to shared slice 'out'"},
+		},
+		{
+			name:      "Semaphore acquired before each goroutine - no issue",
+			goVersion: "1.22",
+			code: `
+package main
+func fetchAll(urls []string, fetch func(string)) {
+	sem := make(chan struct{}, 8)
+	for _, u := range urls {
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			fetch(u)
+		}()
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name:      "Receive followed by other unary expressions still throttles - no issue",
+			goVersion: "1.22",
+			code: `
+package main
+func handleAll(jobs []int, tokens chan int, weigh func(int, int) int, handle func(int)) {
+	for _, j := range jobs {
+		weight := weigh(<-tokens, -j)
+		go handle(weight)
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name:      "Endless accept loop - no issue",
+			goVersion: "1.22",
+			code: `
+package main
+import "net"
+func serve(l net.Listener, handle func(net.Conn)) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go handle(conn)
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name:      "Unknown version does not assume shared loop variables",
+			goVersion: "",
+			code: `
+package main
+func process(items []string, results chan<- string) {
+	for _, item := range items {
+		go func() {
+			results <- item
+		}()
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"unbounded_goroutines", "language version unknown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			pkg := NewPackageContext(fset, []*ast.File{node}, nil)
+			pkg.SetGoVersion(tt.goVersion)
+
+			detector := NewASTLoopHazardDetector()
+			config := valueobjects.DefaultAnalysisConfiguration()
+			findings, err := detector.DetectPackageLoopHazards(pkg, config)
+			if err != nil {
+				t.Fatalf("DetectPackageLoopHazards failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+		})
+	}
+}
+
+func TestLoopHazardDetector_TypedSharedSlice(t *testing.T) {
+	src := `
+package store
+type Store struct {
+	slots []string
+}
+func (s *Store) fill(names []string) {
+	slots := s.slots
+	for range names {
+		go func() {
+			slots[0] = "taken"
+		}()
+	}
+}`
+
+	pkg := checkPackage(t, src)
+
+	pkg.SetGoVersion("go1.23")
+
+	findings, err := NewASTLoopHazardDetector().DetectPackageLoopHazards(pkg, valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("DetectPackageLoopHazards failed: %v", err)
+	}
+
+	var messages []string
+	for _, finding := range findings {
+		messages = append(messages, finding.Message())
+	}
+	joined := strings.Join(messages, "\n")
+
+	if !strings.Contains(joined, "shared slice 'slots' at an index that is the same for every goroutine") {
+		t.Errorf("Expected shared slice write, got:\n%s", joined)
+	}
+	if !strings.Contains(joined, "unbounded_goroutines") {
+		t.Errorf("Expected unbounded goroutines, got:\n%s", joined)
+	}
+	for _, finding := range findings {
+		if finding.Metadata()["go_version"] != "go1.23" {
+			t.Errorf("Expected go_version metadata go1.23, got %v", finding.Metadata()["go_version"])
+		}
+	}
+}
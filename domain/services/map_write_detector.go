@@ -0,0 +1,632 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// MapWriteIssue represents the different ways a map ends up written concurrently
+type MapWriteIssue int
+
+const (
+	MapWriteUnguarded MapWriteIssue = iota
+	MapWriteGuardNotHeld
+)
+
+// String returns a string representation of the map write issue
+func (mwi MapWriteIssue) String() string {
+	switch mwi {
+	case MapWriteUnguarded:
+		return "unguarded_map_write"
+	case MapWriteGuardNotHeld:
+		return "guard_not_held"
+	default:
+		return "unknown"
+	}
+}
+
+// MapWriteDetector detects maps written from goroutines without a guarding lock
+type MapWriteDetector interface {
+	DetectMapWrites(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageMapWrites(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTMapWriteDetector implements MapWriteDetector using AST analysis
+type ASTMapWriteDetector struct{}
+
+// NewASTMapWriteDetector creates a new AST-based concurrent map write detector
+func NewASTMapWriteDetector() *ASTMapWriteDetector {
+	return &ASTMapWriteDetector{}
+}
+
+// mapScope tells where a map variable is declared
+type mapScope int
+
+const (
+	mapLocal mapScope = iota
+	mapField
+	mapGlobal
+)
+
+// mapDecl describes a map variable, keyed by the position of its declaring identifier
+type mapDecl struct {
+	name  string
+	scope mapScope
+	// guard names the mutex field declared immediately above a map field, which guards it by convention
+	guard string
+}
+
+// mapGoroutine is a goroutine together with the function bodies it runs
+type mapGoroutine struct {
+	stmt   *ast.GoStmt
+	owner  *ast.FuncDecl
+	inLoop bool
+	bodies []goroutineFunc
+}
+
+// goroutineFunc is a function body run by a goroutine and the name reported for it
+type goroutineFunc struct {
+	name string
+	body *ast.BlockStmt
+}
+
+// mapWrite is a write to a map found in a goroutine body
+type mapWrite struct {
+	target ast.Expr
+	pos    token.Pos
+}
+
+// mapWriteScan holds the map declarations of a package and the findings
+type mapWriteScan struct {
+	pkg          *PackageContext
+	maps         map[token.Pos]*mapDecl
+	fieldsByName map[string][]token.Pos
+	globals      map[string]token.Pos
+	imports      map[string]bool
+	funcs        []*ast.FuncDecl
+	findings     []entities.AnalysisFinding
+}
+
+// DetectMapWrites analyzes code for maps written concurrently
+func (mwd *ASTMapWriteDetector) DetectMapWrites(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	return mwd.detect(newNodePackageContext(node, fset), []ast.Node{node}), nil
+}
+
+// DetectPackageMapWrites analyzes every file of a package, following goroutines into methods declared elsewhere
+func (mwd *ASTMapWriteDetector) DetectPackageMapWrites(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var nodes []ast.Node
+	for _, file := range pkg.Files() {
+		nodes = append(nodes, file)
+	}
+	return mwd.detect(pkg, nodes), nil
+}
+
+// detect indexes the maps under nodes and checks the writes made by every goroutine
+func (mwd *ASTMapWriteDetector) detect(pkg *PackageContext, nodes []ast.Node) []entities.AnalysisFinding {
+	scan := &mapWriteScan{
+		pkg:          pkg,
+		maps:         make(map[token.Pos]*mapDecl),
+		fieldsByName: make(map[string][]token.Pos),
+		globals:      make(map[string]token.Pos),
+		imports:      make(map[string]bool),
+	}
+	for _, node := range nodes {
+		scan.collectMaps(node)
+	}
+
+	var goroutines []*mapGoroutine
+	for _, funcDecl := range scan.funcs {
+		goroutines = append(goroutines, scan.collectGoroutines(funcDecl)...)
+	}
+	if len(goroutines) == 0 {
+		return nil
+	}
+
+	concurrent := make(map[*ast.BlockStmt]bool)
+	for _, goroutine := range goroutines {
+		for _, fn := range goroutine.bodies {
+			concurrent[fn.body] = true
+		}
+	}
+
+	for _, goroutine := range goroutines {
+		scan.checkGoroutine(goroutine, goroutines, concurrent)
+	}
+	return scan.findings
+}
+
+// collectMaps records map-typed struct fields, package variables and locals, the import
+// names and the function declarations under node
+func (scan *mapWriteScan) collectMaps(node ast.Node) {
+	if file, ok := node.(*ast.File); ok {
+		for _, spec := range file.Imports {
+			name := path.Base(strings.Trim(spec.Path.Value, `"`))
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			scan.imports[name] = true
+		}
+		for _, decl := range file.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.VAR {
+				for _, spec := range genDecl.Specs {
+					for _, name := range scan.recordValueSpec(spec.(*ast.ValueSpec), mapGlobal) {
+						scan.globals[name.Name] = name.Pos()
+					}
+				}
+			}
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch decl := n.(type) {
+		case *ast.FuncDecl:
+			if decl.Body != nil {
+				scan.funcs = append(scan.funcs, decl)
+			}
+		case *ast.StructType:
+			scan.recordFields(decl)
+		case *ast.ValueSpec:
+			if _, known := scan.maps[decl.Names[0].Pos()]; !known {
+				scan.recordValueSpec(decl, mapLocal)
+			}
+		case *ast.AssignStmt:
+			if decl.Tok == token.DEFINE && len(decl.Lhs) == len(decl.Rhs) {
+				for i, lhs := range decl.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && valueExprKind(decl.Rhs[i]) == "map" {
+						scan.maps[ident.Pos()] = &mapDecl{name: ident.Name, scope: mapLocal}
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// recordFields records the map fields of a struct and the mutex declared right above each
+func (scan *mapWriteScan) recordFields(structType *ast.StructType) {
+	previousMutex := ""
+	for _, field := range structType.Fields.List {
+		if typeExprKind(field.Type) == "map" {
+			for _, name := range field.Names {
+				scan.maps[name.Pos()] = &mapDecl{name: name.Name, scope: mapField, guard: previousMutex}
+				scan.fieldsByName[name.Name] = append(scan.fieldsByName[name.Name], name.Pos())
+			}
+		}
+
+		previousMutex = ""
+		fieldType := field.Type
+		if star, ok := fieldType.(*ast.StarExpr); ok {
+			fieldType = star.X
+		}
+		if syncPrimitiveKind(fieldType) == "mutex" && len(field.Names) == 1 {
+			previousMutex = field.Names[0].Name
+		}
+	}
+}
+
+// recordValueSpec records the map variables of a declaration and returns their names
+func (scan *mapWriteScan) recordValueSpec(spec *ast.ValueSpec, scope mapScope) []*ast.Ident {
+	var recorded []*ast.Ident
+	for i, name := range spec.Names {
+		isMap := spec.Type != nil && typeExprKind(spec.Type) == "map"
+		if spec.Type == nil && i < len(spec.Values) {
+			isMap = valueExprKind(spec.Values[i]) == "map"
+		}
+		if isMap && name.Name != "_" {
+			scan.maps[name.Pos()] = &mapDecl{name: name.Name, scope: scope}
+			recorded = append(recorded, name)
+		}
+	}
+	return recorded
+}
+
+// resolveMap returns the declaration of the map denoted by expr, using types when available
+func (scan *mapWriteScan) resolveMap(expr ast.Expr) *mapDecl {
+	var ident *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	case *ast.Ident:
+		ident = e
+	default:
+		return nil
+	}
+
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if obj := info.Uses[ident]; obj != nil {
+			variable, ok := obj.(*types.Var)
+			if !ok {
+				return nil
+			}
+			if _, isMap := variable.Type().Underlying().(*types.Map); !isMap {
+				return nil
+			}
+			if decl := scan.maps[variable.Pos()]; decl != nil {
+				return decl
+			}
+			// Maps declared through named types are only visible to the type checker
+			decl := &mapDecl{name: variable.Name(), scope: mapLocal}
+			switch {
+			case variable.IsField():
+				decl.scope = mapField
+			case variable.Pkg() != nil && variable.Parent() == variable.Pkg().Scope():
+				decl.scope = mapGlobal
+			}
+			scan.maps[variable.Pos()] = decl
+			return decl
+		}
+	}
+
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		// Without types a field name is only trusted when a single struct declares it
+		if base, ok := ast.Unparen(e.X).(*ast.Ident); ok && scan.imports[base.Name] {
+			return nil
+		}
+		if positions := scan.fieldsByName[e.Sel.Name]; len(positions) == 1 {
+			return scan.maps[positions[0]]
+		}
+	case *ast.Ident:
+		if e.Obj != nil {
+			if e.Obj.Kind == ast.Var {
+				return scan.maps[e.Obj.Pos()]
+			}
+			return nil
+		}
+		if pos, ok := scan.globals[e.Name]; ok {
+			return scan.maps[pos]
+		}
+	}
+	return nil
+}
+
+// mapWrites returns the assignments, increments and deletes on maps inside body, leaving out
+// nested goroutines which are checked on their own
+func (scan *mapWriteScan) mapWrites(body *ast.BlockStmt) []mapWrite {
+	var writes []mapWrite
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.GoStmt:
+			return false
+		case *ast.AssignStmt:
+			for _, lhs := range stmt.Lhs {
+				if index, ok := ast.Unparen(lhs).(*ast.IndexExpr); ok {
+					writes = append(writes, mapWrite{target: index.X, pos: lhs.Pos()})
+				}
+			}
+		case *ast.IncDecStmt:
+			if index, ok := ast.Unparen(stmt.X).(*ast.IndexExpr); ok {
+				writes = append(writes, mapWrite{target: index.X, pos: stmt.Pos()})
+			}
+		case *ast.CallExpr:
+			if fun, ok := stmt.Fun.(*ast.Ident); ok && (fun.Name == "delete" || fun.Name == "clear") && len(stmt.Args) > 0 {
+				writes = append(writes, mapWrite{target: stmt.Args[0], pos: stmt.Pos()})
+			}
+		}
+		return true
+	})
+	return writes
+}
+
+// collectGoroutines finds the go statements of a function and the bodies each one runs
+func (scan *mapWriteScan) collectGoroutines(funcDecl *ast.FuncDecl) []*mapGoroutine {
+	var goroutines []*mapGoroutine
+	var loops []ast.Node
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			loops = append(loops, stmt)
+		case *ast.GoStmt:
+			goroutine := &mapGoroutine{stmt: stmt, owner: funcDecl}
+			for _, loop := range loops {
+				if stmt.Pos() >= loop.Pos() && stmt.End() <= loop.End() {
+					goroutine.inLoop = true
+				}
+			}
+
+			var entry goroutineFunc
+			if lit, ok := ast.Unparen(stmt.Call.Fun).(*ast.FuncLit); ok {
+				entry = goroutineFunc{name: funcDecl.Name.Name, body: lit.Body}
+			} else if decl := scan.pkg.ResolveCall(stmt.Call); decl != nil && decl.Body != nil {
+				entry = goroutineFunc{name: decl.Name.Name, body: decl.Body}
+			} else {
+				return true
+			}
+			goroutine.bodies = scan.reachable(entry)
+			goroutines = append(goroutines, goroutine)
+		}
+		return true
+	})
+	return goroutines
+}
+
+// reachable follows the calls made by a goroutine entry into the functions of the package,
+// except calls made while a lock is held, which protect whatever the callee writes
+func (scan *mapWriteScan) reachable(entry goroutineFunc) []goroutineFunc {
+	bodies := []goroutineFunc{entry}
+	visited := map[*ast.BlockStmt]bool{entry.body: true}
+
+	for i := 0; i < len(bodies); i++ {
+		body := bodies[i].body
+		ast.Inspect(body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.GoStmt:
+				return false
+			case *ast.CallExpr:
+				decl := scan.pkg.ResolveCall(node)
+				if decl == nil || decl.Body == nil || visited[decl.Body] || isLockedName(decl.Name.Name) {
+					return true
+				}
+				if lockHeldAt(body, node.Pos(), "") {
+					return true
+				}
+				visited[decl.Body] = true
+				bodies = append(bodies, goroutineFunc{name: decl.Name.Name, body: decl.Body})
+			}
+			return true
+		})
+	}
+	return bodies
+}
+
+// isLockedName reports whether a function follows the convention of being called with its lock held
+func isLockedName(name string) bool {
+	return strings.HasSuffix(name, "Locked") || strings.HasSuffix(name, "_locked")
+}
+
+// lockHeldAt reports whether a Lock call precedes pos in body without a later non-deferred
+// Unlock; mutex restricts the check to locks whose last selector is that name
+func lockHeldAt(body *ast.BlockStmt, pos token.Pos, mutex string) bool {
+	deferred := make(map[*ast.CallExpr]bool)
+	held := make(map[string]bool)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || n.Pos() >= pos {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.DeferStmt:
+			deferred[node.Call] = true
+		case *ast.CallExpr:
+			selector, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			lock := types.ExprString(selector.X)
+			switch selector.Sel.Name {
+			case "Lock":
+				held[lock] = true
+			case "Unlock":
+				if !deferred[node] {
+					held[lock] = false
+				}
+			}
+		}
+		return true
+	})
+
+	for lock, isHeld := range held {
+		if isHeld && (mutex == "" || lock == mutex || strings.HasSuffix(lock, "."+mutex)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkGoroutine reports unguarded writes to shared maps made by one goroutine
+func (scan *mapWriteScan) checkGoroutine(goroutine *mapGoroutine, goroutines []*mapGoroutine, concurrent map[*ast.BlockStmt]bool) {
+	reported := make(map[*mapDecl]bool)
+
+	for i, fn := range goroutine.bodies {
+		for _, write := range scan.mapWrites(fn.body) {
+			decl := scan.resolveMap(write.target)
+			if decl == nil || reported[decl] {
+				continue
+			}
+
+			var evidence string
+			switch decl.scope {
+			case mapLocal:
+				// Captured locals only exist in the goroutine literal itself; loops are left to
+				// the loop hazard detector
+				if i != 0 || goroutine.inLoop || !scan.declaredOutside(write.target, fn.body) {
+					continue
+				}
+				evidence = scan.localEvidence(goroutine, goroutines, write.target)
+			case mapGlobal:
+				if i == 0 && goroutine.inLoop {
+					if _, isIdent := ast.Unparen(write.target).(*ast.Ident); isIdent {
+						continue
+					}
+				}
+				evidence = scan.sharedEvidence(goroutine, goroutines, decl, concurrent)
+			case mapField:
+				evidence = scan.sharedEvidence(goroutine, goroutines, decl, concurrent)
+			}
+
+			if decl.guard != "" {
+				if lockHeldAt(fn.body, write.pos, decl.guard) {
+					continue
+				}
+				reported[decl] = true
+				scan.report(MapWriteGuardNotHeld, write.pos, fn.name, decl, goroutine,
+					fmt.Sprintf("map '%s' is guarded by the mutex '%s' declared above it, but %s writes it from a goroutine without holding '%s'",
+						types.ExprString(write.target), decl.guard, fn.name, decl.guard))
+				continue
+			}
+
+			if evidence == "" || lockHeldAt(fn.body, write.pos, "") {
+				continue
+			}
+			reported[decl] = true
+			scan.report(MapWriteUnguarded, write.pos, fn.name, decl, goroutine,
+				fmt.Sprintf("map '%s' is written from a goroutine without holding a lock while %s; concurrent map writes crash the program, so guard it with a mutex or use sync.Map",
+					types.ExprString(write.target), evidence))
+		}
+	}
+}
+
+// declaredOutside reports whether the variable written through target is declared outside body
+func (scan *mapWriteScan) declaredOutside(target ast.Expr, body *ast.BlockStmt) bool {
+	ident, ok := ast.Unparen(target).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	declared := token.NoPos
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if obj := info.Uses[ident]; obj != nil {
+			declared = obj.Pos()
+		}
+	}
+	if !declared.IsValid() && ident.Obj != nil {
+		declared = ident.Obj.Pos()
+	}
+	return declared.IsValid() && (declared < body.Pos() || declared >= body.End())
+}
+
+// localEvidence explains why a captured local map is used concurrently, or returns ""
+func (scan *mapWriteScan) localEvidence(goroutine *mapGoroutine, goroutines []*mapGoroutine, target ast.Expr) string {
+	decl := scan.resolveMap(target)
+
+	for _, other := range goroutines {
+		if other != goroutine && other.owner == goroutine.owner && scan.accesses(other.stmt, decl) {
+			return "another goroutine started at line " + fmt.Sprint(scan.pkg.FileSet().Position(other.stmt.Pos()).Line) + " uses it too"
+		}
+	}
+
+	// The parent races with the goroutine when it touches the map before synchronizing
+	synchronized := false
+	var access ast.Node
+	ast.Inspect(goroutine.owner.Body, func(n ast.Node) bool {
+		if n == nil || synchronized || access != nil {
+			return false
+		}
+		if n.End() <= goroutine.stmt.End() {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			return false
+		case *ast.UnaryExpr:
+			synchronized = node.Op == token.ARROW
+		case *ast.CallExpr:
+			switch calledMethod(node) {
+			case "Wait", "Lock":
+				synchronized = true
+			}
+		case *ast.SelectStmt:
+			synchronized = true
+		case *ast.Ident:
+			if scan.resolveMap(node) == decl {
+				access = node
+			}
+		}
+		return !synchronized && access == nil
+	})
+
+	if access != nil {
+		return fmt.Sprintf("%s keeps using it at line %d without waiting for the goroutine",
+			goroutine.owner.Name.Name, scan.pkg.FileSet().Position(access.Pos()).Line)
+	}
+	return ""
+}
+
+// sharedEvidence explains why a field or package map is used concurrently, or returns ""
+func (scan *mapWriteScan) sharedEvidence(goroutine *mapGoroutine, goroutines []*mapGoroutine, decl *mapDecl, concurrent map[*ast.BlockStmt]bool) string {
+	if goroutine.inLoop {
+		return "the goroutine is started once per loop iteration"
+	}
+
+	for _, other := range goroutines {
+		if other == goroutine {
+			continue
+		}
+		for _, fn := range other.bodies {
+			if scan.accesses(fn.body, decl) {
+				return fmt.Sprintf("the goroutine started at line %d uses it too", scan.pkg.FileSet().Position(other.stmt.Pos()).Line)
+			}
+		}
+	}
+
+	for _, funcDecl := range scan.funcs {
+		if concurrent[funcDecl.Body] {
+			continue
+		}
+		fresh := freshValues(funcDecl.Body)
+		var access ast.Node
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			if access != nil {
+				return false
+			}
+			// Setup done by the parent before it starts the goroutine is not concurrent
+			if funcDecl == goroutine.owner && n != nil && n.End() <= goroutine.stmt.Pos() {
+				return false
+			}
+			switch node := n.(type) {
+			case *ast.GoStmt:
+				return false
+			case *ast.SelectorExpr:
+				if base, ok := ast.Unparen(node.X).(*ast.Ident); ok && fresh[base.Name] {
+					return false
+				}
+				if scan.resolveMap(node) == decl {
+					access = node
+				}
+			case *ast.Ident:
+				if decl.scope == mapGlobal && scan.resolveMap(node) == decl {
+					access = node
+				}
+			}
+			return access == nil
+		})
+		if access != nil {
+			return fmt.Sprintf("%s also uses it at line %d", funcDecl.Name.Name, scan.pkg.FileSet().Position(access.Pos()).Line)
+		}
+	}
+	return ""
+}
+
+// accesses reports whether node reads or writes the map decl
+func (scan *mapWriteScan) accesses(node ast.Node, decl *mapDecl) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.SelectorExpr:
+			found = found || scan.resolveMap(e) == decl
+		case *ast.Ident:
+			found = found || (decl.scope != mapField && scan.resolveMap(e) == decl)
+		}
+		return !found
+	})
+	return found
+}
+
+// report records a finding located at pos
+func (scan *mapWriteScan) report(issue MapWriteIssue, pos token.Pos, funcName string, decl *mapDecl, goroutine *mapGoroutine, description string) {
+	position := scan.pkg.FileSet().Position(pos)
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+	started := scan.pkg.FileSet().Position(goroutine.stmt.Pos())
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue.String(), funcName, position.Line, position.Column),
+		entities.FindingTypeBug,
+		location,
+		fmt.Sprintf("Concurrent map write in %s: %s detected - %s", funcName, issue.String(), description),
+		valueobjects.SeverityError,
+	)
+	finding.AddMetadata("map", decl.name)
+	finding.AddMetadata("goroutine_location", fmt.Sprintf("%s:%d", started.Filename, started.Line))
+	if decl.guard != "" {
+		finding.AddMetadata("guard", decl.guard)
+	}
+
+	scan.findings = append(scan.findings, finding)
+}
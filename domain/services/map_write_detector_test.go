@@ -0,0 +1,278 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestMapWriteDetector_DetectMapWrites(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		expectedIssues int
+		expectedTypes  []string
+	}{
+		{
+			name: "Captured local written while parent keeps using it",
+			code: `
+package main
+func index(words []string) map[string]int {
+	counts := make(map[string]int)
+	go func() {
+		for _, w := range words {
+			counts[w]++
+		}
+	}()
+	counts["total"] = len(words)
+	return counts
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"unguarded_map_write", "map 'counts'", "index keeps using it at line 10"},
+		},
+		{
+			name: "Parent waits before using the map - no issue",
+			code: `
+package main
+import "sync"
+func index(words []string) map[string]int {
+	counts := make(map[string]int)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, w := range words {
+			counts[w]++
+		}
+	}()
+	wg.Wait()
+	counts["total"] = len(words)
+	return counts
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Struct field written by background goroutine and read by method",
+			code: `
+package main
+type cache struct {
+	items map[string]string
+}
+func (c *cache) start(keys []string) {
+	go c.refresh(keys)
+}
+func (c *cache) refresh(keys []string) {
+	for _, k := range keys {
+		c.store(k)
+	}
+}
+func (c *cache) store(k string) {
+	c.items[k] = k
+}
+func (c *cache) get(k string) string {
+	return c.items[k]
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"Concurrent map write in store", "map 'c.items'", "get also uses it"},
+		},
+		{
+			name: "Write under lock in reachable method - no issue",
+			code: `
+package main
+import "sync"
+type cache struct {
+	lock  sync.Mutex
+	other int
+	items map[string]string
+}
+func (c *cache) start(k string) {
+	go func() {
+		c.lock.Lock()
+		c.store(k)
+		c.lock.Unlock()
+	}()
+}
+func (c *cache) store(k string) {
+	c.items[k] = k
+}
+func (c *cache) get(k string) string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.items[k]
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Sibling mutex convention not held",
+			code: `
+package main
+import "sync"
+type registry struct {
+	mu    sync.Mutex
+	peers map[string]int
+}
+func (r *registry) watch(events <-chan string) {
+	go func() {
+		for name := range events {
+			r.peers[name]++
+		}
+	}()
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"guard_not_held", "mutex 'mu' declared above it", "without holding 'mu'"},
+		},
+		{
+			name: "Sibling mutex convention held",
+			code: `
+package main
+import "sync"
+type registry struct {
+	mu    sync.Mutex
+	peers map[string]int
+}
+func (r *registry) watch(events <-chan string) {
+	go func() {
+		for name := range events {
+			r.mu.Lock()
+			r.peers[name]++
+			r.mu.Unlock()
+		}
+	}()
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Lock held on a different mutex than the sibling",
+			code: `
+package main
+import "sync"
+type registry struct {
+	mu    sync.Mutex
+	peers map[string]int
+	other sync.Mutex
+}
+func (r *registry) add(name string) {
+	go func() {
+		r.other.Lock()
+		defer r.other.Unlock()
+		delete(r.peers, name)
+	}()
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"guard_not_held"},
+		},
+		{
+			name: "Global map written from two goroutines",
+			code: `
+package main
+var sessions = map[string]bool{}
+func login(user string) {
+	go func() {
+		sessions[user] = true
+	}()
+	go func() {
+		delete(sessions, "guest")
+	}()
+}`,
+			expectedIssues: 2,
+			expectedTypes:  []string{"map 'sessions'", "uses it too"},
+		},
+		{
+			name: "Goroutine owns its map - no issue",
+			code: `
+package main
+type loop struct {
+	state map[string]int
+}
+func (l *loop) start(events <-chan string) {
+	go l.run(events)
+}
+func (l *loop) run(events <-chan string) {
+	for e := range events {
+		l.state[e]++
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			detector := NewASTMapWriteDetector()
+			config := valueobjects.DefaultAnalysisConfiguration()
+			findings, err := detector.DetectMapWrites(node, fset, config)
+			if err != nil {
+				t.Fatalf("DetectMapWrites failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+		})
+	}
+}
+
+func TestMapWriteDetector_DetectPackageMapWrites(t *testing.T) {
+	sources := []string{`
+package server
+import "sync"
+type Index map[string]int
+type Server struct {
+	hits Index
+	seen sync.Map
+}
+func (s *Server) Serve(paths <-chan string) {
+	for p := range paths {
+		go s.track(p)
+	}
+}`, `
+package server
+func (s *Server) track(p string) {
+	s.seen.Store(p, true)
+	s.hits[p]++
+}`}
+
+	pkg := checkPackage(t, sources...)
+
+	detector := NewASTMapWriteDetector()
+	findings, err := detector.DetectPackageMapWrites(pkg, valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("DetectPackageMapWrites failed: %v", err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 issue, got %d", len(findings))
+	}
+	if !strings.Contains(findings[0].Message(), "map 's.hits' is written from a goroutine without holding a lock while the goroutine is started once per loop iteration") {
+		t.Errorf("Unexpected finding: %s", findings[0].Message())
+	}
+}
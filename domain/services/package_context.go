@@ -0,0 +1,246 @@
+package services
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// PackageContext carries the parsed files of a single Go package together with
+// optional type information, so detectors can resolve declarations that live
+// outside the file currently being analyzed
+type PackageContext struct {
+	fset         *token.FileSet
+	files        []*ast.File
+	info         *types.Info
+	functions    map[string]*ast.FuncDecl
+	methods      map[string][]*ast.FuncDecl
+	typedDecls   map[*types.Func]*ast.FuncDecl
+	dependencies []packageDependency
+	depsIndexed  bool
+	goVersion    string
+	typeErrors   bool
+}
+
+// packageDependency holds the syntax and type information of an imported package
+type packageDependency struct {
+	files      []*ast.File
+	info       *types.Info
+	typeErrors bool
+}
+
+// NewPackageContext creates a package context; info may be nil when type checking is unavailable
+func NewPackageContext(fset *token.FileSet, files []*ast.File, info *types.Info) *PackageContext {
+	pc := &PackageContext{
+		fset:       fset,
+		files:      files,
+		info:       info,
+		functions:  make(map[string]*ast.FuncDecl),
+		methods:    make(map[string][]*ast.FuncDecl),
+		typedDecls: make(map[*types.Func]*ast.FuncDecl),
+	}
+
+	for _, file := range files {
+		pc.indexFile(file)
+		pc.indexTypedDecls(file, info)
+	}
+
+	return pc
+}
+
+// newNodePackageContext builds a context covering a single node; only files carry declarations
+func newNodePackageContext(node ast.Node, fset *token.FileSet) *PackageContext {
+	if file, ok := node.(*ast.File); ok {
+		return NewPackageContext(fset, []*ast.File{file}, nil)
+	}
+	return NewPackageContext(fset, nil, nil)
+}
+
+// FileSet returns the file set shared by all files of the package
+func (pc *PackageContext) FileSet() *token.FileSet {
+	return pc.fset
+}
+
+// Files returns the parsed files of the package
+func (pc *PackageContext) Files() []*ast.File {
+	return pc.files
+}
+
+// TypesInfo returns the type information for the package, or nil if unavailable
+func (pc *PackageContext) TypesInfo() *types.Info {
+	return pc.info
+}
+
+// SetGoVersion records the language version declared by the go directive of the enclosing go.mod
+func (pc *PackageContext) SetGoVersion(version string) {
+	if version != "" && !strings.HasPrefix(version, "go") {
+		version = "go" + version
+	}
+	pc.goVersion = version
+}
+
+// GoVersion returns the module language version such as "go1.22", or "" when unknown
+func (pc *PackageContext) GoVersion() string {
+	return pc.goVersion
+}
+
+// SetTypeErrors records whether type checking the package reported errors
+func (pc *PackageContext) SetTypeErrors(failed bool) {
+	pc.typeErrors = failed
+}
+
+// HasTypeErrors reports whether type checking failed, leaving TypesInfo incomplete
+func (pc *PackageContext) HasTypeErrors() bool {
+	return pc.typeErrors
+}
+
+// AddDependency registers the syntax of an imported package so calls into it can be resolved;
+// typeErrors marks dependencies whose type information is incomplete
+func (pc *PackageContext) AddDependency(files []*ast.File, info *types.Info, typeErrors bool) {
+	if info == nil {
+		return
+	}
+	pc.dependencies = append(pc.dependencies, packageDependency{files: files, info: info, typeErrors: typeErrors})
+	pc.depsIndexed = false
+}
+
+// ResolveCall returns the declaration of the function or method invoked by call, if known
+func (pc *PackageContext) ResolveCall(call *ast.CallExpr) *ast.FuncDecl {
+	if call == nil {
+		return nil
+	}
+
+	if decl, resolved := pc.resolveTypedCall(call.Fun); resolved {
+		return decl
+	}
+
+	return pc.resolveSyntacticCall(call.Fun)
+}
+
+// resolveTypedCall resolves the callee through type information; resolved reports whether the
+// type checker identified the callee, in which case a nil declaration means it is declared
+// outside the known packages or is not a function at all
+func (pc *PackageContext) resolveTypedCall(fun ast.Expr) (decl *ast.FuncDecl, resolved bool) {
+	if pc.info == nil {
+		return nil, false
+	}
+
+	var ident *ast.Ident
+	switch expr := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		ident = expr
+	case *ast.SelectorExpr:
+		ident = expr.Sel
+	case *ast.IndexExpr:
+		return pc.resolveTypedCall(expr.X)
+	case *ast.IndexListExpr:
+		return pc.resolveTypedCall(expr.X)
+	default:
+		return nil, false
+	}
+
+	obj := pc.info.Uses[ident]
+	if obj == nil {
+		return nil, false
+	}
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil, true
+	}
+	fn = fn.Origin()
+
+	if decl, ok := pc.typedDecls[fn]; ok {
+		return decl, true
+	}
+
+	if !pc.depsIndexed {
+		for _, dep := range pc.dependencies {
+			for _, file := range dep.files {
+				pc.indexTypedDecls(file, dep.info)
+			}
+		}
+		pc.depsIndexed = true
+	}
+
+	return pc.typedDecls[fn], true
+}
+
+// resolveSyntacticCall resolves the callee by name when type information is missing
+func (pc *PackageContext) resolveSyntacticCall(fun ast.Expr) *ast.FuncDecl {
+	switch expr := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return pc.functions[expr.Name]
+	case *ast.SelectorExpr:
+		// Without types we can only trust a method name declared on a single receiver type
+		if candidates := pc.methods[expr.Sel.Name]; len(candidates) == 1 {
+			return candidates[0]
+		}
+	case *ast.IndexExpr:
+		return pc.resolveSyntacticCall(expr.X)
+	case *ast.IndexListExpr:
+		return pc.resolveSyntacticCall(expr.X)
+	}
+	return nil
+}
+
+// indexFile records the function and method declarations of a file by name
+func (pc *PackageContext) indexFile(file *ast.File) {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+
+		if funcDecl.Recv == nil {
+			if _, exists := pc.functions[funcDecl.Name.Name]; !exists {
+				pc.functions[funcDecl.Name.Name] = funcDecl
+			}
+			continue
+		}
+
+		pc.methods[funcDecl.Name.Name] = append(pc.methods[funcDecl.Name.Name], funcDecl)
+	}
+}
+
+// indexTypedDecls records declarations keyed by their type-checked function object
+func (pc *PackageContext) indexTypedDecls(file *ast.File, info *types.Info) {
+	if info == nil {
+		return
+	}
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+		if fn, ok := info.Defs[funcDecl.Name].(*types.Func); ok {
+			pc.typedDecls[fn] = funcDecl
+		}
+	}
+}
+
+// receiverTypeName returns the base type name of a method receiver
+func receiverTypeName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := funcDecl.Recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
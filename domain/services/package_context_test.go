@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+// parseFiles parses sources as the files of one package, named <package>/a.go, <package>/b.go
+// and so on after their package clause
+func parseFiles(t *testing.T, fset *token.FileSet, sources []string) []*ast.File {
+	t.Helper()
+
+	var files []*ast.File
+	for i, src := range sources {
+		clause, err := parser.ParseFile(token.NewFileSet(), "", src, parser.PackageClauseOnly)
+		if err != nil {
+			t.Fatalf("Failed to parse code: %v", err)
+		}
+		name := fmt.Sprintf("%s/%c.go", clause.Name.Name, 'a'+i)
+		file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("Failed to parse code: %v", err)
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// parsePackage parses sources into a package context without type information
+func parsePackage(t *testing.T, sources ...string) *PackageContext {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	return NewPackageContext(fset, parseFiles(t, fset, sources), nil)
+}
+
+// typeCheckPackage parses and type-checks sources into a package context, returning the type
+// checker's first error alongside the partial type information
+func typeCheckPackage(t *testing.T, sources ...string) (*PackageContext, error) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	files := parseFiles(t, fset, sources)
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
+		Instances:  make(map[*ast.Ident]types.Instance),
+	}
+	config := &types.Config{Importer: importer.Default(), Error: func(error) {}}
+	_, err := config.Check(files[0].Name.Name, fset, files, info)
+	return NewPackageContext(fset, files, info), err
+}
+
+// checkPackage is typeCheckPackage for sources that must type-check cleanly
+func checkPackage(t *testing.T, sources ...string) *PackageContext {
+	t.Helper()
+
+	pkg, err := typeCheckPackage(t, sources...)
+	if err != nil {
+		t.Fatalf("Failed to type-check code: %v", err)
+	}
+	return pkg
+}
+
+func TestPackageContext_ResolveCall(t *testing.T) {
+	src := `
+package server
+import (
+	"net"
+	"net/http"
+)
+type mux struct {
+	requests chan net.Conn
+}
+func (m *mux) Serve(l net.Listener) {
+	<-m.requests
+}
+func Serve(l net.Listener) {
+	<-make(chan struct{})
+}
+func start(srv *http.Server, m *mux, l net.Listener) {
+	go srv.Serve(l)
+	go m.Serve(l)
+	go Serve(l)
+}`
+
+	tests := []struct {
+		name     string
+		pkg      *PackageContext
+		expected []string
+	}{
+		{
+			name:     "Types resolve only calls declared in the package",
+			pkg:      checkPackage(t, src),
+			expected: []string{"", "mux.Serve", "Serve"},
+		},
+		{
+			name:     "Names resolve calls without types",
+			pkg:      parsePackage(t, src),
+			expected: []string{"mux.Serve", "mux.Serve", "Serve"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resolved []string
+			ast.Inspect(tt.pkg.Files()[0], func(n ast.Node) bool {
+				goStmt, ok := n.(*ast.GoStmt)
+				if !ok {
+					return true
+				}
+				name := ""
+				if decl := tt.pkg.ResolveCall(goStmt.Call); decl != nil {
+					name = decl.Name.Name
+					if recv := receiverTypeName(decl); recv != "" {
+						name = recv + "." + name
+					}
+				}
+				resolved = append(resolved, name)
+				return true
+			})
+
+			if strings.Join(resolved, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected calls to resolve to %q, got %q", tt.expected, resolved)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"go/ast"
+	"go/token"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+// calculateNPath implements Nejmeh's NPath complexity, the number of acyclic execution paths.
+// Sequential statements multiply, branches add and every && or || in a condition adds a path;
+// the count saturates at valueobjects.MaxNPath instead of overflowing
+func (c *ASTComplexityCalculator) calculateNPath(node ast.Node) int64 {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Body == nil {
+			return 1
+		}
+		return c.blockNPath(n.Body.List)
+	case *ast.FuncLit:
+		return c.blockNPath(n.Body.List)
+	case ast.Stmt:
+		return c.stmtNPath(n)
+	}
+	return 1
+}
+
+// blockNPath returns the paths through a statement sequence
+func (c *ASTComplexityCalculator) blockNPath(stmts []ast.Stmt) int64 {
+	paths := int64(1)
+	for _, stmt := range stmts {
+		paths = saturatingMul(paths, c.stmtNPath(stmt))
+	}
+	return paths
+}
+
+// stmtNPath returns the paths through a single statement
+func (c *ASTComplexityCalculator) stmtNPath(stmt ast.Stmt) int64 {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		return c.blockNPath(s.List)
+	case *ast.LabeledStmt:
+		return c.stmtNPath(s.Stmt)
+	case *ast.IfStmt:
+		elsePaths := int64(1)
+		if s.Else != nil {
+			elsePaths = c.stmtNPath(s.Else)
+		}
+		return saturatingAdd(saturatingAdd(c.blockNPath(s.Body.List), elsePaths), booleanOperators(s.Cond))
+	case *ast.ForStmt:
+		return saturatingAdd(saturatingAdd(c.blockNPath(s.Body.List), booleanOperators(s.Cond)), 1)
+	case *ast.RangeStmt:
+		return saturatingAdd(c.blockNPath(s.Body.List), 1)
+	case *ast.SwitchStmt:
+		return c.clausesNPath(s.Body, booleanOperators(s.Tag))
+	case *ast.TypeSwitchStmt:
+		return c.clausesNPath(s.Body, 0)
+	case *ast.SelectStmt:
+		// One communication clause always runs, so select has no implicit path of its own
+		paths := int64(0)
+		for _, clause := range s.Body.List {
+			if comm, ok := clause.(*ast.CommClause); ok {
+				paths = saturatingAdd(paths, c.blockNPath(comm.Body))
+			}
+		}
+		return max(paths, 1)
+	case *ast.ReturnStmt:
+		paths := int64(0)
+		for _, result := range s.Results {
+			paths = saturatingAdd(paths, booleanOperators(result))
+		}
+		return max(paths, 1)
+	}
+	return 1
+}
+
+// clausesNPath sums the paths of switch clauses; without a default clause the switch may run none
+func (c *ASTComplexityCalculator) clausesNPath(body *ast.BlockStmt, paths int64) int64 {
+	hasDefault := false
+	for _, clause := range body.List {
+		if cc, ok := clause.(*ast.CaseClause); ok {
+			if cc.List == nil {
+				hasDefault = true
+			}
+			paths = saturatingAdd(paths, c.blockNPath(cc.Body))
+		}
+	}
+	if !hasDefault {
+		paths = saturatingAdd(paths, 1)
+	}
+	return paths
+}
+
+// calculateEssentialComplexity approximates McCabe's essential complexity: structured constructs
+// reduce away, leaving one decision for every loop with more than one exit and every goto
+func (c *ASTComplexityCalculator) calculateEssentialComplexity(node ast.Node) int {
+	var body ast.Node = node
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Body == nil {
+			return 1
+		}
+		body = n.Body
+	case *ast.FuncLit:
+		body = n.Body
+	}
+
+	labels := make(map[ast.Stmt]string)
+	essential := 1
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.LabeledStmt:
+			labels[s.Stmt] = s.Label.Name
+		case *ast.BranchStmt:
+			if s.Tok == token.GOTO {
+				essential++
+			}
+		case *ast.ForStmt:
+			if countLoopExits(s.Body, labels[s], s.Cond != nil) > 1 {
+				essential++
+			}
+		case *ast.RangeStmt:
+			if countLoopExits(s.Body, labels[s], true) > 1 {
+				essential++
+			}
+		}
+		return true
+	})
+
+	return essential
+}
+
+// countLoopExits counts the ways control can leave a loop: its condition, breaks that target it, and
+// returns, gotos and labeled branches that leave it for an enclosing statement
+func countLoopExits(body *ast.BlockStmt, label string, hasCondition bool) int {
+	exits := 0
+	if hasCondition {
+		exits++
+	}
+
+	// Labels declared inside the loop are targets that stay within it
+	inner := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if labeled, ok := n.(*ast.LabeledStmt); ok {
+			inner[labeled.Label.Name] = true
+		}
+		_, isFuncLit := n.(*ast.FuncLit)
+		return !isFuncLit
+	})
+	leaves := func(target *ast.Ident) bool {
+		return target.Name == label || !inner[target.Name]
+	}
+
+	var walk func(node ast.Node, nested bool)
+	walk = func(node ast.Node, nested bool) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if n == node {
+				return true
+			}
+			switch s := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+				// An unlabeled break inside these leaves them, not the loop
+				walk(n, true)
+				return false
+			case *ast.ReturnStmt:
+				exits++
+			case *ast.BranchStmt:
+				switch s.Tok {
+				case token.BREAK:
+					if (s.Label == nil && !nested) || (s.Label != nil && leaves(s.Label)) {
+						exits++
+					}
+				case token.CONTINUE:
+					if s.Label != nil && s.Label.Name != label && !inner[s.Label.Name] {
+						exits++
+					}
+				case token.GOTO:
+					if !inner[s.Label.Name] {
+						exits++
+					}
+				}
+			}
+			return true
+		})
+	}
+	walk(body, false)
+
+	return exits
+}
+
+// booleanOperators counts the && and || operators in an expression, each adding a path
+func booleanOperators(expr ast.Expr) int64 {
+	if expr == nil {
+		return 0
+	}
+
+	count := int64(0)
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BinaryExpr:
+			if e.Op == token.LAND || e.Op == token.LOR {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
+
+// saturatingAdd adds two path counts, saturating at valueobjects.MaxNPath
+func saturatingAdd(a, b int64) int64 {
+	if a > valueobjects.MaxNPath-b {
+		return valueobjects.MaxNPath
+	}
+	return a + b
+}
+
+// saturatingMul multiplies two path counts, saturating at valueobjects.MaxNPath
+func saturatingMul(a, b int64) int64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	if a > valueobjects.MaxNPath/b {
+		return valueobjects.MaxNPath
+	}
+	return a * b
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestComplexityCalculator_PathComplexity(t *testing.T) {
+	tests := []struct {
+		name              string
+		code              string
+		expectedNPath     int64
+		expectedEssential int
+	}{
+		{
+			name: "Straight-line code",
+			code: `
+package main
+func f(a, b int) int {
+	c := a + b
+	return c * 2
+}`,
+			expectedNPath:     1,
+			expectedEssential: 1,
+		},
+		{
+			name: "Sequential branches multiply",
+			code: `
+package main
+func f(a, b, c bool) int {
+	n := 0
+	if a {
+		n++
+	}
+	if b {
+		n++
+	} else {
+		n--
+	}
+	if c {
+		n++
+	}
+	return n
+}`,
+			expectedNPath:     8,
+			expectedEssential: 1,
+		},
+		{
+			name: "Boolean operators and nested branches add",
+			code: `
+package main
+func f(a, b, c bool) int {
+	if a && b || c {
+		if b {
+			return 1
+		}
+	}
+	return 0
+}`,
+			expectedNPath:     5, // (2 + 1) + 2 operators
+			expectedEssential: 1,
+		},
+		{
+			name: "Switch without default and loop",
+			code: `
+package main
+func f(kind int, items []int) int {
+	total := 0
+	switch kind {
+	case 1:
+		total = 1
+	case 2:
+		total = 2
+	}
+	for _, item := range items {
+		if item > 0 {
+			total += item
+		}
+	}
+	return total
+}`,
+			expectedNPath:     9, // (1 + 1 + 1) * (2 + 1)
+			expectedEssential: 1,
+		},
+		{
+			name: "Loop with early return is not structured",
+			code: `
+package main
+func find(items []int, target int) int {
+	for i, item := range items {
+		if item == target {
+			return i
+		}
+	}
+	return -1
+}`,
+			expectedNPath:     3,
+			expectedEssential: 2,
+		},
+		{
+			name: "Labeled break out of nested loop and goto",
+			code: `
+package main
+func scan(grid [][]int) int {
+	n := 0
+outer:
+	for _, row := range grid {
+		for _, cell := range row {
+			switch cell {
+			case 0:
+				break
+			case -1:
+				break outer
+			}
+			n++
+		}
+	}
+	if n == 0 {
+		goto done
+	}
+	n *= 2
+done:
+	return n
+}`,
+			expectedNPath:     10, // (switch 3 + inner loop 1 + outer loop 1) * if 2
+			expectedEssential: 4,  // outer loop, inner loop and goto
+		},
+		{
+			name: "Infinite loop with a single break is structured",
+			code: `
+package main
+func drain(ch chan int) int {
+	sum := 0
+	for {
+		v, ok := <-ch
+		if !ok {
+			break
+		}
+		sum += v
+	}
+	return sum
+}`,
+			expectedNPath:     3,
+			expectedEssential: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			funcDecl, fset := parseSingleFunction(t, tt.code)
+
+			calculator := NewASTComplexityCalculator()
+			score, err := calculator.CalculateComplexity(funcDecl, fset)
+			if err != nil {
+				t.Fatalf("CalculateComplexity failed: %v", err)
+			}
+
+			if score.NPath() != tt.expectedNPath {
+				t.Errorf("Expected NPath %d, got %d", tt.expectedNPath, score.NPath())
+			}
+			if score.Essential() != tt.expectedEssential {
+				t.Errorf("Expected essential complexity %d, got %d", tt.expectedEssential, score.Essential())
+			}
+		})
+	}
+}
+
+func TestComplexityCalculator_NPathSaturates(t *testing.T) {
+	var body strings.Builder
+	for i := 0; i < 100; i++ {
+		body.WriteString("\tif a {\n\t\tn++\n\t}\n")
+	}
+	code := "package main\nfunc f(a bool) int {\n\tn := 0\n" + body.String() + "\treturn n\n}"
+
+	funcDecl, fset := parseSingleFunction(t, code)
+
+	score, err := NewASTComplexityCalculator().CalculateComplexity(funcDecl, fset)
+	if err != nil {
+		t.Fatalf("CalculateComplexity failed: %v", err)
+	}
+
+	if score.NPath() != valueobjects.MaxNPath || !score.IsNPathSaturated() {
+		t.Errorf("Expected 2^100 paths to saturate at %d, got %d", int64(valueobjects.MaxNPath), score.NPath())
+	}
+}
+
+// parseSingleFunction parses code and returns its last function declaration
+func parseSingleFunction(t *testing.T, code string) (*ast.FuncDecl, *token.FileSet) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse code: %v", err)
+	}
+
+	var funcDecl *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok {
+			funcDecl = fd
+		}
+	}
+	return funcDecl, fset
+}
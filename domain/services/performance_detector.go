@@ -0,0 +1,1001 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// PerformanceIssue represents the different hot-path anti-patterns
+type PerformanceIssue int
+
+const (
+	PerformanceStringConcatInLoop PerformanceIssue = iota
+	PerformanceAppendWithoutPrealloc
+	PerformanceCompileInHotPath
+	PerformanceDeferInLoop
+	PerformanceSprintfConversion
+	PerformanceConversionInLoop
+	PerformanceLargeValueParam
+)
+
+// String returns a string representation of the performance issue
+func (pi PerformanceIssue) String() string {
+	switch pi {
+	case PerformanceStringConcatInLoop:
+		return "string_concat_in_loop"
+	case PerformanceAppendWithoutPrealloc:
+		return "append_without_prealloc"
+	case PerformanceCompileInHotPath:
+		return "compile_in_hot_path"
+	case PerformanceDeferInLoop:
+		return "defer_in_loop"
+	case PerformanceSprintfConversion:
+		return "sprintf_conversion"
+	case PerformanceConversionInLoop:
+		return "conversion_in_loop"
+	case PerformanceLargeValueParam:
+		return "large_value_param"
+	default:
+		return "unknown"
+	}
+}
+
+// PerformanceImpact estimates how much a performance issue costs at run time
+type PerformanceImpact int
+
+const (
+	ImpactLow PerformanceImpact = iota
+	ImpactMedium
+	ImpactHigh
+)
+
+// String returns a string representation of the impact class
+func (pi PerformanceImpact) String() string {
+	switch pi {
+	case ImpactLow:
+		return "low"
+	case ImpactMedium:
+		return "medium"
+	case ImpactHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// largeValueBytes is the size from which copying a struct on every call is worth avoiding
+const largeValueBytes = 128
+
+// hugeValueBytes is the size from which copying a struct dominates a cheap call
+const hugeValueBytes = 1024
+
+// basicTypeSizes are the sizes of predeclared types on 64-bit platforms, used without type information
+var basicTypeSizes = map[string]int64{
+	"bool": 1, "int8": 1, "uint8": 1, "byte": 1,
+	"int16": 2, "uint16": 2,
+	"int32": 4, "uint32": 4, "float32": 4, "rune": 4,
+	"int": 8, "uint": 8, "int64": 8, "uint64": 8, "uintptr": 8, "float64": 8, "complex64": 8,
+	"complex128": 16, "string": 16, "error": 16, "any": 16,
+}
+
+// PerformanceDetector detects allocation and copying patterns that are costly on hot paths
+type PerformanceDetector interface {
+	DetectPerformanceIssues(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackagePerformanceIssues(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTPerformanceDetector implements PerformanceDetector using AST analysis
+type ASTPerformanceDetector struct{}
+
+// NewASTPerformanceDetector creates a new AST-based performance detector
+func NewASTPerformanceDetector() *ASTPerformanceDetector {
+	return &ASTPerformanceDetector{}
+}
+
+// performanceScan holds the package-wide indexes, the function being analyzed and the findings
+type performanceScan struct {
+	pkg        *PackageContext
+	imports    map[string]string
+	structs    map[string]*ast.StructType
+	callers    map[*ast.FuncDecl]int
+	loopCalled map[*ast.FuncDecl]bool
+	funcDecl   *ast.FuncDecl
+	funcName   string
+	reported   map[string]bool
+	findings   []entities.AnalysisFinding
+}
+
+// DetectPerformanceIssues analyzes code for hot-path anti-patterns
+func (pd *ASTPerformanceDetector) DetectPerformanceIssues(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	scan := newPerformanceScan(newNodePackageContext(node, fset), []ast.Node{node})
+	if file, ok := node.(*ast.File); ok {
+		scan.analyzeFile(file)
+	} else if funcDecl, ok := node.(*ast.FuncDecl); ok && funcDecl.Body != nil {
+		scan.checkFunction(funcDecl)
+	}
+	return scan.findings, nil
+}
+
+// DetectPackagePerformanceIssues analyzes every file of a package with its type information
+func (pd *ASTPerformanceDetector) DetectPackagePerformanceIssues(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var nodes []ast.Node
+	for _, file := range pkg.Files() {
+		nodes = append(nodes, file)
+	}
+
+	scan := newPerformanceScan(pkg, nodes)
+	for _, file := range pkg.Files() {
+		scan.analyzeFile(file)
+	}
+	return scan.findings, nil
+}
+
+// newPerformanceScan indexes the struct declarations of the nodes and how often each function is called
+func newPerformanceScan(pkg *PackageContext, nodes []ast.Node) *performanceScan {
+	scan := &performanceScan{
+		pkg:        pkg,
+		structs:    make(map[string]*ast.StructType),
+		callers:    make(map[*ast.FuncDecl]int),
+		loopCalled: make(map[*ast.FuncDecl]bool),
+		reported:   make(map[string]bool),
+	}
+
+	for _, node := range nodes {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch decl := n.(type) {
+			case *ast.TypeSpec:
+				if structType, ok := decl.Type.(*ast.StructType); ok {
+					scan.structs[decl.Name.Name] = structType
+				}
+			case *ast.FuncDecl:
+				if decl.Body == nil {
+					return false
+				}
+				walkLoops(decl.Body, func(n ast.Node, stack []ast.Node, loop ast.Node) {
+					call, ok := n.(*ast.CallExpr)
+					if !ok {
+						return
+					}
+					if callee := pkg.ResolveCall(call); callee != nil {
+						scan.callers[callee]++
+						scan.loopCalled[callee] = scan.loopCalled[callee] || loop != nil
+					}
+				})
+				return false
+			}
+			return true
+		})
+	}
+	return scan
+}
+
+// walkLoops visits every node of body with its ancestors and the innermost loop whose body
+// contains it; function literals start a new scope without an enclosing loop
+func walkLoops(body *ast.BlockStmt, visit func(n ast.Node, stack []ast.Node, loop ast.Node)) {
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+
+		var loop ast.Node
+	search:
+		for i := len(stack) - 1; i >= 0; i-- {
+			switch stack[i].(type) {
+			case *ast.FuncLit:
+				break search
+			case *ast.ForStmt, *ast.RangeStmt:
+				if block := loopBody(stack[i]); n.Pos() >= block.Pos() && n.End() <= block.End() {
+					loop = stack[i]
+					break search
+				}
+			}
+		}
+
+		visit(n, stack, loop)
+		stack = append(stack, n)
+		return true
+	})
+}
+
+// analyzeFile checks every function declared in a file
+func (scan *performanceScan) analyzeFile(file *ast.File) {
+	scan.imports = fileImports(file)
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Body != nil {
+			scan.checkFunction(funcDecl)
+		}
+	}
+}
+
+// checkFunction runs every rule over one function, including its function literals
+func (scan *performanceScan) checkFunction(funcDecl *ast.FuncDecl) {
+	scan.funcDecl = funcDecl
+	scan.funcName = funcDecl.Name.Name
+	scan.checkParams(funcDecl)
+
+	walkLoops(funcDecl.Body, func(n ast.Node, stack []ast.Node, loop ast.Node) {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if loop != nil {
+				scan.checkConcat(node, loop)
+				scan.checkAppend(node, stack, loop)
+			}
+		case *ast.DeferStmt:
+			// Deferred Close calls are reported by the resource leak detector
+			if loop != nil && calledMethod(node.Call) != "Close" {
+				scan.report(PerformanceDeferInLoop, node.Pos(), ImpactMedium,
+					fmt.Sprintf("defer inside a loop queues %s until %s returns, growing the defer stack every iteration; call it at the end of the iteration or move the body into a function",
+						types.ExprString(node.Call.Fun), scan.funcName))
+			}
+		case *ast.CallExpr:
+			scan.checkCompile(node, stack, loop)
+			scan.checkSprintf(node, loop)
+			if loop != nil {
+				scan.checkConversion(node, stack, loop)
+			}
+		}
+	})
+}
+
+// checkConcat reports strings grown with + inside a loop
+func (scan *performanceScan) checkConcat(assign *ast.AssignStmt, loop ast.Node) {
+	if len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return
+	}
+	target := identOf(assign.Lhs[0])
+	if target == nil {
+		return
+	}
+
+	var value ast.Expr
+	switch assign.Tok {
+	case token.ADD_ASSIGN:
+		value = assign.Rhs[0]
+	case token.ASSIGN:
+		if binary, ok := ast.Unparen(assign.Rhs[0]).(*ast.BinaryExpr); ok && binary.Op == token.ADD {
+			if ident := identOf(binary.X); ident != nil && ident.Name == target.Name {
+				value = binary.Y
+			}
+		}
+	}
+	if value == nil || !scan.isStringVar(target, value) {
+		return
+	}
+
+	declared := scan.declarationPos(target)
+	key := fmt.Sprintf("concat_%d_%s", loop.Pos(), target.Name)
+	if !declared.IsValid() || declared >= loop.Pos() || scan.reported[key] {
+		return
+	}
+	scan.reported[key] = true
+	scan.report(PerformanceStringConcatInLoop, assign.Pos(), ImpactHigh,
+		fmt.Sprintf("'%s' is grown with + inside a loop, copying the whole string every iteration; build it with a strings.Builder", target.Name))
+}
+
+// isStringVar reports whether target holds a string, judging by its type or the values it is given
+func (scan *performanceScan) isStringVar(target *ast.Ident, value ast.Expr) bool {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if t := info.TypeOf(target); t != nil {
+			basic, ok := t.Underlying().(*types.Basic)
+			return ok && basic.Info()&types.IsString != 0
+		}
+	}
+	if isStringLiteral(value) {
+		return true
+	}
+	if target.Obj == nil {
+		return false
+	}
+	switch decl := target.Obj.Decl.(type) {
+	case *ast.ValueSpec:
+		if ident := identOf(decl.Type); ident != nil {
+			return ident.Name == "string"
+		}
+		for i, name := range decl.Names {
+			if name.Name == target.Name && i < len(decl.Values) {
+				return isStringLiteral(decl.Values[i])
+			}
+		}
+	case *ast.AssignStmt:
+		for i, lhs := range decl.Lhs {
+			if ident := identOf(lhs); ident != nil && ident.Name == target.Name && len(decl.Lhs) == len(decl.Rhs) {
+				return isStringLiteral(decl.Rhs[i])
+			}
+		}
+	case *ast.Field:
+		ident := identOf(decl.Type)
+		return ident != nil && ident.Name == "string"
+	}
+	return false
+}
+
+// isStringLiteral reports whether expr is a string literal or a concatenation involving one
+func isStringLiteral(expr ast.Expr) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BasicLit:
+		return e.Kind == token.STRING
+	case *ast.BinaryExpr:
+		return e.Op == token.ADD && (isStringLiteral(e.X) || isStringLiteral(e.Y))
+	}
+	return false
+}
+
+// checkAppend reports slices appended to on every iteration of a loop with a known trip count
+// although they were created without capacity
+func (scan *performanceScan) checkAppend(assign *ast.AssignStmt, stack []ast.Node, loop ast.Node) {
+	if assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return
+	}
+	target := identOf(assign.Lhs[0])
+	call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+	if target == nil || !ok || call.Ellipsis.IsValid() || len(call.Args) < 2 {
+		return
+	}
+	if fun := identOf(call.Fun); fun == nil || fun.Name != "append" {
+		return
+	}
+	if base := identOf(call.Args[0]); base == nil || base.Name != target.Name {
+		return
+	}
+
+	// Only unconditional appends grow the slice by a predictable amount
+	if parent, ok := stack[len(stack)-1].(*ast.BlockStmt); !ok || parent != loopBody(loop) {
+		return
+	}
+	bound := scan.loopBound(loop)
+	if bound == "" {
+		return
+	}
+	elem := scan.emptySliceDecl(target, loop)
+	key := fmt.Sprintf("append_%d_%s", loop.Pos(), target.Name)
+	if elem == nil || scan.reported[key] || appendCount(loopBody(loop), target.Name) != 1 {
+		return
+	}
+	scan.reported[key] = true
+
+	scan.report(PerformanceAppendWithoutPrealloc, assign.Pos(), ImpactMedium,
+		fmt.Sprintf("'%s' grows by one element per iteration over %s but starts without capacity, reallocating as it grows; create it with make(%s, 0, %s)",
+			target.Name, bound, types.ExprString(elem), bound))
+}
+
+// appendCount counts the appends to the slice named name in node
+func appendCount(node ast.Node, name string) int {
+	count := 0
+	ast.Inspect(node, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && len(call.Args) > 0 {
+			if fun, base := identOf(call.Fun), identOf(call.Args[0]); fun != nil && fun.Name == "append" && base != nil && base.Name == name {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
+
+// loopBody returns the body block of a for or range loop
+func loopBody(loop ast.Node) *ast.BlockStmt {
+	switch l := loop.(type) {
+	case *ast.ForStmt:
+		return l.Body
+	case *ast.RangeStmt:
+		return l.Body
+	}
+	return nil
+}
+
+// loopBound returns an expression for the number of iterations, or "" when it is not known up front
+func (scan *performanceScan) loopBound(loop ast.Node) string {
+	switch l := loop.(type) {
+	case *ast.RangeStmt:
+		if info := scan.pkg.TypesInfo(); info != nil {
+			if t := info.TypeOf(l.X); t != nil {
+				switch u := t.Underlying().(type) {
+				case *types.Slice, *types.Array, *types.Map:
+					return "len(" + types.ExprString(l.X) + ")"
+				case *types.Basic:
+					if u.Info()&types.IsInteger != 0 {
+						return types.ExprString(l.X)
+					}
+				}
+				return ""
+			}
+		}
+		switch x := ast.Unparen(l.X).(type) {
+		case *ast.Ident:
+			if x.Obj != nil && valueExprKind(declaredValue(x)) != "" {
+				return "len(" + x.Name + ")"
+			}
+			if x.Obj != nil {
+				if field, ok := x.Obj.Decl.(*ast.Field); ok && typeExprKind(field.Type) != "" {
+					return "len(" + x.Name + ")"
+				}
+			}
+		case *ast.SelectorExpr:
+			return "len(" + types.ExprString(x) + ")"
+		}
+	case *ast.ForStmt:
+		cond, ok := l.Cond.(*ast.BinaryExpr)
+		post, isInc := l.Post.(*ast.IncDecStmt)
+		if !ok || !isInc || post.Tok != token.INC || (cond.Op != token.LSS && cond.Op != token.LEQ) {
+			return ""
+		}
+		if counter := identOf(cond.X); counter != nil && types.ExprString(post.X) == counter.Name {
+			return types.ExprString(cond.Y)
+		}
+	}
+	return ""
+}
+
+// declaredValue returns the initial value of a variable declared with a value
+func declaredValue(ident *ast.Ident) ast.Expr {
+	if ident.Obj == nil {
+		return nil
+	}
+	switch decl := ident.Obj.Decl.(type) {
+	case *ast.AssignStmt:
+		for i, lhs := range decl.Lhs {
+			if id := identOf(lhs); id != nil && id.Name == ident.Name && len(decl.Lhs) == len(decl.Rhs) {
+				return decl.Rhs[i]
+			}
+		}
+	case *ast.ValueSpec:
+		for i, name := range decl.Names {
+			if name.Name == ident.Name && i < len(decl.Values) {
+				return decl.Values[i]
+			}
+		}
+	}
+	return nil
+}
+
+// emptySliceDecl returns the slice type of target when it is declared before the loop without
+// elements or capacity and still empty when the loop starts, or nil otherwise
+func (scan *performanceScan) emptySliceDecl(target *ast.Ident, loop ast.Node) ast.Expr {
+	declared := scan.declarationPos(target)
+	if !declared.IsValid() || declared >= loop.Pos() || declared < scan.funcDecl.Body.Pos() {
+		return nil
+	}
+
+	// The slice must not be filled between its declaration and the loop, and an outer loop
+	// entered after the declaration would run this loop, and its appends, several times
+	between := false
+	ast.Inspect(scan.funcDecl.Body, func(n ast.Node) bool {
+		if between || n == nil || n.End() <= declared || n.Pos() >= loop.Pos() {
+			return !between && n != nil && n.Pos() < loop.Pos()
+		}
+		switch node := n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			between = node.Pos() > declared && node.End() >= loop.End()
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if ident := identOf(lhs); ident != nil && ident.Name == target.Name && ident.Pos() != declared {
+					between = true
+				}
+			}
+		}
+		return !between
+	})
+	if between {
+		return nil
+	}
+
+	var elem ast.Expr
+	ast.Inspect(scan.funcDecl.Body, func(n ast.Node) bool {
+		if elem != nil || n == nil || n.Pos() > declared {
+			return false
+		}
+		switch decl := n.(type) {
+		case *ast.ValueSpec:
+			for i, name := range decl.Names {
+				if name.Pos() != declared {
+					continue
+				}
+				if len(decl.Values) == 0 && typeExprKind(decl.Type) == "slice" {
+					elem = decl.Type
+				} else if i < len(decl.Values) {
+					elem = emptySlice(decl.Values[i])
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range decl.Lhs {
+				if lhs.Pos() == declared && decl.Tok == token.DEFINE && len(decl.Lhs) == len(decl.Rhs) {
+					elem = emptySlice(decl.Rhs[i])
+				}
+			}
+		}
+		return true
+	})
+	return elem
+}
+
+// emptySlice returns the slice type of an empty literal or a zero-length make without capacity
+func emptySlice(value ast.Expr) ast.Expr {
+	switch v := ast.Unparen(value).(type) {
+	case *ast.CompositeLit:
+		if len(v.Elts) == 0 && v.Type != nil && typeExprKind(v.Type) == "slice" {
+			return v.Type
+		}
+	case *ast.CallExpr:
+		fun := identOf(v.Fun)
+		if fun == nil || fun.Name != "make" || len(v.Args) != 2 || typeExprKind(v.Args[0]) != "slice" {
+			return nil
+		}
+		if lit, ok := ast.Unparen(v.Args[1]).(*ast.BasicLit); ok && lit.Value == "0" {
+			return v.Args[0]
+		}
+	}
+	return nil
+}
+
+// checkCompile reports regular expressions and templates compiled from constants inside
+// functions that run repeatedly instead of once at package initialization
+func (scan *performanceScan) checkCompile(call *ast.CallExpr, stack []ast.Node, loop ast.Node) {
+	what := scan.compiledConstant(call)
+	if what == "" || scan.funcName == "init" || scan.cachedOnce(call, stack) {
+		return
+	}
+
+	switch {
+	case loop != nil:
+		scan.report(PerformanceCompileInHotPath, call.Pos(), ImpactHigh,
+			fmt.Sprintf("%s compiles the same constant on every loop iteration; compile it once into a package-level variable", what))
+	case scan.isHandler() || scan.callers[scan.funcDecl] > 1 || scan.loopCalled[scan.funcDecl]:
+		scan.report(PerformanceCompileInHotPath, call.Pos(), ImpactMedium,
+			fmt.Sprintf("%s compiles the same constant every time %s runs, and %s is called repeatedly; compile it once into a package-level variable", what, scan.funcName, scan.funcName))
+	}
+}
+
+// compiledConstant describes a regexp or template compilation of a constant, or returns ""
+func (scan *performanceScan) compiledConstant(call *ast.CallExpr) string {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 || !scan.isConstant(call.Args[0]) {
+		return ""
+	}
+
+	importPath := importedPackage(scan.pkg, scan.imports, selector.X)
+	switch selector.Sel.Name {
+	case "MustCompile", "Compile", "MustCompilePOSIX", "CompilePOSIX":
+		if importPath == "regexp" {
+			return "regexp." + selector.Sel.Name
+		}
+	case "ParseFiles", "ParseGlob":
+		if importPath == "text/template" || importPath == "html/template" {
+			return "template." + selector.Sel.Name
+		}
+	case "Parse":
+		if scan.isTemplate(selector.X) {
+			return "template.Parse"
+		}
+	}
+	return ""
+}
+
+// isTemplate reports whether expr is a text/template or html/template Template
+func (scan *performanceScan) isTemplate(expr ast.Expr) bool {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if t := info.TypeOf(expr); t != nil {
+			name := types.TypeString(t, nil)
+			return name == "*text/template.Template" || name == "*html/template.Template"
+		}
+	}
+
+	// Without types, follow the builder chain back to template.New
+	for {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		if importPath := importedPackage(scan.pkg, scan.imports, selector.X); importPath != "" {
+			return selector.Sel.Name == "New" && (importPath == "text/template" || importPath == "html/template")
+		}
+		expr = selector.X
+	}
+}
+
+// cachedOnce reports whether the compiled value is stored for reuse: inside a function passed to
+// sync.Once and friends, or assigned to a variable that outlives the function
+func (scan *performanceScan) cachedOnce(call *ast.CallExpr, stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch node := stack[i].(type) {
+		case *ast.FuncLit:
+			if i > 0 {
+				if parent, ok := stack[i-1].(*ast.CallExpr); ok {
+					switch calledMethod(parent) {
+					case "Do", "OnceFunc", "OnceValue", "OnceValues":
+						return true
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				ident := identOf(lhs)
+				if ident == nil {
+					// Fields and map entries keep the value beyond this call
+					return true
+				}
+				if declared := scan.declarationPos(ident); declared.IsValid() && !scan.inFunction(declared) {
+					return true
+				}
+			}
+			return false
+		case ast.Stmt:
+			return false
+		}
+	}
+	return false
+}
+
+// inFunction reports whether pos lies in the function being analyzed
+func (scan *performanceScan) inFunction(pos token.Pos) bool {
+	return pos >= scan.funcDecl.Pos() && pos < scan.funcDecl.End()
+}
+
+// isHandler reports whether the function serves HTTP requests
+func (scan *performanceScan) isHandler() bool {
+	for _, field := range scan.funcDecl.Type.Params.List {
+		if star, ok := field.Type.(*ast.StarExpr); ok && types.ExprString(star.X) == "http.Request" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSprintf reports fmt.Sprintf calls that only convert a single value to a string
+func (scan *performanceScan) checkSprintf(call *ast.CallExpr, loop ast.Node) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "Sprintf" || len(call.Args) != 2 || importedPackage(scan.pkg, scan.imports, selector.X) != "fmt" {
+		return
+	}
+	format, ok := call.Args[0].(*ast.BasicLit)
+	if !ok {
+		return
+	}
+
+	arg := types.ExprString(call.Args[1])
+	replacement := ""
+	kind := scan.valueKind(call.Args[1])
+	switch format.Value {
+	case `"%d"`:
+		if kind == "int" || kind == "" {
+			replacement = "strconv.Itoa(" + arg + ")"
+		} else if kind == "int64" {
+			replacement = "strconv.FormatInt(" + arg + ", 10)"
+		}
+	case `"%s"`, `"%v"`:
+		switch kind {
+		case "string":
+			replacement = arg
+		case "int":
+			replacement = "strconv.Itoa(" + arg + ")"
+		case "":
+			if format.Value == `"%s"` {
+				replacement = arg + " (or its String method)"
+			}
+		}
+	case `"%t"`:
+		if kind == "bool" || kind == "" {
+			replacement = "strconv.FormatBool(" + arg + ")"
+		}
+	}
+	if replacement == "" {
+		return
+	}
+
+	impact := ImpactLow
+	if loop != nil {
+		impact = ImpactMedium
+	}
+	scan.report(PerformanceSprintfConversion, call.Pos(), impact,
+		fmt.Sprintf("fmt.Sprintf(%s, %s) parses the format and boxes the value to convert it; use %s", format.Value, arg, replacement))
+}
+
+// valueKind classifies the type of expr as "int", "int64", "string", "bool" or "other",
+// or "" when no type information is available
+func (scan *performanceScan) valueKind(expr ast.Expr) string {
+	info := scan.pkg.TypesInfo()
+	if info == nil {
+		return ""
+	}
+	t := info.TypeOf(expr)
+	if t == nil {
+		return ""
+	}
+	basic, ok := t.(*types.Basic)
+	if !ok {
+		return "other"
+	}
+	switch basic.Kind() {
+	case types.Int, types.UntypedInt:
+		return "int"
+	case types.Int64:
+		return "int64"
+	case types.String:
+		return "string"
+	case types.Bool:
+		return "bool"
+	}
+	return "other"
+}
+
+// checkConversion reports []byte and string conversions of loop-invariant values inside a loop
+func (scan *performanceScan) checkConversion(call *ast.CallExpr, stack []ast.Node, loop ast.Node) {
+	if len(call.Args) != 1 {
+		return
+	}
+	operand := identOf(call.Args[0])
+	if operand == nil {
+		return
+	}
+	target := scan.conversionTarget(call, operand)
+	if target == "" {
+		return
+	}
+
+	// The compiler avoids the copy for map keys, comparisons, concatenation and range
+	switch parent := stack[len(stack)-1].(type) {
+	case *ast.IndexExpr:
+		if parent.Index == call {
+			return
+		}
+	case *ast.BinaryExpr, *ast.RangeStmt, *ast.SwitchStmt:
+		return
+	}
+
+	declared := scan.declarationPos(operand)
+	if !declared.IsValid() || declared >= loop.Pos() || assignedIn(loopBody(loop), operand.Name) {
+		return
+	}
+
+	// Storing the result outside the loop caches it, typically under a nil check
+	if assign, ok := stack[len(stack)-1].(*ast.AssignStmt); ok {
+		for _, lhs := range assign.Lhs {
+			if ident := identOf(lhs); ident != nil {
+				if pos := scan.declarationPos(ident); pos.IsValid() && pos < loop.Pos() {
+					return
+				}
+			}
+		}
+	}
+
+	scan.report(PerformanceConversionInLoop, call.Pos(), ImpactMedium,
+		fmt.Sprintf("%s(%s) copies '%s' on every iteration although it does not change in the loop; convert it once before the loop", target, operand.Name, operand.Name))
+}
+
+// conversionTarget returns "[]byte" or "string" when call converts between the two, or ""
+func (scan *performanceScan) conversionTarget(call *ast.CallExpr, operand *ast.Ident) string {
+	target := ""
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.ArrayType:
+		if elem := identOf(fun.Elt); fun.Len == nil && elem != nil && (elem.Name == "byte" || elem.Name == "uint8") {
+			target = "[]byte"
+		}
+	case *ast.Ident:
+		if fun.Name == "string" {
+			target = "string"
+		}
+	}
+	if target == "" {
+		return ""
+	}
+
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if t := info.TypeOf(operand); t != nil {
+			if target == "[]byte" {
+				if basic, ok := t.Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+					return target
+				}
+				return ""
+			}
+			if slice, ok := t.Underlying().(*types.Slice); ok {
+				if elem, ok := slice.Elem().(*types.Basic); ok && elem.Kind() == types.Byte {
+					return target
+				}
+			}
+			return ""
+		}
+	}
+
+	if target == "[]byte" {
+		return target
+	}
+	// Without types, string(x) is only a copy when x is known to be a byte slice
+	if value := declaredValue(operand); value != nil {
+		if call, ok := ast.Unparen(value).(*ast.CallExpr); ok && len(call.Args) > 0 {
+			if fun := identOf(call.Fun); fun != nil && fun.Name == "make" && types.ExprString(call.Args[0]) == "[]byte" {
+				return target
+			}
+			if types.ExprString(call.Fun) == "[]byte" {
+				return target
+			}
+		}
+	}
+	if operand.Obj != nil {
+		if field, ok := operand.Obj.Decl.(*ast.Field); ok && types.ExprString(field.Type) == "[]byte" {
+			return target
+		}
+	}
+	return ""
+}
+
+// assignedIn reports whether a variable named name is assigned anywhere in node
+func assignedIn(node ast.Node, name string) bool {
+	assigned := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range stmt.Lhs {
+				if ident := identOf(lhs); ident != nil && ident.Name == name {
+					assigned = true
+				}
+			}
+		case *ast.IncDecStmt:
+			if ident := identOf(stmt.X); ident != nil && ident.Name == name {
+				assigned = true
+			}
+		case *ast.UnaryExpr:
+			if ident := identOf(stmt.X); stmt.Op == token.AND && ident != nil && ident.Name == name {
+				assigned = true
+			}
+		}
+		return !assigned
+	})
+	return assigned
+}
+
+// checkParams reports receivers and parameters copying a large struct on every call
+func (scan *performanceScan) checkParams(funcDecl *ast.FuncDecl) {
+	var fields []*ast.Field
+	if funcDecl.Recv != nil {
+		fields = append(fields, funcDecl.Recv.List...)
+	}
+	fields = append(fields, funcDecl.Type.Params.List...)
+
+	for _, field := range fields {
+		size := scan.structSize(field.Type)
+		if size < largeValueBytes || len(field.Names) == 0 {
+			continue
+		}
+
+		impact := ImpactLow
+		if size >= hugeValueBytes {
+			impact = ImpactMedium
+		}
+		role, fix := "parameter", "pass *"+types.ExprString(field.Type)+" instead"
+		if funcDecl.Recv != nil && field == funcDecl.Recv.List[0] {
+			role, fix = "receiver", "use a pointer receiver"
+		}
+		scan.report(PerformanceLargeValueParam, field.Pos(), impact,
+			fmt.Sprintf("%s '%s' copies %s (about %d bytes) on every call; %s",
+				role, field.Names[0].Name, types.ExprString(field.Type), size, fix))
+	}
+}
+
+// structSize returns the size of a struct type expression, or 0 for other types; packages that
+// failed to type-check fall back to the syntactic estimate
+func (scan *performanceScan) structSize(expr ast.Expr) int64 {
+	if info := scan.pkg.TypesInfo(); info != nil && !scan.pkg.HasTypeErrors() {
+		if t := info.TypeOf(expr); t != nil {
+			if _, ok := t.Underlying().(*types.Struct); !ok {
+				return 0
+			}
+			return types.SizesFor("gc", "amd64").Sizeof(t)
+		}
+	}
+
+	ident := identOf(expr)
+	if ident == nil || scan.structs[ident.Name] == nil {
+		return 0
+	}
+	return scan.estimateSize(ident, map[string]bool{})
+}
+
+// estimateSize approximates the size of a type expression from its declaration; types declared
+// elsewhere count as zero, so the estimate is a lower bound
+func (scan *performanceScan) estimateSize(expr ast.Expr, visiting map[string]bool) int64 {
+	switch t := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		if size, ok := basicTypeSizes[t.Name]; ok {
+			return size
+		}
+		structType := scan.structs[t.Name]
+		if structType == nil || visiting[t.Name] {
+			return 0
+		}
+		visiting[t.Name] = true
+		defer delete(visiting, t.Name)
+		return scan.estimateSize(structType, visiting)
+	case *ast.StructType:
+		var size int64
+		for _, field := range t.Fields.List {
+			count := int64(len(field.Names))
+			if count == 0 {
+				count = 1
+			}
+			size += count * scan.estimateSize(field.Type, visiting)
+		}
+		return size
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return 24
+		}
+		lit, ok := t.Len.(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return 0
+		}
+		length, err := strconv.ParseInt(lit.Value, 0, 64)
+		if err != nil {
+			return 0
+		}
+		return length * scan.estimateSize(t.Elt, visiting)
+	case *ast.StarExpr, *ast.MapType, *ast.ChanType, *ast.FuncType:
+		return 8
+	case *ast.InterfaceType:
+		return 16
+	}
+	return 0
+}
+
+// isConstant reports whether expr is a compile-time constant
+func (scan *performanceScan) isConstant(expr ast.Expr) bool {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if tv, ok := info.Types[expr]; ok {
+			return tv.Value != nil
+		}
+	}
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.Ident:
+		return e.Obj != nil && e.Obj.Kind == ast.Con
+	case *ast.BinaryExpr:
+		return scan.isConstant(e.X) && scan.isConstant(e.Y)
+	}
+	return false
+}
+
+// declarationPos returns where the variable named by ident is declared, or NoPos if unresolved
+func (scan *performanceScan) declarationPos(ident *ast.Ident) token.Pos {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if obj := info.ObjectOf(ident); obj != nil {
+			return obj.Pos()
+		}
+	}
+	if ident.Obj != nil {
+		return ident.Obj.Pos()
+	}
+	return token.NoPos
+}
+
+// report records a finding located at pos with its estimated impact
+func (scan *performanceScan) report(issue PerformanceIssue, pos token.Pos, impact PerformanceImpact, description string) {
+	position := scan.pkg.FileSet().Position(pos)
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+
+	severity := valueobjects.SeverityInfo
+	if impact >= ImpactMedium {
+		severity = valueobjects.SeverityWarning
+	}
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue.String(), scan.funcName, position.Line, position.Column),
+		entities.FindingTypePerformance,
+		location,
+		fmt.Sprintf("Performance issue in %s: %s detected - %s (impact: %s)", scan.funcName, issue.String(), description, impact.String()),
+		severity,
+	)
+	finding.AddMetadata("impact", impact.String())
+
+	scan.findings = append(scan.findings, finding)
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestPerformanceDetector_DetectPerformanceIssues(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		expectedIssues int
+		expectedTypes  []string
+	}{
+		{
+			name: "String concatenation in loop",
+			code: `
+package main
+func join(parts []string) string {
+	result := ""
+	for _, p := range parts {
+		result += p + ","
+	}
+	return result
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"string_concat_in_loop", "'result' is grown with +", "impact: high"},
+		},
+		{
+			name: "String declared inside the loop - no issue",
+			code: `
+package main
+func labels(parts []string, emit func(string)) {
+	for _, p := range parts {
+		label := "item"
+		label += ":" + p
+		emit(label)
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Append without preallocation",
+			code: `
+package main
+func double(values []int) []int {
+	var out []int
+	for _, v := range values {
+		out = append(out, v*2)
+	}
+	return out
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"append_without_prealloc", "make([]int, 0, len(values))", "impact: medium"},
+		},
+		{
+			name: "Conditional append and preallocated slice - no issue",
+			code: `
+package main
+func filter(values []int) ([]int, []int) {
+	var even []int
+	for _, v := range values {
+		if v%2 == 0 {
+			even = append(even, v)
+		}
+	}
+	all := make([]int, 0, len(values))
+	for _, v := range values {
+		all = append(all, v)
+	}
+	return even, all
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Regexp compiled inside a loop",
+			code: `
+package main
+import "regexp"
+func count(lines []string) int {
+	n := 0
+	for _, line := range lines {
+		if regexp.MustCompile("^[a-z]+$").MatchString(line) {
+			n++
+		}
+	}
+	return n
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"compile_in_hot_path", "regexp.MustCompile compiles the same constant on every loop iteration"},
+		},
+		{
+			name: "Template parsed in a function called from a loop",
+			code: `
+package main
+import (
+	"io"
+	"text/template"
+)
+func render(w io.Writer, name string) error {
+	t := template.Must(template.New("greet").Parse("Hello {{.}}\n"))
+	return t.Execute(w, name)
+}
+func renderAll(w io.Writer, names []string) {
+	for _, name := range names {
+		render(w, name)
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"template.Parse compiles the same constant every time render runs", "impact: medium"},
+		},
+		{
+			name: "Regexp cached in a package variable - no issue",
+			code: `
+package main
+import (
+	"regexp"
+	"sync"
+)
+var (
+	wordOnce sync.Once
+	word     *regexp.Regexp
+)
+func isWord(s string) bool {
+	wordOnce.Do(func() {
+		word = regexp.MustCompile("^[a-z]+$")
+	})
+	return word.MatchString(s)
+}
+func check(items []string) {
+	for _, item := range items {
+		isWord(item)
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Defer inside loop",
+			code: `
+package main
+import "sync"
+func drain(mu *sync.Mutex, jobs []func()) {
+	for _, job := range jobs {
+		mu.Lock()
+		defer mu.Unlock()
+		job()
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"defer_in_loop", "queues mu.Unlock until drain returns"},
+		},
+		{
+			name: "Sprintf used for a simple conversion",
+			code: `
+package main
+import "fmt"
+func label(n int) string {
+	return fmt.Sprintf("%d", n)
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"sprintf_conversion", "use strconv.Itoa(n)", "impact: low"},
+		},
+		{
+			name: "Loop-invariant []byte conversion",
+			code: `
+package main
+import "io"
+func repeat(w io.Writer, line string, n int) {
+	for i := 0; i < n; i++ {
+		w.Write([]byte(line))
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"conversion_in_loop", "[]byte(line) copies 'line' on every iteration"},
+		},
+		{
+			name: "Conversion used as map key - no issue",
+			code: `
+package main
+func lookup(counts map[string]int, key []byte, n int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		total += counts[string(key)]
+	}
+	return total
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Large struct passed by value",
+			code: `
+package main
+type frame struct {
+	header  [64]byte
+	payload [96]byte
+	length  int
+}
+func checksum(f frame) int {
+	return int(f.header[0]) + f.length
+}
+func (f frame) size() int {
+	return f.length
+}`,
+			expectedIssues: 2,
+			expectedTypes:  []string{"parameter 'f' copies frame (about 168 bytes)", "receiver 'f' copies frame", "use a pointer receiver"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			detector := NewASTPerformanceDetector()
+			config := valueobjects.DefaultAnalysisConfiguration()
+			findings, err := detector.DetectPerformanceIssues(node, fset, config)
+			if err != nil {
+				t.Fatalf("DetectPerformanceIssues failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+		})
+	}
+}
+
+func TestPerformanceDetector_TypedChecks(t *testing.T) {
+	src := `
+package store
+import (
+	"fmt"
+	"sync"
+)
+type record struct {
+	mu    sync.Mutex
+	names [16]string
+}
+func describe(r record, id int64, name string) string {
+	return fmt.Sprintf("%d", id) + fmt.Sprintf("%s", name) + fmt.Sprintf("%d", r.names)
+}`
+
+	pkg := checkPackage(t, src)
+
+	findings, err := NewASTPerformanceDetector().DetectPackagePerformanceIssues(pkg, valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("DetectPackagePerformanceIssues failed: %v", err)
+	}
+
+	var messages []string
+	for _, finding := range findings {
+		messages = append(messages, finding.Message())
+	}
+	joined := strings.Join(messages, "\n")
+
+	for _, expected := range []string{
+		"parameter 'r' copies record (about 264 bytes)",
+		"use strconv.FormatInt(id, 10)",
+		`fmt.Sprintf("%s", name) parses the format and boxes the value to convert it; use name`,
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected %q, got:\n%s", expected, joined)
+		}
+	}
+	if len(findings) != 3 {
+		t.Errorf("Expected 3 findings, got %d:\n%s", len(findings), joined)
+	}
+	for _, finding := range findings {
+		if finding.Metadata()["impact"] != "low" {
+			t.Errorf("Expected low impact, got %v", finding.Metadata()["impact"])
+		}
+	}
+}
+
+func TestPerformanceDetector_TypeErrorsUseEstimatedSize(t *testing.T) {
+	src := `
+package store
+type record struct {
+	owner Missing
+	slots [64]int64
+}
+func describe(r record) int64 {
+	return r.slots[0]
+}`
+
+	pkg, err := typeCheckPackage(t, src)
+	if err == nil {
+		t.Fatalf("Expected type errors for undefined Missing")
+	}
+	pkg.SetTypeErrors(true)
+	findings, err := NewASTPerformanceDetector().DetectPackagePerformanceIssues(pkg, valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("DetectPackagePerformanceIssues failed: %v", err)
+	}
+
+	if len(findings) != 1 || !strings.Contains(findings[0].Message(), "parameter 'r' copies record (about 512 bytes)") {
+		var messages []string
+		for _, finding := range findings {
+			messages = append(messages, finding.Message())
+		}
+		t.Errorf("Expected estimated 512-byte copy of record, got:\n%s", strings.Join(messages, "\n"))
+	}
+}
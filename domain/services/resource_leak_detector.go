@@ -0,0 +1,623 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// ResourceLeakIssue represents the different ways a Close-able value is leaked
+type ResourceLeakIssue int
+
+const (
+	ResourceUnclosed ResourceLeakIssue = iota
+	ResourceCloseSkipped
+	ResourceDeferBeforeCheck
+	ResourceDeferInLoop
+)
+
+// String returns a string representation of the resource leak issue
+func (rli ResourceLeakIssue) String() string {
+	switch rli {
+	case ResourceUnclosed:
+		return "unclosed_resource"
+	case ResourceCloseSkipped:
+		return "close_skipped_on_return"
+	case ResourceDeferBeforeCheck:
+		return "defer_before_error_check"
+	case ResourceDeferInLoop:
+		return "deferred_close_in_loop"
+	default:
+		return "unknown"
+	}
+}
+
+// acquisitionPrefixes are the callee name prefixes that hand ownership of a new resource to the caller
+var acquisitionPrefixes = []string{"open", "create", "dial", "listen", "accept", "query", "prepare", "tempfile"}
+
+// acquisitionNames are the HTTP-style callee names that only acquire a resource when matched exactly
+var acquisitionNames = map[string]bool{"get": true, "post": true, "postform": true, "head": true, "do": true}
+
+// knownAcquisitions lists the standard library functions returning resources, used without type information;
+// the value is the selector of the Close-able part of the result
+var knownAcquisitions = map[string]string{
+	"os.Open":         "",
+	"os.Create":       "",
+	"os.OpenFile":     "",
+	"os.CreateTemp":   "",
+	"net.Dial":        "",
+	"net.DialTimeout": "",
+	"net.Listen":      "",
+	"sql.Open":        "",
+	"http.Get":        "Body",
+	"http.Post":       "Body",
+	"http.PostForm":   "Body",
+	"http.Head":       "Body",
+}
+
+// ResourceLeakDetector detects Close-able values that are not closed on every path
+type ResourceLeakDetector interface {
+	DetectResourceLeaks(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageResourceLeaks(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTResourceLeakDetector implements ResourceLeakDetector using AST analysis
+type ASTResourceLeakDetector struct{}
+
+// NewASTResourceLeakDetector creates a new AST-based resource leak detector
+func NewASTResourceLeakDetector() *ASTResourceLeakDetector {
+	return &ASTResourceLeakDetector{}
+}
+
+// resourceAcquisition is a statement obtaining a Close-able value
+type resourceAcquisition struct {
+	stmt     ast.Stmt
+	list     []ast.Stmt
+	index    int
+	value    *ast.Ident
+	errIdent *ast.Ident
+	resource string
+	call     string
+	inLoop   ast.Node
+	declares bool
+}
+
+// resourceScan holds the function being analyzed and the findings
+type resourceScan struct {
+	pkg      *PackageContext
+	funcName string
+	body     *ast.BlockStmt
+	findings []entities.AnalysisFinding
+}
+
+// DetectResourceLeaks analyzes code for leaked files, connections, rows and response bodies
+func (rld *ASTResourceLeakDetector) DetectResourceLeaks(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	scan := &resourceScan{pkg: newNodePackageContext(node, fset)}
+	scan.analyze(node)
+	return scan.findings, nil
+}
+
+// DetectPackageResourceLeaks analyzes every file of a package with its type information
+func (rld *ASTResourceLeakDetector) DetectPackageResourceLeaks(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	scan := &resourceScan{pkg: pkg}
+	for _, file := range pkg.Files() {
+		scan.analyze(file)
+	}
+	return scan.findings, nil
+}
+
+// analyze checks every function body under node, including function literals
+func (scan *resourceScan) analyze(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		funcDecl, ok := n.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			return true
+		}
+
+		scan.funcName = funcDecl.Name.Name
+		scan.checkBody(funcDecl.Body)
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok {
+				scan.checkBody(lit.Body)
+			}
+			return true
+		})
+		return false
+	})
+}
+
+// checkBody checks the resources acquired directly in one function body
+func (scan *resourceScan) checkBody(body *ast.BlockStmt) {
+	scan.body = body
+	for _, acquisition := range scan.acquisitions(body.List, nil) {
+		scan.checkAcquisition(acquisition)
+	}
+}
+
+// acquisitions walks nested statement lists, without entering function literals, for resource acquisitions
+func (scan *resourceScan) acquisitions(list []ast.Stmt, loop ast.Node) []*resourceAcquisition {
+	var found []*resourceAcquisition
+	for i, stmt := range list {
+		if acquisition := scan.acquisition(stmt); acquisition != nil {
+			acquisition.list, acquisition.index, acquisition.inLoop = list, i, loop
+			found = append(found, acquisition)
+		}
+
+		innerLoop := loop
+		switch stmt.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			innerLoop = stmt
+		}
+		for _, child := range childStmtLists(stmt) {
+			found = append(found, scan.acquisitions(child, innerLoop)...)
+		}
+	}
+	return found
+}
+
+// acquisition recognizes x, err := call(...) where the result or its Body field has a Close method
+func (scan *resourceScan) acquisition(stmt ast.Stmt) *resourceAcquisition {
+	var lhs []ast.Expr
+	var rhs []ast.Expr
+	declares := true
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		lhs, rhs, declares = s.Lhs, s.Rhs, s.Tok == token.DEFINE
+	case *ast.DeclStmt:
+		genDecl, ok := s.Decl.(*ast.GenDecl)
+		if !ok || len(genDecl.Specs) != 1 {
+			return nil
+		}
+		spec, ok := genDecl.Specs[0].(*ast.ValueSpec)
+		if !ok {
+			return nil
+		}
+		for _, name := range spec.Names {
+			lhs = append(lhs, name)
+		}
+		rhs = spec.Values
+	}
+	if len(lhs) == 0 || len(lhs) > 2 || len(rhs) != 1 {
+		return nil
+	}
+
+	value := identOf(lhs[0])
+	call, ok := ast.Unparen(rhs[0]).(*ast.CallExpr)
+	if value == nil || value.Name == "_" || !ok {
+		return nil
+	}
+
+	field, ok := scan.closeablePart(call, value)
+	if !ok {
+		return nil
+	}
+
+	acquisition := &resourceAcquisition{stmt: stmt, value: value, resource: value.Name, call: types.ExprString(call.Fun), declares: declares}
+	if field != "" {
+		acquisition.resource = value.Name + "." + field
+	}
+	if len(lhs) == 2 {
+		if errIdent := identOf(lhs[1]); errIdent != nil && errIdent.Name != "_" {
+			acquisition.errIdent = errIdent
+		}
+	}
+	return acquisition
+}
+
+// closeablePart reports whether call acquires a resource and names the field holding it,
+// "" when the result itself must be closed
+func (scan *resourceScan) closeablePart(call *ast.CallExpr, value *ast.Ident) (string, bool) {
+	info := scan.pkg.TypesInfo()
+	if info == nil {
+		field, ok := knownAcquisitions[types.ExprString(call.Fun)]
+		return field, ok
+	}
+
+	if !isAcquisitionName(calleeName(call)) {
+		return "", false
+	}
+	t := info.TypeOf(value)
+	if t == nil {
+		return "", false
+	}
+	if hasCloseMethod(t) {
+		return "", true
+	}
+	if pointer, ok := t.Underlying().(*types.Pointer); ok {
+		if structType, ok := pointer.Elem().Underlying().(*types.Struct); ok {
+			for i := 0; i < structType.NumFields(); i++ {
+				if field := structType.Field(i); field.Name() == "Body" && hasCloseMethod(field.Type()) {
+					return "Body", true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// calleeName returns the name of the function or method invoked by call
+func calleeName(call *ast.CallExpr) string {
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
+
+// isAcquisitionName reports whether a callee name suggests the caller receives a new resource
+func isAcquisitionName(name string) bool {
+	lower := strings.ToLower(name)
+	if acquisitionNames[lower] {
+		return true
+	}
+	for _, prefix := range acquisitionPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCloseMethod reports whether t has a Close() error method
+func hasCloseMethod(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, "Close")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	signature, ok := fn.Type().(*types.Signature)
+	if !ok || signature.Params().Len() != 0 || signature.Results().Len() != 1 {
+		return false
+	}
+	return types.Identical(signature.Results().At(0).Type(), types.Universe.Lookup("error").Type())
+}
+
+// checkAcquisition reports a resource that is leaked or closed at the wrong time
+func (scan *resourceScan) checkAcquisition(acquisition *resourceAcquisition) {
+	errCheck := scan.errorCheck(acquisition)
+
+	var closes []*ast.CallExpr
+	var deferred *ast.DeferStmt
+	handled := false
+	ast.Inspect(scan.body, func(n ast.Node) bool {
+		if n == nil || n.End() <= acquisition.stmt.End() {
+			return n != nil && n.Pos() <= acquisition.stmt.Pos()
+		}
+		switch node := n.(type) {
+		case *ast.DeferStmt:
+			if scan.closesResource(node, acquisition) {
+				if deferred == nil {
+					deferred = node
+				}
+				return false
+			}
+		case *ast.FuncLit:
+			// A closure closing the resource, such as a cleanup callback, takes care of it
+			if scan.closesResource(node, acquisition) {
+				handled = true
+			}
+			return false
+		case *ast.CallExpr:
+			if isSelectorCallOn(node, acquisition.resource, "Close") {
+				closes = append(closes, node)
+			}
+		}
+		return true
+	})
+
+	if deferred != nil {
+		// A defer nested under a nil guard is safe before the error check
+		if errCheck != nil && deferred.Pos() < errCheck.Pos() && scan.inList(deferred, acquisition.list) {
+			scan.report(ResourceDeferBeforeCheck, deferred.Pos(), acquisition, valueobjects.SeverityError,
+				fmt.Sprintf("defer %s.Close() runs before %s is checked, so it acts on an invalid value when %s fails; move it after the error check",
+					acquisition.resource, acquisition.errIdent.Name, acquisition.call))
+		}
+		if acquisition.inLoop != nil && deferred.Pos() > acquisition.inLoop.Pos() && deferred.End() < acquisition.inLoop.End() {
+			scan.report(ResourceDeferInLoop, deferred.Pos(), acquisition, valueobjects.SeverityWarning,
+				fmt.Sprintf("defer %s.Close() inside a loop keeps every %s open until %s returns; close it at the end of each iteration or move the loop body into a function",
+					acquisition.resource, acquisition.resource, scan.funcName))
+		}
+		return
+	}
+
+	if handled || scan.escapes(acquisition) || (scan.funcName == "main" && len(closes) == 0) {
+		return
+	}
+
+	if len(closes) == 0 {
+		scan.report(ResourceUnclosed, acquisition.stmt.Pos(), acquisition, valueobjects.SeverityWarning,
+			fmt.Sprintf("'%s' from %s is never closed, returned or stored; add defer %s.Close() after the error check",
+				acquisition.resource, acquisition.call, acquisition.resource))
+		return
+	}
+
+	// Assignments to variables declared elsewhere outlive this block, and a close guarded by a
+	// success check covers every path on which the resource exists
+	if !acquisition.declares || scan.guardedClose(acquisition, closes) {
+		return
+	}
+
+	for _, ret := range scan.returnsAfter(acquisition, errCheck) {
+		if !scan.closedBefore(ret.End()-1, closes) {
+			scan.report(ResourceCloseSkipped, ret.Pos(), acquisition, valueobjects.SeverityWarning,
+				fmt.Sprintf("'%s' from %s is not closed when %s returns at line %d; defer %s.Close() right after the error check",
+					acquisition.resource, acquisition.call, scan.funcName, scan.pkg.FileSet().Position(ret.Pos()).Line, acquisition.resource))
+			return
+		}
+	}
+
+	// The resource also goes out of scope when its block falls through
+	last := acquisition.list[len(acquisition.list)-1]
+	if !isTerminatingStmt(last) && !scan.closedBefore(last.End()-1, closes) {
+		scan.report(ResourceCloseSkipped, last.End()-1, acquisition, valueobjects.SeverityWarning,
+			fmt.Sprintf("'%s' from %s is only closed on some paths before line %d; defer %s.Close() right after the error check",
+				acquisition.resource, acquisition.call, scan.pkg.FileSet().Position(last.End()).Line, acquisition.resource))
+	}
+}
+
+// guardedClose reports whether one of the closes sits in an if statement testing that the
+// acquisition succeeded, such as err == nil or f != nil
+func (scan *resourceScan) guardedClose(acquisition *resourceAcquisition, closes []*ast.CallExpr) bool {
+	guards := map[string]bool{acquisition.value.Name + " != nil": true, acquisition.resource + " != nil": true}
+	failures := map[string]bool{acquisition.value.Name + " == nil": true}
+	if acquisition.errIdent != nil {
+		guards[acquisition.errIdent.Name+" == nil"] = true
+		failures[acquisition.errIdent.Name+" != nil"] = true
+	}
+	closesIn := func(node ast.Node) bool {
+		for _, call := range closes {
+			if node != nil && call.Pos() >= node.Pos() && call.End() <= node.End() {
+				return true
+			}
+		}
+		return false
+	}
+
+	guarded := false
+	ast.Inspect(scan.body, func(n ast.Node) bool {
+		ifStmt, ok := n.(*ast.IfStmt)
+		if !ok || guarded {
+			return !guarded
+		}
+		cond := types.ExprString(ifStmt.Cond)
+		guarded = (guards[cond] && closesIn(ifStmt.Body)) || (failures[cond] && closesIn(ifStmt.Else))
+		return !guarded
+	})
+	return guarded
+}
+
+// inList reports whether stmt is one of the statements of list
+func (scan *resourceScan) inList(stmt ast.Stmt, list []ast.Stmt) bool {
+	for _, candidate := range list {
+		if candidate == stmt {
+			return true
+		}
+	}
+	return false
+}
+
+// isSelectorCallOn reports whether call invokes method on the expression spelled receiver
+func isSelectorCallOn(call *ast.CallExpr, receiver, method string) bool {
+	selector, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == method && types.ExprString(ast.Unparen(selector.X)) == receiver
+}
+
+// closesResource reports whether node calls Close on the resource or hands it to a cleanup function
+func (scan *resourceScan) closesResource(node ast.Node, acquisition *resourceAcquisition) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && !found {
+			if isSelectorCallOn(call, acquisition.resource, "Close") {
+				found = true
+			}
+			if strings.Contains(strings.ToLower(calleeName(call)), "close") && scan.passesResource(call, acquisition) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// passesResource reports whether call receives the resource or the value holding it as an argument
+func (scan *resourceScan) passesResource(call *ast.CallExpr, acquisition *resourceAcquisition) bool {
+	for _, arg := range call.Args {
+		text := types.ExprString(ast.Unparen(arg))
+		if text == acquisition.resource || text == acquisition.value.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// errorCheck returns the first if statement after the acquisition testing its error
+func (scan *resourceScan) errorCheck(acquisition *resourceAcquisition) *ast.IfStmt {
+	if acquisition.errIdent == nil {
+		return nil
+	}
+	for _, stmt := range acquisition.list[acquisition.index+1:] {
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok {
+			continue
+		}
+		mentions := false
+		ast.Inspect(ifStmt.Cond, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && ident.Name == acquisition.errIdent.Name {
+				mentions = true
+			}
+			return !mentions
+		})
+		if mentions {
+			return ifStmt
+		}
+	}
+	return nil
+}
+
+// escapes reports whether the resource is returned, stored, sent or handed to a function of the
+// package, any of which transfers the duty to close it
+func (scan *resourceScan) escapes(acquisition *resourceAcquisition) bool {
+	name := acquisition.value.Name
+	isValue := func(expr ast.Expr) bool {
+		text := types.ExprString(ast.Unparen(expr))
+		if text == name || text == acquisition.resource {
+			return true
+		}
+		if unary, ok := ast.Unparen(expr).(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			return types.ExprString(ast.Unparen(unary.X)) == name
+		}
+		if assert, ok := ast.Unparen(expr).(*ast.TypeAssertExpr); ok {
+			return types.ExprString(ast.Unparen(assert.X)) == name
+		}
+		return false
+	}
+	contains := func(expr ast.Expr) bool {
+		found := false
+		ast.Inspect(expr, func(n ast.Node) bool {
+			// Calls outside the package only use the resource; its owner still has to close it
+			if call, ok := n.(*ast.CallExpr); ok && scan.pkg.ResolveCall(call) == nil {
+				return false
+			}
+			if e, ok := n.(ast.Expr); ok && isValue(e) {
+				found = true
+			}
+			return !found
+		})
+		return found
+	}
+
+	// Named results and variables captured from an enclosing function carry the resource out of the body
+	if declared := scan.declarationPos(acquisition.value); declared.IsValid() && (declared < scan.body.Pos() || declared >= scan.body.End()) {
+		return true
+	}
+
+	escaped := false
+	ast.Inspect(scan.body, func(n ast.Node) bool {
+		if escaped || n == nil || n.End() <= acquisition.stmt.End() {
+			return !escaped && n != nil && n.Pos() <= acquisition.stmt.Pos()
+		}
+		switch node := n.(type) {
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				escaped = escaped || contains(result)
+			}
+		case *ast.AssignStmt:
+			for i, rhs := range node.Rhs {
+				if i < len(node.Lhs) && identOf(node.Lhs[i]) != nil && identOf(node.Lhs[i]).Name == "_" {
+					continue
+				}
+				escaped = escaped || isValue(rhs)
+			}
+		case *ast.CompositeLit:
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				escaped = escaped || isValue(elt)
+			}
+		case *ast.SendStmt:
+			escaped = isValue(node.Value)
+		case *ast.CallExpr:
+			if fun, ok := node.Fun.(*ast.Ident); ok && fun.Name == "append" {
+				escaped = scan.passesResource(node, acquisition)
+			} else if scan.pkg.ResolveCall(node) != nil {
+				escaped = scan.passesResource(node, acquisition)
+			}
+		case *ast.GoStmt:
+			escaped = scan.passesResource(node.Call, acquisition)
+		}
+		return !escaped
+	})
+	return escaped
+}
+
+// declarationPos returns where the variable named by ident is declared, or NoPos if unresolved
+func (scan *resourceScan) declarationPos(ident *ast.Ident) token.Pos {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if obj := info.ObjectOf(ident); obj != nil {
+			return obj.Pos()
+		}
+	}
+	if ident.Obj != nil {
+		return ident.Obj.Pos()
+	}
+	return token.NoPos
+}
+
+// returnsAfter lists the return statements following the acquisition, leaving out those in
+// the error check where the resource was never obtained
+func (scan *resourceScan) returnsAfter(acquisition *resourceAcquisition, errCheck *ast.IfStmt) []*ast.ReturnStmt {
+	var returns []*ast.ReturnStmt
+	ast.Inspect(scan.body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			// Returns outside the block of the acquisition run after the resource went out of scope
+			if node.Pos() < acquisition.stmt.End() || node.Pos() >= acquisition.list[len(acquisition.list)-1].End() {
+				return false
+			}
+			if errCheck != nil && node.Pos() >= errCheck.Body.Pos() && node.End() <= errCheck.Body.End() {
+				return false
+			}
+			returns = append(returns, node)
+		}
+		return true
+	})
+	return returns
+}
+
+// closedBefore reports whether one of the close calls runs on the way to pos: it precedes pos
+// in a block that also encloses pos
+func (scan *resourceScan) closedBefore(pos token.Pos, closes []*ast.CallExpr) bool {
+	for _, call := range closes {
+		if call.Pos() >= pos {
+			continue
+		}
+		if block := enclosingBlock(scan.body, call.Pos()); block != nil && pos >= block.Pos() && pos < block.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// enclosingBlock returns the innermost block or clause of body containing pos
+func enclosingBlock(body *ast.BlockStmt, pos token.Pos) ast.Node {
+	var block ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || pos < n.Pos() || pos >= n.End() {
+			return false
+		}
+		switch n.(type) {
+		case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+			block = n
+		}
+		return true
+	})
+	return block
+}
+
+// report records a finding located at pos
+func (scan *resourceScan) report(issue ResourceLeakIssue, pos token.Pos, acquisition *resourceAcquisition, severity valueobjects.SeverityLevel, description string) {
+	position := scan.pkg.FileSet().Position(pos)
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue.String(), scan.funcName, position.Line, position.Column),
+		entities.FindingTypeBug,
+		location,
+		fmt.Sprintf("Resource leak in %s: %s detected - %s", scan.funcName, issue.String(), description),
+		severity,
+	)
+	finding.AddMetadata("resource", acquisition.resource)
+	finding.AddMetadata("acquired_by", acquisition.call)
+
+	scan.findings = append(scan.findings, finding)
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestResourceLeakDetector_DetectResourceLeaks(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		expectedIssues int
+		expectedTypes  []string
+	}{
+		{
+			name: "File opened and never closed",
+			code: `
+package main
+import "os"
+func size(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"unclosed_resource", "'f' from os.Open"},
+		},
+		{
+			name: "Deferred close after error check - no issue",
+			code: `
+package main
+import "os"
+func size(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Response body deferred before error check",
+			code: `
+package main
+import "net/http"
+func status(url string) (int, error) {
+	resp, err := http.Get(url)
+	defer resp.Body.Close()
+	if err != nil {
+		return 0, err
+	}
+	return resp.StatusCode, nil
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"defer_before_error_check", "defer resp.Body.Close() runs before err is checked"},
+		},
+		{
+			name: "Deferred close inside a loop",
+			code: `
+package main
+import "os"
+func touch(paths []string) error {
+	for _, path := range paths {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+	}
+	return nil
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"deferred_close_in_loop", "inside a loop keeps every f open"},
+		},
+		{
+			name: "Early return skips the close",
+			code: `
+package main
+import (
+	"errors"
+	"os"
+)
+func write(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("empty")
+	}
+	_, err = f.Write(data)
+	f.Close()
+	return err
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"close_skipped_on_return", "returns at line 13"},
+		},
+		{
+			name: "Close returned as the result - no issue",
+			code: `
+package main
+import "os"
+func write(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Resource returned to the caller - no issue",
+			code: `
+package main
+import "net"
+func connect(addr string) (net.Conn, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Resource stored in a struct - no issue",
+			code: `
+package main
+import "os"
+type logger struct {
+	out *os.File
+}
+func newLogger(path string) (*logger, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &logger{out: f}, nil
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Close guarded by a nil check - no issue",
+			code: `
+package main
+import "os"
+func probe(path string) bool {
+	f, err := os.Open(path)
+	if f != nil {
+		f.Close()
+	}
+	return err == nil
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			detector := NewASTResourceLeakDetector()
+			config := valueobjects.DefaultAnalysisConfiguration()
+			findings, err := detector.DetectResourceLeaks(node, fset, config)
+			if err != nil {
+				t.Fatalf("DetectResourceLeaks failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceLeakDetector_TypedAcquisition(t *testing.T) {
+	src := `
+package store
+import (
+	"io"
+	"os"
+)
+type archive struct{}
+func (archive) OpenEntry(name string) (*os.File, error) { return os.Open(name) }
+func (archive) Names() []string                          { return nil }
+func checksum(a archive, sum func(io.Reader) uint32) uint32 {
+	var total uint32
+	for _, name := range a.Names() {
+		entry, err := a.OpenEntry(name)
+		if err != nil {
+			continue
+		}
+		total += sum(entry)
+	}
+	return total
+}`
+
+	pkg := checkPackage(t, src)
+
+	findings, err := NewASTResourceLeakDetector().DetectPackageResourceLeaks(pkg, valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("DetectPackageResourceLeaks failed: %v", err)
+	}
+
+	if len(findings) != 1 {
+		for i, finding := range findings {
+			t.Logf("Finding %d: %s", i, finding.Message())
+		}
+		t.Fatalf("Expected 1 finding, got %d", len(findings))
+	}
+	if !strings.Contains(findings[0].Message(), "'entry' from a.OpenEntry is never closed") {
+		t.Errorf("Unexpected message: %s", findings[0].Message())
+	}
+	if findings[0].Metadata()["acquired_by"] != "a.OpenEntry" {
+		t.Errorf("Expected acquired_by metadata a.OpenEntry, got %v", findings[0].Metadata()["acquired_by"])
+	}
+}
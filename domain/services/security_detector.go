@@ -0,0 +1,819 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// SecurityIssue represents the different security weaknesses found in code
+type SecurityIssue int
+
+const (
+	SecuritySQLInjection SecurityIssue = iota
+	SecurityCommandInjection
+	SecurityWeakRandom
+	SecurityWeakCrypto
+	SecurityInsecureTLS
+	SecurityHardcodedCredential
+	SecurityPathTraversal
+)
+
+// String returns a string representation of the security issue
+func (si SecurityIssue) String() string {
+	switch si {
+	case SecuritySQLInjection:
+		return "sql_injection"
+	case SecurityCommandInjection:
+		return "command_injection"
+	case SecurityWeakRandom:
+		return "weak_random"
+	case SecurityWeakCrypto:
+		return "weak_crypto"
+	case SecurityInsecureTLS:
+		return "insecure_tls"
+	case SecurityHardcodedCredential:
+		return "hardcoded_credential"
+	case SecurityPathTraversal:
+		return "path_traversal"
+	default:
+		return "unknown"
+	}
+}
+
+// CWE returns the Common Weakness Enumeration id of the issue
+func (si SecurityIssue) CWE() string {
+	switch si {
+	case SecuritySQLInjection:
+		return "CWE-89"
+	case SecurityCommandInjection:
+		return "CWE-78"
+	case SecurityWeakRandom:
+		return "CWE-338"
+	case SecurityWeakCrypto:
+		return "CWE-327"
+	case SecurityInsecureTLS:
+		return "CWE-295"
+	case SecurityHardcodedCredential:
+		return "CWE-798"
+	case SecurityPathTraversal:
+		return "CWE-22"
+	default:
+		return ""
+	}
+}
+
+// sqlQueryMethods maps the database/sql methods taking a query to the index of the query argument
+var sqlQueryMethods = map[string]int{
+	"Query": 0, "QueryRow": 0, "Exec": 0, "Prepare": 0,
+	"QueryContext": 1, "QueryRowContext": 1, "ExecContext": 1, "PrepareContext": 1,
+}
+
+// shellFlags maps shell names to the flag introducing an inline script
+var shellFlags = map[string]string{
+	"sh": "-c", "bash": "-c", "zsh": "-c", "dash": "-c", "ksh": "-c",
+	"cmd": "/c", "cmd.exe": "/c", "powershell": "-command", "powershell.exe": "-command", "pwsh": "-command",
+}
+
+// weakCryptoPackages maps broken crypto packages to the weakness and a replacement
+var weakCryptoPackages = map[string][2]string{
+	"crypto/md5":  {"CWE-328", "MD5 is a broken hash; use crypto/sha256 or, for passwords, golang.org/x/crypto/bcrypt"},
+	"crypto/sha1": {"CWE-328", "SHA-1 is a broken hash; use crypto/sha256 or, for passwords, golang.org/x/crypto/bcrypt"},
+	"crypto/des":  {"CWE-327", "DES and 3DES are broken ciphers; use crypto/aes with crypto/cipher.NewGCM"},
+	"crypto/rc4":  {"CWE-327", "RC4 is a broken cipher; use crypto/aes with crypto/cipher.NewGCM"},
+}
+
+// secretWords are the name fragments marking values that must be unpredictable or kept secret
+var secretWords = []string{"password", "passwd", "secret", "token", "apikey", "accesskey", "privatekey", "credential", "nonce", "salt", "session", "csrf", "otp"}
+
+// credentialWords are the name fragments marking values that hold credentials
+var credentialWords = []string{"password", "passwd", "passphrase", "secret", "token", "apikey", "accesskey", "privatekey", "credential"}
+
+// descriptiveSuffixes mark names that describe a credential (its field, header or variable name) rather than hold it
+var descriptiveSuffixes = []string{"name", "type", "kind", "field", "header", "env", "var", "path", "file", "dir", "prefix", "suffix", "len", "length", "size", "url", "uri", "endpoint", "format", "label", "param", "pattern", "regex", "id", "prompt", "msg", "message", "err", "error", "flag", "usage", "help", "text", "desc", "description"}
+
+// requestInputs are the *http.Request members carrying client-controlled data
+var requestInputs = map[string]bool{
+	"URL": true, "Form": true, "PostForm": true, "MultipartForm": true, "Header": true,
+	"FormValue": true, "PostFormValue": true, "PathValue": true, "FormFile": true,
+}
+
+// credentialURL matches URLs with an inline user:password pair
+var credentialURL = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:([^/\s@]+)@`)
+
+// SecurityDetector detects injection, weak cryptography and credential handling weaknesses
+type SecurityDetector interface {
+	DetectSecurityIssues(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageSecurityIssues(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTSecurityDetector implements SecurityDetector using AST analysis
+type ASTSecurityDetector struct{}
+
+// NewASTSecurityDetector creates a new AST-based security detector
+func NewASTSecurityDetector() *ASTSecurityDetector {
+	return &ASTSecurityDetector{}
+}
+
+// securityScan holds the function being analyzed and the findings
+type securityScan struct {
+	pkg       *PackageContext
+	imports   map[string]string
+	testFile  bool
+	funcName  string
+	body      *ast.BlockStmt
+	requests  map[string]bool
+	tainted   map[string]bool
+	sanitized map[string]bool
+	reported  map[token.Pos]bool
+	findings  []entities.AnalysisFinding
+}
+
+// DetectSecurityIssues analyzes code for security weaknesses
+func (sd *ASTSecurityDetector) DetectSecurityIssues(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	scan := &securityScan{pkg: newNodePackageContext(node, fset), reported: make(map[token.Pos]bool)}
+	scan.analyze(node)
+	return scan.findings, nil
+}
+
+// DetectPackageSecurityIssues analyzes every file of a package with its type information
+func (sd *ASTSecurityDetector) DetectPackageSecurityIssues(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	scan := &securityScan{pkg: pkg, reported: make(map[token.Pos]bool)}
+	for _, file := range pkg.Files() {
+		scan.analyze(file)
+	}
+	return scan.findings, nil
+}
+
+// analyze checks the declarations under node; package-level values are attributed to the package
+func (scan *securityScan) analyze(node ast.Node) {
+	file, ok := node.(*ast.File)
+	if !ok {
+		scan.imports = nil
+		scan.testFile = false
+		if funcDecl, ok := node.(*ast.FuncDecl); ok && funcDecl.Body != nil {
+			scan.checkFunction(funcDecl)
+		}
+		return
+	}
+
+	scan.imports = fileImports(file)
+	scan.testFile = strings.HasSuffix(scan.pkg.FileSet().Position(file.Pos()).Filename, "_test.go")
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Body != nil {
+				scan.checkFunction(decl)
+			}
+		case *ast.GenDecl:
+			scan.funcName = file.Name.Name
+			scan.body = nil
+			ast.Inspect(decl, scan.checkValue)
+		}
+	}
+}
+
+// checkFunction runs every rule over one function, including its function literals
+func (scan *securityScan) checkFunction(funcDecl *ast.FuncDecl) {
+	scan.funcName = funcDecl.Name.Name
+	scan.body = funcDecl.Body
+	scan.collectRequestInput(funcDecl)
+
+	secretFunc := isSecretName(funcDecl.Name.Name)
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		scan.checkValue(n)
+
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		importPath, name := scan.packageCall(call)
+		switch {
+		case importPath == "os/exec" && (name == "Command" || name == "CommandContext"):
+			scan.checkCommand(call, name == "CommandContext")
+		case importPath == "math/rand" || importPath == "math/rand/v2":
+			if secretFunc && name != "Seed" && name != "New" && name != "NewSource" {
+				scan.report(SecurityWeakRandom, call.Pos(), valueobjects.SeverityWarning, "",
+					fmt.Sprintf("%s.%s generates a predictable value in %s; use crypto/rand for secrets", path.Base(importPath), name, scan.funcName))
+			}
+		case weakCryptoPackages[importPath][0] != "":
+			weakness := weakCryptoPackages[importPath]
+			severity := valueobjects.SeverityWarning
+			if weakness[0] == "CWE-327" {
+				severity = valueobjects.SeverityError
+			}
+			scan.report(SecurityWeakCrypto, call.Pos(), severity, weakness[0],
+				fmt.Sprintf("%s.%s: %s", path.Base(importPath), name, weakness[1]))
+		case (importPath == "path/filepath" || importPath == "path") && name == "Join":
+			scan.checkJoin(call)
+		case importPath == "":
+			scan.checkQuery(call)
+		}
+		return true
+	})
+}
+
+// checkValue reports credentials, insecure TLS settings and predictable secrets in one declaration or assignment
+func (scan *securityScan) checkValue(n ast.Node) bool {
+	switch node := n.(type) {
+	case *ast.ValueSpec:
+		for i, ident := range node.Names {
+			if i < len(node.Values) {
+				scan.checkNamedValue(ident.Name, node.Values[i])
+			}
+		}
+	case *ast.AssignStmt:
+		for i, lhs := range node.Lhs {
+			if i >= len(node.Rhs) || len(node.Lhs) != len(node.Rhs) {
+				break
+			}
+			if selector, ok := lhs.(*ast.SelectorExpr); ok && selector.Sel.Name == "InsecureSkipVerify" && scan.isTrue(node.Rhs[i]) {
+				scan.reportInsecureTLS(node.Rhs[i].Pos())
+				continue
+			}
+			if name := assignedName(lhs); name != "" {
+				scan.checkNamedValue(name, node.Rhs[i])
+			}
+		}
+	case *ast.KeyValueExpr:
+		key, ok := node.Key.(*ast.Ident)
+		if !ok {
+			break
+		}
+		if key.Name == "InsecureSkipVerify" && scan.isTrue(node.Value) {
+			scan.reportInsecureTLS(node.Value.Pos())
+			break
+		}
+		scan.checkNamedValue(key.Name, node.Value)
+	case *ast.BasicLit:
+		if value, err := strconv.Unquote(node.Value); err == nil && node.Kind == token.STRING && !scan.testFile {
+			if match := credentialURL.FindStringSubmatch(value); match != nil && isCredentialValue(match[1]) {
+				scan.report(SecurityHardcodedCredential, node.Pos(), valueobjects.SeverityError, "",
+					"URL literal embeds a user:password pair; read the credentials from the environment or a secret store")
+			}
+		}
+	}
+	return true
+}
+
+// checkNamedValue reports a credential literal or predictable random value bound to a secret-looking name
+func (scan *securityScan) checkNamedValue(name string, value ast.Expr) {
+	if lit, ok := ast.Unparen(value).(*ast.BasicLit); ok && lit.Kind == token.STRING && !scan.testFile && isCredentialName(name) {
+		text, err := strconv.Unquote(lit.Value)
+		if err == nil && isCredentialValue(text) && !strings.EqualFold(normalizeName(text), normalizeName(name)) {
+			scan.report(SecurityHardcodedCredential, lit.Pos(), valueobjects.SeverityError, "",
+				fmt.Sprintf("'%s' is assigned a hardcoded credential literal; read it from the environment or a secret store", name))
+		}
+		return
+	}
+
+	if !isSecretName(name) {
+		return
+	}
+	ast.Inspect(value, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if importPath, fn := scan.packageCall(call); importPath == "math/rand" || importPath == "math/rand/v2" {
+			scan.report(SecurityWeakRandom, call.Pos(), valueobjects.SeverityWarning, "",
+				fmt.Sprintf("'%s' is derived from %s.%s, which is predictable; use crypto/rand for secrets", name, path.Base(importPath), fn))
+		}
+		return true
+	})
+}
+
+// checkQuery reports database/sql queries assembled from dynamic strings
+func (scan *securityScan) checkQuery(call *ast.CallExpr) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	index, ok := sqlQueryMethods[selector.Sel.Name]
+	if !ok || index >= len(call.Args) || !scan.isSQLReceiver(selector.X) {
+		return
+	}
+
+	query := call.Args[index]
+	if how := scan.dynamicString(query, 2); how != "" {
+		scan.report(SecuritySQLInjection, query.Pos(), valueobjects.SeverityError, "",
+			fmt.Sprintf("query passed to %s is built with %s; use placeholders and pass the values as arguments", types.ExprString(selector), how))
+	}
+}
+
+// isSQLReceiver reports whether expr is a database/sql DB, Tx or Conn; a Stmt already holds its query
+func (scan *securityScan) isSQLReceiver(expr ast.Expr) bool {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if t := info.TypeOf(expr); t != nil {
+			if pointer, ok := t.(*types.Pointer); ok {
+				t = pointer.Elem()
+			}
+			named, ok := t.(*types.Named)
+			return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "database/sql" && named.Obj().Name() != "Stmt"
+		}
+	}
+	if ident := identOf(expr); ident != nil && ident.Obj != nil {
+		switch decl := ident.Obj.Decl.(type) {
+		case *ast.AssignStmt:
+			for _, rhs := range decl.Rhs {
+				if call, ok := rhs.(*ast.CallExpr); ok && strings.HasPrefix(calledMethod(call), "Prepare") {
+					return false
+				}
+			}
+		case *ast.Field:
+			if strings.HasSuffix(types.ExprString(decl.Type), ".Stmt") {
+				return false
+			}
+		case *ast.ValueSpec:
+			if decl.Type != nil && strings.HasSuffix(types.ExprString(decl.Type), ".Stmt") {
+				return false
+			}
+		}
+	}
+	if scan.imports == nil {
+		return true
+	}
+	for _, importPath := range scan.imports {
+		if importPath == "database/sql" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCommand reports shells started with an inline script assembled from dynamic strings
+func (scan *securityScan) checkCommand(call *ast.CallExpr, withContext bool) {
+	args := call.Args
+	if withContext {
+		if len(args) == 0 {
+			return
+		}
+		args = args[1:]
+	}
+	if len(args) < 3 {
+		return
+	}
+
+	shell, ok := scan.constantString(args[0])
+	if !ok {
+		return
+	}
+	flag := shellFlags[strings.ToLower(path.Base(strings.ReplaceAll(shell, `\`, "/")))]
+	if flag == "" {
+		return
+	}
+	for i := 1; i+1 < len(args); i++ {
+		if value, ok := scan.constantString(args[i]); !ok || strings.ToLower(value) != flag {
+			continue
+		}
+		if how := scan.dynamicString(args[i+1], 2); how != "" {
+			scan.report(SecurityCommandInjection, args[i+1].Pos(), valueobjects.SeverityError, "",
+				fmt.Sprintf("%s %s script is built with %s; run the program directly with separate arguments instead of through a shell", shell, flag, how))
+		}
+		return
+	}
+}
+
+// checkJoin reports file paths joined from request input that was never sanitized
+func (scan *securityScan) checkJoin(call *ast.CallExpr) {
+	if len(scan.requests) == 0 || scan.joinChecked(call) {
+		return
+	}
+	for _, arg := range call.Args {
+		if scan.isRequestInput(arg) {
+			scan.report(SecurityPathTraversal, arg.Pos(), valueobjects.SeverityError, "",
+				fmt.Sprintf("%s joins '%s' from the HTTP request without sanitizing it; reject '..' with filepath.IsLocal or serve from an os.Root", types.ExprString(call.Fun), types.ExprString(arg)))
+			return
+		}
+	}
+}
+
+// collectRequestInput finds the *http.Request parameters of a function and the variables holding their input
+func (scan *securityScan) collectRequestInput(funcDecl *ast.FuncDecl) {
+	scan.requests = make(map[string]bool)
+	scan.tainted = make(map[string]bool)
+	scan.sanitized = make(map[string]bool)
+
+	ast.Inspect(funcDecl, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncType:
+			if node.Params == nil {
+				break
+			}
+			for _, field := range node.Params.List {
+				if scan.isRequestType(field.Type) {
+					for _, ident := range field.Names {
+						scan.requests[ident.Name] = true
+					}
+				}
+			}
+		case *ast.CallExpr:
+			if scan.isSanitizer(node) && len(node.Args) > 0 {
+				if ident := identOf(node.Args[0]); ident != nil {
+					scan.sanitized[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	if len(scan.requests) == 0 {
+		return
+	}
+
+	// Propagate through assignments until no new variable picks up request input
+	for changed := true; changed; {
+		changed = false
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok {
+				return true
+			}
+			for i, lhs := range assign.Lhs {
+				rhs := assign.Rhs[0]
+				if len(assign.Rhs) == len(assign.Lhs) {
+					rhs = assign.Rhs[i]
+				}
+				ident := identOf(lhs)
+				if ident != nil && ident.Name != "_" && !scan.tainted[ident.Name] && scan.isRequestInput(rhs) {
+					scan.tainted[ident.Name] = true
+					changed = true
+				}
+			}
+			return true
+		})
+	}
+}
+
+// isRequestType reports whether expr denotes *net/http.Request
+func (scan *securityScan) isRequestType(expr ast.Expr) bool {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if t := info.TypeOf(expr); t != nil {
+			return types.TypeString(t, nil) == "*net/http.Request"
+		}
+	}
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := star.X.(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == "Request" && scan.packagePath(selector.X) == "net/http"
+}
+
+// isRequestInput reports whether expr carries unsanitized client-controlled data
+func (scan *securityScan) isRequestInput(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if scan.isSanitizer(node) {
+				return false
+			}
+			if selector, ok := node.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Vars" && len(node.Args) == 1 {
+				if ident := identOf(node.Args[0]); ident != nil && scan.requests[ident.Name] {
+					found = true
+				}
+			}
+		case *ast.SelectorExpr:
+			if ident := identOf(node.X); ident != nil && scan.requests[ident.Name] && requestInputs[node.Sel.Name] {
+				found = true
+			}
+		case *ast.Ident:
+			found = scan.tainted[node.Name] && !scan.sanitized[node.Name]
+		}
+		return !found
+	})
+	return found
+}
+
+// isSanitizer reports whether call confines or validates a path
+func (scan *securityScan) isSanitizer(call *ast.CallExpr) bool {
+	importPath, name := scan.packageCall(call)
+	switch importPath {
+	case "path/filepath", "path":
+		switch name {
+		case "Base", "IsLocal", "Rel":
+			return true
+		case "Clean":
+			// Cleaning a rooted path removes every leading ".."
+			if len(call.Args) == 1 {
+				if binary, ok := ast.Unparen(call.Args[0]).(*ast.BinaryExpr); ok && binary.Op == token.ADD {
+					prefix, ok := scan.constantString(binary.X)
+					return ok && strings.HasPrefix(prefix, "/")
+				}
+			}
+		}
+	case "strings":
+		if name == "HasPrefix" {
+			return true
+		}
+		if name == "Contains" && len(call.Args) == 2 {
+			substr, ok := scan.constantString(call.Args[1])
+			return ok && strings.Contains(substr, "..")
+		}
+	}
+	return false
+}
+
+// joinChecked reports whether the joined path is stored in a variable that is validated afterwards
+func (scan *securityScan) joinChecked(call *ast.CallExpr) bool {
+	checked := false
+	ast.Inspect(scan.body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || checked {
+			return !checked
+		}
+		for i, rhs := range assign.Rhs {
+			if rhs == call && i < len(assign.Lhs) {
+				if ident := identOf(assign.Lhs[i]); ident != nil && scan.sanitized[ident.Name] {
+					checked = true
+				}
+			}
+		}
+		return true
+	})
+	return checked
+}
+
+// dynamicString describes how expr is assembled at run time, or returns "" for constants and opaque values
+func (scan *securityScan) dynamicString(expr ast.Expr, depth int) string {
+	if scan.isConstant(expr) {
+		return ""
+	}
+
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			return "string concatenation"
+		}
+	case *ast.CallExpr:
+		if importPath, name := scan.packageCall(e); importPath == "fmt" && name == "Sprintf" {
+			for _, arg := range e.Args[1:] {
+				if !scan.isConstant(arg) {
+					return "fmt.Sprintf"
+				}
+			}
+		}
+	case *ast.Ident:
+		if depth == 0 || scan.body == nil {
+			return ""
+		}
+		how := ""
+		ast.Inspect(scan.body, func(n ast.Node) bool {
+			if how != "" {
+				return false
+			}
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				for i, lhs := range node.Lhs {
+					if ident := identOf(lhs); ident == nil || ident.Name != e.Name || len(node.Rhs) != len(node.Lhs) {
+						continue
+					}
+					if node.Tok == token.ADD_ASSIGN && !scan.isConstant(node.Rhs[i]) {
+						how = "string concatenation"
+					} else {
+						how = scan.dynamicString(node.Rhs[i], depth-1)
+					}
+				}
+			case *ast.ValueSpec:
+				for i, ident := range node.Names {
+					if ident.Name == e.Name && i < len(node.Values) {
+						how = scan.dynamicString(node.Values[i], depth-1)
+					}
+				}
+			}
+			return true
+		})
+		return how
+	}
+	return ""
+}
+
+// isConstant reports whether expr is a compile-time constant
+func (scan *securityScan) isConstant(expr ast.Expr) bool {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if tv, ok := info.Types[expr]; ok {
+			return tv.Value != nil
+		}
+	}
+
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.Ident:
+		return e.Obj != nil && e.Obj.Kind == ast.Con
+	case *ast.BinaryExpr:
+		return scan.isConstant(e.X) && scan.isConstant(e.Y)
+	}
+	return false
+}
+
+// constantString returns the value of a constant string expression
+func (scan *securityScan) constantString(expr ast.Expr) (string, bool) {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if tv, ok := info.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			return constant.StringVal(tv.Value), true
+		}
+	}
+	if lit, ok := ast.Unparen(expr).(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		value, err := strconv.Unquote(lit.Value)
+		return value, err == nil
+	}
+	return "", false
+}
+
+// isTrue reports whether expr is the constant true
+func (scan *securityScan) isTrue(expr ast.Expr) bool {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+			return tv.Value.Kind() == constant.Bool && constant.BoolVal(tv.Value)
+		}
+	}
+	ident := identOf(expr)
+	return ident != nil && ident.Name == "true"
+}
+
+// packageCall returns the import path and function name of a package-qualified call
+func (scan *securityScan) packageCall(call *ast.CallExpr) (string, string) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", ""
+	}
+	return scan.packagePath(selector.X), selector.Sel.Name
+}
+
+// packagePath returns the import path of a package name, or "" if expr is not one
+func (scan *securityScan) packagePath(expr ast.Expr) string {
+	return importedPackage(scan.pkg, scan.imports, expr)
+}
+
+// reportInsecureTLS records a disabled certificate check
+func (scan *securityScan) reportInsecureTLS(pos token.Pos) {
+	scan.report(SecurityInsecureTLS, pos, valueobjects.SeverityError, "",
+		"InsecureSkipVerify: true disables certificate verification and allows man-in-the-middle attacks; configure RootCAs instead")
+}
+
+// report records a finding located at pos, tagged with the CWE of the issue unless cwe overrides it
+func (scan *securityScan) report(issue SecurityIssue, pos token.Pos, severity valueobjects.SeverityLevel, cwe string, description string) {
+	if scan.reported[pos] {
+		return
+	}
+	scan.reported[pos] = true
+	if cwe == "" {
+		cwe = issue.CWE()
+	}
+
+	position := scan.pkg.FileSet().Position(pos)
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue.String(), scan.funcName, position.Line, position.Column),
+		entities.FindingTypeSecurity,
+		location,
+		fmt.Sprintf("Security issue in %s: %s detected - %s (%s)", scan.funcName, issue.String(), description, cwe),
+		severity,
+	)
+	finding.AddMetadata("cwe", cwe)
+	finding.AddMetadata("rule", issue.String())
+
+	scan.findings = append(scan.findings, finding)
+}
+
+// importedPackage returns the import path of the package name expr, or "" if expr is not one;
+// imports maps the file's import names to paths and is nil when the file is unknown
+func importedPackage(pkg *PackageContext, imports map[string]string, expr ast.Expr) string {
+	ident := identOf(expr)
+	if ident == nil {
+		return ""
+	}
+	if info := pkg.TypesInfo(); info != nil {
+		if pkgName, ok := info.Uses[ident].(*types.PkgName); ok {
+			return pkgName.Imported().Path()
+		}
+		if info.Uses[ident] != nil {
+			return ""
+		}
+	}
+	if ident.Obj != nil {
+		return ""
+	}
+	if imports != nil {
+		return imports[ident.Name]
+	}
+	// Without the file, fall back to the conventional names of the standard packages
+	switch ident.Name {
+	case "sql":
+		return "database/sql"
+	case "exec":
+		return "os/exec"
+	case "filepath":
+		return "path/filepath"
+	case "md5", "sha1", "des", "rc4":
+		return "crypto/" + ident.Name
+	case "fmt", "strings", "strconv", "regexp", "path":
+		return ident.Name
+	case "template":
+		return "text/template"
+	case "http":
+		return "net/http"
+	}
+	return ""
+}
+
+// fileImports maps the names a file refers to its imports by to their paths
+func fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(importPath)
+		if strings.HasPrefix(name, "v") && strings.Contains(importPath, "/") {
+			// Major version suffixes such as math/rand/v2 keep the parent name
+			if _, err := strconv.Atoi(name[1:]); err == nil {
+				name = path.Base(path.Dir(importPath))
+			}
+		}
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = importPath
+	}
+	return imports
+}
+
+// assignedName returns the variable or field name an assignment target stores into
+func assignedName(expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
+
+// normalizeName lowercases name and drops separators
+func normalizeName(name string) string {
+	return strings.NewReplacer("_", "", "-", "", ".", "", " ", "").Replace(strings.ToLower(name))
+}
+
+// isSecretName reports whether a name suggests its value must be unpredictable
+func isSecretName(name string) bool {
+	normalized := normalizeName(name)
+	for _, word := range secretWords {
+		if strings.Contains(normalized, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCredentialName reports whether a name suggests it holds a credential rather than describes one
+func isCredentialName(name string) bool {
+	normalized := normalizeName(name)
+	matched := false
+	for _, word := range credentialWords {
+		if strings.Contains(normalized, word) {
+			matched = true
+		}
+	}
+	if !matched {
+		return false
+	}
+	for _, suffix := range descriptiveSuffixes {
+		if strings.HasSuffix(normalized, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// isCredentialValue reports whether a literal looks like a real secret rather than a placeholder or template
+func isCredentialValue(value string) bool {
+	if len(value) < 6 || strings.ContainsAny(value, " \t\n%{}$<>") {
+		return false
+	}
+	if strings.HasPrefix(value, "/") || strings.HasPrefix(value, "./") || strings.Trim(value, "*xX.-_") == "" {
+		return false
+	}
+	// Upper-case identifiers are environment variable or header names
+	if strings.ToUpper(value) == value && strings.Trim(strings.ToUpper(value), "ABCDEFGHIJKLMNOPQRSTUVWXYZ_") == "" {
+		return false
+	}
+	switch strings.ToLower(value) {
+	case "changeme", "password", "passwd", "secret", "example", "placeholder", "redacted":
+		return false
+	}
+	return true
+}
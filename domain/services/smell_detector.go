@@ -25,6 +25,8 @@ const (
 	SmellTypeChannelSendLeak
 	SmellTypeBlockingBug
 	SmellTypeRaceCondition
+	SmellTypeDuplicateCode
+	SmellTypeLowCohesion
 )
 
 // String returns a string representation of the smell type
@@ -54,6 +56,10 @@ func (st SmellType) String() string {
 		return "blocking_bug"
 	case SmellTypeRaceCondition:
 		return "race_condition"
+	case SmellTypeDuplicateCode:
+		return "duplicate_code"
+	case SmellTypeLowCohesion:
+		return "low_cohesion"
 	default:
 		return "unknown"
 	}
@@ -62,24 +68,225 @@ func (st SmellType) String() string {
 // SmellDetector detects architectural smells in Go code
 type SmellDetector interface {
 	DetectSmells(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageSmells(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
 }
 
 // ASTSmellDetector implements SmellDetector using AST analysis
 type ASTSmellDetector struct {
-	goroutineLeakDetector   GoroutineLeakDetector
-	concurrencyBugDetector  ConcurrencyBugDetector
+	goroutineLeakDetector    GoroutineLeakDetector
+	concurrencyBugDetector   ConcurrencyBugDetector
+	channelLifecycleDetector ChannelLifecycleDetector
+	timerMisuseDetector      TimerMisuseDetector
+	waitGroupDetector        WaitGroupDetector
+	lockCopyDetector         LockCopyDetector
+	loopHazardDetector       LoopHazardDetector
+	atomicAccessDetector     AtomicAccessDetector
+	mapWriteDetector         MapWriteDetector
+	resourceLeakDetector     ResourceLeakDetector
+	securityDetector         SecurityDetector
+	performanceDetector      PerformanceDetector
+	taintAnalyzer            TaintAnalyzer
+	errorWrapDetector        ErrorWrapDetector
+	fixSuggester             FixSuggester
 }
 
 // NewASTSmellDetector creates a new AST-based smell detector
 func NewASTSmellDetector() *ASTSmellDetector {
 	return &ASTSmellDetector{
-		goroutineLeakDetector:  NewASTGoroutineLeakDetector(),
-		concurrencyBugDetector: NewASTConcurrencyBugDetector(),
+		goroutineLeakDetector:    NewASTGoroutineLeakDetector(),
+		concurrencyBugDetector:   NewASTConcurrencyBugDetector(),
+		channelLifecycleDetector: NewASTChannelLifecycleDetector(),
+		timerMisuseDetector:      NewASTTimerMisuseDetector(),
+		waitGroupDetector:        NewASTWaitGroupDetector(),
+		lockCopyDetector:         NewASTLockCopyDetector(),
+		loopHazardDetector:       NewASTLoopHazardDetector(),
+		atomicAccessDetector:     NewASTAtomicAccessDetector(),
+		mapWriteDetector:         NewASTMapWriteDetector(),
+		resourceLeakDetector:     NewASTResourceLeakDetector(),
+		securityDetector:         NewASTSecurityDetector(),
+		performanceDetector:      NewASTPerformanceDetector(),
+		taintAnalyzer:            NewSSATaintAnalyzer(),
+		errorWrapDetector:        NewASTErrorWrapDetector(),
+		fixSuggester:             NewASTFixSuggester(),
 	}
 }
 
+// WithoutConcurrencyDetectors returns a copy of the detector that leaves goroutine leaks and
+// concurrency bugs to their own detectors, for callers that run those separately
+func (sd *ASTSmellDetector) WithoutConcurrencyDetectors() *ASTSmellDetector {
+	detector := *sd
+	detector.goroutineLeakDetector = noGoroutineLeakDetector{}
+	detector.concurrencyBugDetector = noConcurrencyBugDetector{}
+	return &detector
+}
+
+// noGoroutineLeakDetector is a GoroutineLeakDetector that reports nothing
+type noGoroutineLeakDetector struct{}
+
+// DetectLeaks reports no leaks
+func (noGoroutineLeakDetector) DetectLeaks(ast.Node, *token.FileSet, valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	return nil, nil
+}
+
+// DetectPackageLeaks reports no leaks
+func (noGoroutineLeakDetector) DetectPackageLeaks(*PackageContext, valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	return nil, nil
+}
+
+// noConcurrencyBugDetector is a ConcurrencyBugDetector that reports nothing
+type noConcurrencyBugDetector struct{}
+
+// DetectBugs reports no bugs
+func (noConcurrencyBugDetector) DetectBugs(ast.Node, *token.FileSet, valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	return nil, nil
+}
+
 // DetectSmells analyzes code for architectural smells
 func (sd *ASTSmellDetector) DetectSmells(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	findings := sd.detectFileSmells(node, fset, config)
+
+	// Detect goroutine leaks
+	if leakFindings, err := sd.goroutineLeakDetector.DetectLeaks(node, fset, config); err == nil {
+		findings = append(findings, leakFindings...)
+	}
+
+	// Detect channel lifecycle bugs
+	if channelFindings, err := sd.channelLifecycleDetector.DetectChannelIssues(node, fset, config); err == nil {
+		findings = append(findings, channelFindings...)
+	}
+
+	// Detect timer and ticker misuse
+	if timerFindings, err := sd.timerMisuseDetector.DetectTimerIssues(node, fset, config); err == nil {
+		findings = append(findings, timerFindings...)
+	}
+
+	// Detect WaitGroup misuse
+	if waitGroupFindings, err := sd.waitGroupDetector.DetectWaitGroupIssues(node, fset, config); err == nil {
+		findings = append(findings, waitGroupFindings...)
+	}
+
+	// Detect copied sync primitives
+	if copyFindings, err := sd.lockCopyDetector.DetectLockCopies(node, fset, config); err == nil {
+		findings = append(findings, copyFindings...)
+	}
+
+	// Detect goroutines and closures started in loops
+	if loopFindings, err := sd.loopHazardDetector.DetectLoopHazards(node, fset, config); err == nil {
+		findings = append(findings, loopFindings...)
+	}
+
+	// Detect values mixing atomic and plain access
+	if atomicFindings, err := sd.atomicAccessDetector.DetectAtomicAccess(node, fset, config); err == nil {
+		findings = append(findings, atomicFindings...)
+	}
+
+	// Detect maps written concurrently
+	if mapFindings, err := sd.mapWriteDetector.DetectMapWrites(node, fset, config); err == nil {
+		findings = append(findings, mapFindings...)
+	}
+
+	// Detect leaked Close-able resources
+	if resourceFindings, err := sd.resourceLeakDetector.DetectResourceLeaks(node, fset, config); err == nil {
+		findings = append(findings, resourceFindings...)
+	}
+
+	// Detect injection, weak crypto and credential weaknesses
+	if securityFindings, err := sd.securityDetector.DetectSecurityIssues(node, fset, config); err == nil {
+		findings = append(findings, securityFindings...)
+	}
+
+	// Detect hot-path allocation and copying
+	if performanceFindings, err := sd.performanceDetector.DetectPerformanceIssues(node, fset, config); err == nil {
+		findings = append(findings, performanceFindings...)
+	}
+
+	// Detect errors formatted without wrapping
+	if wrapFindings, err := sd.errorWrapDetector.DetectErrorWrapping(node, fset, config); err == nil {
+		findings = append(findings, wrapFindings...)
+	}
+
+	return sd.fixSuggester.SuggestFixes(findings, newNodePackageContext(node, fset)), nil
+}
+
+// DetectPackageSmells analyzes every file of a package, letting detectors resolve
+// declarations across files and packages
+func (sd *ASTSmellDetector) DetectPackageSmells(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var findings []entities.AnalysisFinding
+
+	for _, file := range pkg.Files() {
+		findings = append(findings, sd.detectFileSmells(file, pkg.FileSet(), config)...)
+	}
+
+	// Detect goroutine leaks
+	if leakFindings, err := sd.goroutineLeakDetector.DetectPackageLeaks(pkg, config); err == nil {
+		findings = append(findings, leakFindings...)
+	}
+
+	// Detect channel lifecycle bugs
+	if channelFindings, err := sd.channelLifecycleDetector.DetectPackageChannelIssues(pkg, config); err == nil {
+		findings = append(findings, channelFindings...)
+	}
+
+	// Detect timer and ticker misuse
+	if timerFindings, err := sd.timerMisuseDetector.DetectPackageTimerIssues(pkg, config); err == nil {
+		findings = append(findings, timerFindings...)
+	}
+
+	// Detect WaitGroup misuse
+	if waitGroupFindings, err := sd.waitGroupDetector.DetectPackageWaitGroupIssues(pkg, config); err == nil {
+		findings = append(findings, waitGroupFindings...)
+	}
+
+	// Detect copied sync primitives
+	if copyFindings, err := sd.lockCopyDetector.DetectPackageLockCopies(pkg, config); err == nil {
+		findings = append(findings, copyFindings...)
+	}
+
+	// Detect goroutines and closures started in loops
+	if loopFindings, err := sd.loopHazardDetector.DetectPackageLoopHazards(pkg, config); err == nil {
+		findings = append(findings, loopFindings...)
+	}
+
+	// Detect values mixing atomic and plain access
+	if atomicFindings, err := sd.atomicAccessDetector.DetectPackageAtomicAccess(pkg, config); err == nil {
+		findings = append(findings, atomicFindings...)
+	}
+
+	// Detect maps written concurrently
+	if mapFindings, err := sd.mapWriteDetector.DetectPackageMapWrites(pkg, config); err == nil {
+		findings = append(findings, mapFindings...)
+	}
+
+	// Detect leaked Close-able resources
+	if resourceFindings, err := sd.resourceLeakDetector.DetectPackageResourceLeaks(pkg, config); err == nil {
+		findings = append(findings, resourceFindings...)
+	}
+
+	// Detect injection, weak crypto and credential weaknesses
+	if securityFindings, err := sd.securityDetector.DetectPackageSecurityIssues(pkg, config); err == nil {
+		findings = append(findings, securityFindings...)
+	}
+
+	// Detect hot-path allocation and copying
+	if performanceFindings, err := sd.performanceDetector.DetectPackagePerformanceIssues(pkg, config); err == nil {
+		findings = append(findings, performanceFindings...)
+	}
+
+	// Track untrusted data from sources to sinks
+	if taintFindings, err := sd.taintAnalyzer.AnalyzePackageTaint(pkg, config); err == nil {
+		findings = append(findings, taintFindings...)
+	}
+
+	// Detect errors formatted without wrapping
+	if wrapFindings, err := sd.errorWrapDetector.DetectPackageErrorWrapping(pkg, config); err == nil {
+		findings = append(findings, wrapFindings...)
+	}
+
+	return sd.fixSuggester.SuggestFixes(findings, pkg), nil
+}
+
+// detectFileSmells runs the detectors that only need the syntax of node
+func (sd *ASTSmellDetector) detectFileSmells(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) []entities.AnalysisFinding {
 	var findings []entities.AnalysisFinding
 
 	// Detect traditional architectural smells
@@ -90,24 +297,19 @@ func (sd *ASTSmellDetector) DetectSmells(node ast.Node, fset *token.FileSet, con
 				findings = append(findings, funcFindings...)
 			}
 		case *ast.GenDecl:
-			if declFindings := sd.detectDeclarationSmells(node, fset); len(declFindings) > 0 {
+			if declFindings := sd.detectDeclarationSmells(node, fset, config); len(declFindings) > 0 {
 				findings = append(findings, declFindings...)
 			}
 		}
 		return true
 	})
 
-	// Detect goroutine leaks
-	if leakFindings, err := sd.goroutineLeakDetector.DetectLeaks(node, fset, config); err == nil {
-		findings = append(findings, leakFindings...)
-	}
-
 	// Detect concurrency bugs
 	if bugFindings, err := sd.concurrencyBugDetector.DetectBugs(node, fset, config); err == nil {
 		findings = append(findings, bugFindings...)
 	}
 
-	return findings, nil
+	return findings
 }
 
 // detectFunctionSmells detects smells in function declarations
@@ -151,21 +353,21 @@ func (sd *ASTSmellDetector) detectFunctionSmells(funcDecl *ast.FuncDecl, fset *t
 }
 
 // detectDeclarationSmells detects smells in type/struct declarations
-func (sd *ASTSmellDetector) detectDeclarationSmells(genDecl *ast.GenDecl, fset *token.FileSet) []entities.AnalysisFinding {
+func (sd *ASTSmellDetector) detectDeclarationSmells(genDecl *ast.GenDecl, fset *token.FileSet, config valueobjects.AnalysisConfiguration) []entities.AnalysisFinding {
 	var findings []entities.AnalysisFinding
 
 	for _, spec := range genDecl.Specs {
 		if typeSpec, ok := spec.(*ast.TypeSpec); ok {
 			if structType, ok := typeSpec.Type.(*ast.StructType); ok {
-				if fieldCount := sd.countStructFields(structType); fieldCount > 10 {
+				if fieldCount := sd.countStructFields(structType); fieldCount > config.MaxStructFields() {
 					pos := fset.Position(typeSpec.Pos())
 					location, _ := valueobjects.NewSourceLocation(pos.Filename, pos.Line, pos.Column)
 					finding, _ := entities.NewAnalysisFinding(
 						fmt.Sprintf("god_struct_%s_%d", typeSpec.Name.Name, pos.Line),
 						entities.FindingTypeSmell,
 						location,
-						fmt.Sprintf("Struct %s has too many fields: %d (max recommended: 10)",
-							typeSpec.Name.Name, fieldCount),
+						fmt.Sprintf("Struct %s has too many fields: %d (max recommended: %d)",
+							typeSpec.Name.Name, fieldCount, config.MaxStructFields()),
 						valueobjects.SeverityWarning,
 					)
 					findings = append(findings, finding)
@@ -262,10 +464,7 @@ func (sd *ASTSmellDetector) calculateMaxNesting(block *ast.BlockStmt) int {
 
 // countStructFields counts the number of fields in a struct
 func (sd *ASTSmellDetector) countStructFields(structType *ast.StructType) int {
-	if structType.Fields == nil {
-		return 0
-	}
-	return len(structType.Fields.List)
+	return countFields(structType)
 }
 
 // countInterfaceMethods counts the number of methods in an interface
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// SuppressionDirective starts a comment that silences findings. It is followed by an optional
+// comma-separated list of rules and an optional reason: //goast:ignore deep_nesting,complexity generated
+const SuppressionDirective = "//goast:ignore"
+
+// SuppressionScanner finds the suppression comments of a file
+type SuppressionScanner interface {
+	ScanSuppressions(file *ast.File, fset *token.FileSet) []valueobjects.Suppression
+}
+
+// CommentSuppressionScanner implements SuppressionScanner. A directive after code silences its own
+// line; a directive on a line of its own silences the line following its comment block, so it can
+// sit in the doc comment of the function it applies to
+type CommentSuppressionScanner struct{}
+
+// NewCommentSuppressionScanner creates a new suppression comment scanner
+func NewCommentSuppressionScanner() *CommentSuppressionScanner {
+	return &CommentSuppressionScanner{}
+}
+
+// ScanSuppressions returns the suppressions declared by the comments of a file
+func (s *CommentSuppressionScanner) ScanSuppressions(file *ast.File, fset *token.FileSet) []valueobjects.Suppression {
+	// The leftmost column at which code starts on each line tells trailing comments apart
+	codeStart := make(map[int]int)
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node.(type) {
+		case nil, *ast.Comment, *ast.CommentGroup:
+			return false
+		}
+		pos := fset.Position(node.Pos())
+		if column, ok := codeStart[pos.Line]; !ok || pos.Column < column {
+			codeStart[pos.Line] = pos.Column
+		}
+		return true
+	})
+
+	var suppressions []valueobjects.Suppression
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			rules, ok := parseSuppression(comment.Text)
+			if !ok {
+				continue
+			}
+
+			pos := fset.Position(comment.Pos())
+			line := fset.Position(group.End()).Line + 1
+			if column, ok := codeStart[pos.Line]; ok && column < pos.Column {
+				line = pos.Line
+			}
+
+			suppression, err := valueobjects.NewSuppression(pos.Filename, line, rules)
+			if err == nil {
+				suppressions = append(suppressions, suppression)
+			}
+		}
+	}
+	return suppressions
+}
+
+// parseSuppression returns the rules of a suppression directive and whether the comment is one
+func parseSuppression(text string) ([]string, bool) {
+	rest, ok := strings.CutPrefix(text, SuppressionDirective)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return nil, false
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return nil, true
+	}
+	var rules []string
+	for _, rule := range strings.Split(fields[0], ",") {
+		if rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, true
+}
+
+// IsSuppressed reports whether any of the suppressions silences a finding, matching rules against
+// the finding's type and ID
+func IsSuppressed(finding entities.AnalysisFinding, suppressions []valueobjects.Suppression) bool {
+	for _, suppression := range suppressions {
+		if suppression.Covers(finding.Location(), finding.Type().String(), finding.ID()) {
+			return true
+		}
+	}
+	return false
+}
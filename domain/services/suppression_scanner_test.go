@@ -0,0 +1,109 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestSuppressionScanner_ScanSuppressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected []string
+	}{
+		{
+			name: "Directive in the doc comment applies to the function",
+			code: `package main
+
+// parse reads input.
+//goast:ignore deep_nesting,complexity generated code
+func parse() {}
+`,
+			expected: []string{"main.go:5: //goast:ignore deep_nesting,complexity"},
+		},
+		{
+			name: "Trailing directive applies to its own line",
+			code: `package main
+
+func run(ch chan int) {
+	go func() { ch <- 1 }() //goast:ignore
+}
+`,
+			expected: []string{"main.go:4: //goast:ignore"},
+		},
+		{
+			name: "Directive on its own line inside a function",
+			code: `package main
+
+func run() {
+	//goast:ignore bug
+	x := 1
+	_ = x
+}
+`,
+			expected: []string{"main.go:5: //goast:ignore bug"},
+		},
+		{
+			name: "Other comments are not directives",
+			code: `package main
+
+// goast:ignore is only a directive without the space
+//goast:ignored
+func run() {}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "main.go", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			var found []string
+			for _, suppression := range NewCommentSuppressionScanner().ScanSuppressions(file, fset) {
+				found = append(found, suppression.String())
+			}
+			if strings.Join(found, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected suppressions %v, got %v", tt.expected, found)
+			}
+		})
+	}
+}
+
+func TestIsSuppressed(t *testing.T) {
+	location, _ := valueobjects.NewSourceLocation("main.go", 5, 1)
+	finding, _ := entities.NewAnalysisFinding("deep_nesting_parse_5", entities.FindingTypeSmell, location, "Function parse has deep nesting", valueobjects.SeverityWarning)
+
+	tests := []struct {
+		name     string
+		line     int
+		rules    []string
+		expected bool
+	}{
+		{"Every rule", 5, nil, true},
+		{"Rule prefix of the ID", 5, []string{"deep_nesting"}, true},
+		{"Finding type", 5, []string{"complexity", "smell"}, true},
+		{"Other rule", 5, []string{"nesting", "bug"}, false},
+		{"Other line", 6, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suppression, err := valueobjects.NewSuppression("main.go", tt.line, tt.rules)
+			if err != nil {
+				t.Fatalf("Failed to create suppression: %v", err)
+			}
+			if got := IsSuppressed(finding, []valueobjects.Suppression{suppression}); got != tt.expected {
+				t.Errorf("Expected IsSuppressed to be %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
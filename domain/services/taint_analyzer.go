@@ -0,0 +1,696 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// maxTraceSteps bounds the recorded source-to-sink trace of a single flow
+const maxTraceSteps = 64
+
+// taintCWEs maps the built-in sink kinds to the weakness they expose
+var taintCWEs = map[string]string{
+	"sql":      "CWE-89",
+	"exec":     "CWE-78",
+	"path":     "CWE-22",
+	"template": "CWE-79",
+}
+
+// TaintAnalyzer tracks untrusted data from configured sources to sinks
+type TaintAnalyzer interface {
+	AnalyzePackageTaint(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// SSATaintAnalyzer implements TaintAnalyzer over the SSA form of a type-checked package.
+// Each function is first analyzed on its own; calls into functions with bodies in the
+// program are then resolved through per-parameter summaries
+type SSATaintAnalyzer struct{}
+
+// NewSSATaintAnalyzer creates a new SSA-based taint analyzer
+func NewSSATaintAnalyzer() *SSATaintAnalyzer {
+	return &SSATaintAnalyzer{}
+}
+
+// taintFlow is untrusted data together with the positions it passed through
+type taintFlow struct {
+	kind   string
+	source string
+	steps  []token.Pos
+}
+
+// through returns the flow extended by a step at pos
+func (f *taintFlow) through(pos token.Pos) *taintFlow {
+	if !pos.IsValid() || len(f.steps) >= maxTraceSteps {
+		return f
+	}
+	return &taintFlow{kind: f.kind, source: f.source, steps: append(append([]token.Pos(nil), f.steps...), pos)}
+}
+
+// then returns the flow continued by the steps of a flow inside a callee
+func (f *taintFlow) then(callee *taintFlow) *taintFlow {
+	steps := append(append([]token.Pos(nil), f.steps...), callee.steps...)
+	if len(steps) > maxTraceSteps {
+		steps = steps[:maxTraceSteps]
+	}
+	return &taintFlow{kind: f.kind, source: f.source, steps: steps}
+}
+
+// taintHit is tainted data reaching a sink
+type taintHit struct {
+	sink     valueobjects.TaintSink
+	at       token.Pos
+	reached  token.Pos
+	flow     *taintFlow
+	function *ssa.Function
+}
+
+// taintHitKey identifies a hit so fixpoint iterations record it once
+type taintHitKey struct {
+	at      token.Pos
+	reached token.Pos
+}
+
+// taintRun is the result of analyzing one function, either from its own sources
+// or with a single parameter assumed tainted
+type taintRun struct {
+	facts    map[ssa.Value]*taintFlow
+	fields   map[taintFieldKey]*taintFlow
+	returned *taintFlow
+	hits     map[taintHitKey]taintHit
+}
+
+// taintFieldKey identifies a struct field reached through base, keeping taint field-sensitive
+type taintFieldKey struct {
+	base  ssa.Value
+	field int
+}
+
+// taintRunKey identifies a run; param is -1 for the run driven by sources
+type taintRunKey struct {
+	function *ssa.Function
+	param    int
+}
+
+// taintEngine holds the resolved rules and memoized runs for one package
+type taintEngine struct {
+	sources    map[string]valueobjects.TaintSource
+	sanitizers map[string]bool
+	sinks      map[string][]valueobjects.TaintSink
+	runs       map[taintRunKey]*taintRun
+}
+
+// AnalyzePackageTaint reports source-to-sink flows in a type-checked package
+func (ta *SSATaintAnalyzer) AnalyzePackageTaint(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	// SSA construction requires complete, well-typed information
+	info := pkg.TypesInfo()
+	if info == nil || pkg.HasTypeErrors() {
+		return nil, nil
+	}
+
+	prog, ssaPkg, err := buildSSAPackage(pkg)
+	if err != nil || ssaPkg == nil {
+		return nil, err
+	}
+
+	engine := newTaintEngine(config.TaintConfiguration())
+
+	var hits []taintHit
+	for _, fn := range packageFunctions(prog, ssaPkg, pkg.Files(), info) {
+		for _, hit := range engine.run(fn, -1).hits {
+			hits = append(hits, hit)
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].at != hits[j].at {
+			return hits[i].at < hits[j].at
+		}
+		return hits[i].reached < hits[j].reached
+	})
+
+	var findings []entities.AnalysisFinding
+	reported := make(map[token.Pos]bool)
+	for _, hit := range hits {
+		if reported[hit.at] {
+			continue
+		}
+		reported[hit.at] = true
+		findings = append(findings, ta.createFinding(pkg.FileSet(), hit))
+	}
+
+	return findings, nil
+}
+
+// createFinding builds the finding for a hit, attaching the source-to-sink trace
+func (ta *SSATaintAnalyzer) createFinding(fset *token.FileSet, hit taintHit) entities.AnalysisFinding {
+	position := fset.Position(hit.at)
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+
+	cwe := taintCWEs[hit.sink.Kind()]
+	if cwe == "" {
+		cwe = "CWE-20"
+	}
+	issue := "taint_" + hit.sink.Kind()
+	funcName := hit.function.Name()
+
+	description := fmt.Sprintf("%s input from %s reaches %s", hit.flow.kind, hit.flow.source, hit.sink.Name())
+	if hit.reached != hit.at {
+		reached := fset.Position(hit.reached)
+		description += fmt.Sprintf(" at %s:%d", shortFileName(reached.Filename), reached.Line)
+	}
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue, funcName, position.Line, position.Column),
+		entities.FindingTypeSecurity,
+		location,
+		fmt.Sprintf("Tainted data in %s: %s detected - %s (%s)", funcName, issue, description, cwe),
+		valueobjects.SeverityError,
+	)
+	finding.AddMetadata("cwe", cwe)
+	finding.AddMetadata("source", hit.flow.source)
+	finding.AddMetadata("source_kind", hit.flow.kind)
+	finding.AddMetadata("sink", hit.sink.Name())
+	finding.AddMetadata("sink_kind", hit.sink.Kind())
+
+	last := ""
+	for _, pos := range append(hit.flow.steps, hit.reached) {
+		step := fset.Position(pos)
+		if !step.IsValid() {
+			continue
+		}
+		key := fmt.Sprintf("%s:%d", step.Filename, step.Line)
+		if key == last {
+			continue
+		}
+		last = key
+		if stepLocation, err := valueobjects.NewSourceLocation(step.Filename, step.Line, step.Column); err == nil {
+			finding.AddTraceStep(stepLocation)
+		}
+	}
+
+	return finding
+}
+
+// shortFileName returns the base name of a file path for messages
+func shortFileName(path string) string {
+	if index := strings.LastIndexAny(path, `/\`); index >= 0 {
+		return path[index+1:]
+	}
+	return path
+}
+
+// typesPackageOf returns the package whose declarations info describes
+func typesPackageOf(info *types.Info) *types.Package {
+	if info == nil {
+		return nil
+	}
+	for _, obj := range info.Defs {
+		if obj != nil && obj.Pkg() != nil {
+			return obj.Pkg()
+		}
+	}
+	return nil
+}
+
+// buildSSAPackage builds SSA for the package and its source dependencies; other
+// imports are created from type information only, so their functions have no bodies
+func buildSSAPackage(pkg *PackageContext) (prog *ssa.Program, ssaPkg *ssa.Package, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			prog, ssaPkg, err = nil, nil, fmt.Errorf("building SSA form: %v", r)
+		}
+	}()
+
+	typesPkg := typesPackageOf(pkg.TypesInfo())
+	if typesPkg == nil {
+		return nil, nil, nil
+	}
+
+	prog = ssa.NewProgram(pkg.FileSet(), 0)
+	created := map[*types.Package]bool{typesPkg: true}
+	var sourcePkgs []*ssa.Package
+
+	for _, dep := range pkg.dependencies {
+		depPkg := typesPackageOf(dep.info)
+		if depPkg == nil || created[depPkg] || dep.typeErrors {
+			continue
+		}
+		created[depPkg] = true
+		sourcePkgs = append(sourcePkgs, prog.CreatePackage(depPkg, dep.files, dep.info, true))
+	}
+	ssaPkg = prog.CreatePackage(typesPkg, pkg.Files(), pkg.TypesInfo(), true)
+	sourcePkgs = append(sourcePkgs, ssaPkg)
+
+	var createImports func(p *types.Package)
+	createImports = func(p *types.Package) {
+		for _, imported := range p.Imports() {
+			if !created[imported] {
+				created[imported] = true
+				prog.CreatePackage(imported, nil, nil, true)
+				createImports(imported)
+			}
+		}
+	}
+	for _, sourcePkg := range sourcePkgs {
+		createImports(sourcePkg.Pkg)
+	}
+
+	// Build sequentially: Program.Build runs packages concurrently, where a panic could not be recovered
+	for _, sourcePkg := range sourcePkgs {
+		sourcePkg.Build()
+	}
+
+	return prog, ssaPkg, nil
+}
+
+// packageFunctions returns the functions, methods and closures declared in the package
+func packageFunctions(prog *ssa.Program, ssaPkg *ssa.Package, files []*ast.File, info *types.Info) []*ssa.Function {
+	var functions []*ssa.Function
+	seen := make(map[*ssa.Function]bool)
+
+	var add func(fn *ssa.Function)
+	add = func(fn *ssa.Function) {
+		if fn == nil || seen[fn] || len(fn.Blocks) == 0 {
+			return
+		}
+		seen[fn] = true
+		functions = append(functions, fn)
+		for _, anon := range fn.AnonFuncs {
+			add(anon)
+		}
+	}
+
+	// The package initializer holds the closures of package-level variables
+	add(ssaPkg.Func("init"))
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+				if obj, ok := info.Defs[funcDecl.Name].(*types.Func); ok {
+					add(prog.FuncValue(obj))
+				}
+			}
+		}
+	}
+
+	return functions
+}
+
+// newTaintEngine indexes the configured rules by name
+func newTaintEngine(config valueobjects.TaintConfiguration) *taintEngine {
+	engine := &taintEngine{
+		sources:    make(map[string]valueobjects.TaintSource),
+		sanitizers: make(map[string]bool),
+		sinks:      make(map[string][]valueobjects.TaintSink),
+		runs:       make(map[taintRunKey]*taintRun),
+	}
+	for _, source := range config.Sources() {
+		engine.sources[source.Name()] = source
+	}
+	for _, sanitizer := range config.Sanitizers() {
+		engine.sanitizers[sanitizer] = true
+	}
+	for _, sink := range config.Sinks() {
+		engine.sinks[sink.Name()] = append(engine.sinks[sink.Name()], sink)
+	}
+	return engine
+}
+
+// run analyzes fn to a fixpoint; with param >= 0 only that parameter is tainted and
+// sources are ignored, which yields the summary used at call sites
+func (e *taintEngine) run(fn *ssa.Function, param int) *taintRun {
+	key := taintRunKey{function: fn, param: param}
+	if run, ok := e.runs[key]; ok {
+		// Recursive calls see the partial result of the run in progress
+		return run
+	}
+
+	run := &taintRun{
+		facts:  make(map[ssa.Value]*taintFlow),
+		fields: make(map[taintFieldKey]*taintFlow),
+		hits:   make(map[taintHitKey]taintHit),
+	}
+	e.runs[key] = run
+
+	if param >= 0 {
+		p := fn.Params[param]
+		run.facts[p] = &taintFlow{steps: []token.Pos{p.Pos()}}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				if e.visit(run, fn, instr, param < 0) {
+					changed = true
+				}
+			}
+		}
+	}
+
+	return run
+}
+
+// visit propagates taint across one instruction, reporting whether anything changed
+func (e *taintEngine) visit(run *taintRun, fn *ssa.Function, instr ssa.Instruction, sources bool) bool {
+	switch in := instr.(type) {
+	case *ssa.Call:
+		return e.call(run, fn, in.Common(), in, sources)
+	case *ssa.Go:
+		return e.call(run, fn, in.Common(), nil, sources)
+	case *ssa.Defer:
+		return e.call(run, fn, in.Common(), nil, sources)
+	case *ssa.Store:
+		changed := run.copyFields(in.Val, in.Addr)
+		if flow := run.facts[in.Val]; flow != nil {
+			return run.taintMemory(in.Addr, flow.through(in.Pos())) || changed
+		}
+		return changed
+	case *ssa.MapUpdate:
+		if flow := run.taintOf(in.Key, in.Value); flow != nil {
+			return run.taintMemory(in.Map, flow.through(in.Pos()))
+		}
+	case *ssa.Send:
+		if flow := run.facts[in.X]; flow != nil {
+			return run.taintMemory(in.Chan, flow.through(in.Pos()))
+		}
+	case *ssa.Return:
+		if run.returned == nil {
+			if flow := run.taintOf(in.Results...); flow != nil {
+				run.returned = flow.through(in.Pos())
+				return true
+			}
+		}
+	case *ssa.UnOp:
+		if global, ok := in.X.(*ssa.Global); ok && in.Op == token.MUL && sources && global.Pkg != nil {
+			if source, ok := e.sources[global.Pkg.Pkg.Path()+"."+global.Name()]; ok {
+				return run.taint(in, &taintFlow{kind: source.Kind(), source: source.Name(), steps: []token.Pos{in.Pos()}})
+			}
+		}
+		changed := in.Op == token.MUL && run.copyFields(in.X, in)
+		return run.propagate(in, in.X) || changed
+	case *ssa.FieldAddr:
+		if sources {
+			if source, ok := e.sources[fieldName(in.X.Type(), in.Field)]; ok {
+				return run.taint(in, &taintFlow{kind: source.Kind(), source: source.Name(), steps: []token.Pos{in.Pos()}})
+			}
+		}
+		if flow := run.fields[taintFieldKey{base: in.X, field: in.Field}]; flow != nil {
+			return run.taint(in, flow)
+		}
+		return run.propagate(in, in.X)
+	case *ssa.Field:
+		if sources {
+			if source, ok := e.sources[fieldName(in.X.Type(), in.Field)]; ok {
+				return run.taint(in, &taintFlow{kind: source.Kind(), source: source.Name(), steps: []token.Pos{in.Pos()}})
+			}
+		}
+		if flow := run.fields[taintFieldKey{base: in.X, field: in.Field}]; flow != nil {
+			return run.taint(in, flow)
+		}
+		return run.propagate(in, in.X)
+	case *ssa.BinOp:
+		switch in.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return false
+		}
+		if flow := run.taintOf(in.X, in.Y); flow != nil {
+			return run.taint(in, flow.through(in.Pos()))
+		}
+	case *ssa.ChangeType:
+		return e.convert(run, fn, in, in.X)
+	case *ssa.Convert:
+		return e.convert(run, fn, in, in.X)
+	case *ssa.Index:
+		return run.propagate(in, in.X)
+	case *ssa.IndexAddr:
+		return run.propagate(in, in.X)
+	case *ssa.Lookup:
+		return run.propagate(in, in.X)
+	case *ssa.Phi, *ssa.MakeInterface, *ssa.ChangeInterface, *ssa.Slice, *ssa.Extract, *ssa.TypeAssert,
+		*ssa.Next, *ssa.Range, *ssa.MultiConvert, *ssa.SliceToArrayPointer:
+		value := instr.(ssa.Value)
+		var operands []ssa.Value
+		for _, operand := range instr.Operands(nil) {
+			if *operand != nil {
+				operands = append(operands, *operand)
+			}
+		}
+		return run.propagate(value, operands...)
+	}
+	return false
+}
+
+// convert propagates taint through a conversion and checks conversions to sink types
+func (e *taintEngine) convert(run *taintRun, fn *ssa.Function, value ssa.Value, operand ssa.Value) bool {
+	flow := run.facts[operand]
+	if flow == nil {
+		return false
+	}
+	changed := false
+	for _, sink := range e.sinks[typeName(value.Type())] {
+		changed = run.hit(fn, sink, value.Pos(), value.Pos(), flow) || changed
+	}
+	return run.taint(value, flow) || changed
+}
+
+// call applies sanitizers, sources, sinks and callee summaries at a call site
+func (e *taintEngine) call(run *taintRun, fn *ssa.Function, common *ssa.CallCommon, result ssa.Value, sources bool) bool {
+	pos := common.Pos()
+	callee := common.StaticCallee()
+	name := callName(common)
+
+	args := common.Args
+	offset := 0
+	if common.IsInvoke() {
+		args = append([]ssa.Value{common.Value}, args...)
+		offset = 1
+	} else if callee != nil && callee.Signature.Recv() != nil {
+		offset = 1
+	}
+
+	if e.sanitizers[name] {
+		return false
+	}
+
+	changed := false
+	if source, ok := e.sources[name]; ok && sources && result != nil {
+		changed = run.taint(result, &taintFlow{kind: source.Kind(), source: source.Name(), steps: []token.Pos{pos}})
+	}
+
+	for _, sink := range e.sinks[name] {
+		for i := offset; i < len(args); i++ {
+			if !sink.Accepts(i - offset) {
+				continue
+			}
+			if flow := run.facts[args[i]]; flow != nil {
+				changed = run.hit(fn, sink, pos, pos, flow) || changed
+				break
+			}
+		}
+	}
+
+	if callee != nil && len(callee.Blocks) > 0 {
+		return e.summarize(run, fn, callee, args, result, pos, sources) || changed
+	}
+
+	// Without a body the result is assumed to derive from any tainted argument
+	flow := run.taintOf(args...)
+	if flow == nil {
+		return changed
+	}
+	flow = flow.through(pos)
+	if result != nil {
+		changed = run.taint(result, flow) || changed
+	}
+	if target := mutatedArgument(name, args, offset); target != nil {
+		changed = run.taintMemory(target, flow) || changed
+	}
+	return changed
+}
+
+// summarize applies the summaries of a callee with a body to the tainted arguments of a call
+func (e *taintEngine) summarize(run *taintRun, fn, callee *ssa.Function, args []ssa.Value, result ssa.Value, pos token.Pos, sources bool) bool {
+	changed := false
+
+	if sources && result != nil {
+		if returned := e.run(callee, -1).returned; returned != nil {
+			changed = run.taint(result, returned.through(pos))
+		}
+	}
+
+	for i, arg := range args {
+		flow := run.facts[arg]
+		if flow == nil || i >= len(callee.Params) {
+			continue
+		}
+		flow = flow.through(pos)
+		summary := e.run(callee, i)
+		if summary.returned != nil && result != nil {
+			changed = run.taint(result, flow.then(summary.returned)) || changed
+		}
+		for _, hit := range summary.hits {
+			changed = run.hit(fn, hit.sink, pos, hit.reached, flow.then(hit.flow)) || changed
+		}
+	}
+
+	return changed
+}
+
+// taint marks value as carrying flow unless it already does
+func (run *taintRun) taint(value ssa.Value, flow *taintFlow) bool {
+	if value == nil || run.facts[value] != nil {
+		return false
+	}
+	run.facts[value] = flow
+	return true
+}
+
+// taintOf returns the flow of the first tainted value
+func (run *taintRun) taintOf(values ...ssa.Value) *taintFlow {
+	for _, value := range values {
+		if flow := run.facts[value]; flow != nil {
+			return flow
+		}
+	}
+	return nil
+}
+
+// propagate taints value when any operand is tainted
+func (run *taintRun) propagate(value ssa.Value, operands ...ssa.Value) bool {
+	if flow := run.taintOf(operands...); flow != nil {
+		return run.taint(value, flow)
+	}
+	return false
+}
+
+// hit records tainted data reaching a sink; at is the position reported in this
+// function and reached the sink itself, which differ when the sink is in a callee
+func (run *taintRun) hit(fn *ssa.Function, sink valueobjects.TaintSink, at, reached token.Pos, flow *taintFlow) bool {
+	key := taintHitKey{at: at, reached: reached}
+	if _, ok := run.hits[key]; ok {
+		return false
+	}
+	run.hits[key] = taintHit{sink: sink, at: at, reached: reached, flow: flow, function: fn}
+	return true
+}
+
+// copyFields carries the field facts of a struct to a copy of it, such as a composite
+// literal loaded from its temporary and stored into a variable
+func (run *taintRun) copyFields(from, to ssa.Value) bool {
+	changed := false
+	for key, flow := range run.fields {
+		if key.base != from {
+			continue
+		}
+		copied := taintFieldKey{base: to, field: key.field}
+		if run.fields[copied] == nil {
+			run.fields[copied] = flow
+			changed = true
+		}
+	}
+	return changed
+}
+
+// taintMemory marks the variable or struct field behind addr as carrying flow
+func (run *taintRun) taintMemory(addr ssa.Value, flow *taintFlow) bool {
+	for {
+		switch v := addr.(type) {
+		case *ssa.FieldAddr:
+			changed := run.taint(v, flow)
+			key := taintFieldKey{base: v.X, field: v.Field}
+			if run.fields[key] == nil {
+				run.fields[key] = flow
+				changed = true
+			}
+			return changed
+		case *ssa.UnOp:
+			if v.Op != token.MUL {
+				return run.taint(v, flow)
+			}
+			// A map, slice or channel loaded from a variable shares its contents
+			changed := run.taint(v, flow)
+			return run.taintMemory(v.X, flow) || changed
+		case *ssa.IndexAddr:
+			addr = v.X
+		case *ssa.MakeInterface:
+			addr = v.X
+		case *ssa.ChangeType:
+			addr = v.X
+		case *ssa.Slice:
+			addr = v.X
+		default:
+			return run.taint(addr, flow)
+		}
+	}
+}
+
+// mutatedArgument returns the argument an external call writes its other arguments into
+func mutatedArgument(name string, args []ssa.Value, offset int) ssa.Value {
+	if len(args) == 0 {
+		return nil
+	}
+	if offset == 1 {
+		method := name[strings.LastIndex(name, ".")+1:]
+		for _, prefix := range []string{"Write", "Append", "Add", "Set", "Store", "Put", "Push"} {
+			if strings.HasPrefix(method, prefix) {
+				return args[0]
+			}
+		}
+		return nil
+	}
+	if strings.HasPrefix(name, "fmt.Fprint") || name == "io.WriteString" || name == "io.Copy" {
+		return args[0]
+	}
+	return nil
+}
+
+// callName returns the normalized qualified name of the function a call invokes
+func callName(common *ssa.CallCommon) string {
+	if common.IsInvoke() {
+		return valueobjects.NormalizeTaintName(common.Method.FullName())
+	}
+	callee := common.StaticCallee()
+	if callee == nil {
+		return ""
+	}
+	if origin := callee.Origin(); origin != nil {
+		callee = origin
+	}
+	if obj, ok := callee.Object().(*types.Func); ok {
+		return valueobjects.NormalizeTaintName(obj.FullName())
+	}
+	return ""
+}
+
+// fieldName returns the qualified name of field index of the struct behind typ
+func fieldName(typ types.Type, index int) string {
+	if pointer, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = pointer.Elem()
+	}
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	structType, ok := named.Underlying().(*types.Struct)
+	if !ok || index >= structType.NumFields() {
+		return ""
+	}
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name() + "." + structType.Field(index).Name()
+}
+
+// typeName returns the qualified name of a named type
+func typeName(typ types.Type) string {
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+}
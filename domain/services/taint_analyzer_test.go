@@ -0,0 +1,280 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestTaintAnalyzer_AnalyzePackageTaint(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		expectedIssues int
+		expectedTypes  []string
+	}{
+		{
+			name: "Form value concatenated into a query",
+			code: `
+package app
+import (
+	"database/sql"
+	"net/http"
+)
+func search(db *sql.DB, r *http.Request) {
+	name := r.FormValue("name")
+	query := "SELECT id FROM users WHERE name = '" + name + "'"
+	db.Query(query)
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"taint_sql", "request input from net/http.Request.FormValue reaches database/sql.DB.Query", "CWE-89"},
+		},
+		{
+			name: "Query parameter passed as placeholder argument - no issue",
+			code: `
+package app
+import (
+	"database/sql"
+	"net/http"
+)
+func search(db *sql.DB, r *http.Request) {
+	db.Query("SELECT id FROM users WHERE name = ?", r.FormValue("name"))
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Command-line argument reaches exec through a helper",
+			code: `
+package app
+import (
+	"os"
+	"os/exec"
+)
+func run(tool string) error {
+	return exec.Command(tool, "--version").Run()
+}
+func main() {
+	run(os.Args[1])
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"taint_exec", "args input from os.Args reaches os/exec.Command at a.go:8", "CWE-78"},
+		},
+		{
+			name: "Environment variable returned by a helper opens a file",
+			code: `
+package app
+import "os"
+func configPath() string {
+	return os.Getenv("APP_CONFIG")
+}
+func load() ([]byte, error) {
+	return os.ReadFile(configPath())
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"taint_path", "env input from os.Getenv reaches os.ReadFile", "CWE-22"},
+		},
+		{
+			name: "Sanitized path - no issue",
+			code: `
+package app
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+)
+func download(w http.ResponseWriter, r *http.Request) {
+	name := filepath.Base(r.URL.Query().Get("file"))
+	data, _ := os.ReadFile(filepath.Join("/srv/files", name))
+	w.Write(data)
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Request header built into a template string",
+			code: `
+package app
+import (
+	"html/template"
+	"net/http"
+	"strings"
+)
+func greet(w http.ResponseWriter, r *http.Request) {
+	var b strings.Builder
+	b.WriteString("<p>")
+	b.WriteString(r.Header.Get("X-Name"))
+	page := template.HTML(b.String())
+	template.Must(template.New("p").Parse("{{.}}")).Execute(w, page)
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"taint_template", "request input from net/http.Request.Header reaches html/template.HTML", "CWE-79"},
+		},
+		{
+			name: "Numeric conversion sanitizes input - no issue",
+			code: `
+package app
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+func byID(db *sql.DB, r *http.Request) {
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		return
+	}
+	db.Exec(fmt.Sprintf("DELETE FROM users WHERE id = %d", id))
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Form value assigned to a struct field reaches a query",
+			code: `
+package app
+import (
+	"database/sql"
+	"net/http"
+)
+type search struct {
+	Name string
+}
+func find(db *sql.DB, r *http.Request) {
+	var s search
+	s.Name = r.FormValue("name")
+	db.Query("SELECT id FROM users WHERE name = '" + s.Name + "'")
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"taint_sql", "request input from net/http.Request.FormValue reaches database/sql.DB.Query"},
+		},
+		{
+			name: "Form value in a struct composite literal reaches a query",
+			code: `
+package app
+import (
+	"database/sql"
+	"net/http"
+)
+type search struct {
+	Name string
+}
+func find(db *sql.DB, r *http.Request) {
+	s := search{Name: r.FormValue("name")}
+	db.Query("SELECT id FROM users WHERE name = '" + s.Name + "'")
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"taint_sql", "request input from net/http.Request.FormValue reaches database/sql.DB.Query"},
+		},
+		{
+			name: "Clean field of a composite literal with tainted neighbours - no issue",
+			code: `
+package app
+import (
+	"database/sql"
+	"net/http"
+)
+type search struct {
+	Name  string
+	Table string
+}
+func find(db *sql.DB, r *http.Request) {
+	s := search{Name: r.FormValue("name"), Table: "users"}
+	db.Query("SELECT id FROM " + s.Table)
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := checkPackage(t, tt.code)
+
+			analyzer := NewSSATaintAnalyzer()
+			findings, err := analyzer.AnalyzePackageTaint(pkg, valueobjects.DefaultAnalysisConfiguration())
+			if err != nil {
+				t.Fatalf("AnalyzePackageTaint failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+		})
+	}
+}
+
+func TestTaintAnalyzer_TraceAndCustomRules(t *testing.T) {
+	src := `
+package app
+import "net/http"
+func audit(entry string) {}
+func record(r *http.Request) string {
+	user := r.PathValue("user")
+	return "login:" + user
+}
+func handle(w http.ResponseWriter, r *http.Request) {
+	line := record(r)
+	audit(line)
+}`
+
+	pkg := checkPackage(t, src)
+
+	sink, err := valueobjects.NewTaintSink("app.audit", "log", nil)
+	if err != nil {
+		t.Fatalf("NewTaintSink failed: %v", err)
+	}
+	defaults := valueobjects.DefaultTaintConfiguration()
+	config := valueobjects.DefaultAnalysisConfiguration().WithTaintConfiguration(
+		valueobjects.NewTaintConfiguration(defaults.Sources(), nil, []valueobjects.TaintSink{sink}),
+	)
+
+	findings, err := NewSSATaintAnalyzer().AnalyzePackageTaint(pkg, config)
+	if err != nil {
+		t.Fatalf("AnalyzePackageTaint failed: %v", err)
+	}
+	if len(findings) != 1 {
+		for i, finding := range findings {
+			t.Logf("Finding %d: %s", i, finding.Message())
+		}
+		t.Fatalf("Expected 1 finding, got %d", len(findings))
+	}
+
+	finding := findings[0]
+	if !strings.Contains(finding.Message(), "taint_log detected - request input from net/http.Request.PathValue reaches app.audit (CWE-20)") {
+		t.Errorf("Unexpected message: %s", finding.Message())
+	}
+
+	var lines []int
+	for _, step := range finding.Trace() {
+		lines = append(lines, step.Line())
+	}
+	expected := []int{6, 7, 10, 11}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected trace lines %v, got %v", expected, lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Expected trace lines %v, got %v", expected, lines)
+			break
+		}
+	}
+}
@@ -0,0 +1,465 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// TimerIssue represents different kinds of time.Timer and time.Ticker misuse
+type TimerIssue int
+
+const (
+	TimerIssueAfterInLoop TimerIssue = iota
+	TimerIssueTickLeak
+	TimerIssueUnstoppedTicker
+	TimerIssueUnstoppedTimer
+	TimerIssueResetUnstopped
+)
+
+// String returns a string representation of the timer issue
+func (ti TimerIssue) String() string {
+	switch ti {
+	case TimerIssueAfterInLoop:
+		return "time_after_in_loop"
+	case TimerIssueTickLeak:
+		return "time_tick_leak"
+	case TimerIssueUnstoppedTicker:
+		return "unstopped_ticker"
+	case TimerIssueUnstoppedTimer:
+		return "unstopped_timer"
+	case TimerIssueResetUnstopped:
+		return "reset_unstopped_timer"
+	default:
+		return "unknown"
+	}
+}
+
+// TimerMisuseDetector detects leaking or wasteful uses of the time package timers
+type TimerMisuseDetector interface {
+	DetectTimerIssues(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageTimerIssues(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTTimerMisuseDetector implements TimerMisuseDetector using AST analysis
+type ASTTimerMisuseDetector struct{}
+
+// NewASTTimerMisuseDetector creates a new AST-based timer misuse detector
+func NewASTTimerMisuseDetector() *ASTTimerMisuseDetector {
+	return &ASTTimerMisuseDetector{}
+}
+
+// timerScan holds the state of the analysis of one function declaration
+type timerScan struct {
+	pkg      *PackageContext
+	funcDecl *ast.FuncDecl
+	findings []entities.AnalysisFinding
+}
+
+// DetectTimerIssues analyzes code for timer and ticker misuse
+func (tmd *ASTTimerMisuseDetector) DetectTimerIssues(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	return tmd.detectTimerIssues(node, newNodePackageContext(node, fset)), nil
+}
+
+// DetectPackageTimerIssues analyzes every file of a package, using type information to recognize the time package
+func (tmd *ASTTimerMisuseDetector) DetectPackageTimerIssues(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var findings []entities.AnalysisFinding
+
+	for _, file := range pkg.Files() {
+		findings = append(findings, tmd.detectTimerIssues(file, pkg)...)
+	}
+
+	return findings, nil
+}
+
+// detectTimerIssues analyzes each function declaration under node
+func (tmd *ASTTimerMisuseDetector) detectTimerIssues(node ast.Node, pkg *PackageContext) []entities.AnalysisFinding {
+	var findings []entities.AnalysisFinding
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if funcDecl, ok := n.(*ast.FuncDecl); ok && funcDecl.Body != nil {
+			scan := &timerScan{pkg: pkg, funcDecl: funcDecl}
+			scan.checkCalls(funcDecl.Body, 0)
+			scan.checkUnstopped()
+			scan.checkResets(funcDecl.Body.List, make(map[string]bool))
+			findings = append(findings, scan.findings...)
+			return false
+		}
+		return true
+	})
+
+	return findings
+}
+
+// checkCalls reports time.After inside loops and time.Tick outside long-lived code
+func (scan *timerScan) checkCalls(node ast.Node, loopDepth int) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			// A literal runs in its own frame; its loops are counted from zero
+			scan.checkCalls(stmt.Body, 0)
+			return false
+		case *ast.ForStmt:
+			if stmt != node {
+				scan.checkLoop(stmt.Init, stmt.Cond, stmt.Post, stmt.Body, loopDepth)
+				return false
+			}
+		case *ast.RangeStmt:
+			if stmt != node {
+				if call, ok := stmt.X.(*ast.CallExpr); ok && scan.isTimeCall(call, "Tick") && !loopExits(stmt.Body, true) {
+					// for range time.Tick(d) without an exit runs for the life of the program
+					scan.checkCalls(stmt.Body, loopDepth+1)
+					return false
+				}
+				scan.checkLoop(nil, stmt.X, nil, stmt.Body, loopDepth)
+				return false
+			}
+		case *ast.CallExpr:
+			switch {
+			case loopDepth > 0 && scan.isTimeCall(stmt, "After"):
+				scan.report(TimerIssueAfterInLoop, stmt.Pos(), "",
+					"time.After allocates a new timer on every loop iteration that is not released until it fires; create one time.NewTimer outside the loop and Reset it",
+					entities.FindingTypePerformance, "hoist time.NewTimer out of the loop and call Reset")
+			case scan.isTimeCall(stmt, "Tick") && !scan.isLongLived():
+				scan.report(TimerIssueTickLeak, stmt.Pos(), "",
+					"the ticker behind time.Tick can never be stopped, so it keeps firing after the caller returns; use time.NewTicker and defer Stop",
+					entities.FindingTypeBug, "ticker := time.NewTicker(d); defer ticker.Stop()")
+			}
+		}
+		return true
+	})
+}
+
+// checkLoop analyzes the header of a loop at the current depth and its body one level deeper
+func (scan *timerScan) checkLoop(init ast.Stmt, cond ast.Expr, post ast.Stmt, body *ast.BlockStmt, loopDepth int) {
+	for _, header := range []ast.Node{init, cond, post} {
+		if header != nil {
+			scan.checkCalls(header, loopDepth)
+		}
+	}
+	scan.checkCalls(body, loopDepth+1)
+}
+
+// loopExits reports whether a loop body contains a break out of the loop, or
+// optionally a return
+func loopExits(body *ast.BlockStmt, returns bool) bool {
+	exits := false
+	var inspect func(node ast.Node, nested bool)
+	inspect = func(node ast.Node, nested bool) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch stmt := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				exits = exits || returns
+			case *ast.BranchStmt:
+				if stmt.Tok == token.GOTO || stmt.Label != nil || stmt.Tok == token.BREAK && !nested {
+					exits = true
+				}
+			case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+				if n != node {
+					inspect(n, true)
+					return false
+				}
+			}
+			return !exits
+		})
+	}
+	inspect(body, false)
+	return exits
+}
+
+// isLongLived reports whether the function runs for the life of the program
+func (scan *timerScan) isLongLived() bool {
+	name := scan.funcDecl.Name.Name
+	return scan.funcDecl.Recv == nil && (name == "main" || name == "init")
+}
+
+// checkUnstopped reports tickers and timers that are created but never stopped
+func (scan *timerScan) checkUnstopped() {
+	created := make(map[string]*ast.CallExpr)
+	var order []string
+
+	ast.Inspect(scan.funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for i, rhs := range node.Rhs {
+				if call, ok := rhs.(*ast.CallExpr); ok && scan.isTimerConstructor(call) && len(node.Lhs) == len(node.Rhs) {
+					if ident, ok := node.Lhs[i].(*ast.Ident); ok && ident.Name != "_" {
+						if _, seen := created[ident.Name]; !seen {
+							order = append(order, ident.Name)
+						}
+						created[ident.Name] = call
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, value := range node.Values {
+				if call, ok := value.(*ast.CallExpr); ok && scan.isTimerConstructor(call) && i < len(node.Names) {
+					if _, seen := created[node.Names[i].Name]; !seen {
+						order = append(order, node.Names[i].Name)
+					}
+					created[node.Names[i].Name] = call
+				}
+			}
+		case *ast.SelectorExpr:
+			// time.NewTicker(d).C cannot be stopped by anyone
+			if call, ok := node.X.(*ast.CallExpr); ok && scan.isTimerConstructor(call) {
+				scan.reportUnstopped(call, "")
+			}
+		}
+		return true
+	})
+
+	for _, name := range order {
+		if !scan.stoppedOrEscapes(name) {
+			scan.reportUnstopped(created[name], name)
+		}
+	}
+}
+
+// reportUnstopped reports a timer or ticker that is never stopped
+func (scan *timerScan) reportUnstopped(call *ast.CallExpr, name string) {
+	display := name
+	if display == "" {
+		display = types.ExprString(call)
+	}
+
+	if scan.isTimeCall(call, "NewTicker") {
+		scan.report(TimerIssueUnstoppedTicker, call.Pos(), display,
+			fmt.Sprintf("ticker '%s' is never stopped and keeps firing after the function returns", display),
+			entities.FindingTypeBug, "defer ticker.Stop()")
+		return
+	}
+	scan.report(TimerIssueUnstoppedTimer, call.Pos(), display,
+		fmt.Sprintf("timer '%s' is never stopped and stays scheduled until it fires", display),
+		entities.FindingTypePerformance, "defer timer.Stop()")
+}
+
+// stoppedOrEscapes reports whether a local timer is stopped, or leaves the function so someone else may stop it
+func (scan *timerScan) stoppedOrEscapes(name string) bool {
+	stopped, escapes := false, false
+	methodUses := make(map[*ast.Ident]bool)
+
+	ast.Inspect(scan.funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := node.X.(*ast.Ident); ok && ident.Name == name {
+				methodUses[ident] = true
+				switch node.Sel.Name {
+				case "Stop":
+					stopped = true
+				case "C", "Reset":
+				default:
+					escapes = true
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name {
+					methodUses[ident] = true
+					// Reassigning from anything but a constructor hands the value elsewhere
+					if len(node.Lhs) == len(node.Rhs) {
+						if call, ok := node.Rhs[i].(*ast.CallExpr); !ok || !scan.isTimerConstructor(call) {
+							escapes = true
+						}
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, ident := range node.Names {
+				methodUses[ident] = true
+			}
+		case *ast.Ident:
+			if node.Name == name && !methodUses[node] {
+				escapes = true
+			}
+		}
+		return !stopped && !escapes
+	})
+
+	return stopped || escapes
+}
+
+// checkResets reports Reset calls on timers that are neither stopped nor drained on the path before them
+func (scan *timerScan) checkResets(list []ast.Stmt, safe map[string]bool) {
+	for _, stmt := range list {
+		scan.checkResetStmt(stmt, safe)
+	}
+}
+
+// checkResetStmt analyzes one statement, updating which timers are known to be stopped or drained
+func (scan *timerScan) checkResetStmt(stmt ast.Stmt, safe map[string]bool) {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		scan.checkResets(s.List, safe)
+	case *ast.LabeledStmt:
+		scan.checkResetStmt(s.Stmt, safe)
+	case *ast.IfStmt:
+		if s.Init != nil {
+			scan.checkResetStmt(s.Init, safe)
+		}
+		scan.checkResetExpr(s.Cond, safe)
+		scan.checkResets(s.Body.List, copySet(safe))
+		if s.Else != nil {
+			scan.checkResetStmt(s.Else, copySet(safe))
+		}
+	case *ast.ForStmt:
+		scan.checkResets(s.Body.List, copySet(safe))
+	case *ast.RangeStmt:
+		branch := copySet(safe)
+		if name := scan.timerChannel(s.X); name != "" {
+			branch[name] = true
+		}
+		scan.checkResets(s.Body.List, branch)
+	case *ast.SwitchStmt:
+		scan.checkClauses(s.Body, safe)
+	case *ast.TypeSwitchStmt:
+		scan.checkClauses(s.Body, safe)
+	case *ast.SelectStmt:
+		scan.checkClauses(s.Body, safe)
+	case *ast.DeferStmt, *ast.GoStmt:
+		// Deferred and concurrent calls do not run before the statements that follow
+	default:
+		scan.checkResetExpr(stmt, safe)
+	}
+}
+
+// checkClauses analyzes each case of a switch or select; receiving from a timer drains it
+func (scan *timerScan) checkClauses(body *ast.BlockStmt, safe map[string]bool) {
+	for _, clause := range body.List {
+		branch := copySet(safe)
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			scan.checkResets(c.Body, branch)
+		case *ast.CommClause:
+			if c.Comm != nil {
+				scan.checkResetExpr(c.Comm, branch)
+			}
+			scan.checkResets(c.Body, branch)
+		}
+	}
+}
+
+// checkResetExpr reports unsafe Reset calls in a simple statement or expression and records Stop calls and drains
+func (scan *timerScan) checkResetExpr(node ast.Node, safe map[string]bool) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch expr := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.UnaryExpr:
+			if name := scan.timerChannel(expr.X); expr.Op == token.ARROW && name != "" {
+				safe[name] = true
+			}
+		case *ast.CallExpr:
+			selector, ok := expr.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			ident, ok := selector.X.(*ast.Ident)
+			if !ok || !scan.isLocalTimer(ident.Name) {
+				return true
+			}
+			switch selector.Sel.Name {
+			case "Stop":
+				safe[ident.Name] = true
+			case "Reset":
+				if !safe[ident.Name] {
+					scan.report(TimerIssueResetUnstopped, expr.Pos(), ident.Name,
+						fmt.Sprintf("timer '%s' is reset without being stopped and drained first, so a stale value can remain in its channel", ident.Name),
+						entities.FindingTypeBug, fmt.Sprintf("if !%s.Stop() { <-%s.C }", ident.Name, ident.Name))
+				}
+				delete(safe, ident.Name)
+			}
+		}
+		return true
+	})
+}
+
+// timerChannel returns the timer name of an expression of the form t.C
+func (scan *timerScan) timerChannel(expr ast.Expr) string {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "C" {
+		return ""
+	}
+	if ident, ok := selector.X.(*ast.Ident); ok && scan.isLocalTimer(ident.Name) {
+		return ident.Name
+	}
+	return ""
+}
+
+// isLocalTimer reports whether name is assigned from time.NewTimer in the current function
+func (scan *timerScan) isLocalTimer(name string) bool {
+	found := false
+	ast.Inspect(scan.funcDecl.Body, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+			for i, lhs := range assign.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name {
+					if call, ok := assign.Rhs[i].(*ast.CallExpr); ok && scan.isTimeCall(call, "NewTimer") {
+						found = true
+					}
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// isTimerConstructor reports whether call is time.NewTimer or time.NewTicker
+func (scan *timerScan) isTimerConstructor(call *ast.CallExpr) bool {
+	return scan.isTimeCall(call, "NewTimer") || scan.isTimeCall(call, "NewTicker")
+}
+
+// isTimeCall reports whether call invokes the named function of the time package,
+// using type information to see through renamed imports when available
+func (scan *timerScan) isTimeCall(call *ast.CallExpr, name string) bool {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != name {
+		return false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if pkgName, ok := info.Uses[ident].(*types.PkgName); ok {
+			return pkgName.Imported().Path() == "time"
+		}
+	}
+	return ident.Name == "time"
+}
+
+// report records a finding located at pos
+func (scan *timerScan) report(issue TimerIssue, pos token.Pos, timer, description string, findingType entities.FindingType, suggestion string) {
+	position := scan.pkg.FileSet().Position(pos)
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue.String(), scan.funcDecl.Name.Name, position.Line, position.Column),
+		findingType,
+		location,
+		fmt.Sprintf("Timer misuse in %s: %s detected - %s", scan.funcDecl.Name.Name, issue.String(), description),
+		valueobjects.SeverityWarning,
+	)
+	if timer != "" {
+		finding.AddMetadata("timer", timer)
+	}
+	finding.AddMetadata("suggestion", suggestion)
+
+	scan.findings = append(scan.findings, finding)
+}
+
+// copySet returns a copy of a set of names
+func copySet(set map[string]bool) map[string]bool {
+	copied := make(map[string]bool, len(set))
+	for name := range set {
+		copied[name] = true
+	}
+	return copied
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestTimerMisuseDetector_DetectTimerIssues(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		expectedIssues int
+		expectedTypes  []string
+		expectedKind   entities.FindingType
+	}{
+		{
+			name: "time.After inside select loop",
+			code: `
+package main
+import "time"
+func test(events chan int) {
+	for {
+		select {
+		case <-events:
+		case <-time.After(time.Second):
+			return
+		}
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"time_after_in_loop"},
+			expectedKind:   entities.FindingTypePerformance,
+		},
+		{
+			name: "time.After outside loop - no issue",
+			code: `
+package main
+import "time"
+func test(events chan int) {
+	select {
+	case <-events:
+	case <-time.After(time.Second):
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "time.After in goroutine literal started from loop - no issue",
+			code: `
+package main
+import "time"
+func test(items []int) {
+	for range items {
+		go func() {
+			<-time.After(time.Second)
+		}()
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "time.Tick in short-lived function",
+			code: `
+package main
+import "time"
+func poll(done chan struct{}) {
+	tick := time.Tick(time.Second)
+	for {
+		select {
+		case <-tick:
+		case <-done:
+			return
+		}
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"time_tick_leak"},
+			expectedKind:   entities.FindingTypeBug,
+		},
+		{
+			name: "time.Tick in main - no issue",
+			code: `
+package main
+import "time"
+func main() {
+	for range time.Tick(time.Second) {
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Endless range over time.Tick - no issue",
+			code: `
+package main
+import "time"
+func heartbeat() {
+	for range time.Tick(time.Minute) {
+		println("alive")
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "NewTicker without Stop",
+			code: `
+package main
+import "time"
+func test(done chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	for {
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"unstopped_ticker", "'ticker'"},
+			expectedKind:   entities.FindingTypeBug,
+		},
+		{
+			name: "NewTicker with deferred Stop - no issue",
+			code: `
+package main
+import "time"
+func test(done chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	<-ticker.C
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "NewTimer without Stop",
+			code: `
+package main
+import "time"
+func test(events chan int) {
+	timer := time.NewTimer(time.Second)
+	select {
+	case <-events:
+	case <-timer.C:
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"unstopped_timer"},
+			expectedKind:   entities.FindingTypePerformance,
+		},
+		{
+			name: "Timer returned to caller - no issue",
+			code: `
+package main
+import "time"
+func test() *time.Timer {
+	timer := time.NewTimer(time.Second)
+	return timer
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Reset on unstopped timer",
+			code: `
+package main
+import "time"
+func test(events chan int) {
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	for range events {
+		timer.Reset(time.Second)
+	}
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"reset_unstopped_timer"},
+			expectedKind:   entities.FindingTypeBug,
+		},
+		{
+			name: "Reset after stop and drain - no issue",
+			code: `
+package main
+import "time"
+func test(events chan int) {
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	for range events {
+		if !timer.Stop() {
+			<-timer.C
+		}
+		timer.Reset(time.Second)
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Reset after timer fired - no issue",
+			code: `
+package main
+import "time"
+func test(events chan int) {
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	for {
+		select {
+		case <-events:
+			return
+		case <-timer.C:
+			timer.Reset(time.Second)
+		}
+	}
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			detector := NewASTTimerMisuseDetector()
+			config := valueobjects.DefaultAnalysisConfiguration()
+			findings, err := detector.DetectTimerIssues(node, fset, config)
+			if err != nil {
+				t.Fatalf("DetectTimerIssues failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+
+			for _, finding := range findings {
+				if finding.Type() != tt.expectedKind {
+					t.Errorf("Expected finding type %s, got %s", tt.expectedKind, finding.Type())
+				}
+			}
+		})
+	}
+}
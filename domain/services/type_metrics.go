@@ -0,0 +1,302 @@
+package services
+
+import (
+	"go/ast"
+	"sort"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+// CalculateTypeMetrics calculates field and method counts and LCOM4 cohesion for every struct type
+// of a package, collecting methods from all of its files. Two methods are connected when they use
+// a common field or one calls the other; methods that use no field take no part in LCOM4
+func (c *ASTComplexityCalculator) CalculateTypeMetrics(pkg *PackageContext) ([]valueobjects.TypeMetrics, error) {
+	type structDecl struct {
+		spec   *ast.TypeSpec
+		fields map[string]bool
+		count  int
+	}
+
+	var order []string
+	structs := make(map[string]*structDecl)
+	methods := make(map[string][]*ast.FuncDecl)
+
+	for _, file := range pkg.Files() {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok || structs[typeSpec.Name.Name] != nil {
+						continue
+					}
+					order = append(order, typeSpec.Name.Name)
+					structs[typeSpec.Name.Name] = &structDecl{
+						spec:   typeSpec,
+						fields: structFieldNames(structType),
+						count:  countFields(structType),
+					}
+				}
+			case *ast.FuncDecl:
+				if name := receiverTypeName(d); name != "" {
+					methods[name] = append(methods[name], d)
+				}
+			}
+		}
+	}
+
+	var metrics []valueobjects.TypeMetrics
+	for _, name := range order {
+		decl := structs[name]
+		pos := pkg.FileSet().Position(decl.spec.Pos())
+		location, err := valueobjects.NewSourceLocation(pos.Filename, pos.Line, pos.Column)
+		if err != nil {
+			return nil, err
+		}
+
+		typeMetrics, err := valueobjects.NewTypeMetrics(name, location, decl.count, len(methods[name]),
+			cohesionGroups(methods[name], decl.fields))
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, typeMetrics)
+	}
+
+	return metrics, nil
+}
+
+// cohesionGroups returns the connected components of the methods that use fields, ordered by
+// their first method. Getters and setters take no part: a call to one counts as using its field
+func cohesionGroups(methods []*ast.FuncDecl, fields map[string]bool) []valueobjects.CohesionGroup {
+	accessors := make(map[string]string)
+	index := make(map[string]int, len(methods))
+	for i, method := range methods {
+		if field := accessedField(method, fields); field != "" {
+			accessors[method.Name.Name] = field
+			continue
+		}
+		index[method.Name.Name] = i
+	}
+
+	components := newDisjointSet(len(methods))
+	usedFields := make([]map[string]bool, len(methods))
+	fieldOwner := make(map[string]int)
+	for i, method := range methods {
+		if _, ok := accessors[method.Name.Name]; ok {
+			continue
+		}
+		used, calls := receiverUses(method, fields, index, accessors)
+		usedFields[i] = used
+		for field := range used {
+			if owner, ok := fieldOwner[field]; ok {
+				components.union(i, owner)
+			} else {
+				fieldOwner[field] = i
+			}
+		}
+		for _, callee := range calls {
+			components.union(i, callee)
+		}
+	}
+
+	return collectCohesionGroups(methods, usedFields, components)
+}
+
+// collectCohesionGroups turns the components that use at least one field into cohesion groups
+func collectCohesionGroups(methods []*ast.FuncDecl, usedFields []map[string]bool, components disjointSet) []valueobjects.CohesionGroup {
+	var roots []int
+	names := make(map[int][]string)
+	groupFields := make(map[int]map[string]bool)
+	for i, used := range usedFields {
+		if used == nil {
+			continue
+		}
+		root := components.find(i)
+		if _, ok := names[root]; !ok {
+			roots = append(roots, root)
+			groupFields[root] = make(map[string]bool)
+		}
+		names[root] = append(names[root], methods[i].Name.Name)
+		for field := range used {
+			groupFields[root][field] = true
+		}
+	}
+
+	groups := make([]valueobjects.CohesionGroup, 0, len(roots))
+	for _, root := range roots {
+		// A component whose methods touch no state does not count towards LCOM4
+		if len(groupFields[root]) == 0 {
+			continue
+		}
+		fieldNames := make([]string, 0, len(groupFields[root]))
+		for field := range groupFields[root] {
+			fieldNames = append(fieldNames, field)
+		}
+		sort.Strings(names[root])
+		sort.Strings(fieldNames)
+
+		group, _ := valueobjects.NewCohesionGroup(names[root], fieldNames)
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// disjointSet is a union-find structure over method indexes
+type disjointSet []int
+
+// newDisjointSet creates a disjoint set where every element is its own component
+func newDisjointSet(size int) disjointSet {
+	set := make(disjointSet, size)
+	for i := range set {
+		set[i] = i
+	}
+	return set
+}
+
+// find returns the representative of an element's component
+func (s disjointSet) find(i int) int {
+	for s[i] != i {
+		s[i] = s[s[i]]
+		i = s[i]
+	}
+	return i
+}
+
+// union merges the components of two elements
+func (s disjointSet) union(a, b int) {
+	s[s.find(a)] = s.find(b)
+}
+
+// methodReceiver returns the name of a method's receiver, or "" when it is unnamed
+func methodReceiver(method *ast.FuncDecl) string {
+	if method.Recv == nil || len(method.Recv.List) == 0 || len(method.Recv.List[0].Names) == 0 {
+		return ""
+	}
+	if name := method.Recv.List[0].Names[0].Name; name != "_" {
+		return name
+	}
+	return ""
+}
+
+// accessedField returns the field a getter returns or a setter assigns, or "" when the method
+// does more than that single statement
+func accessedField(method *ast.FuncDecl, fields map[string]bool) string {
+	receiver := methodReceiver(method)
+	if receiver == "" || method.Body == nil || len(method.Body.List) != 1 {
+		return ""
+	}
+
+	var expr ast.Expr
+	switch stmt := method.Body.List[0].(type) {
+	case *ast.ReturnStmt:
+		if len(stmt.Results) != 1 {
+			return ""
+		}
+		expr = stmt.Results[0]
+	case *ast.AssignStmt:
+		if len(stmt.Lhs) != 1 {
+			return ""
+		}
+		expr = stmt.Lhs[0]
+	default:
+		return ""
+	}
+
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok || !fields[sel.Sel.Name] {
+		return ""
+	}
+	if ident, ok := ast.Unparen(sel.X).(*ast.Ident); ok && ident.Name == receiver {
+		return sel.Sel.Name
+	}
+	return ""
+}
+
+// receiverUses returns the fields a method reads or writes through its receiver, directly or
+// through an accessor, and the indexes of the other sibling methods it calls
+func receiverUses(method *ast.FuncDecl, fields map[string]bool, methods map[string]int, accessors map[string]string) (map[string]bool, []int) {
+	used := make(map[string]bool)
+	var calls []int
+
+	receiver := methodReceiver(method)
+	if receiver == "" || method.Body == nil {
+		return used, calls
+	}
+
+	ast.Inspect(method.Body, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := ast.Unparen(sel.X).(*ast.Ident); !ok || ident.Name != receiver {
+			return true
+		}
+		if fields[sel.Sel.Name] {
+			used[sel.Sel.Name] = true
+		} else if field, ok := accessors[sel.Sel.Name]; ok {
+			used[field] = true
+		} else if callee, ok := methods[sel.Sel.Name]; ok {
+			calls = append(calls, callee)
+		}
+		return true
+	})
+
+	return used, calls
+}
+
+// structFieldNames returns the names of a struct's fields; an embedded field is named after its type
+func structFieldNames(structType *ast.StructType) map[string]bool {
+	names := make(map[string]bool)
+	if structType.Fields == nil {
+		return names
+	}
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			if ident := embeddedTypeName(field.Type); ident != "" {
+				names[ident] = true
+			}
+			continue
+		}
+		for _, name := range field.Names {
+			names[name.Name] = true
+		}
+	}
+	return names
+}
+
+// embeddedTypeName returns the field name implied by an embedded type expression
+func embeddedTypeName(expr ast.Expr) string {
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.SelectorExpr:
+			return t.Sel.Name
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// countFields counts the fields of a struct, so that "a, b int" counts as two fields
+func countFields(structType *ast.StructType) int {
+	if structType.Fields == nil {
+		return 0
+	}
+	count := 0
+	for _, field := range structType.Fields.List {
+		count += max(len(field.Names), 1)
+	}
+	return count
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestComplexityCalculator_CalculateTypeMetrics(t *testing.T) {
+	tests := []struct {
+		name            string
+		files           []string
+		typeName        string
+		expectedFields  int
+		expectedMethods int
+		expectedGroups  []string
+	}{
+		{
+			name: "Multi-name fields are counted individually",
+			files: []string{`
+package shapes
+type Point struct {
+	X, Y, Z float64
+	label   string
+}`},
+			typeName:        "Point",
+			expectedFields:  4,
+			expectedMethods: 0,
+			expectedGroups:  nil,
+		},
+		{
+			name: "Methods across files form one cohesive group",
+			files: []string{`
+package cache
+import "sync"
+type Cache struct {
+	sync.Mutex
+	items map[string]int
+	hits  int
+}
+func (c *Cache) Get(key string) int {
+	c.Lock()
+	defer c.Unlock()
+	c.hits++
+	return c.items[key]
+}`, `
+package cache
+func (c *Cache) Put(key string, value int) {
+	c.Lock()
+	c.items[key] = value
+	c.Unlock()
+}
+func (c *Cache) Reset() {
+	c.clear()
+}
+func (c *Cache) clear() {
+	c.items = map[string]int{}
+	c.hits = 0
+}`},
+			typeName:        "Cache",
+			expectedFields:  3,
+			expectedMethods: 4,
+			expectedGroups:  []string{"{Get, Put, Reset, clear: hits, items}"},
+		},
+		{
+			name: "Disjoint field sets split into groups, accessors and stateless methods excluded",
+			files: []string{`
+package server
+type Server struct {
+	addr, certFile string
+	users          map[string]string
+	sessions       int
+}
+func (s *Server) Addr() string { return s.addr }
+func (s *Server) Listen() error { return listen(s.addr, s.certFile) }
+func (s *Server) Reload() error { return s.Listen() }
+func (s *Server) Login(name string) bool {
+	if _, ok := s.users[name]; ok {
+		s.sessions++
+		return true
+	}
+	return false
+}
+func (s *Server) Logout() { s.sessions-- }
+func (s *Server) Version() string { return "1.0" }
+func listen(addr, cert string) error { return nil }`},
+			typeName:        "Server",
+			expectedFields:  4,
+			expectedMethods: 6,
+			expectedGroups: []string{
+				"{Listen, Reload: addr, certFile}",
+				"{Login, Logout: sessions, users}",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := parsePackage(t, tt.files...)
+
+			metrics, err := NewASTComplexityCalculator().CalculateTypeMetrics(pkg)
+			if err != nil {
+				t.Fatalf("CalculateTypeMetrics failed: %v", err)
+			}
+
+			var found *valueobjects.TypeMetrics
+			for i := range metrics {
+				if metrics[i].Name() == tt.typeName {
+					found = &metrics[i]
+				}
+			}
+			if found == nil {
+				t.Fatalf("Expected metrics for type %s, got %v", tt.typeName, metrics)
+			}
+
+			if found.Fields() != tt.expectedFields {
+				t.Errorf("Expected %d fields, got %d", tt.expectedFields, found.Fields())
+			}
+			if found.Methods() != tt.expectedMethods {
+				t.Errorf("Expected %d methods, got %d", tt.expectedMethods, found.Methods())
+			}
+			if found.LCOM4() != len(tt.expectedGroups) {
+				t.Errorf("Expected LCOM4 %d, got %d", len(tt.expectedGroups), found.LCOM4())
+			}
+
+			var groups []string
+			for _, group := range found.Groups() {
+				groups = append(groups, group.String())
+			}
+			if strings.Join(groups, " and ") != strings.Join(tt.expectedGroups, " and ") {
+				t.Errorf("Expected groups %v, got %v", tt.expectedGroups, groups)
+			}
+		})
+	}
+}
+
+func TestSmellDetector_GodStructCountsMultiNameFields(t *testing.T) {
+	code := `
+package main
+type Settings struct {
+	a, b, c, d int
+	e, f, g, h string
+	i, j, k    bool
+}`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse code: %v", err)
+	}
+
+	config, _ := valueobjects.NewAnalysisConfiguration(10, 15, 50, true, valueobjects.SeverityInfo)
+	findings, err := NewASTSmellDetector().DetectSmells(file, fset, config)
+	if err != nil {
+		t.Fatalf("DetectSmells failed: %v", err)
+	}
+
+	for _, finding := range findings {
+		if strings.Contains(finding.Message(), "too many fields: 11") {
+			return
+		}
+	}
+	t.Errorf("Expected a god struct finding for 11 fields, got %v", findings)
+}
+
+func TestSmellDetector_GodStructThresholdFromConfig(t *testing.T) {
+	code := `
+package main
+type Settings struct {
+	a, b, c, d int
+	e, f, g, h string
+}`
+
+	tests := []struct {
+		name      string
+		maxFields int
+		expected  string
+	}{
+		{name: "Fields above the threshold", maxFields: 6, expected: "too many fields: 8 (max recommended: 6)"},
+		{name: "Fields within the threshold - no issue", maxFields: 8, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "test.go", code, 0)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			config := valueobjects.DefaultAnalysisConfiguration().WithMaxStructFields(tt.maxFields)
+			findings, err := NewASTSmellDetector().DetectSmells(file, fset, config)
+			if err != nil {
+				t.Fatalf("DetectSmells failed: %v", err)
+			}
+
+			var reported []string
+			for _, finding := range findings {
+				if strings.Contains(finding.Message(), "too many fields") {
+					reported = append(reported, finding.Message())
+				}
+			}
+			if tt.expected == "" {
+				if len(reported) != 0 {
+					t.Errorf("Expected no god struct finding, got %v", reported)
+				}
+				return
+			}
+			if len(reported) != 1 || !strings.Contains(reported[0], tt.expected) {
+				t.Errorf("Expected a finding containing %q, got %v", tt.expected, reported)
+			}
+		})
+	}
+}
@@ -0,0 +1,753 @@
+package services
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// WaitGroupIssue represents different kinds of sync.WaitGroup misuse
+type WaitGroupIssue int
+
+const (
+	WaitGroupIssueAddInGoroutine WaitGroupIssue = iota
+	WaitGroupIssueMissingDone
+	WaitGroupIssueCopied
+	WaitGroupIssueWaitInGoroutine
+	WaitGroupIssueAddCountMismatch
+)
+
+// String returns a string representation of the WaitGroup issue
+func (wgi WaitGroupIssue) String() string {
+	switch wgi {
+	case WaitGroupIssueAddInGoroutine:
+		return "add_in_goroutine"
+	case WaitGroupIssueMissingDone:
+		return "missing_done"
+	case WaitGroupIssueCopied:
+		return "waitgroup_copied"
+	case WaitGroupIssueWaitInGoroutine:
+		return "wait_in_goroutine"
+	case WaitGroupIssueAddCountMismatch:
+		return "add_count_mismatch"
+	default:
+		return "unknown"
+	}
+}
+
+// WaitGroupDetector detects misuse of sync.WaitGroup in Go code
+type WaitGroupDetector interface {
+	DetectWaitGroupIssues(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+	DetectPackageWaitGroupIssues(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error)
+}
+
+// ASTWaitGroupDetector implements WaitGroupDetector using AST analysis
+type ASTWaitGroupDetector struct{}
+
+// NewASTWaitGroupDetector creates a new AST-based WaitGroup detector
+func NewASTWaitGroupDetector() *ASTWaitGroupDetector {
+	return &ASTWaitGroupDetector{}
+}
+
+// waitGroupScan holds the state of the analysis of one function declaration
+type waitGroupScan struct {
+	pkg      *PackageContext
+	funcDecl *ast.FuncDecl
+	names    map[string]bool
+	findings []entities.AnalysisFinding
+}
+
+// goroutineBody is the code run by a go statement together with the caller-side
+// expressions bound to its parameters
+type goroutineBody struct {
+	body     *ast.BlockStmt
+	bindings map[string]ast.Expr
+}
+
+// DetectWaitGroupIssues analyzes code for WaitGroup misuse
+func (wgd *ASTWaitGroupDetector) DetectWaitGroupIssues(node ast.Node, fset *token.FileSet, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	return wgd.detectWaitGroupIssues(node, newNodePackageContext(node, fset)), nil
+}
+
+// DetectPackageWaitGroupIssues analyzes every file of a package, following goroutines into named callees
+func (wgd *ASTWaitGroupDetector) DetectPackageWaitGroupIssues(pkg *PackageContext, config valueobjects.AnalysisConfiguration) ([]entities.AnalysisFinding, error) {
+	var findings []entities.AnalysisFinding
+
+	for _, file := range pkg.Files() {
+		findings = append(findings, wgd.detectWaitGroupIssues(file, pkg)...)
+	}
+
+	return findings, nil
+}
+
+// detectWaitGroupIssues analyzes each function declaration under node
+func (wgd *ASTWaitGroupDetector) detectWaitGroupIssues(node ast.Node, pkg *PackageContext) []entities.AnalysisFinding {
+	var findings []entities.AnalysisFinding
+	names := wgd.waitGroupNames(node)
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if funcDecl, ok := n.(*ast.FuncDecl); ok && funcDecl.Body != nil {
+			scan := &waitGroupScan{pkg: pkg, funcDecl: funcDecl, names: names}
+			scan.checkCopies()
+			scan.checkGoroutines()
+			scan.checkAddCounts(funcDecl.Body)
+			findings = append(findings, scan.findings...)
+			return false
+		}
+		return true
+	})
+
+	return findings
+}
+
+// waitGroupNames collects the names of variables, parameters and fields declared as
+// WaitGroups, used to recognize them when type information is unavailable
+func (wgd *ASTWaitGroupDetector) waitGroupNames(node ast.Node) map[string]bool {
+	names := make(map[string]bool)
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch decl := n.(type) {
+		case *ast.Field:
+			if isWaitGroupType(decl.Type) {
+				for _, name := range decl.Names {
+					names[name.Name] = true
+				}
+			}
+		case *ast.ValueSpec:
+			if decl.Type != nil && isWaitGroupType(decl.Type) {
+				for _, name := range decl.Names {
+					names[name.Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			for i, rhs := range decl.Rhs {
+				if i >= len(decl.Lhs) {
+					break
+				}
+				if ident, ok := decl.Lhs[i].(*ast.Ident); ok && isWaitGroupValue(rhs) {
+					names[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+
+	return names
+}
+
+// isWaitGroupType reports whether a type expression is sync.WaitGroup or *sync.WaitGroup
+func isWaitGroupType(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "WaitGroup" {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && pkg.Name == "sync"
+}
+
+// isWaitGroupValue reports whether expr constructs a WaitGroup
+func isWaitGroupValue(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return e.Type != nil && isWaitGroupType(e.Type)
+	case *ast.UnaryExpr:
+		return e.Op == token.AND && isWaitGroupValue(e.X)
+	case *ast.CallExpr:
+		if fun, ok := e.Fun.(*ast.Ident); ok && fun.Name == "new" && len(e.Args) == 1 {
+			return isWaitGroupType(e.Args[0])
+		}
+	}
+	return false
+}
+
+// isWaitGroup reports whether expr denotes a WaitGroup, preferring type information
+func (scan *waitGroupScan) isWaitGroup(expr ast.Expr) bool {
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if t := info.TypeOf(expr); t != nil {
+			return isWaitGroupTypeOf(t)
+		}
+	}
+
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return scan.names[e.Name]
+	case *ast.SelectorExpr:
+		return scan.names[e.Sel.Name]
+	case *ast.StarExpr:
+		return scan.isWaitGroup(e.X)
+	}
+	return false
+}
+
+// isWaitGroupTypeOf reports whether t is sync.WaitGroup or a pointer to it
+func isWaitGroupTypeOf(t types.Type) bool {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "sync" && named.Obj().Name() == "WaitGroup"
+}
+
+// waitGroupCall returns the WaitGroup receiver of a call to the named method
+func (scan *waitGroupScan) waitGroupCall(node ast.Node, method string) ast.Expr {
+	call, ok := node.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != method || !scan.isWaitGroup(selector.X) {
+		return nil
+	}
+	return selector.X
+}
+
+// checkCopies reports WaitGroup parameters taken by value
+func (scan *waitGroupScan) checkCopies() {
+	ast.Inspect(scan.funcDecl, func(n ast.Node) bool {
+		funcType, ok := n.(*ast.FuncType)
+		if !ok || funcType.Params == nil {
+			return true
+		}
+		for _, field := range funcType.Params.List {
+			if _, isPointer := field.Type.(*ast.StarExpr); isPointer || !isWaitGroupType(field.Type) {
+				continue
+			}
+			name := "_"
+			if len(field.Names) > 0 {
+				name = field.Names[0].Name
+			}
+			scan.report(WaitGroupIssueCopied, field.Pos(), name,
+				fmt.Sprintf("WaitGroup '%s' is passed by value, so Add and Done on the copy never reach the caller's Wait; pass *sync.WaitGroup", name),
+				valueobjects.SeverityError)
+		}
+		return true
+	})
+}
+
+// checkGoroutines analyzes the goroutines started by the function
+func (scan *waitGroupScan) checkGoroutines() {
+	ast.Inspect(scan.funcDecl.Body, func(n ast.Node) bool {
+		goStmt, ok := n.(*ast.GoStmt)
+		if !ok {
+			return true
+		}
+
+		target := scan.goroutineBody(goStmt)
+		if target == nil {
+			return true
+		}
+
+		scan.checkAddInGoroutine(target)
+		scan.checkWaitInGoroutine(target)
+		scan.checkMissingDone(target)
+		return true
+	})
+}
+
+// goroutineBody returns the body run by a go statement, resolving named targets through the package
+func (scan *waitGroupScan) goroutineBody(goStmt *ast.GoStmt) *goroutineBody {
+	var funcType *ast.FuncType
+	var body *ast.BlockStmt
+	if funcLit, ok := goStmt.Call.Fun.(*ast.FuncLit); ok {
+		funcType, body = funcLit.Type, funcLit.Body
+	} else if funcDecl := scan.pkg.ResolveCall(goStmt.Call); funcDecl != nil && funcDecl.Body != nil {
+		funcType, body = funcDecl.Type, funcDecl.Body
+	} else {
+		return nil
+	}
+
+	bindings := make(map[string]ast.Expr)
+	for i, param := range flattenParams(funcType.Params) {
+		if i < len(goStmt.Call.Args) {
+			bindings[param.Name] = goStmt.Call.Args[i]
+		}
+	}
+
+	return &goroutineBody{body: body, bindings: bindings}
+}
+
+// callerKey renders a WaitGroup expression of a goroutine body in terms of the caller's names
+func (target *goroutineBody) callerKey(expr ast.Expr) string {
+	expr = ast.Unparen(expr)
+	if ident, ok := expr.(*ast.Ident); ok {
+		if bound, ok := target.bindings[ident.Name]; ok {
+			expr = bound
+		}
+	}
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	return types.ExprString(expr)
+}
+
+// checkAddInGoroutine reports Add calls made by the goroutine being counted
+func (scan *waitGroupScan) checkAddInGoroutine(target *goroutineBody) {
+	if containsGoStmt(target.body) {
+		// A goroutine that starts its own workers may Add for them before each go
+		return
+	}
+
+	inspectOutsideLiterals(target.body, func(n ast.Node) {
+		if wg := scan.waitGroupCall(n, "Add"); wg != nil {
+			key := target.callerKey(wg)
+			scan.report(WaitGroupIssueAddInGoroutine, n.Pos(), key,
+				fmt.Sprintf("%s.Add is called inside the goroutine it counts, so Wait can return before the goroutine starts; call Add before the go statement", key),
+				valueobjects.SeverityError)
+		}
+	})
+}
+
+// checkWaitInGoroutine reports a goroutine waiting on the WaitGroup it is itself counted by,
+// unless it calls Done before waiting as a barrier
+func (scan *waitGroupScan) checkWaitInGoroutine(target *goroutineBody) {
+	counted := make(map[string]bool)
+	firstDone := make(map[string]token.Pos)
+	ast.Inspect(target.body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.GoStmt); ok {
+			return false
+		}
+		if deferStmt, ok := n.(*ast.DeferStmt); ok {
+			ast.Inspect(deferStmt.Call, func(inner ast.Node) bool {
+				if wg := scan.waitGroupCall(inner, "Done"); wg != nil {
+					counted[target.callerKey(wg)] = true
+				}
+				return true
+			})
+			return false
+		}
+		if wg := scan.waitGroupCall(n, "Done"); wg != nil {
+			key := target.callerKey(wg)
+			counted[key] = true
+			if _, seen := firstDone[key]; !seen {
+				firstDone[key] = n.Pos()
+			}
+		}
+		return true
+	})
+
+	inspectOutsideLiterals(target.body, func(n ast.Node) {
+		wg := scan.waitGroupCall(n, "Wait")
+		if wg == nil {
+			return
+		}
+		key := target.callerKey(wg)
+		if done, seen := firstDone[key]; !counted[key] || seen && done < n.Pos() {
+			return
+		}
+		scan.report(WaitGroupIssueWaitInGoroutine, n.Pos(), key,
+			fmt.Sprintf("goroutine calls %s.Wait while it is counted by the same WaitGroup, so Wait can never return", key),
+			valueobjects.SeverityCritical)
+	})
+}
+
+// checkMissingDone reports goroutines that call Done without defer and can leave without calling it
+func (scan *waitGroupScan) checkMissingDone(target *goroutineBody) {
+	var doneCall ast.Expr
+	deferred, inLoop := false, false
+	ast.Inspect(target.body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			// A goroutine calling Done once per iteration is counted per item, not per goroutine
+			inLoop = inLoop || scan.calls(node, "Done")
+			return false
+		case *ast.DeferStmt:
+			ast.Inspect(node.Call, func(inner ast.Node) bool {
+				if scan.waitGroupCall(inner, "Done") != nil {
+					deferred = true
+				}
+				return !deferred
+			})
+			return false
+		case *ast.GoStmt:
+			return false
+		case *ast.CallExpr:
+			if wg := scan.waitGroupCall(node, "Done"); wg != nil && doneCall == nil {
+				doneCall = wg
+			}
+		}
+		return true
+	})
+	if doneCall == nil || deferred || inLoop {
+		return
+	}
+
+	key := target.callerKey(doneCall)
+	flow := &doneFlow{scan: scan, key: key, target: target}
+	if done := flow.stmts(target.body.List, false); !done && flow.exit == token.NoPos {
+		flow.exit = target.body.Rbrace
+	}
+	if flow.exit != token.NoPos {
+		scan.report(WaitGroupIssueMissingDone, flow.exit, key,
+			fmt.Sprintf("goroutine can exit here without calling %s.Done, leaving Wait blocked forever; use defer %s.Done()", key, key),
+			valueobjects.SeverityError)
+	}
+}
+
+// doneFlow follows the statements of a goroutine to find an exit reached before Done
+type doneFlow struct {
+	scan   *waitGroupScan
+	key    string
+	target *goroutineBody
+	exit   token.Pos
+}
+
+// stmts returns whether Done has been called on every path completing list
+func (flow *doneFlow) stmts(list []ast.Stmt, done bool) bool {
+	for _, stmt := range list {
+		if flow.exit != token.NoPos {
+			return true
+		}
+		done = flow.stmt(stmt, done)
+	}
+	return done
+}
+
+// stmt returns whether Done has been called after stmt completes
+func (flow *doneFlow) stmt(stmt ast.Stmt, done bool) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		if !done {
+			flow.exit = s.Pos()
+		}
+		return true
+	case *ast.ExprStmt:
+		if wg := flow.scan.waitGroupCall(s.X, "Done"); wg != nil && flow.target.callerKey(wg) == flow.key {
+			return true
+		}
+		// Nothing after a panic or exit is reachable
+		return done || isTerminatingStmt(s)
+	case *ast.BlockStmt:
+		return flow.stmts(s.List, done)
+	case *ast.LabeledStmt:
+		return flow.stmt(s.Stmt, done)
+	case *ast.IfStmt:
+		if flow.isNilGuard(s.Cond) {
+			// if wg != nil { wg.Done() } only skips Done when there is nothing to count
+			return true
+		}
+		bodyDone := flow.stmts(s.Body.List, done)
+		elseDone := done
+		if s.Else != nil {
+			elseDone = flow.stmt(s.Else, done)
+		}
+		return done || bodyDone && elseDone
+	case *ast.ForStmt:
+		flow.stmts(s.Body.List, done)
+		// Nothing after an endless loop is reachable
+		return done || s.Cond == nil && !loopExits(s.Body, false)
+	case *ast.RangeStmt:
+		flow.stmts(s.Body.List, done)
+	case *ast.SwitchStmt:
+		flow.clauses(s.Body, done)
+	case *ast.TypeSwitchStmt:
+		flow.clauses(s.Body, done)
+	case *ast.SelectStmt:
+		flow.clauses(s.Body, done)
+	}
+	return done
+}
+
+// isNilGuard reports whether cond compares the tracked WaitGroup with nil
+func (flow *doneFlow) isNilGuard(cond ast.Expr) bool {
+	binary, ok := cond.(*ast.BinaryExpr)
+	if !ok || binary.Op != token.NEQ {
+		return false
+	}
+	if ident, ok := binary.Y.(*ast.Ident); !ok || ident.Name != "nil" {
+		return false
+	}
+	return flow.target.callerKey(binary.X) == flow.key
+}
+
+// clauses follows each case of a switch or select
+func (flow *doneFlow) clauses(body *ast.BlockStmt, done bool) {
+	for _, list := range clauseBodies(body) {
+		flow.stmts(list, done)
+	}
+}
+
+// checkAddCounts reports constant Add calls that do not match the goroutines started by a following fixed-size loop
+func (scan *waitGroupScan) checkAddCounts(body *ast.BlockStmt) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+
+		for i, stmt := range block.List {
+			exprStmt, ok := stmt.(*ast.ExprStmt)
+			if !ok {
+				continue
+			}
+			wg := scan.waitGroupCall(exprStmt.X, "Add")
+			if wg == nil {
+				continue
+			}
+			added, ok := scan.constInt(exprStmt.X.(*ast.CallExpr).Args[0])
+			if !ok {
+				continue
+			}
+			scan.checkAddCount(exprStmt, types.ExprString(wg), added, block.List[i+1:])
+		}
+		return true
+	})
+}
+
+// checkAddCount compares one constant Add with the first loop after it
+func (scan *waitGroupScan) checkAddCount(add *ast.ExprStmt, key string, added int64, rest []ast.Stmt) {
+	for _, stmt := range rest {
+		var iterations int64
+		var loopBody *ast.BlockStmt
+		switch loop := stmt.(type) {
+		case *ast.ForStmt:
+			count, ok := scan.forIterations(loop)
+			if !ok {
+				return
+			}
+			iterations, loopBody = count, loop.Body
+		case *ast.RangeStmt:
+			count, ok := scan.rangeIterations(loop.X)
+			if !ok {
+				return
+			}
+			iterations, loopBody = count, loop.Body
+		default:
+			if scan.usesWaitGroup(stmt, key) {
+				return
+			}
+			continue
+		}
+
+		if scan.usesWaitGroup(loopBody, key, "Add") || scan.spawnsAfter(rest, stmt) {
+			return
+		}
+		spawned := int64(0)
+		for _, bodyStmt := range loopBody.List {
+			goStmt, ok := bodyStmt.(*ast.GoStmt)
+			if !ok {
+				continue
+			}
+			// Only goroutines that finish with exactly one Done have a known count
+			target := scan.goroutineBody(goStmt)
+			if target == nil || !scan.callsDoneOnce(target.body) {
+				return
+			}
+			spawned++
+		}
+		if spawned > 0 && spawned*iterations != added {
+			scan.report(WaitGroupIssueAddCountMismatch, add.Pos(), key,
+				fmt.Sprintf("%s.Add(%d) does not match the %d goroutines started by the loop at line %d",
+					key, added, spawned*iterations, scan.pkg.FileSet().Position(stmt.Pos()).Line),
+				valueobjects.SeverityError)
+		}
+		return
+	}
+}
+
+// spawnsAfter reports whether statements after loop in rest start more goroutines
+func (scan *waitGroupScan) spawnsAfter(rest []ast.Stmt, loop ast.Stmt) bool {
+	after := false
+	for _, stmt := range rest {
+		if stmt == loop {
+			after = true
+			continue
+		}
+		if after && containsGoStmt(stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+// callsDoneOnce reports whether a goroutine body calls Done exactly once, outside loops,
+// and starts no goroutines of its own
+func (scan *waitGroupScan) callsDoneOnce(body *ast.BlockStmt) bool {
+	if containsGoStmt(body) {
+		return false
+	}
+
+	count, inLoop := 0, false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			inLoop = inLoop || scan.calls(n, "Done")
+			return false
+		}
+		if scan.waitGroupCall(n, "Done") != nil {
+			count++
+		}
+		return true
+	})
+	return count == 1 && !inLoop
+}
+
+// calls reports whether node calls the named method on any WaitGroup
+func (scan *waitGroupScan) calls(node ast.Node, method string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		found = found || scan.waitGroupCall(n, method) != nil
+		return !found
+	})
+	return found
+}
+
+// containsGoStmt reports whether node starts a goroutine
+func containsGoStmt(node ast.Node) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		_, isGo := n.(*ast.GoStmt)
+		found = found || isGo
+		return !found
+	})
+	return found
+}
+
+// usesWaitGroup reports whether node calls a method of the WaitGroup rendered as key, optionally restricted to one method
+func (scan *waitGroupScan) usesWaitGroup(node ast.Node, key string, methods ...string) bool {
+	if len(methods) == 0 {
+		methods = []string{"Add", "Done", "Wait"}
+	}
+
+	used := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		for _, method := range methods {
+			if wg := scan.waitGroupCall(n, method); wg != nil && types.ExprString(wg) == key {
+				used = true
+			}
+		}
+		return !used
+	})
+	return used
+}
+
+// forIterations returns the trip count of a loop of the form for i := a; i < b; i++
+func (scan *waitGroupScan) forIterations(loop *ast.ForStmt) (int64, bool) {
+	init, ok := loop.Init.(*ast.AssignStmt)
+	if !ok || len(init.Lhs) != 1 || len(init.Rhs) != 1 {
+		return 0, false
+	}
+	counter, ok := init.Lhs[0].(*ast.Ident)
+	if !ok {
+		return 0, false
+	}
+	start, ok := scan.constInt(init.Rhs[0])
+	if !ok {
+		return 0, false
+	}
+
+	post, ok := loop.Post.(*ast.IncDecStmt)
+	if !ok || post.Tok != token.INC {
+		return 0, false
+	}
+	if ident, ok := post.X.(*ast.Ident); !ok || ident.Name != counter.Name {
+		return 0, false
+	}
+
+	cond, ok := loop.Cond.(*ast.BinaryExpr)
+	if !ok {
+		return 0, false
+	}
+	if ident, ok := cond.X.(*ast.Ident); !ok || ident.Name != counter.Name {
+		return 0, false
+	}
+	end, ok := scan.constInt(cond.Y)
+	if !ok {
+		return 0, false
+	}
+
+	switch cond.Op {
+	case token.LSS:
+		return max(end-start, 0), true
+	case token.LEQ:
+		return max(end-start+1, 0), true
+	}
+	return 0, false
+}
+
+// rangeIterations returns the trip count of ranging over an integer constant or a fixed-size literal
+func (scan *waitGroupScan) rangeIterations(expr ast.Expr) (int64, bool) {
+	if count, ok := scan.constInt(expr); ok {
+		return count, true
+	}
+
+	composite, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return 0, false
+	}
+	for _, elt := range composite.Elts {
+		if _, keyed := elt.(*ast.KeyValueExpr); keyed {
+			return 0, false
+		}
+	}
+	arrayType, ok := composite.Type.(*ast.ArrayType)
+	if !ok {
+		return 0, false
+	}
+	if length, ok := scan.constInt(arrayType.Len); ok {
+		return length, true
+	}
+	return int64(len(composite.Elts)), true
+}
+
+// constInt evaluates an integer constant expression
+func (scan *waitGroupScan) constInt(expr ast.Expr) (int64, bool) {
+	if expr == nil {
+		return 0, false
+	}
+	if info := scan.pkg.TypesInfo(); info != nil {
+		if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+			return constant.Int64Val(constant.ToInt(tv.Value))
+		}
+	}
+	if lit, ok := ast.Unparen(expr).(*ast.BasicLit); ok && lit.Kind == token.INT {
+		value, err := strconv.ParseInt(lit.Value, 0, 64)
+		return value, err == nil
+	}
+	return 0, false
+}
+
+// inspectOutsideLiterals visits the nodes of body without entering function literals or nested go statements
+func inspectOutsideLiterals(body *ast.BlockStmt, visit func(ast.Node)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit, *ast.GoStmt:
+			return false
+		}
+		if n != nil {
+			visit(n)
+		}
+		return true
+	})
+}
+
+// report records a finding located at pos
+func (scan *waitGroupScan) report(issue WaitGroupIssue, pos token.Pos, waitGroup, description string, severity valueobjects.SeverityLevel) {
+	position := scan.pkg.FileSet().Position(pos)
+	location, _ := valueobjects.NewSourceLocation(position.Filename, position.Line, position.Column)
+
+	finding, _ := entities.NewAnalysisFinding(
+		fmt.Sprintf("%s_%s_%d_%d", issue.String(), scan.funcDecl.Name.Name, position.Line, position.Column),
+		entities.FindingTypeBug,
+		location,
+		fmt.Sprintf("WaitGroup misuse in %s: %s detected - %s", scan.funcDecl.Name.Name, issue.String(), description),
+		severity,
+	)
+	finding.AddMetadata("waitgroup", waitGroup)
+
+	scan.findings = append(scan.findings, finding)
+}
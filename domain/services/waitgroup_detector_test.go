@@ -0,0 +1,268 @@
+package services
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestWaitGroupDetector_DetectWaitGroupIssues(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           string
+		expectedIssues int
+		expectedTypes  []string
+	}{
+		{
+			name: "Correct worker pool - no issue",
+			code: `
+package main
+import "sync"
+func test(items []int) {
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func(v int) {
+			defer wg.Done()
+			_ = v
+		}(item)
+	}
+	wg.Wait()
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Add inside spawned goroutine",
+			code: `
+package main
+import "sync"
+func test(items []int) {
+	var wg sync.WaitGroup
+	for range items {
+		go func() {
+			wg.Add(1)
+			defer wg.Done()
+		}()
+	}
+	wg.Wait()
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"add_in_goroutine"},
+		},
+		{
+			name: "Done skipped by early return",
+			code: `
+package main
+import "sync"
+func work() error { return nil }
+func test() {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		if err := work(); err != nil {
+			return
+		}
+		wg.Done()
+	}()
+	wg.Wait()
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"missing_done", "defer wg.Done()"},
+		},
+		{
+			name: "Done called before every return - no issue",
+			code: `
+package main
+import "sync"
+func work() error { return nil }
+func test() {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		if err := work(); err != nil {
+			wg.Done()
+			return
+		}
+		wg.Done()
+	}()
+	wg.Wait()
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "WaitGroup passed by value to worker",
+			code: `
+package main
+import "sync"
+func worker(wg sync.WaitGroup) {
+	defer wg.Done()
+}
+func test() {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go worker(wg)
+	wg.Wait()
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"waitgroup_copied"},
+		},
+		{
+			name: "Named worker with missing Done on error path",
+			code: `
+package main
+import "sync"
+func work() error { return nil }
+func worker(group *sync.WaitGroup) {
+	if work() != nil {
+		return
+	}
+	group.Done()
+}
+func test() {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go worker(&wg)
+	wg.Wait()
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"missing_done", "wg.Done"},
+		},
+		{
+			name: "Wait inside goroutine counted by the same WaitGroup",
+			code: `
+package main
+import "sync"
+func test() {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		wg.Wait()
+	}()
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"wait_in_goroutine"},
+		},
+		{
+			name: "Closer goroutine waiting for workers - no issue",
+			code: `
+package main
+import "sync"
+func test(results chan int) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results <- 1
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Add count does not match fixed-size loop",
+			code: `
+package main
+import "sync"
+func test() {
+	var wg sync.WaitGroup
+	wg.Add(3)
+	for i := 0; i < 4; i++ {
+		go func() {
+			defer wg.Done()
+		}()
+	}
+	wg.Wait()
+}`,
+			expectedIssues: 1,
+			expectedTypes:  []string{"add_count_mismatch", "4 goroutines"},
+		},
+		{
+			name: "Add count matches range over constant - no issue",
+			code: `
+package main
+import "sync"
+func test() {
+	wg := &sync.WaitGroup{}
+	wg.Add(4)
+	for range 4 {
+		go func() {
+			defer wg.Done()
+		}()
+	}
+	wg.Wait()
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+		{
+			name: "Endless worker loop with Done before return - no issue",
+			code: `
+package main
+import "sync"
+func test(jobs chan int, quit chan struct{}) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		for {
+			select {
+			case <-jobs:
+			case <-quit:
+				wg.Done()
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}`,
+			expectedIssues: 0,
+			expectedTypes:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "", tt.code, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse code: %v", err)
+			}
+
+			detector := NewASTWaitGroupDetector()
+			config := valueobjects.DefaultAnalysisConfiguration()
+			findings, err := detector.DetectWaitGroupIssues(node, fset, config)
+			if err != nil {
+				t.Fatalf("DetectWaitGroupIssues failed: %v", err)
+			}
+
+			if len(findings) != tt.expectedIssues {
+				t.Errorf("Expected %d issues, got %d", tt.expectedIssues, len(findings))
+				for i, finding := range findings {
+					t.Logf("Finding %d: %s", i, finding.Message())
+				}
+			}
+
+			for _, expectedType := range tt.expectedTypes {
+				found := false
+				for _, finding := range findings {
+					if strings.Contains(finding.Message(), expectedType) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected to find issue type %s, but didn't", expectedType)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package valueobjects
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// ChangeSet records the changed lines of files, as read from a diff against their current contents
+type ChangeSet struct {
+	hunks map[string][]DiffHunk
+}
+
+// NewChangeSet creates a change set from the hunks of each changed file; paths are cleaned
+// so that they compare equal to the paths of analyzed files
+func NewChangeSet(hunks map[string][]DiffHunk) ChangeSet {
+	changes := ChangeSet{hunks: make(map[string][]DiffHunk, len(hunks))}
+	for path, fileHunks := range hunks {
+		clean := filepath.Clean(path)
+		changes.hunks[clean] = append(changes.hunks[clean], fileHunks...)
+	}
+	return changes
+}
+
+// Files returns the changed files in sorted order
+func (c ChangeSet) Files() []string {
+	files := make([]string, 0, len(c.hunks))
+	for path := range c.hunks {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Hunks returns the changed line ranges of a file
+func (c ChangeSet) Hunks(path string) []DiffHunk {
+	return append([]DiffHunk(nil), c.hunks[filepath.Clean(path)]...)
+}
+
+// Contains reports whether a line of a file was added or modified
+func (c ChangeSet) Contains(path string, line int) bool {
+	return c.Overlaps(path, line, line)
+}
+
+// Overlaps reports whether any line within start..end of a file was changed
+func (c ChangeSet) Overlaps(path string, start, end int) bool {
+	for _, hunk := range c.hunks[filepath.Clean(path)] {
+		if hunk.Touches(start, end) {
+			return true
+		}
+	}
+	return false
+}
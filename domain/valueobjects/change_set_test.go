@@ -0,0 +1,37 @@
+package valueobjects
+
+import "testing"
+
+func TestChangeSet_Overlaps(t *testing.T) {
+	modified, _ := NewDiffHunk(10, 2, 10, 3) // lines 10-12 replaced
+	deleted, _ := NewDiffHunk(30, 4, 27, 0)  // lines removed after line 27
+	changes := NewChangeSet(map[string][]DiffHunk{"./pkg/a.go": {modified, deleted}})
+
+	tests := []struct {
+		name     string
+		path     string
+		start    int
+		end      int
+		expected bool
+	}{
+		{name: "Changed line", path: "pkg/a.go", start: 11, end: 11, expected: true},
+		{name: "Range ending in a change", path: "pkg/a.go", start: 1, end: 10, expected: true},
+		{name: "Unchanged line", path: "pkg/a.go", start: 13, end: 13, expected: false},
+		{name: "Deletion inside the range", path: "pkg/a.go", start: 20, end: 28, expected: true},
+		{name: "Deletion after the range", path: "pkg/a.go", start: 20, end: 27, expected: false},
+		{name: "Deletion is not a changed line", path: "pkg/a.go", start: 27, end: 27, expected: false},
+		{name: "Unchanged file", path: "pkg/b.go", start: 1, end: 100, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changes.Overlaps(tt.path, tt.start, tt.end); got != tt.expected {
+				t.Errorf("Overlaps(%s, %d, %d) = %v, expected %v", tt.path, tt.start, tt.end, got, tt.expected)
+			}
+		})
+	}
+
+	if files := changes.Files(); len(files) != 1 || files[0] != "pkg/a.go" {
+		t.Errorf("Expected cleaned path pkg/a.go, got %v", files)
+	}
+}
@@ -0,0 +1,209 @@
+package valueobjects
+
+import (
+	"fmt"
+	"time"
+)
+
+// DiffHunk is a changed line range of a unified diff without context lines. A range with no
+// lines starts after its start line, as in git's "@@ -a,0 +c,d @@" headers
+type DiffHunk struct {
+	oldStart int
+	oldLines int
+	newStart int
+	newLines int
+}
+
+// NewDiffHunk creates a diff hunk from the old and new line ranges of a hunk header
+func NewDiffHunk(oldStart, oldLines, newStart, newLines int) (DiffHunk, error) {
+	if oldStart < 0 || newStart < 0 {
+		return DiffHunk{}, fmt.Errorf("hunk start lines must be >= 0, got -%d +%d", oldStart, newStart)
+	}
+	if oldLines < 0 || newLines < 0 {
+		return DiffHunk{}, fmt.Errorf("hunk line counts must be >= 0, got -%d +%d", oldLines, newLines)
+	}
+
+	return DiffHunk{
+		oldStart: oldStart,
+		oldLines: oldLines,
+		newStart: newStart,
+		newLines: newLines,
+	}, nil
+}
+
+// OldStart returns the first removed line, or the line after which lines were added
+func (h DiffHunk) OldStart() int {
+	return h.oldStart
+}
+
+// OldLines returns the number of removed lines
+func (h DiffHunk) OldLines() int {
+	return h.oldLines
+}
+
+// NewStart returns the first added line, or the line after which lines were removed
+func (h DiffHunk) NewStart() int {
+	return h.newStart
+}
+
+// NewLines returns the number of added lines
+func (h DiffHunk) NewLines() int {
+	return h.newLines
+}
+
+// Touches reports whether the hunk adds lines within start..end or removes lines from strictly
+// inside that range
+func (h DiffHunk) Touches(start, end int) bool {
+	if h.newLines == 0 {
+		return h.newStart >= start && h.newStart < end
+	}
+	return h.newStart <= end && h.newStart+h.newLines-1 >= start
+}
+
+// String returns the hunk header
+func (h DiffHunk) String() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.oldStart, h.oldLines, h.newStart, h.newLines)
+}
+
+// FileRevision is one commit's change to a file
+type FileRevision struct {
+	commit  string
+	author  string
+	time    time.Time
+	hunks   []DiffHunk
+	created bool
+}
+
+// NewFileRevision creates a file revision; hunks are in ascending line order and created
+// marks the commit that added the file
+func NewFileRevision(commit, author string, when time.Time, hunks []DiffHunk, created bool) (FileRevision, error) {
+	if commit == "" {
+		return FileRevision{}, fmt.Errorf("commit cannot be empty")
+	}
+
+	return FileRevision{
+		commit:  commit,
+		author:  author,
+		time:    when,
+		hunks:   append([]DiffHunk(nil), hunks...),
+		created: created,
+	}, nil
+}
+
+// Commit returns the commit hash
+func (r FileRevision) Commit() string {
+	return r.commit
+}
+
+// Author returns the author of the commit
+func (r FileRevision) Author() string {
+	return r.author
+}
+
+// Time returns when the commit was authored
+func (r FileRevision) Time() time.Time {
+	return r.time
+}
+
+// Hunks returns the changed line ranges
+func (r FileRevision) Hunks() []DiffHunk {
+	return append([]DiffHunk(nil), r.hunks...)
+}
+
+// Created reports whether the commit added the file
+func (r FileRevision) Created() bool {
+	return r.created
+}
+
+// HotspotKind distinguishes file and function hotspots
+type HotspotKind int
+
+const (
+	HotspotKindFile HotspotKind = iota
+	HotspotKindFunction
+)
+
+// String returns a string representation of the hotspot kind
+func (k HotspotKind) String() string {
+	switch k {
+	case HotspotKindFile:
+		return "file"
+	case HotspotKindFunction:
+		return "function"
+	default:
+		return "unknown"
+	}
+}
+
+// Hotspot is code that is both complex and frequently changed
+type Hotspot struct {
+	kind       HotspotKind
+	name       string
+	location   SourceLocation
+	revisions  int
+	authors    int
+	complexity int
+}
+
+// NewHotspot creates a hotspot from the change history and cyclomatic complexity of a file or function
+func NewHotspot(kind HotspotKind, name string, location SourceLocation, revisions, authors, complexity int) (Hotspot, error) {
+	if name == "" {
+		return Hotspot{}, fmt.Errorf("hotspot name cannot be empty")
+	}
+	if revisions < 0 || complexity < 0 {
+		return Hotspot{}, fmt.Errorf("revisions and complexity must be >= 0, got %d and %d", revisions, complexity)
+	}
+	if authors < 0 || authors > revisions {
+		return Hotspot{}, fmt.Errorf("authors must be between 0 and %d, got %d", revisions, authors)
+	}
+
+	return Hotspot{
+		kind:       kind,
+		name:       name,
+		location:   location,
+		revisions:  revisions,
+		authors:    authors,
+		complexity: complexity,
+	}, nil
+}
+
+// Kind returns whether the hotspot is a file or a function
+func (h Hotspot) Kind() HotspotKind {
+	return h.kind
+}
+
+// Name returns the file path or function name
+func (h Hotspot) Name() string {
+	return h.name
+}
+
+// Location returns where the file or function starts
+func (h Hotspot) Location() SourceLocation {
+	return h.location
+}
+
+// Revisions returns the number of commits that changed the code in the time window
+func (h Hotspot) Revisions() int {
+	return h.revisions
+}
+
+// Authors returns the number of distinct authors of those commits
+func (h Hotspot) Authors() int {
+	return h.authors
+}
+
+// Complexity returns the cyclomatic complexity, summed over its functions for a file
+func (h Hotspot) Complexity() int {
+	return h.complexity
+}
+
+// Score returns revisions times complexity, the ranking key of hotspots
+func (h Hotspot) Score() int {
+	return h.revisions * h.complexity
+}
+
+// String returns a human-readable representation
+func (h Hotspot) String() string {
+	return fmt.Sprintf("%s %s: score=%d (revisions=%d, authors=%d, complexity=%d)",
+		h.kind, h.name, h.Score(), h.revisions, h.authors, h.complexity)
+}
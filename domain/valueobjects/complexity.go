@@ -1,11 +1,19 @@
 package valueobjects
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
+
+// MaxNPath is the value at which NPath complexity saturates instead of overflowing
+const MaxNPath = math.MaxInt64
 
 // ComplexityScore represents calculated complexity metrics for a code construct
 type ComplexityScore struct {
 	cyclomatic int
 	cognitive  int
+	npath      int64
+	essential  int
 }
 
 // NewComplexityScore creates a new complexity score
@@ -20,9 +28,25 @@ func NewComplexityScore(cyclomatic, cognitive int) (ComplexityScore, error) {
 	return ComplexityScore{
 		cyclomatic: cyclomatic,
 		cognitive:  cognitive,
+		npath:      1,
+		essential:  1,
 	}, nil
 }
 
+// WithPathComplexity returns a copy of the score carrying NPath and essential complexity
+func (c ComplexityScore) WithPathComplexity(npath int64, essential int) (ComplexityScore, error) {
+	if npath < 1 {
+		return ComplexityScore{}, fmt.Errorf("npath complexity must be >= 1, got %d", npath)
+	}
+	if essential < 1 {
+		return ComplexityScore{}, fmt.Errorf("essential complexity must be >= 1, got %d", essential)
+	}
+
+	c.npath = npath
+	c.essential = essential
+	return c, nil
+}
+
 // Cyclomatic returns the cyclomatic complexity score
 func (c ComplexityScore) Cyclomatic() int {
 	return c.cyclomatic
@@ -33,6 +57,21 @@ func (c ComplexityScore) Cognitive() int {
 	return c.cognitive
 }
 
+// NPath returns the number of acyclic execution paths, saturated at MaxNPath
+func (c ComplexityScore) NPath() int64 {
+	return c.npath
+}
+
+// IsNPathSaturated reports whether the NPath count exceeded what can be represented
+func (c ComplexityScore) IsNPathSaturated() bool {
+	return c.npath == MaxNPath
+}
+
+// Essential returns the essential complexity, the complexity left after reducing structured constructs
+func (c ComplexityScore) Essential() int {
+	return c.essential
+}
+
 // IsHighComplexity checks if either metric exceeds Go-adjusted thresholds
 func (c ComplexityScore) IsHighComplexity() bool {
 	return c.cyclomatic > 15 || c.cognitive > 20
@@ -90,3 +90,43 @@ func TestComplexityScore_Equals(t *testing.T) {
 		t.Error("expected different scores to not be equal")
 	}
 }
+
+func TestComplexityScore_WithPathComplexity(t *testing.T) {
+	tests := []struct {
+		name        string
+		npath       int64
+		essential   int
+		expectError bool
+	}{
+		{"valid values", 240, 3, false},
+		{"saturated npath", MaxNPath, 1, false},
+		{"zero npath", 0, 1, true},
+		{"zero essential", 10, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, _ := NewComplexityScore(5, 8)
+			score, err := base.WithPathComplexity(tt.npath, tt.essential)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if score.NPath() != tt.npath || score.Essential() != tt.essential {
+				t.Errorf("expected npath=%d essential=%d, got npath=%d essential=%d", tt.npath, tt.essential, score.NPath(), score.Essential())
+			}
+			if score.IsNPathSaturated() != (tt.npath == MaxNPath) {
+				t.Errorf("unexpected IsNPathSaturated() = %v", score.IsNPathSaturated())
+			}
+			if !score.Equals(base) {
+				t.Errorf("expected cyclomatic and cognitive values to be preserved")
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package valueobjects
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // AnalysisConfiguration defines the parameters for code analysis
 type AnalysisConfiguration struct {
@@ -9,6 +12,14 @@ type AnalysisConfiguration struct {
 	maxFunctionLength       int
 	enableSmellDetection    bool
 	severityThreshold       SeverityLevel
+	taint                   TaintConfiguration
+	minCloneTokens          int
+	maxHalsteadVolume       int
+	maxHalsteadEffort       int
+	minMaintainability      int
+	maxNPath                int
+	maxEssential            int
+	maxStructFields         int
 }
 
 // SeverityLevel represents the severity of detected issues
@@ -45,6 +56,14 @@ func DefaultAnalysisConfiguration() AnalysisConfiguration {
 		maxFunctionLength:       80,
 		enableSmellDetection:    true,
 		severityThreshold:       SeverityWarning,
+		taint:                   DefaultTaintConfiguration(),
+		minCloneTokens:          50,
+		maxHalsteadVolume:       3000,
+		maxHalsteadEffort:       150000,
+		minMaintainability:      20,
+		maxNPath:                200,
+		maxEssential:            4,
+		maxStructFields:         10,
 	}
 }
 
@@ -66,6 +85,14 @@ func NewAnalysisConfiguration(maxCyclomatic, maxCognitive, maxLength int, enable
 		maxFunctionLength:       maxLength,
 		enableSmellDetection:    enableSmells,
 		severityThreshold:       severity,
+		taint:                   DefaultTaintConfiguration(),
+		minCloneTokens:          50,
+		maxHalsteadVolume:       3000,
+		maxHalsteadEffort:       150000,
+		minMaintainability:      20,
+		maxNPath:                200,
+		maxEssential:            4,
+		maxStructFields:         10,
 	}, nil
 }
 
@@ -77,6 +104,14 @@ func (c AnalysisConfiguration) WithMaxCyclomaticComplexity(max int) AnalysisConf
 		maxFunctionLength:       c.maxFunctionLength,
 		enableSmellDetection:    c.enableSmellDetection,
 		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                c.maxNPath,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         c.maxStructFields,
 	}
 }
 
@@ -88,6 +123,14 @@ func (c AnalysisConfiguration) WithMaxCognitiveComplexity(max int) AnalysisConfi
 		maxFunctionLength:       c.maxFunctionLength,
 		enableSmellDetection:    c.enableSmellDetection,
 		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                c.maxNPath,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         c.maxStructFields,
 	}
 }
 
@@ -99,6 +142,14 @@ func (c AnalysisConfiguration) WithMaxFunctionLength(max int) AnalysisConfigurat
 		maxFunctionLength:       max,
 		enableSmellDetection:    c.enableSmellDetection,
 		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                c.maxNPath,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         c.maxStructFields,
 	}
 }
 
@@ -110,6 +161,166 @@ func (c AnalysisConfiguration) WithSmellDetection(enabled bool) AnalysisConfigur
 		maxFunctionLength:       c.maxFunctionLength,
 		enableSmellDetection:    enabled,
 		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                c.maxNPath,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         c.maxStructFields,
+	}
+}
+
+// WithTaintConfiguration returns a new configuration with updated taint sources, sanitizers and sinks
+func (c AnalysisConfiguration) WithTaintConfiguration(taint TaintConfiguration) AnalysisConfiguration {
+	return AnalysisConfiguration{
+		maxCyclomaticComplexity: c.maxCyclomaticComplexity,
+		maxCognitiveComplexity:  c.maxCognitiveComplexity,
+		maxFunctionLength:       c.maxFunctionLength,
+		enableSmellDetection:    c.enableSmellDetection,
+		severityThreshold:       c.severityThreshold,
+		taint:                   taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                c.maxNPath,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         c.maxStructFields,
+	}
+}
+
+// WithMinCloneTokens returns a new configuration with updated minimum clone size
+func (c AnalysisConfiguration) WithMinCloneTokens(min int) AnalysisConfiguration {
+	return AnalysisConfiguration{
+		maxCyclomaticComplexity: c.maxCyclomaticComplexity,
+		maxCognitiveComplexity:  c.maxCognitiveComplexity,
+		maxFunctionLength:       c.maxFunctionLength,
+		enableSmellDetection:    c.enableSmellDetection,
+		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          min,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                c.maxNPath,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         c.maxStructFields,
+	}
+}
+
+// WithMaxHalsteadVolume returns a new configuration with updated Halstead volume threshold; 0 disables it
+func (c AnalysisConfiguration) WithMaxHalsteadVolume(max int) AnalysisConfiguration {
+	return AnalysisConfiguration{
+		maxCyclomaticComplexity: c.maxCyclomaticComplexity,
+		maxCognitiveComplexity:  c.maxCognitiveComplexity,
+		maxFunctionLength:       c.maxFunctionLength,
+		enableSmellDetection:    c.enableSmellDetection,
+		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       max,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                c.maxNPath,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         c.maxStructFields,
+	}
+}
+
+// WithMaxHalsteadEffort returns a new configuration with updated Halstead effort threshold; 0 disables it
+func (c AnalysisConfiguration) WithMaxHalsteadEffort(max int) AnalysisConfiguration {
+	return AnalysisConfiguration{
+		maxCyclomaticComplexity: c.maxCyclomaticComplexity,
+		maxCognitiveComplexity:  c.maxCognitiveComplexity,
+		maxFunctionLength:       c.maxFunctionLength,
+		enableSmellDetection:    c.enableSmellDetection,
+		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       max,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                c.maxNPath,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         c.maxStructFields,
+	}
+}
+
+// WithMinMaintainabilityIndex returns a new configuration with updated maintainability index threshold; 0 disables it
+func (c AnalysisConfiguration) WithMinMaintainabilityIndex(min int) AnalysisConfiguration {
+	return AnalysisConfiguration{
+		maxCyclomaticComplexity: c.maxCyclomaticComplexity,
+		maxCognitiveComplexity:  c.maxCognitiveComplexity,
+		maxFunctionLength:       c.maxFunctionLength,
+		enableSmellDetection:    c.enableSmellDetection,
+		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      min,
+		maxNPath:                c.maxNPath,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         c.maxStructFields,
+	}
+}
+
+// WithMaxNPath returns a new configuration with updated NPath complexity threshold; 0 disables it
+func (c AnalysisConfiguration) WithMaxNPath(max int) AnalysisConfiguration {
+	return AnalysisConfiguration{
+		maxCyclomaticComplexity: c.maxCyclomaticComplexity,
+		maxCognitiveComplexity:  c.maxCognitiveComplexity,
+		maxFunctionLength:       c.maxFunctionLength,
+		enableSmellDetection:    c.enableSmellDetection,
+		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                max,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         c.maxStructFields,
+	}
+}
+
+// WithMaxEssentialComplexity returns a new configuration with updated essential complexity threshold; 0 disables it
+func (c AnalysisConfiguration) WithMaxEssentialComplexity(max int) AnalysisConfiguration {
+	return AnalysisConfiguration{
+		maxCyclomaticComplexity: c.maxCyclomaticComplexity,
+		maxCognitiveComplexity:  c.maxCognitiveComplexity,
+		maxFunctionLength:       c.maxFunctionLength,
+		enableSmellDetection:    c.enableSmellDetection,
+		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                c.maxNPath,
+		maxEssential:            max,
+		maxStructFields:         c.maxStructFields,
+	}
+}
+
+// WithMaxStructFields returns a new configuration with updated struct field count threshold
+func (c AnalysisConfiguration) WithMaxStructFields(max int) AnalysisConfiguration {
+	return AnalysisConfiguration{
+		maxCyclomaticComplexity: c.maxCyclomaticComplexity,
+		maxCognitiveComplexity:  c.maxCognitiveComplexity,
+		maxFunctionLength:       c.maxFunctionLength,
+		enableSmellDetection:    c.enableSmellDetection,
+		severityThreshold:       c.severityThreshold,
+		taint:                   c.taint,
+		minCloneTokens:          c.minCloneTokens,
+		maxHalsteadVolume:       c.maxHalsteadVolume,
+		maxHalsteadEffort:       c.maxHalsteadEffort,
+		minMaintainability:      c.minMaintainability,
+		maxNPath:                c.maxNPath,
+		maxEssential:            c.maxEssential,
+		maxStructFields:         max,
 	}
 }
 
@@ -137,3 +348,106 @@ func (c AnalysisConfiguration) IsSmellDetectionEnabled() bool {
 func (c AnalysisConfiguration) SeverityThreshold() SeverityLevel {
 	return c.severityThreshold
 }
+
+// TaintConfiguration returns the sources, sanitizers and sinks used for taint tracking
+func (c AnalysisConfiguration) TaintConfiguration() TaintConfiguration {
+	return c.taint
+}
+
+// MinCloneTokens returns the minimum size in tokens of a reported duplicate code block
+func (c AnalysisConfiguration) MinCloneTokens() int {
+	return c.minCloneTokens
+}
+
+// MaxHalsteadVolume returns the maximum allowed Halstead volume of a function, or 0 if unchecked
+func (c AnalysisConfiguration) MaxHalsteadVolume() int {
+	return c.maxHalsteadVolume
+}
+
+// MaxHalsteadEffort returns the maximum allowed Halstead effort of a function, or 0 if unchecked
+func (c AnalysisConfiguration) MaxHalsteadEffort() int {
+	return c.maxHalsteadEffort
+}
+
+// MinMaintainabilityIndex returns the lowest acceptable maintainability index (0-100) of a function, or 0 if unchecked
+func (c AnalysisConfiguration) MinMaintainabilityIndex() int {
+	return c.minMaintainability
+}
+
+// MaxNPath returns the maximum allowed NPath complexity of a function, or 0 if unchecked
+func (c AnalysisConfiguration) MaxNPath() int {
+	return c.maxNPath
+}
+
+// MaxEssentialComplexity returns the maximum allowed essential complexity of a function, or 0 if unchecked
+func (c AnalysisConfiguration) MaxEssentialComplexity() int {
+	return c.maxEssential
+}
+
+// MaxStructFields returns the number of fields above which a struct is reported as a god struct
+func (c AnalysisConfiguration) MaxStructFields() int {
+	return c.maxStructFields
+}
+
+// configurationFingerprint is the stored form of every setting that can change findings; a new
+// setting must be added here, or results computed before it existed would be reused
+type configurationFingerprint struct {
+	MaxCyclomatic      int              `json:"max_cyclomatic"`
+	MaxCognitive       int              `json:"max_cognitive"`
+	MaxFunctionLength  int              `json:"max_function_length"`
+	SmellDetection     bool             `json:"smell_detection"`
+	SeverityThreshold  int              `json:"severity_threshold"`
+	Taint              taintFingerprint `json:"taint"`
+	MinCloneTokens     int              `json:"min_clone_tokens"`
+	MaxHalsteadVolume  int              `json:"max_halstead_volume"`
+	MaxHalsteadEffort  int              `json:"max_halstead_effort"`
+	MinMaintainability int              `json:"min_maintainability"`
+	MaxNPath           int              `json:"max_npath"`
+	MaxEssential       int              `json:"max_essential"`
+	MaxStructFields    int              `json:"max_struct_fields"`
+}
+
+// taintFingerprint is the stored form of the taint rules
+type taintFingerprint struct {
+	Sources    [][2]string       `json:"sources"`
+	Sanitizers []string          `json:"sanitizers"`
+	Sinks      []sinkFingerprint `json:"sinks"`
+}
+
+// sinkFingerprint is the stored form of a taint sink
+type sinkFingerprint struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Args []int  `json:"args"`
+}
+
+// Fingerprint returns a text that differs between any two configurations that can produce
+// different findings, so results computed under one configuration can be recognized later. It is
+// the JSON encoding of each setting, which does not depend on how the struct happens to print
+func (c AnalysisConfiguration) Fingerprint() string {
+	fingerprint := configurationFingerprint{
+		MaxCyclomatic:      c.maxCyclomaticComplexity,
+		MaxCognitive:       c.maxCognitiveComplexity,
+		MaxFunctionLength:  c.maxFunctionLength,
+		SmellDetection:     c.enableSmellDetection,
+		SeverityThreshold:  int(c.severityThreshold),
+		Taint:              taintFingerprint{Sanitizers: c.taint.Sanitizers()},
+		MinCloneTokens:     c.minCloneTokens,
+		MaxHalsteadVolume:  c.maxHalsteadVolume,
+		MaxHalsteadEffort:  c.maxHalsteadEffort,
+		MinMaintainability: c.minMaintainability,
+		MaxNPath:           c.maxNPath,
+		MaxEssential:       c.maxEssential,
+		MaxStructFields:    c.maxStructFields,
+	}
+	for _, source := range c.taint.Sources() {
+		fingerprint.Taint.Sources = append(fingerprint.Taint.Sources, [2]string{source.Name(), source.Kind()})
+	}
+	for _, sink := range c.taint.Sinks() {
+		fingerprint.Taint.Sinks = append(fingerprint.Taint.Sinks, sinkFingerprint{Name: sink.Name(), Kind: sink.Kind(), Args: sink.Args()})
+	}
+
+	// Only strings, numbers and slices of them are encoded, which cannot fail
+	data, _ := json.Marshal(fingerprint)
+	return string(data)
+}
@@ -0,0 +1,72 @@
+package valueobjects
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalysisConfiguration_Fingerprint(t *testing.T) {
+	base := DefaultAnalysisConfiguration()
+	sink, _ := NewTaintSink("db.Query", "sql", []int{0})
+
+	tests := []struct {
+		name     string
+		config   AnalysisConfiguration
+		expected bool
+	}{
+		{"same thresholds", DefaultAnalysisConfiguration(), true},
+		{"threshold set to its current value", base.WithMaxNPath(base.MaxNPath()), true},
+		{"different cyclomatic threshold", base.WithMaxCyclomaticComplexity(10), false},
+		{"smell detection disabled", base.WithSmellDetection(false), false},
+		{"different clone size", base.WithMinCloneTokens(80), false},
+		{"different struct field threshold", base.WithMaxStructFields(15), false},
+		{"different taint sinks", base.WithTaintConfiguration(NewTaintConfiguration(nil, nil, []TaintSink{sink})), false},
+		{"same custom taint rules", base.WithTaintConfiguration(base.TaintConfiguration()), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			same := tt.config.Fingerprint() == base.Fingerprint()
+			if same != tt.expected {
+				t.Errorf("expected equal fingerprints to be %v, got %q and %q", tt.expected, tt.config.Fingerprint(), base.Fingerprint())
+			}
+		})
+	}
+}
+
+func TestAnalysisConfiguration_FingerprintTaintRules(t *testing.T) {
+	sink, _ := NewTaintSink("db.Query", "sql", []int{0})
+	otherArgs, _ := NewTaintSink("db.Query", "sql", []int{1})
+	source, _ := NewTaintSource("os.Getenv", "env")
+	otherKind, _ := NewTaintSource("os.Getenv", "request")
+	base := DefaultAnalysisConfiguration().WithTaintConfiguration(NewTaintConfiguration([]TaintSource{source}, []string{"html.EscapeString"}, []TaintSink{sink}))
+
+	tests := []struct {
+		name     string
+		taint    TaintConfiguration
+		expected bool
+	}{
+		{"same rules", NewTaintConfiguration([]TaintSource{source}, []string{"html.EscapeString"}, []TaintSink{sink}), true},
+		{"different source kind", NewTaintConfiguration([]TaintSource{otherKind}, []string{"html.EscapeString"}, []TaintSink{sink}), false},
+		{"different sanitizer", NewTaintConfiguration([]TaintSource{source}, []string{"url.QueryEscape"}, []TaintSink{sink}), false},
+		{"different sink arguments", NewTaintConfiguration([]TaintSource{source}, []string{"html.EscapeString"}, []TaintSink{otherArgs}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultAnalysisConfiguration().WithTaintConfiguration(tt.taint)
+			same := config.Fingerprint() == base.Fingerprint()
+			if same != tt.expected {
+				t.Errorf("expected equal fingerprints to be %v, got %q and %q", tt.expected, config.Fingerprint(), base.Fingerprint())
+			}
+		})
+	}
+}
+
+func TestAnalysisConfiguration_FingerprintCoversEverySetting(t *testing.T) {
+	// A setting missing from the fingerprint would let results computed under another value be reused
+	settings := reflect.TypeOf(AnalysisConfiguration{}).NumField()
+	if fingerprinted := reflect.TypeOf(configurationFingerprint{}).NumField(); fingerprinted != settings {
+		t.Errorf("expected the fingerprint to cover all %d settings, got %d", settings, fingerprinted)
+	}
+}
@@ -0,0 +1,58 @@
+package valueobjects
+
+import "fmt"
+
+// PackageDuplication records how much of a package's code is part of a reported clone
+type PackageDuplication struct {
+	pkg              string
+	totalTokens      int
+	duplicatedTokens int
+}
+
+// NewPackageDuplication creates a duplication measurement for a package
+func NewPackageDuplication(pkg string, totalTokens, duplicatedTokens int) (PackageDuplication, error) {
+	if pkg == "" {
+		return PackageDuplication{}, fmt.Errorf("package cannot be empty")
+	}
+	if totalTokens < 0 {
+		return PackageDuplication{}, fmt.Errorf("total tokens must be >= 0, got %d", totalTokens)
+	}
+	if duplicatedTokens < 0 || duplicatedTokens > totalTokens {
+		return PackageDuplication{}, fmt.Errorf("duplicated tokens must be between 0 and %d, got %d", totalTokens, duplicatedTokens)
+	}
+
+	return PackageDuplication{
+		pkg:              pkg,
+		totalTokens:      totalTokens,
+		duplicatedTokens: duplicatedTokens,
+	}, nil
+}
+
+// Package returns the name of the measured package, followed by its directory when several
+// measured packages share the name
+func (d PackageDuplication) Package() string {
+	return d.pkg
+}
+
+// TotalTokens returns the number of tokens in the package
+func (d PackageDuplication) TotalTokens() int {
+	return d.totalTokens
+}
+
+// DuplicatedTokens returns the number of tokens covered by clones
+func (d PackageDuplication) DuplicatedTokens() int {
+	return d.duplicatedTokens
+}
+
+// Percentage returns the share of duplicated tokens from 0 to 100
+func (d PackageDuplication) Percentage() float64 {
+	if d.totalTokens == 0 {
+		return 0
+	}
+	return float64(d.duplicatedTokens) * 100 / float64(d.totalTokens)
+}
+
+// String returns a human-readable representation
+func (d PackageDuplication) String() string {
+	return fmt.Sprintf("%s %.1f%%", d.pkg, d.Percentage())
+}
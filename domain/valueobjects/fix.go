@@ -0,0 +1,122 @@
+package valueobjects
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// TextEdit replaces the bytes [offset, end) of a file with new text; an empty range inserts
+type TextEdit struct {
+	filePath string
+	offset   int
+	end      int
+	newText  string
+}
+
+// NewTextEdit creates an edit of the byte range [offset, end) of a file
+func NewTextEdit(filePath string, offset, end int, newText string) (TextEdit, error) {
+	if filePath == "" {
+		return TextEdit{}, fmt.Errorf("file path cannot be empty")
+	}
+	if offset < 0 {
+		return TextEdit{}, fmt.Errorf("offset must be >= 0, got %d", offset)
+	}
+	if end < offset {
+		return TextEdit{}, fmt.Errorf("end %d cannot precede offset %d", end, offset)
+	}
+
+	return TextEdit{
+		filePath: filepath.Clean(filePath),
+		offset:   offset,
+		end:      end,
+		newText:  newText,
+	}, nil
+}
+
+// FilePath returns the edited file
+func (e TextEdit) FilePath() string {
+	return e.filePath
+}
+
+// Offset returns the byte offset where the replaced range starts
+func (e TextEdit) Offset() int {
+	return e.offset
+}
+
+// End returns the byte offset just after the replaced range
+func (e TextEdit) End() int {
+	return e.end
+}
+
+// NewText returns the replacement text
+func (e TextEdit) NewText() string {
+	return e.newText
+}
+
+// Overlaps reports whether two edits of the same file touch the same bytes. Two insertions at the
+// same offset overlap too, since their order would be ambiguous
+func (e TextEdit) Overlaps(other TextEdit) bool {
+	if e.filePath != other.filePath {
+		return false
+	}
+	if e.offset == other.offset {
+		return true
+	}
+	return e.offset < other.end && other.offset < e.end
+}
+
+// String returns the edited range and the replacement
+func (e TextEdit) String() string {
+	return fmt.Sprintf("%s:#%d-#%d: %q", e.filePath, e.offset, e.end, e.newText)
+}
+
+// SuggestedFix is a set of edits that together resolve a finding
+type SuggestedFix struct {
+	description string
+	edits       []TextEdit
+}
+
+// NewSuggestedFix creates a fix from non-overlapping edits, which are kept in file and offset order
+func NewSuggestedFix(description string, edits ...TextEdit) (SuggestedFix, error) {
+	if description == "" {
+		return SuggestedFix{}, fmt.Errorf("fix description cannot be empty")
+	}
+	if len(edits) == 0 {
+		return SuggestedFix{}, fmt.Errorf("fix must have at least one edit")
+	}
+
+	sorted := SortTextEdits(edits)
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i-1].Overlaps(sorted[i]) {
+			return SuggestedFix{}, fmt.Errorf("edits %s and %s overlap", sorted[i-1], sorted[i])
+		}
+	}
+
+	return SuggestedFix{description: description, edits: sorted}, nil
+}
+
+// Description returns what the fix does
+func (f SuggestedFix) Description() string {
+	return f.description
+}
+
+// Edits returns the edits of the fix in file and offset order
+func (f SuggestedFix) Edits() []TextEdit {
+	return append([]TextEdit(nil), f.edits...)
+}
+
+// SortTextEdits returns a copy of edits ordered by file and offset
+func SortTextEdits(edits []TextEdit) []TextEdit {
+	sorted := append([]TextEdit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].filePath != sorted[j].filePath {
+			return sorted[i].filePath < sorted[j].filePath
+		}
+		if sorted[i].offset != sorted[j].offset {
+			return sorted[i].offset < sorted[j].offset
+		}
+		return sorted[i].end < sorted[j].end
+	})
+	return sorted
+}
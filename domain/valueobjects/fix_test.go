@@ -0,0 +1,83 @@
+package valueobjects
+
+import "testing"
+
+func TestNewTextEdit(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		offset  int
+		end     int
+		wantErr bool
+	}{
+		{name: "Replacement", path: "main.go", offset: 3, end: 5},
+		{name: "Insertion", path: "main.go", offset: 3, end: 3},
+		{name: "Empty path", path: "", offset: 0, end: 0, wantErr: true},
+		{name: "Negative offset", path: "main.go", offset: -1, end: 0, wantErr: true},
+		{name: "End before offset", path: "main.go", offset: 5, end: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTextEdit(tt.path, tt.offset, tt.end, "x")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewTextEdit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTextEdit_Overlaps(t *testing.T) {
+	edit, _ := NewTextEdit("./pkg/a.go", 10, 20, "x")
+
+	tests := []struct {
+		name     string
+		path     string
+		offset   int
+		end      int
+		expected bool
+	}{
+		{name: "Intersecting range", path: "pkg/a.go", offset: 15, end: 25, expected: true},
+		{name: "Contained range", path: "pkg/a.go", offset: 12, end: 14, expected: true},
+		{name: "Insertion at the start", path: "pkg/a.go", offset: 10, end: 10, expected: true},
+		{name: "Adjacent range", path: "pkg/a.go", offset: 20, end: 30, expected: false},
+		{name: "Range before", path: "pkg/a.go", offset: 0, end: 10, expected: false},
+		{name: "Other file", path: "pkg/b.go", offset: 10, end: 20, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other, _ := NewTextEdit(tt.path, tt.offset, tt.end, "y")
+			if got := edit.Overlaps(other); got != tt.expected {
+				t.Errorf("Overlaps(%s) = %v, expected %v", other, got, tt.expected)
+			}
+			if got := other.Overlaps(edit); got != tt.expected {
+				t.Errorf("Overlaps is not symmetric for %s", other)
+			}
+		})
+	}
+}
+
+func TestNewSuggestedFix(t *testing.T) {
+	first, _ := NewTextEdit("main.go", 30, 31, "w")
+	second, _ := NewTextEdit("main.go", 5, 5, ", 1")
+	overlapping, _ := NewTextEdit("main.go", 30, 30, "x")
+
+	fix, err := NewSuggestedFix("wrap the error with %w", first, second)
+	if err != nil {
+		t.Fatalf("NewSuggestedFix failed: %v", err)
+	}
+	if edits := fix.Edits(); len(edits) != 2 || edits[0].Offset() != 5 || edits[1].Offset() != 30 {
+		t.Errorf("Expected edits in offset order, got %v", edits)
+	}
+
+	if _, err := NewSuggestedFix("", first); err == nil {
+		t.Error("Expected an empty description to be rejected")
+	}
+	if _, err := NewSuggestedFix("no edits"); err == nil {
+		t.Error("Expected a fix without edits to be rejected")
+	}
+	if _, err := NewSuggestedFix("overlap", first, overlapping); err == nil {
+		t.Error("Expected overlapping edits to be rejected")
+	}
+}
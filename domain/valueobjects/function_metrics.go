@@ -0,0 +1,200 @@
+package valueobjects
+
+import (
+	"fmt"
+	"math"
+)
+
+// HalsteadMetrics holds the operator and operand counts of a code construct and the
+// Halstead measures derived from them
+type HalsteadMetrics struct {
+	distinctOperators int
+	distinctOperands  int
+	totalOperators    int
+	totalOperands     int
+}
+
+// NewHalsteadMetrics creates Halstead metrics from distinct (n1, n2) and total (N1, N2) counts
+func NewHalsteadMetrics(distinctOperators, distinctOperands, totalOperators, totalOperands int) (HalsteadMetrics, error) {
+	if distinctOperators < 0 || distinctOperands < 0 || totalOperators < 0 || totalOperands < 0 {
+		return HalsteadMetrics{}, fmt.Errorf("halstead counts must be >= 0")
+	}
+	if distinctOperators > totalOperators || distinctOperands > totalOperands {
+		return HalsteadMetrics{}, fmt.Errorf("distinct halstead counts cannot exceed totals")
+	}
+
+	return HalsteadMetrics{
+		distinctOperators: distinctOperators,
+		distinctOperands:  distinctOperands,
+		totalOperators:    totalOperators,
+		totalOperands:     totalOperands,
+	}, nil
+}
+
+// DistinctOperators returns the number of distinct operators (n1)
+func (h HalsteadMetrics) DistinctOperators() int {
+	return h.distinctOperators
+}
+
+// DistinctOperands returns the number of distinct operands (n2)
+func (h HalsteadMetrics) DistinctOperands() int {
+	return h.distinctOperands
+}
+
+// TotalOperators returns the total number of operators (N1)
+func (h HalsteadMetrics) TotalOperators() int {
+	return h.totalOperators
+}
+
+// TotalOperands returns the total number of operands (N2)
+func (h HalsteadMetrics) TotalOperands() int {
+	return h.totalOperands
+}
+
+// Vocabulary returns the program vocabulary n = n1 + n2
+func (h HalsteadMetrics) Vocabulary() int {
+	return h.distinctOperators + h.distinctOperands
+}
+
+// Length returns the program length N = N1 + N2
+func (h HalsteadMetrics) Length() int {
+	return h.totalOperators + h.totalOperands
+}
+
+// Volume returns the program volume V = N * log2(n)
+func (h HalsteadMetrics) Volume() float64 {
+	if h.Vocabulary() < 2 {
+		return 0
+	}
+	return float64(h.Length()) * math.Log2(float64(h.Vocabulary()))
+}
+
+// Difficulty returns the difficulty D = (n1 / 2) * (N2 / n2)
+func (h HalsteadMetrics) Difficulty() float64 {
+	if h.distinctOperands == 0 {
+		return 0
+	}
+	return float64(h.distinctOperators) / 2 * float64(h.totalOperands) / float64(h.distinctOperands)
+}
+
+// Effort returns the effort E = D * V
+func (h HalsteadMetrics) Effort() float64 {
+	return h.Difficulty() * h.Volume()
+}
+
+// String returns a human-readable representation
+func (h HalsteadMetrics) String() string {
+	return fmt.Sprintf("volume=%.1f, difficulty=%.1f, effort=%.0f", h.Volume(), h.Difficulty(), h.Effort())
+}
+
+// LinesOfCode counts the lines of a code construct
+type LinesOfCode struct {
+	physical int
+	logical  int
+	comment  int
+}
+
+// NewLinesOfCode creates line counts; physical lines span the construct, logical lines count
+// statements and comment lines count lines holding a comment
+func NewLinesOfCode(physical, logical, comment int) (LinesOfCode, error) {
+	if physical < 1 {
+		return LinesOfCode{}, fmt.Errorf("physical lines must be >= 1, got %d", physical)
+	}
+	if logical < 0 {
+		return LinesOfCode{}, fmt.Errorf("logical lines must be >= 0, got %d", logical)
+	}
+	if comment < 0 {
+		return LinesOfCode{}, fmt.Errorf("comment lines must be >= 0, got %d", comment)
+	}
+
+	return LinesOfCode{
+		physical: physical,
+		logical:  logical,
+		comment:  comment,
+	}, nil
+}
+
+// Physical returns the number of source lines spanned
+func (l LinesOfCode) Physical() int {
+	return l.physical
+}
+
+// Logical returns the number of statements
+func (l LinesOfCode) Logical() int {
+	return l.logical
+}
+
+// Comment returns the number of lines holding a comment
+func (l LinesOfCode) Comment() int {
+	return l.comment
+}
+
+// String returns a human-readable representation
+func (l LinesOfCode) String() string {
+	return fmt.Sprintf("physical=%d, logical=%d, comment=%d", l.physical, l.logical, l.comment)
+}
+
+// FunctionMetrics combines the complexity, Halstead and size measures of one function
+type FunctionMetrics struct {
+	name       string
+	location   SourceLocation
+	complexity ComplexityScore
+	halstead   HalsteadMetrics
+	lines      LinesOfCode
+}
+
+// NewFunctionMetrics creates the metrics of a function; methods are named "Type.Method"
+func NewFunctionMetrics(name string, location SourceLocation, complexity ComplexityScore, halstead HalsteadMetrics, lines LinesOfCode) (FunctionMetrics, error) {
+	if name == "" {
+		return FunctionMetrics{}, fmt.Errorf("function name cannot be empty")
+	}
+
+	return FunctionMetrics{
+		name:       name,
+		location:   location,
+		complexity: complexity,
+		halstead:   halstead,
+		lines:      lines,
+	}, nil
+}
+
+// Name returns the function name
+func (m FunctionMetrics) Name() string {
+	return m.name
+}
+
+// Location returns where the function is declared
+func (m FunctionMetrics) Location() SourceLocation {
+	return m.location
+}
+
+// Complexity returns the cyclomatic and cognitive complexity
+func (m FunctionMetrics) Complexity() ComplexityScore {
+	return m.complexity
+}
+
+// Halstead returns the Halstead metrics
+func (m FunctionMetrics) Halstead() HalsteadMetrics {
+	return m.halstead
+}
+
+// Lines returns the line counts
+func (m FunctionMetrics) Lines() LinesOfCode {
+	return m.lines
+}
+
+// MaintainabilityIndex returns the maintainability index normalized to 0-100, as popularized
+// by Visual Studio: 171 - 5.2 ln(V) - 0.23 CC - 16.2 ln(LOC), scaled by 100/171
+func (m FunctionMetrics) MaintainabilityIndex() float64 {
+	index := 171 - 0.23*float64(m.complexity.Cyclomatic()) - 16.2*math.Log(float64(m.lines.Physical()))
+	if volume := m.halstead.Volume(); volume > 1 {
+		index -= 5.2 * math.Log(volume)
+	}
+	return math.Max(0, math.Min(100, index*100/171))
+}
+
+// String returns a human-readable representation
+func (m FunctionMetrics) String() string {
+	return fmt.Sprintf("%s: %s, %s, loc=%d, mi=%.1f",
+		m.name, m.complexity.String(), m.halstead.String(), m.lines.Physical(), m.MaintainabilityIndex())
+}
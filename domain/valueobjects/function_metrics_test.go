@@ -0,0 +1,113 @@
+package valueobjects
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewHalsteadMetrics(t *testing.T) {
+	tests := []struct {
+		name               string
+		n1, n2, N1, N2     int
+		expectError        bool
+		expectedVolume     float64
+		expectedDifficulty float64
+	}{
+		{"valid counts", 4, 4, 8, 8, false, 48, 4},
+		{"empty function", 0, 0, 0, 0, false, 0, 0},
+		{"negative count", -1, 2, 3, 4, true, 0, 0},
+		{"distinct exceeds total", 5, 2, 3, 4, true, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := NewHalsteadMetrics(tt.n1, tt.n2, tt.N1, tt.N2)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if metrics.Volume() != tt.expectedVolume {
+				t.Errorf("expected volume %.1f, got %.1f", tt.expectedVolume, metrics.Volume())
+			}
+			if metrics.Difficulty() != tt.expectedDifficulty {
+				t.Errorf("expected difficulty %.1f, got %.1f", tt.expectedDifficulty, metrics.Difficulty())
+			}
+			if metrics.Effort() != tt.expectedVolume*tt.expectedDifficulty {
+				t.Errorf("expected effort %.1f, got %.1f", tt.expectedVolume*tt.expectedDifficulty, metrics.Effort())
+			}
+		})
+	}
+}
+
+func TestNewLinesOfCode(t *testing.T) {
+	tests := []struct {
+		name                       string
+		physical, logical, comment int
+		expectError                bool
+	}{
+		{"valid counts", 10, 6, 2, false},
+		{"single line", 1, 0, 0, false},
+		{"zero physical", 0, 0, 0, true},
+		{"negative logical", 5, -1, 0, true},
+		{"negative comment", 5, 2, -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := NewLinesOfCode(tt.physical, tt.logical, tt.comment)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if lines.Physical() != tt.physical || lines.Logical() != tt.logical || lines.Comment() != tt.comment {
+				t.Errorf("expected %d/%d/%d lines, got %s", tt.physical, tt.logical, tt.comment, lines)
+			}
+		})
+	}
+}
+
+func TestFunctionMetrics_MaintainabilityIndex(t *testing.T) {
+	location, _ := NewSourceLocation("main.go", 1, 1)
+
+	tests := []struct {
+		name       string
+		cyclomatic int
+		operators  int
+		operands   int
+		physical   int
+		expected   float64
+	}{
+		{"tiny function", 1, 2, 2, 1, 100 * (171 - 0.23 - 5.2*math.Log(8)) / 171},
+		{"large function", 30, 400, 400, 200, 100 * (171 - 6.9 - 16.2*math.Log(200) - 5.2*math.Log(800*math.Log2(40))) / 171},
+		{"huge function is clamped at zero", 200, 5000, 5000, 5000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			complexity, _ := NewComplexityScore(tt.cyclomatic, 0)
+			halstead, _ := NewHalsteadMetrics(min(tt.operators, 20), min(tt.operands, 20), tt.operators, tt.operands)
+			lines, _ := NewLinesOfCode(tt.physical, 0, 0)
+
+			metrics, err := NewFunctionMetrics("f", location, complexity, halstead, lines)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := metrics.MaintainabilityIndex(); math.Abs(got-tt.expected) > 0.01 {
+				t.Errorf("expected maintainability index %.2f, got %.2f", tt.expected, got)
+			}
+		})
+	}
+}
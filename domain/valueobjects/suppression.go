@@ -0,0 +1,74 @@
+package valueobjects
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Suppression silences findings on one line of a file, as requested by a //goast:ignore comment
+type Suppression struct {
+	filePath string
+	line     int
+	rules    []string
+}
+
+// NewSuppression creates a suppression of the given rules on a line; no rules silences every finding
+func NewSuppression(filePath string, line int, rules []string) (Suppression, error) {
+	if filePath == "" {
+		return Suppression{}, fmt.Errorf("file path cannot be empty")
+	}
+	if line < 1 {
+		return Suppression{}, fmt.Errorf("line must be >= 1, got %d", line)
+	}
+
+	return Suppression{
+		filePath: filepath.Clean(filePath),
+		line:     line,
+		rules:    append([]string(nil), rules...),
+	}, nil
+}
+
+// FilePath returns the file containing the suppressed line
+func (s Suppression) FilePath() string {
+	return s.filePath
+}
+
+// Line returns the suppressed line
+func (s Suppression) Line() int {
+	return s.line
+}
+
+// Rules returns the suppressed rules, empty when every finding is suppressed
+func (s Suppression) Rules() []string {
+	return append([]string(nil), s.rules...)
+}
+
+// Covers reports whether the suppression silences a finding at location known by the given names,
+// such as its type and ID. A rule matches a name equal to it or starting with it and "_", so
+// "deep_nesting" matches the finding "deep_nesting_parse_12"
+func (s Suppression) Covers(location SourceLocation, names ...string) bool {
+	if location.Line() != s.line || filepath.Clean(location.FilePath()) != s.filePath {
+		return false
+	}
+	if len(s.rules) == 0 {
+		return true
+	}
+
+	for _, rule := range s.rules {
+		for _, name := range names {
+			if name == rule || strings.HasPrefix(name, rule+"_") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String returns the directive that creates the suppression
+func (s Suppression) String() string {
+	if len(s.rules) == 0 {
+		return fmt.Sprintf("%s:%d: //goast:ignore", s.filePath, s.line)
+	}
+	return fmt.Sprintf("%s:%d: //goast:ignore %s", s.filePath, s.line, strings.Join(s.rules, ","))
+}
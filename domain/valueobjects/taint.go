@@ -0,0 +1,186 @@
+package valueobjects
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaintSource names a function, method, struct field or package variable whose value is untrusted.
+// Names are package-qualified, e.g. "os.Getenv", "net/http.Request.FormValue" or "os.Args"
+type TaintSource struct {
+	name string
+	kind string
+}
+
+// NewTaintSource creates a taint source; kind describes the origin, e.g. "request", "args" or "env"
+func NewTaintSource(name, kind string) (TaintSource, error) {
+	name = NormalizeTaintName(name)
+	if name == "" {
+		return TaintSource{}, fmt.Errorf("taint source name cannot be empty")
+	}
+	if kind == "" {
+		return TaintSource{}, fmt.Errorf("taint source %s needs a kind", name)
+	}
+	return TaintSource{name: name, kind: kind}, nil
+}
+
+// Name returns the qualified name of the source
+func (s TaintSource) Name() string {
+	return s.name
+}
+
+// Kind returns the kind of untrusted input the source produces
+func (s TaintSource) Kind() string {
+	return s.kind
+}
+
+// TaintSink names a function or method that must not receive untrusted data, or a named
+// type that untrusted data must not be converted to
+type TaintSink struct {
+	name string
+	kind string
+	args []int
+}
+
+// NewTaintSink creates a taint sink; args lists the checked argument indexes, not counting
+// the receiver, and an empty list checks every argument
+func NewTaintSink(name, kind string, args []int) (TaintSink, error) {
+	name = NormalizeTaintName(name)
+	if name == "" {
+		return TaintSink{}, fmt.Errorf("taint sink name cannot be empty")
+	}
+	if kind == "" {
+		return TaintSink{}, fmt.Errorf("taint sink %s needs a kind", name)
+	}
+	for _, index := range args {
+		if index < 0 {
+			return TaintSink{}, fmt.Errorf("taint sink %s has negative argument index %d", name, index)
+		}
+	}
+	return TaintSink{name: name, kind: kind, args: append([]int(nil), args...)}, nil
+}
+
+// Name returns the qualified name of the sink
+func (s TaintSink) Name() string {
+	return s.name
+}
+
+// Kind returns the class of vulnerability the sink represents, e.g. "sql", "exec", "path" or "template"
+func (s TaintSink) Kind() string {
+	return s.kind
+}
+
+// Args returns the checked argument indexes; empty means every argument
+func (s TaintSink) Args() []int {
+	return append([]int(nil), s.args...)
+}
+
+// Accepts reports whether argument index is checked by the sink
+func (s TaintSink) Accepts(index int) bool {
+	if len(s.args) == 0 {
+		return true
+	}
+	for _, arg := range s.args {
+		if arg == index {
+			return true
+		}
+	}
+	return false
+}
+
+// TaintConfiguration lists the sources, sanitizers and sinks used by taint tracking
+type TaintConfiguration struct {
+	sources    []TaintSource
+	sanitizers []string
+	sinks      []TaintSink
+}
+
+// NewTaintConfiguration creates a taint configuration from explicit rules
+func NewTaintConfiguration(sources []TaintSource, sanitizers []string, sinks []TaintSink) TaintConfiguration {
+	normalized := make([]string, 0, len(sanitizers))
+	for _, sanitizer := range sanitizers {
+		if name := NormalizeTaintName(sanitizer); name != "" {
+			normalized = append(normalized, name)
+		}
+	}
+	return TaintConfiguration{
+		sources:    append([]TaintSource(nil), sources...),
+		sanitizers: normalized,
+		sinks:      append([]TaintSink(nil), sinks...),
+	}
+}
+
+// DefaultTaintConfiguration returns rules covering HTTP requests, command-line arguments and the
+// environment flowing into SQL queries, commands, file paths and templates
+func DefaultTaintConfiguration() TaintConfiguration {
+	var sources []TaintSource
+	addSources := func(kind string, names ...string) {
+		for _, name := range names {
+			source, _ := NewTaintSource(name, kind)
+			sources = append(sources, source)
+		}
+	}
+	addSources("request",
+		"net/http.Request.URL", "net/http.Request.Header", "net/http.Request.Form", "net/http.Request.PostForm",
+		"net/http.Request.MultipartForm", "net/http.Request.Body", "net/http.Request.RequestURI", "net/http.Request.Host",
+		"net/http.Request.FormValue", "net/http.Request.PostFormValue", "net/http.Request.PathValue", "net/http.Request.FormFile",
+		"net/http.Request.Cookie", "net/http.Request.Cookies", "net/http.Request.Referer", "net/http.Request.UserAgent")
+	addSources("args", "os.Args", "flag.Arg", "flag.Args")
+	addSources("env", "os.Getenv", "os.LookupEnv", "os.Environ")
+
+	var sinks []TaintSink
+	addSinks := func(kind string, args []int, names ...string) {
+		for _, name := range names {
+			sink, _ := NewTaintSink(name, kind, args)
+			sinks = append(sinks, sink)
+		}
+	}
+	for _, receiver := range []string{"database/sql.DB", "database/sql.Tx", "database/sql.Conn"} {
+		addSinks("sql", []int{0}, receiver+".Query", receiver+".QueryRow", receiver+".Exec", receiver+".Prepare")
+		addSinks("sql", []int{1}, receiver+".QueryContext", receiver+".QueryRowContext", receiver+".ExecContext", receiver+".PrepareContext")
+	}
+	addSinks("exec", nil, "os/exec.Command", "os/exec.CommandContext", "syscall.Exec", "os.StartProcess")
+	addSinks("path", []int{0}, "os.Open", "os.OpenFile", "os.Create", "os.ReadFile", "os.WriteFile", "os.Remove",
+		"os.RemoveAll", "os.Mkdir", "os.MkdirAll", "os.ReadDir", "os.Chmod", "os.Rename")
+	addSinks("path", []int{2}, "net/http.ServeFile")
+	addSinks("template", []int{0}, "text/template.Template.Parse", "html/template.Template.Parse")
+	addSinks("template", nil, "html/template.HTML", "html/template.JS", "html/template.URL", "html/template.CSS", "html/template.HTMLAttr")
+
+	sanitizers := []string{
+		"strconv.Atoi", "strconv.ParseInt", "strconv.ParseUint", "strconv.ParseFloat", "strconv.ParseBool", "strconv.Quote",
+		"path/filepath.Base", "path.Base", "html.EscapeString", "html/template.HTMLEscapeString", "html/template.JSEscapeString",
+		"net/url.QueryEscape", "net/url.PathEscape",
+	}
+
+	return NewTaintConfiguration(sources, sanitizers, sinks)
+}
+
+// Merge returns a configuration with the rules of other added to these
+func (c TaintConfiguration) Merge(other TaintConfiguration) TaintConfiguration {
+	return NewTaintConfiguration(
+		append(c.Sources(), other.sources...),
+		append(c.Sanitizers(), other.sanitizers...),
+		append(c.Sinks(), other.sinks...),
+	)
+}
+
+// Sources returns the configured taint sources
+func (c TaintConfiguration) Sources() []TaintSource {
+	return append([]TaintSource(nil), c.sources...)
+}
+
+// Sanitizers returns the qualified names of functions whose result is trusted
+func (c TaintConfiguration) Sanitizers() []string {
+	return append([]string(nil), c.sanitizers...)
+}
+
+// Sinks returns the configured taint sinks
+func (c TaintConfiguration) Sinks() []TaintSink {
+	return append([]TaintSink(nil), c.sinks...)
+}
+
+// NormalizeTaintName turns method expressions such as "(*net/http.Request).FormValue" into
+// the "net/http.Request.FormValue" form used by taint rules
+func NormalizeTaintName(name string) string {
+	return strings.NewReplacer("(", "", ")", "", "*", "").Replace(strings.TrimSpace(name))
+}
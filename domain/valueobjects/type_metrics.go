@@ -0,0 +1,107 @@
+package valueobjects
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CohesionGroup is a set of methods connected through the fields they use or the methods they call
+type CohesionGroup struct {
+	methods []string
+	fields  []string
+}
+
+// NewCohesionGroup creates a cohesion group; a group always has at least one method
+func NewCohesionGroup(methods, fields []string) (CohesionGroup, error) {
+	if len(methods) == 0 {
+		return CohesionGroup{}, fmt.Errorf("cohesion group needs at least one method")
+	}
+	return CohesionGroup{
+		methods: append([]string(nil), methods...),
+		fields:  append([]string(nil), fields...),
+	}, nil
+}
+
+// Methods returns the methods of the group
+func (g CohesionGroup) Methods() []string {
+	return append([]string(nil), g.methods...)
+}
+
+// Fields returns the fields used by the methods of the group
+func (g CohesionGroup) Fields() []string {
+	return append([]string(nil), g.fields...)
+}
+
+// String returns a human-readable representation such as "{Start, Stop: conns, listener}"
+func (g CohesionGroup) String() string {
+	return fmt.Sprintf("{%s: %s}", strings.Join(g.methods, ", "), strings.Join(g.fields, ", "))
+}
+
+// TypeMetrics holds the design metrics of a named struct type
+type TypeMetrics struct {
+	name     string
+	location SourceLocation
+	fields   int
+	methods  int
+	groups   []CohesionGroup
+}
+
+// NewTypeMetrics creates type metrics; groups are the connected components of the methods that
+// use the type's fields, so LCOM4 is their number
+func NewTypeMetrics(name string, location SourceLocation, fields, methods int, groups []CohesionGroup) (TypeMetrics, error) {
+	if name == "" {
+		return TypeMetrics{}, fmt.Errorf("type name cannot be empty")
+	}
+	if fields < 0 {
+		return TypeMetrics{}, fmt.Errorf("field count must be >= 0, got %d", fields)
+	}
+	if methods < 0 {
+		return TypeMetrics{}, fmt.Errorf("method count must be >= 0, got %d", methods)
+	}
+	if len(groups) > methods {
+		return TypeMetrics{}, fmt.Errorf("type %s has %d cohesion groups but only %d methods", name, len(groups), methods)
+	}
+
+	return TypeMetrics{
+		name:     name,
+		location: location,
+		fields:   fields,
+		methods:  methods,
+		groups:   append([]CohesionGroup(nil), groups...),
+	}, nil
+}
+
+// Name returns the type name
+func (m TypeMetrics) Name() string {
+	return m.name
+}
+
+// Location returns where the type is declared
+func (m TypeMetrics) Location() SourceLocation {
+	return m.location
+}
+
+// Fields returns the number of fields, counting each name of a multi-name field declaration
+func (m TypeMetrics) Fields() int {
+	return m.fields
+}
+
+// Methods returns the number of methods declared on the type across the package
+func (m TypeMetrics) Methods() int {
+	return m.methods
+}
+
+// LCOM4 returns the number of connected method groups; 1 is fully cohesive, 0 means no method uses a field
+func (m TypeMetrics) LCOM4() int {
+	return len(m.groups)
+}
+
+// Groups returns the connected method groups
+func (m TypeMetrics) Groups() []CohesionGroup {
+	return append([]CohesionGroup(nil), m.groups...)
+}
+
+// String returns a human-readable representation
+func (m TypeMetrics) String() string {
+	return fmt.Sprintf("%s: fields=%d, methods=%d, lcom4=%d", m.name, m.fields, m.methods, m.LCOM4())
+}
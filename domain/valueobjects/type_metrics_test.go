@@ -0,0 +1,74 @@
+package valueobjects
+
+import "testing"
+
+func TestNewTypeMetrics(t *testing.T) {
+	location, _ := NewSourceLocation("server.go", 3, 6)
+	network, _ := NewCohesionGroup([]string{"Listen", "Reload"}, []string{"addr"})
+	auth, _ := NewCohesionGroup([]string{"Login"}, []string{"sessions", "users"})
+
+	tests := []struct {
+		name        string
+		typeName    string
+		methods     int
+		groups      []CohesionGroup
+		expectError bool
+		expected    string
+	}{
+		{
+			name:     "Two disjoint groups",
+			typeName: "Server",
+			methods:  4,
+			groups:   []CohesionGroup{network, auth},
+			expected: "Server: fields=4, methods=4, lcom4=2",
+		},
+		{
+			name:     "No method uses a field",
+			typeName: "Server",
+			methods:  2,
+			expected: "Server: fields=4, methods=2, lcom4=0",
+		},
+		{
+			name:        "Empty name",
+			typeName:    "",
+			methods:     1,
+			expectError: true,
+		},
+		{
+			name:        "More groups than methods",
+			typeName:    "Server",
+			methods:     1,
+			groups:      []CohesionGroup{network, auth},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := NewTypeMetrics(tt.typeName, location, 4, tt.methods, tt.groups)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if metrics.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, metrics.String())
+			}
+		})
+	}
+}
+
+func TestCohesionGroup_String(t *testing.T) {
+	if _, err := NewCohesionGroup(nil, []string{"addr"}); err == nil {
+		t.Error("Expected error for a group without methods")
+	}
+
+	group, _ := NewCohesionGroup([]string{"Listen", "Reload"}, []string{"addr", "certFile"})
+	if got := group.String(); got != "{Listen, Reload: addr, certFile}" {
+		t.Errorf("Unexpected group representation %q", got)
+	}
+}
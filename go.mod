@@ -2,4 +2,9 @@ module goastanalyzer
 
 go 1.25.5
 
-require golang.org/x/sync v0.19.0
+require (
+	golang.org/x/sync v0.21.0
+	golang.org/x/tools v0.47.0
+)
+
+require golang.org/x/mod v0.37.0 // indirect
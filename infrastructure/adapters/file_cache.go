@@ -0,0 +1,594 @@
+package adapters
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"goastanalyzer/application/usecases"
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// cacheEntrySuffix marks the files the cache owns, so that cleaning never touches anything else
+const cacheEntrySuffix = "-a"
+
+func init() {
+	// Finding metadata is stored as interface values; basic types and their slices are
+	// registered by gob itself
+	gob.Register(map[string]string{})
+}
+
+// FileCache implements the AnalysisCache interface with gob-encoded entries in a directory,
+// sharded by the first two hex digits of their key like the Go build cache
+type FileCache struct {
+	dir string
+
+	versionOnce sync.Once
+	version     string
+	versionErr  error
+
+	hashes map[string]fileHash
+}
+
+// fileHash is a content hash remembered for a file with a given size and modification time
+type fileHash struct {
+	size    int64
+	modTime time.Time
+	hash    string
+}
+
+// CacheStats describes the entries in a cache directory
+type CacheStats struct {
+	Dir     string
+	Entries int
+	Bytes   int64
+}
+
+// NewFileCache creates a cache in dir; an empty dir disables caching
+func NewFileCache(dir string) *FileCache {
+	return &FileCache{dir: dir, hashes: make(map[string]fileHash)}
+}
+
+// SetDir moves the cache to dir; an empty dir disables caching
+func (c *FileCache) SetDir(dir string) {
+	c.dir = dir
+}
+
+// Dir returns the cache directory, or "" when caching is disabled
+func (c *FileCache) Dir() string {
+	return c.dir
+}
+
+// HashFile returns the SHA-256 of a file's content, or "" when caching is disabled. Hashes are
+// remembered while the file's size and modification time stay the same
+func (c *FileCache) HashFile(filePath string) (string, error) {
+	if c.dir == "" {
+		return "", nil
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+	if known, ok := c.hashes[filePath]; ok && known.size == info.Size() && known.modTime.Equal(info.ModTime()) {
+		return known.hash, nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	c.hashes[filePath] = fileHash{size: info.Size(), modTime: info.ModTime(), hash: sum}
+	return sum, nil
+}
+
+// Load returns the entry stored under key by this build of the analyzer. Unreadable or
+// undecodable entries are misses
+func (c *FileCache) Load(key string) (*usecases.CachedAnalysis, bool) {
+	path, err := c.entryPath(key)
+	if err != nil {
+		return nil, false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	var stored cachedEntry
+	if err := gob.NewDecoder(file).Decode(&stored); err != nil {
+		return nil, false
+	}
+	entry, err := stored.toAnalysis()
+	if err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+// Store writes an entry under key, replacing the previous entry atomically
+func (c *FileCache) Store(key string, entry *usecases.CachedAnalysis) error {
+	path, err := c.entryPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "tmp-*")
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(temp).Encode(newCachedEntry(entry)); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	return nil
+}
+
+// Stats counts the entries in the cache directory, including those of other analyzer builds
+func (c *FileCache) Stats() (CacheStats, error) {
+	stats := CacheStats{Dir: c.dir}
+	err := c.walkEntries(func(path string, info fs.FileInfo) error {
+		stats.Entries++
+		stats.Bytes += info.Size()
+		return nil
+	})
+	return stats, err
+}
+
+// Clean removes every entry from the cache directory, leaving other files in it alone
+func (c *FileCache) Clean() error {
+	return c.walkEntries(func(path string, info fs.FileInfo) error {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		// Shard directories are removed once empty
+		os.Remove(filepath.Dir(path))
+		return nil
+	})
+}
+
+// walkEntries calls fn for every entry file in the shard directories of the cache
+func (c *FileCache) walkEntries(fn func(path string, info fs.FileInfo) error) error {
+	if c.dir == "" {
+		return fmt.Errorf("the cache is disabled")
+	}
+
+	shards, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, shard := range shards {
+		if !shard.IsDir() || !isShardName(shard.Name()) {
+			continue
+		}
+		shardDir := filepath.Join(c.dir, shard.Name())
+		entries, err := os.ReadDir(shardDir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || len(name) != 64+len(cacheEntrySuffix) || name[64:] != cacheEntrySuffix {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			if err := fn(filepath.Join(shardDir, name), info); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isShardName reports whether name is two lowercase hex digits
+func isShardName(name string) bool {
+	return len(name) == 2 && strings.Trim(name, "0123456789abcdef") == ""
+}
+
+// entryPath returns the file of the entry for key, mixing in the analyzer version so that a new
+// build never reads results of an older one
+func (c *FileCache) entryPath(key string) (string, error) {
+	if c.dir == "" {
+		return "", fmt.Errorf("the cache is disabled")
+	}
+
+	version, err := c.analyzerVersion()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(version + "\x00" + key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, name[:2], name+cacheEntrySuffix), nil
+}
+
+// analyzerVersion returns the hash of the running executable, so that any rebuild of the
+// analyzer, including a change to a single rule, starts from an empty cache
+func (c *FileCache) analyzerVersion() (string, error) {
+	c.versionOnce.Do(func() {
+		executable, err := os.Executable()
+		if err != nil {
+			c.versionErr = err
+			return
+		}
+		file, err := os.Open(executable)
+		if err != nil {
+			c.versionErr = err
+			return
+		}
+		defer file.Close()
+
+		hash := sha256.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			c.versionErr = err
+			return
+		}
+		c.version = hex.EncodeToString(hash.Sum(nil))
+	})
+	return c.version, c.versionErr
+}
+
+// cachedEntry is the stored form of a CachedAnalysis; domain values keep their fields
+// unexported, so they are copied into exported fields for gob
+type cachedEntry struct {
+	PackageName     string
+	Findings        []cachedFinding
+	FunctionMetrics []cachedFunctionMetrics
+	Suppressions    []cachedSuppression
+	TypeMetrics     []cachedTypeMetrics
+	Duplication     []cachedDuplication
+	FunctionCount   int
+	TotalCyclomatic int
+	TotalCognitive  int
+}
+
+// cachedLocation is the stored form of a source location; the zero location has no path
+type cachedLocation struct {
+	FilePath string
+	Line     int
+	Column   int
+}
+
+// cachedFinding is the stored form of a finding; its timestamp is not kept
+type cachedFinding struct {
+	ID       string
+	Type     int
+	Location cachedLocation
+	Message  string
+	Severity int
+	Metadata map[string]interface{}
+	Trace    []cachedLocation
+	Fixes    []cachedFix
+}
+
+// cachedFix is the stored form of a suggested fix
+type cachedFix struct {
+	Description string
+	Edits       []cachedEdit
+}
+
+// cachedEdit is the stored form of a text edit
+type cachedEdit struct {
+	FilePath string
+	Offset   int
+	End      int
+	NewText  string
+}
+
+// cachedFunctionMetrics is the stored form of function metrics
+type cachedFunctionMetrics struct {
+	Name              string
+	Location          cachedLocation
+	Cyclomatic        int
+	Cognitive         int
+	NPath             int64
+	Essential         int
+	DistinctOperators int
+	DistinctOperands  int
+	TotalOperators    int
+	TotalOperands     int
+	Physical          int
+	Logical           int
+	Comment           int
+}
+
+// cachedTypeMetrics is the stored form of type metrics
+type cachedTypeMetrics struct {
+	Name     string
+	Location cachedLocation
+	Fields   int
+	Methods  int
+	Groups   []cachedCohesionGroup
+}
+
+// cachedCohesionGroup is the stored form of a cohesion group
+type cachedCohesionGroup struct {
+	Methods []string
+	Fields  []string
+}
+
+// cachedSuppression is the stored form of a suppression
+type cachedSuppression struct {
+	FilePath string
+	Line     int
+	Rules    []string
+}
+
+// cachedDuplication is the stored form of a package's duplication
+type cachedDuplication struct {
+	Package          string
+	TotalTokens      int
+	DuplicatedTokens int
+}
+
+// newCachedEntry copies an analysis into its stored form
+func newCachedEntry(entry *usecases.CachedAnalysis) cachedEntry {
+	stored := cachedEntry{
+		PackageName:     entry.PackageName,
+		FunctionCount:   entry.FunctionCount,
+		TotalCyclomatic: entry.TotalCyclomatic,
+		TotalCognitive:  entry.TotalCognitive,
+	}
+
+	for _, finding := range entry.Findings {
+		trace := make([]cachedLocation, 0, len(finding.Trace()))
+		for _, step := range finding.Trace() {
+			trace = append(trace, newCachedLocation(step))
+		}
+		var fixes []cachedFix
+		for _, fix := range finding.Fixes() {
+			stored := cachedFix{Description: fix.Description()}
+			for _, edit := range fix.Edits() {
+				stored.Edits = append(stored.Edits, cachedEdit{FilePath: edit.FilePath(), Offset: edit.Offset(), End: edit.End(), NewText: edit.NewText()})
+			}
+			fixes = append(fixes, stored)
+		}
+		stored.Findings = append(stored.Findings, cachedFinding{
+			ID:       finding.ID(),
+			Type:     int(finding.Type()),
+			Location: newCachedLocation(finding.Location()),
+			Message:  finding.Message(),
+			Severity: int(finding.Severity()),
+			Metadata: finding.Metadata(),
+			Trace:    trace,
+			Fixes:    fixes,
+		})
+	}
+
+	for _, metrics := range entry.FunctionMetrics {
+		complexity, halstead, lines := metrics.Complexity(), metrics.Halstead(), metrics.Lines()
+		stored.FunctionMetrics = append(stored.FunctionMetrics, cachedFunctionMetrics{
+			Name:              metrics.Name(),
+			Location:          newCachedLocation(metrics.Location()),
+			Cyclomatic:        complexity.Cyclomatic(),
+			Cognitive:         complexity.Cognitive(),
+			NPath:             complexity.NPath(),
+			Essential:         complexity.Essential(),
+			DistinctOperators: halstead.DistinctOperators(),
+			DistinctOperands:  halstead.DistinctOperands(),
+			TotalOperators:    halstead.TotalOperators(),
+			TotalOperands:     halstead.TotalOperands(),
+			Physical:          lines.Physical(),
+			Logical:           lines.Logical(),
+			Comment:           lines.Comment(),
+		})
+	}
+
+	for _, suppression := range entry.Suppressions {
+		stored.Suppressions = append(stored.Suppressions, cachedSuppression{
+			FilePath: suppression.FilePath(),
+			Line:     suppression.Line(),
+			Rules:    suppression.Rules(),
+		})
+	}
+
+	for _, metrics := range entry.TypeMetrics {
+		var groups []cachedCohesionGroup
+		for _, group := range metrics.Groups() {
+			groups = append(groups, cachedCohesionGroup{Methods: group.Methods(), Fields: group.Fields()})
+		}
+		stored.TypeMetrics = append(stored.TypeMetrics, cachedTypeMetrics{
+			Name:     metrics.Name(),
+			Location: newCachedLocation(metrics.Location()),
+			Fields:   metrics.Fields(),
+			Methods:  metrics.Methods(),
+			Groups:   groups,
+		})
+	}
+
+	for _, duplication := range entry.Duplication {
+		stored.Duplication = append(stored.Duplication, cachedDuplication{
+			Package:          duplication.Package(),
+			TotalTokens:      duplication.TotalTokens(),
+			DuplicatedTokens: duplication.DuplicatedTokens(),
+		})
+	}
+
+	return stored
+}
+
+// toAnalysis rebuilds the analysis through the domain constructors
+func (e cachedEntry) toAnalysis() (*usecases.CachedAnalysis, error) {
+	entry := &usecases.CachedAnalysis{
+		PackageName:     e.PackageName,
+		FunctionCount:   e.FunctionCount,
+		TotalCyclomatic: e.TotalCyclomatic,
+		TotalCognitive:  e.TotalCognitive,
+	}
+
+	for _, stored := range e.Findings {
+		finding, err := stored.toFinding()
+		if err != nil {
+			return nil, err
+		}
+		entry.Findings = append(entry.Findings, finding)
+	}
+
+	for _, stored := range e.FunctionMetrics {
+		metrics, err := stored.toFunctionMetrics()
+		if err != nil {
+			return nil, err
+		}
+		entry.FunctionMetrics = append(entry.FunctionMetrics, metrics)
+	}
+
+	for _, stored := range e.Suppressions {
+		suppression, err := valueobjects.NewSuppression(stored.FilePath, stored.Line, stored.Rules)
+		if err != nil {
+			return nil, err
+		}
+		entry.Suppressions = append(entry.Suppressions, suppression)
+	}
+
+	for _, stored := range e.TypeMetrics {
+		metrics, err := stored.toTypeMetrics()
+		if err != nil {
+			return nil, err
+		}
+		entry.TypeMetrics = append(entry.TypeMetrics, metrics)
+	}
+
+	for _, stored := range e.Duplication {
+		duplication, err := valueobjects.NewPackageDuplication(stored.Package, stored.TotalTokens, stored.DuplicatedTokens)
+		if err != nil {
+			return nil, err
+		}
+		entry.Duplication = append(entry.Duplication, duplication)
+	}
+
+	return entry, nil
+}
+
+// newCachedLocation copies a source location into its stored form
+func newCachedLocation(location valueobjects.SourceLocation) cachedLocation {
+	return cachedLocation{FilePath: location.FilePath(), Line: location.Line(), Column: location.Column()}
+}
+
+// toLocation rebuilds a source location, keeping the zero location of findings without one
+func (l cachedLocation) toLocation() (valueobjects.SourceLocation, error) {
+	if l.FilePath == "" {
+		return valueobjects.SourceLocation{}, nil
+	}
+	return valueobjects.NewSourceLocation(l.FilePath, l.Line, l.Column)
+}
+
+// toFinding rebuilds a finding with its metadata and trace
+func (f cachedFinding) toFinding() (entities.AnalysisFinding, error) {
+	location, err := f.Location.toLocation()
+	if err != nil {
+		return entities.AnalysisFinding{}, err
+	}
+	finding, err := entities.NewAnalysisFinding(f.ID, entities.FindingType(f.Type), location, f.Message, valueobjects.SeverityLevel(f.Severity))
+	if err != nil {
+		return entities.AnalysisFinding{}, err
+	}
+
+	for key, value := range f.Metadata {
+		finding.AddMetadata(key, value)
+	}
+	for _, stored := range f.Trace {
+		step, err := stored.toLocation()
+		if err != nil {
+			return entities.AnalysisFinding{}, err
+		}
+		finding.AddTraceStep(step)
+	}
+	for _, stored := range f.Fixes {
+		fix, err := stored.toFix()
+		if err != nil {
+			return entities.AnalysisFinding{}, err
+		}
+		finding.AddFix(fix)
+	}
+	return finding, nil
+}
+
+// toFix rebuilds a suggested fix
+func (f cachedFix) toFix() (valueobjects.SuggestedFix, error) {
+	edits := make([]valueobjects.TextEdit, 0, len(f.Edits))
+	for _, stored := range f.Edits {
+		edit, err := valueobjects.NewTextEdit(stored.FilePath, stored.Offset, stored.End, stored.NewText)
+		if err != nil {
+			return valueobjects.SuggestedFix{}, err
+		}
+		edits = append(edits, edit)
+	}
+	return valueobjects.NewSuggestedFix(f.Description, edits...)
+}
+
+// toFunctionMetrics rebuilds function metrics
+func (m cachedFunctionMetrics) toFunctionMetrics() (valueobjects.FunctionMetrics, error) {
+	location, err := m.Location.toLocation()
+	if err != nil {
+		return valueobjects.FunctionMetrics{}, err
+	}
+	complexity, err := valueobjects.NewComplexityScore(m.Cyclomatic, m.Cognitive)
+	if err != nil {
+		return valueobjects.FunctionMetrics{}, err
+	}
+	if complexity, err = complexity.WithPathComplexity(m.NPath, m.Essential); err != nil {
+		return valueobjects.FunctionMetrics{}, err
+	}
+	halstead, err := valueobjects.NewHalsteadMetrics(m.DistinctOperators, m.DistinctOperands, m.TotalOperators, m.TotalOperands)
+	if err != nil {
+		return valueobjects.FunctionMetrics{}, err
+	}
+	lines, err := valueobjects.NewLinesOfCode(m.Physical, m.Logical, m.Comment)
+	if err != nil {
+		return valueobjects.FunctionMetrics{}, err
+	}
+	return valueobjects.NewFunctionMetrics(m.Name, location, complexity, halstead, lines)
+}
+
+// toTypeMetrics rebuilds type metrics with their cohesion groups
+func (m cachedTypeMetrics) toTypeMetrics() (valueobjects.TypeMetrics, error) {
+	location, err := m.Location.toLocation()
+	if err != nil {
+		return valueobjects.TypeMetrics{}, err
+	}
+
+	groups := make([]valueobjects.CohesionGroup, 0, len(m.Groups))
+	for _, stored := range m.Groups {
+		group, err := valueobjects.NewCohesionGroup(stored.Methods, stored.Fields)
+		if err != nil {
+			return valueobjects.TypeMetrics{}, err
+		}
+		groups = append(groups, group)
+	}
+	return valueobjects.NewTypeMetrics(m.Name, location, m.Fields, m.Methods, groups)
+}
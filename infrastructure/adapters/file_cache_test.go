@@ -0,0 +1,251 @@
+package adapters
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goastanalyzer/application/usecases"
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestFileCache_HashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	writeFile(t, path, "package main\n")
+
+	cache := NewFileCache(t.TempDir())
+	hash, err := cache.HashFile(path)
+	if err != nil {
+		t.Fatalf("HashFile failed: %v", err)
+	}
+	sum := sha256.Sum256([]byte("package main\n"))
+	if hash != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected the SHA-256 of the content, got %s", hash)
+	}
+
+	writeFile(t, path, "package main\n\nfunc main() {}\n")
+	if changed, _ := cache.HashFile(path); changed == hash {
+		t.Errorf("Expected the hash to change with the content")
+	}
+
+	if _, err := cache.HashFile(filepath.Join(t.TempDir(), "missing.go")); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+	if disabled, err := NewFileCache("").HashFile(path); disabled != "" || err != nil {
+		t.Errorf("Expected a disabled cache to hash nothing, got %q, %v", disabled, err)
+	}
+}
+
+func TestFileCache_StoreLoad(t *testing.T) {
+	cache := NewFileCache(t.TempDir())
+	entry := newTestCachedAnalysis(t)
+
+	if err := cache.Store("key", entry); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	loaded, ok := cache.Load("key")
+	if !ok {
+		t.Fatalf("Expected the stored entry to load")
+	}
+
+	if loaded.PackageName != "main" || loaded.FunctionCount != 1 || loaded.TotalCyclomatic != 4 {
+		t.Errorf("Unexpected totals: %+v", loaded)
+	}
+	if len(loaded.Findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %d", len(loaded.Findings))
+	}
+	finding := loaded.Findings[0]
+	if finding.ID() != "leak_main_3" || finding.Location().Line() != 3 || finding.Severity() != valueobjects.SeverityError {
+		t.Errorf("Unexpected finding: %s at %s", finding.ID(), finding.Location())
+	}
+	if finding.Metadata()["rule"] != "leak" || len(finding.Trace()) != 1 || len(finding.Fixes()) != 1 {
+		t.Errorf("Expected metadata, trace and fixes to be kept, got %v, %v, %v", finding.Metadata(), finding.Trace(), finding.Fixes())
+	}
+	if len(loaded.FunctionMetrics) != 1 || loaded.FunctionMetrics[0].Complexity().NPath() != 8 {
+		t.Errorf("Expected function metrics with NPath 8, got %v", loaded.FunctionMetrics)
+	}
+	if len(loaded.TypeMetrics) != 1 || len(loaded.TypeMetrics[0].Groups()) != 1 {
+		t.Errorf("Expected type metrics with one cohesion group, got %v", loaded.TypeMetrics)
+	}
+	if len(loaded.Suppressions) != 1 || len(loaded.Duplication) != 1 {
+		t.Errorf("Expected suppressions and duplication to be kept")
+	}
+}
+
+func TestFileCache_Keys(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewFileCache(dir)
+	if err := cache.Store("key", newTestCachedAnalysis(t)); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	if _, ok := cache.Load("other"); ok {
+		t.Errorf("Expected another key to miss")
+	}
+
+	// Another build of the analyzer has a different executable hash and never reads the entry
+	rebuilt := NewFileCache(dir)
+	rebuilt.versionOnce.Do(func() { rebuilt.version = "rebuilt" })
+	if _, ok := rebuilt.Load("key"); ok {
+		t.Errorf("Expected an entry of another analyzer build to miss")
+	}
+
+	if _, ok := NewFileCache("").Load("key"); ok {
+		t.Errorf("Expected a disabled cache to miss")
+	}
+	if err := NewFileCache("").Store("key", newTestCachedAnalysis(t)); err == nil {
+		t.Errorf("Expected storing in a disabled cache to fail")
+	}
+}
+
+func TestFileCache_CorruptEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, path string)
+	}{
+		{
+			name: "Missing entry",
+			corrupt: func(t *testing.T, path string) {
+				if err := os.Remove(path); err != nil {
+					t.Fatalf("Failed to remove entry: %v", err)
+				}
+			},
+		},
+		{
+			name: "Garbage",
+			corrupt: func(t *testing.T, path string) {
+				writeFile(t, path, "not gob")
+			},
+		},
+		{
+			name: "Truncated",
+			corrupt: func(t *testing.T, path string) {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("Failed to read entry: %v", err)
+				}
+				writeFile(t, path, string(content[:len(content)/2]))
+			},
+		},
+		{
+			name: "Empty",
+			corrupt: func(t *testing.T, path string) {
+				writeFile(t, path, "")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewFileCache(t.TempDir())
+			if err := cache.Store("key", newTestCachedAnalysis(t)); err != nil {
+				t.Fatalf("Store failed: %v", err)
+			}
+			path, err := cache.entryPath("key")
+			if err != nil {
+				t.Fatalf("entryPath failed: %v", err)
+			}
+
+			tt.corrupt(t, path)
+			if _, ok := cache.Load("key"); ok {
+				t.Fatalf("Expected a corrupt entry to miss")
+			}
+
+			// The next run stores a fresh entry over the corrupt one
+			if err := cache.Store("key", newTestCachedAnalysis(t)); err != nil {
+				t.Fatalf("Store failed: %v", err)
+			}
+			if _, ok := cache.Load("key"); !ok {
+				t.Errorf("Expected the replaced entry to load")
+			}
+		})
+	}
+}
+
+func TestFileCache_StatsAndClean(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewFileCache(dir)
+	for _, key := range []string{"a", "b", "c"} {
+		if err := cache.Store(key, newTestCachedAnalysis(t)); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+	// Files the cache does not own are neither counted nor removed
+	writeFile(t, filepath.Join(dir, "README"), "notes\n")
+	if err := os.Mkdir(filepath.Join(dir, "ab"), 0o755); err != nil && !os.IsExist(err) {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "ab", "unrelated"), "data\n")
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.Dir != dir || stats.Entries != 3 || stats.Bytes <= 0 {
+		t.Errorf("Expected 3 entries in %s, got %+v", dir, stats)
+	}
+
+	if err := cache.Clean(); err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if stats, _ := cache.Stats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Errorf("Expected no entries after cleaning, got %+v", stats)
+	}
+	if _, ok := cache.Load("a"); ok {
+		t.Errorf("Expected cleaned entries to miss")
+	}
+	for _, kept := range []string{"README", filepath.Join("ab", "unrelated")} {
+		if _, err := os.Stat(filepath.Join(dir, kept)); err != nil {
+			t.Errorf("Expected %s to be kept: %v", kept, err)
+		}
+	}
+
+	if stats, err := NewFileCache(filepath.Join(dir, "missing")).Stats(); err != nil || stats.Entries != 0 {
+		t.Errorf("Expected a missing directory to be an empty cache, got %+v, %v", stats, err)
+	}
+	if _, err := NewFileCache("").Stats(); err == nil {
+		t.Errorf("Expected an error for a disabled cache")
+	}
+}
+
+// newTestCachedAnalysis builds an entry using every kind of stored value
+func newTestCachedAnalysis(t *testing.T) *usecases.CachedAnalysis {
+	t.Helper()
+
+	location, _ := valueobjects.NewSourceLocation("main.go", 3, 2)
+	finding, err := entities.NewAnalysisFinding("leak_main_3", entities.FindingTypeSmell, location, "leak", valueobjects.SeverityError)
+	if err != nil {
+		t.Fatalf("Failed to create finding: %v", err)
+	}
+	finding.AddMetadata("rule", "leak")
+	finding.AddTraceStep(location)
+	edit, _ := valueobjects.NewTextEdit("main.go", 10, 12, "x")
+	fix, _ := valueobjects.NewSuggestedFix("rename", edit)
+	finding.AddFix(fix)
+
+	complexity, _ := valueobjects.NewComplexityScore(4, 2)
+	complexity, _ = complexity.WithPathComplexity(8, 1)
+	halstead, _ := valueobjects.NewHalsteadMetrics(3, 4, 5, 6)
+	lines, _ := valueobjects.NewLinesOfCode(10, 8, 1)
+	metrics, _ := valueobjects.NewFunctionMetrics("main", location, complexity, halstead, lines)
+
+	group, _ := valueobjects.NewCohesionGroup([]string{"Run"}, []string{"name"})
+	typeMetrics, _ := valueobjects.NewTypeMetrics("server", location, 1, 1, []valueobjects.CohesionGroup{group})
+	suppression, _ := valueobjects.NewSuppression("main.go", 3, []string{"leak"})
+	duplication, _ := valueobjects.NewPackageDuplication("main", 100, 20)
+
+	return &usecases.CachedAnalysis{
+		PackageName:     "main",
+		Findings:        []entities.AnalysisFinding{finding},
+		FunctionMetrics: []valueobjects.FunctionMetrics{metrics},
+		Suppressions:    []valueobjects.Suppression{suppression},
+		TypeMetrics:     []valueobjects.TypeMetrics{typeMetrics},
+		Duplication:     []valueobjects.PackageDuplication{duplication},
+		FunctionCount:   1,
+		TotalCyclomatic: 4,
+		TotalCognitive:  2,
+	}
+}
@@ -7,22 +7,24 @@ import (
 )
 
 // GoFileParser implements the FileParser interface using Go's standard library
-type GoFileParser struct{}
+type GoFileParser struct {
+	fset *token.FileSet
+}
 
 // NewGoFileParser creates a new Go file parser
 func NewGoFileParser() *GoFileParser {
-	return &GoFileParser{}
+	return &GoFileParser{fset: token.NewFileSet()}
 }
 
-// ParseFile parses a Go source file and returns its AST and file set
+// ParseFile parses a Go source file and returns its AST and file set.
+// All files parsed by the same parser share one file set so that files of a
+// package can be type-checked together.
 func (p *GoFileParser) ParseFile(filePath string) (*ast.File, *token.FileSet, error) {
-	fset := token.NewFileSet()
-
 	// Parse the file with all syntax features enabled
-	file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	file, err := parser.ParseFile(p.fset, filePath, nil, parser.ParseComments)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return file, fset, nil
+	return file, p.fset, nil
 }
@@ -0,0 +1,172 @@
+package adapters
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PollingFileWatcher implements the FileWatcher interface by comparing the size and modification
+// time of the watched files at a fixed interval, which works on every platform and file system
+type PollingFileWatcher struct {
+	paths     []string
+	recursive bool
+	interval  time.Duration
+	snapshot  map[string]fileState
+}
+
+// fileState is what the poller compares to notice a change
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// NewPollingFileWatcher creates a watcher of the given files and directories. A directory is
+// watched with its subdirectories when recursive is set or when given as "dir/...", as with the
+// go command, in which case directories named testdata or starting with "." or "_" are skipped
+func NewPollingFileWatcher(paths []string, recursive bool, interval time.Duration) *PollingFileWatcher {
+	return &PollingFileWatcher{
+		paths:     paths,
+		recursive: recursive,
+		interval:  interval,
+	}
+}
+
+// Files returns the watched Go files in a stable order and remembers their state
+func (w *PollingFileWatcher) Files() ([]string, error) {
+	snapshot, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+	w.snapshot = snapshot
+
+	files := make([]string, 0, len(snapshot))
+	for path := range snapshot {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Wait polls until files change, then keeps polling until they stop changing so that an editor
+// writing several files, or one file in steps, triggers a single analysis
+func (w *PollingFileWatcher) Wait(stop <-chan struct{}) ([]string, error) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	changed := make(map[string]bool)
+	current := w.snapshot
+	for {
+		select {
+		case <-stop:
+			return nil, nil
+		case <-ticker.C:
+		}
+
+		next, err := w.scan()
+		if err != nil {
+			return nil, err
+		}
+		differences := diffSnapshots(current, next)
+		current = next
+
+		if len(differences) == 0 && len(changed) > 0 {
+			files := make([]string, 0, len(changed))
+			for path := range changed {
+				files = append(files, path)
+			}
+			sort.Strings(files)
+			return files, nil
+		}
+		for _, path := range differences {
+			changed[path] = true
+		}
+	}
+}
+
+// scan records the state of every watched Go file. A watched path must exist on the first scan;
+// afterwards a missing path just has no files
+func (w *PollingFileWatcher) scan() (map[string]fileState, error) {
+	snapshot := make(map[string]fileState)
+	for _, path := range w.paths {
+		root, pattern := strings.CutSuffix(path, "/...")
+		if root == "" {
+			root = "."
+		}
+
+		info, err := os.Stat(root)
+		if os.IsNotExist(err) && w.snapshot != nil {
+			// An editor saving by rename leaves the file briefly missing; report it as removed
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			snapshot[root] = fileState{size: info.Size(), modTime: info.ModTime()}
+			continue
+		}
+
+		err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				// Files may vanish while an editor saves
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if entry.IsDir() {
+				if path == root {
+					return nil
+				}
+				if !w.recursive && !pattern {
+					return filepath.SkipDir
+				}
+				if pattern && skippedDir(entry.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(path, ".go") {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			snapshot[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// skippedDir reports whether the go command ignores a directory when matching "..."
+func skippedDir(name string) bool {
+	return name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// diffSnapshots returns the files added, modified or removed between two snapshots
+func diffSnapshots(before, after map[string]fileState) []string {
+	var changed []string
+	for path, state := range after {
+		if previous, ok := before[path]; !ok || previous.size != state.size || !previous.modTime.Equal(state.modTime) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,158 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPollingFileWatcher_Wait(t *testing.T) {
+	tests := []struct {
+		name     string
+		recurse  bool
+		setup    []string
+		edit     func(t *testing.T, dir string)
+		expected []string
+	}{
+		{
+			name:  "File appears",
+			setup: []string{"a.go"},
+			edit: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "b.go"), "package p\n")
+			},
+			expected: []string{"b.go"},
+		},
+		{
+			name:  "File changes",
+			setup: []string{"a.go", "b.go"},
+			edit: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "a.go"), "package p\n\nfunc f() {}\n")
+			},
+			expected: []string{"a.go"},
+		},
+		{
+			name:  "File disappears",
+			setup: []string{"a.go", "b.go"},
+			edit: func(t *testing.T, dir string) {
+				if err := os.Remove(filepath.Join(dir, "b.go")); err != nil {
+					t.Fatalf("Failed to remove file: %v", err)
+				}
+			},
+			expected: []string{"b.go"},
+		},
+		{
+			name:  "Non-Go files and subdirectories are ignored",
+			setup: []string{"a.go"},
+			edit: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "notes.txt"), "notes\n")
+				if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+					t.Fatalf("Failed to create directory: %v", err)
+				}
+				writeFile(t, filepath.Join(dir, "sub", "c.go"), "package sub\n")
+				writeFile(t, filepath.Join(dir, "d.go"), "package p\n")
+			},
+			expected: []string{"d.go"},
+		},
+		{
+			name:    "Recursive watch sees subdirectories",
+			recurse: true,
+			setup:   []string{"a.go"},
+			edit: func(t *testing.T, dir string) {
+				if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+					t.Fatalf("Failed to create directory: %v", err)
+				}
+				writeFile(t, filepath.Join(dir, "sub", "c.go"), "package sub\n")
+			},
+			expected: []string{"sub/c.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.setup {
+				writeFile(t, filepath.Join(dir, name), "package p\n")
+			}
+
+			watcher := NewPollingFileWatcher([]string{dir}, tt.recurse, 5*time.Millisecond)
+			files, err := watcher.Files()
+			if err != nil {
+				t.Fatalf("Files failed: %v", err)
+			}
+			if len(files) != len(tt.setup) {
+				t.Fatalf("Expected %d watched files, got %v", len(tt.setup), files)
+			}
+
+			tt.edit(t, dir)
+			changed := waitForChanges(t, watcher)
+
+			var expected []string
+			for _, name := range tt.expected {
+				expected = append(expected, filepath.Join(dir, filepath.FromSlash(name)))
+			}
+			if strings.Join(changed, ",") != strings.Join(expected, ",") {
+				t.Errorf("Expected changes %v, got %v", expected, changed)
+			}
+		})
+	}
+}
+
+func TestPollingFileWatcher_WatchedFileReplaced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	writeFile(t, path, "package main\n")
+
+	watcher := NewPollingFileWatcher([]string{path}, false, 5*time.Millisecond)
+	if _, err := watcher.Files(); err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+
+	// An editor saving by rename removes the file before the new version takes its place
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	if changed := waitForChanges(t, watcher); len(changed) != 1 || changed[0] != path {
+		t.Fatalf("Expected the removed file to be reported, got %v", changed)
+	}
+	files, err := watcher.Files()
+	if err != nil {
+		t.Fatalf("Files failed while the file was missing: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Expected no watched files while the file is missing, got %v", files)
+	}
+
+	writeFile(t, path, "package main\n\nfunc main() {}\n")
+	if changed := waitForChanges(t, watcher); len(changed) != 1 || changed[0] != path {
+		t.Fatalf("Expected the restored file to be reported, got %v", changed)
+	}
+	if files, err := watcher.Files(); err != nil || len(files) != 1 {
+		t.Errorf("Expected the restored file to be watched again, got %v, %v", files, err)
+	}
+}
+
+func TestPollingFileWatcher_MissingPathAtStart(t *testing.T) {
+	watcher := NewPollingFileWatcher([]string{filepath.Join(t.TempDir(), "missing.go")}, false, 5*time.Millisecond)
+	if _, err := watcher.Files(); err == nil {
+		t.Errorf("Expected an error for a path that does not exist")
+	}
+}
+
+// waitForChanges returns the next changes seen by watcher, failing the test if none arrive in time
+func waitForChanges(t *testing.T, watcher *PollingFileWatcher) []string {
+	t.Helper()
+
+	stop := make(chan struct{})
+	timer := time.AfterFunc(5*time.Second, func() { close(stop) })
+	defer timer.Stop()
+
+	changed, err := watcher.Wait(stop)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if changed == nil {
+		t.Fatalf("Timed out waiting for changes")
+	}
+	return changed
+}
@@ -0,0 +1,273 @@
+package adapters
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+// GitDiffReader reads the changed lines of files from git or from a unified diff.
+// Changed paths are returned relative to the working directory, matching the paths
+// produced when analyzing "." and its subdirectories.
+type GitDiffReader struct {
+	git string
+}
+
+// NewGitDiffReader creates a diff reader that runs git from the PATH
+func NewGitDiffReader() *GitDiffReader {
+	return &GitDiffReader{git: "git"}
+}
+
+// ReadChanges returns the lines of the working tree that changed since the merge base of ref and
+// HEAD, so that "-diff main" on a branch reports what the branch would merge
+func (r *GitDiffReader) ReadChanges(ref string) (valueobjects.ChangeSet, error) {
+	// A ref starting with a dash would be parsed by git as an option
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return valueobjects.ChangeSet{}, fmt.Errorf("invalid git ref %q", ref)
+	}
+
+	root, err := r.repositoryRoot()
+	if err != nil {
+		return valueobjects.ChangeSet{}, err
+	}
+	if root == "" {
+		return valueobjects.ChangeSet{}, fmt.Errorf("-diff needs a git repository")
+	}
+
+	verify := exec.Command(r.git, "-C", root, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	if err := verify.Run(); err != nil {
+		return valueobjects.ChangeSet{}, fmt.Errorf("unknown git ref %q", ref)
+	}
+
+	cmd := exec.Command(r.git, "-C", root, "-c", "core.quotepath=off", "diff", "--no-color", "--no-ext-diff",
+		"-U0", "--merge-base", ref, "--")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return valueobjects.ChangeSet{}, fmt.Errorf("git diff %s failed: %v: %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return r.changeSet(root, bytes.NewReader(output))
+}
+
+// ParseDiff reads a unified diff, with or without context lines. Its paths are taken relative
+// to the repository enclosing the working directory, or to the working directory outside one
+func (r *GitDiffReader) ParseDiff(reader io.Reader) (valueobjects.ChangeSet, error) {
+	root, err := r.repositoryRoot()
+	if err != nil {
+		return valueobjects.ChangeSet{}, err
+	}
+	if root == "" {
+		if root, err = os.Getwd(); err != nil {
+			return valueobjects.ChangeSet{}, err
+		}
+	}
+
+	return r.changeSet(root, reader)
+}
+
+// repositoryRoot returns the top directory of the repository enclosing the working directory, or ""
+func (r *GitDiffReader) repositoryRoot() (string, error) {
+	output, err := exec.Command(r.git, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to run git: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// changeSet parses a diff whose paths are relative to root into a change set keyed by paths
+// relative to the working directory
+func (r *GitDiffReader) changeSet(root string, reader io.Reader) (valueobjects.ChangeSet, error) {
+	hunks, err := parseUnifiedDiff(reader)
+	if err != nil {
+		return valueobjects.ChangeSet{}, err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return valueobjects.ChangeSet{}, err
+	}
+	// The toplevel is reported with symlinks resolved
+	if resolved, err := filepath.EvalSymlinks(wd); err == nil {
+		wd = resolved
+	}
+
+	files := make(map[string][]valueobjects.DiffHunk, len(hunks))
+	for path, fileHunks := range hunks {
+		abs := filepath.Join(root, filepath.FromSlash(path))
+		if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			abs = rel
+		}
+		files[abs] = fileHunks
+	}
+	return valueobjects.NewChangeSet(files), nil
+}
+
+// parseUnifiedDiff returns the changed line ranges of every file a unified diff adds or modifies.
+// Context lines are dropped, so each run of removed and added lines becomes one hunk as with -U0
+func parseUnifiedDiff(reader io.Reader) (map[string][]valueobjects.DiffHunk, error) {
+	files := make(map[string][]valueobjects.DiffHunk)
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	path := ""
+	var run *diffRun
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if run != nil {
+			if run.consume(line) {
+				continue
+			}
+			hunks, err := run.hunks()
+			if err != nil {
+				return nil, err
+			}
+			if path != "" {
+				files[path] = append(files[path], hunks...)
+			}
+			run = nil
+		}
+
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			path = diffPath(line[4:])
+		case strings.HasPrefix(line, "@@ "):
+			header, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			run = newDiffRun(header)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if run != nil {
+		hunks, err := run.hunks()
+		if err != nil {
+			return nil, err
+		}
+		if path != "" {
+			files[path] = append(files[path], hunks...)
+		}
+	}
+	return files, nil
+}
+
+// diffPath returns the path of a "+++" line, or "" for a deleted file
+func diffPath(name string) string {
+	// diff -u appends a tab and a timestamp
+	name, _, _ = strings.Cut(name, "\t")
+	if name == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(name, "b/")
+}
+
+// diffRun walks the body of one hunk, splitting it at context lines
+type diffRun struct {
+	oldLeft, newLeft int
+	oldLine, newLine int
+
+	changes            [][4]int
+	oldStart, newStart int
+	removed, added     int
+}
+
+// newDiffRun starts walking the body of a hunk; a side without lines starts after its start line
+func newDiffRun(header valueobjects.DiffHunk) *diffRun {
+	run := &diffRun{
+		oldLeft: header.OldLines(),
+		newLeft: header.NewLines(),
+		oldLine: header.OldStart(),
+		newLine: header.NewStart(),
+	}
+	if header.OldLines() == 0 {
+		run.oldLine++
+	}
+	if header.NewLines() == 0 {
+		run.newLine++
+	}
+	return run
+}
+
+// consume reads the next body line and reports whether it belonged to the hunk
+func (r *diffRun) consume(line string) bool {
+	if r.oldLeft == 0 && r.newLeft == 0 {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(line, `\`):
+		// "\ No newline at end of file"
+	case strings.HasPrefix(line, "-"):
+		r.begin()
+		r.removed++
+		r.oldLine++
+		r.oldLeft--
+	case strings.HasPrefix(line, "+"):
+		r.begin()
+		r.added++
+		r.newLine++
+		r.newLeft--
+	default:
+		r.end()
+		r.oldLine++
+		r.newLine++
+		r.oldLeft--
+		r.newLeft--
+	}
+	return true
+}
+
+// begin starts a run of changed lines unless one is open
+func (r *diffRun) begin() {
+	if r.removed == 0 && r.added == 0 {
+		r.oldStart, r.newStart = r.oldLine, r.newLine
+	}
+}
+
+// end closes the open run of changed lines
+func (r *diffRun) end() {
+	if r.removed == 0 && r.added == 0 {
+		return
+	}
+
+	oldStart, newStart := r.oldStart, r.newStart
+	if r.removed == 0 {
+		oldStart--
+	}
+	if r.added == 0 {
+		newStart--
+	}
+	r.changes = append(r.changes, [4]int{oldStart, r.removed, newStart, r.added})
+	r.removed, r.added = 0, 0
+}
+
+// hunks returns the runs of changed lines as hunks without context
+func (r *diffRun) hunks() ([]valueobjects.DiffHunk, error) {
+	r.end()
+
+	hunks := make([]valueobjects.DiffHunk, 0, len(r.changes))
+	for _, change := range r.changes {
+		hunk, err := valueobjects.NewDiffHunk(change[0], change[1], change[2], change[3])
+		if err != nil {
+			return nil, err
+		}
+		hunks = append(hunks, hunk)
+	}
+	return hunks, nil
+}
@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitDiffReader_ReadChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {}\n")
+	runGit(t, dir, "add", "main.go")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {\n\tprintln()\n}\n")
+	t.Chdir(dir)
+
+	tests := []struct {
+		name          string
+		ref           string
+		expectedError string
+	}{
+		{name: "Existing ref", ref: "HEAD"},
+		{name: "Ref parsed as an option is rejected", ref: "--output=" + filepath.Join(dir, "out"), expectedError: "invalid git ref"},
+		{name: "Short option is rejected", ref: "-p", expectedError: "invalid git ref"},
+		{name: "Empty ref is rejected", ref: "", expectedError: "invalid git ref"},
+		{name: "Unknown ref", ref: "no-such-branch", expectedError: "unknown git ref"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := NewGitDiffReader().ReadChanges(tt.ref)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadChanges failed: %v", err)
+			}
+			if !changes.Overlaps("main.go", 4, 4) {
+				t.Errorf("Expected line 4 of main.go to be changed")
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "out")); err == nil {
+		t.Errorf("Expected the rejected ref not to reach git")
+	}
+}
@@ -0,0 +1,295 @@
+package adapters
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+// GitHistoryReader implements the ChangeHistoryReader interface with the local git CLI.
+// It runs one "git log -p -U0" per repository and keeps only the hunk headers, so no
+// file contents are held in memory. Merges are skipped and renames break the history.
+type GitHistoryReader struct {
+	git string
+}
+
+// NewGitHistoryReader creates a history reader that runs git from the PATH
+func NewGitHistoryReader() *GitHistoryReader {
+	return &GitHistoryReader{git: "git"}
+}
+
+// commitHeader starts each commit in the log output: NUL, hash, NUL, author email, NUL, author time
+const commitHeader = "%x00%H%x00%ae%x00%at"
+
+// ReadHistory returns the revisions of each file since the given time, newest first.
+// Files outside any git repository have no history
+func (r *GitHistoryReader) ReadHistory(filePaths []string, since string) (map[string][]valueobjects.FileRevision, error) {
+	repositories, err := r.groupByRepository(filePaths)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(map[string][]valueobjects.FileRevision)
+	for root, files := range repositories {
+		if err := r.readRepository(root, files, since, history); err != nil {
+			return nil, err
+		}
+	}
+	return history, nil
+}
+
+// groupByRepository maps each repository root to its files, keyed by slash-separated path
+// relative to the root and valued by the path as given
+func (r *GitHistoryReader) groupByRepository(filePaths []string) (map[string]map[string]string, error) {
+	roots := make(map[string]string)
+	repositories := make(map[string]map[string]string)
+
+	for _, path := range filePaths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		dir := filepath.Dir(abs)
+		root, ok := roots[dir]
+		if !ok {
+			output, err := exec.Command(r.git, "-C", dir, "rev-parse", "--show-toplevel").Output()
+			if err == nil {
+				root = strings.TrimSpace(string(output))
+			}
+			roots[dir] = root
+		}
+		if root == "" {
+			continue
+		}
+
+		// The toplevel is reported with symlinks resolved
+		resolved, err := filepath.EvalSymlinks(abs)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(root, resolved)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+
+		if repositories[root] == nil {
+			repositories[root] = make(map[string]string)
+		}
+		repositories[root][filepath.ToSlash(rel)] = path
+	}
+
+	return repositories, nil
+}
+
+// readRepository reads the log of the directories holding files and adds their revisions to history
+func (r *GitHistoryReader) readRepository(root string, files map[string]string, since string, history map[string][]valueobjects.FileRevision) error {
+	args := []string{"-C", root, "-c", "core.quotepath=off", "log", "--no-merges", "--no-renames", "--no-color",
+		"-p", "-U0", "--format=" + commitHeader}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	args = append(args, "--")
+	args = append(args, pathspecs(files)...)
+
+	cmd := exec.Command(r.git, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run git: %w", err)
+	}
+
+	parseErr := parseGitLog(stdout, files, history)
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git log failed in %s: %v: %s", root, err, strings.TrimSpace(stderr.String()))
+	}
+	return parseErr
+}
+
+// pathspecs returns the smallest set of directories that contains all files
+func pathspecs(files map[string]string) []string {
+	dirs := make([]string, 0, len(files))
+	for rel := range files {
+		dirs = append(dirs, pathDir(rel))
+	}
+	sort.Strings(dirs)
+
+	var specs []string
+	for _, dir := range dirs {
+		if n := len(specs); n > 0 && (specs[n-1] == "." || dir == specs[n-1] || strings.HasPrefix(dir, specs[n-1]+"/")) {
+			continue
+		}
+		specs = append(specs, dir)
+	}
+	return specs
+}
+
+// pathDir returns the directory of a slash-separated relative path, "." at the top
+func pathDir(rel string) string {
+	if i := strings.LastIndex(rel, "/"); i >= 0 {
+		return rel[:i]
+	}
+	return "."
+}
+
+// gitLogParser collects file revisions from "git log -p -U0" output
+type gitLogParser struct {
+	files   map[string]string
+	history map[string][]valueobjects.FileRevision
+	ended   map[string]bool
+
+	commit  string
+	author  string
+	time    time.Time
+	path    string
+	created bool
+	deleted bool
+	hunks   []valueobjects.DiffHunk
+}
+
+// parseGitLog parses log output into history. Once a file's creation or deletion is seen,
+// its older revisions are ignored, since they belong to code that no longer exists
+func parseGitLog(reader io.Reader, files map[string]string, history map[string][]valueobjects.FileRevision) error {
+	p := &gitLogParser{files: files, history: history, ended: make(map[string]bool)}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	skip := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Hunk bodies are skipped by count, since their lines may look like headers
+		if skip > 0 {
+			if !strings.HasPrefix(line, `\`) {
+				skip--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "\x00"):
+			if err := p.startCommit(line); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "diff --git "):
+			if err := p.flushFile(); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "--- "):
+			p.created = line == "--- /dev/null"
+			if !p.created {
+				p.path = strings.TrimPrefix(line[4:], "a/")
+			}
+		case strings.HasPrefix(line, "+++ "):
+			p.deleted = line == "+++ /dev/null"
+			if !p.deleted {
+				p.path = strings.TrimPrefix(line[4:], "b/")
+			}
+		case strings.HasPrefix(line, "@@ "):
+			hunk, err := parseHunkHeader(line)
+			if err != nil {
+				return err
+			}
+			p.hunks = append(p.hunks, hunk)
+			skip = hunk.OldLines() + hunk.NewLines()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return p.flushFile()
+}
+
+// startCommit finishes the previous commit's last file and reads a commit header line
+func (p *gitLogParser) startCommit(line string) error {
+	if err := p.flushFile(); err != nil {
+		return err
+	}
+
+	fields := strings.Split(line[1:], "\x00")
+	if len(fields) != 3 {
+		return fmt.Errorf("unexpected git log header %q", line)
+	}
+	seconds, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("unexpected commit time %q: %w", fields[2], err)
+	}
+
+	p.commit = fields[0]
+	p.author = fields[1]
+	p.time = time.Unix(seconds, 0)
+	return nil
+}
+
+// flushFile records the revision of the file whose diff was just read
+func (p *gitLogParser) flushFile() error {
+	path, ok := p.files[p.path]
+	created, deleted, hunks := p.created, p.deleted, p.hunks
+	p.path, p.created, p.deleted, p.hunks = "", false, false, nil
+
+	if !ok || p.ended[path] {
+		return nil
+	}
+	if deleted || created {
+		p.ended[path] = true
+	}
+	if deleted {
+		return nil
+	}
+
+	revision, err := valueobjects.NewFileRevision(p.commit, p.author, p.time, hunks, created)
+	if err != nil {
+		return err
+	}
+	p.history[path] = append(p.history[path], revision)
+	return nil
+}
+
+// parseHunkHeader parses "@@ -a[,b] +c[,d] @@ ..."
+func parseHunkHeader(line string) (valueobjects.DiffHunk, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return valueobjects.DiffHunk{}, fmt.Errorf("unexpected hunk header %q", line)
+	}
+
+	oldStart, oldLines, err := parseHunkRange(fields[1][1:])
+	if err != nil {
+		return valueobjects.DiffHunk{}, fmt.Errorf("unexpected hunk header %q: %w", line, err)
+	}
+	newStart, newLines, err := parseHunkRange(fields[2][1:])
+	if err != nil {
+		return valueobjects.DiffHunk{}, fmt.Errorf("unexpected hunk header %q: %w", line, err)
+	}
+
+	return valueobjects.NewDiffHunk(oldStart, oldLines, newStart, newLines)
+}
+
+// parseHunkRange parses "start[,count]"; a missing count means one line
+func parseHunkRange(text string) (int, int, error) {
+	start, count, found := strings.Cut(text, ",")
+	first, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return first, 1, nil
+	}
+	lines, err := strconv.Atoi(count)
+	if err != nil {
+		return 0, 0, err
+	}
+	return first, lines, nil
+}
@@ -0,0 +1,281 @@
+package adapters
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"goastanalyzer/domain/services"
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestParseGitLog(t *testing.T) {
+	// Newest first: bob edits main.go, alice creates it, and an older main.go was deleted before.
+	// The first hunk body holds lines that look like diff headers
+	log := strings.Join([]string{
+		"\x00c3\x00bob@example.com\x001700000200",
+		"",
+		"diff --git a/main.go b/main.go",
+		"index 1111111..2222222 100644",
+		"--- a/main.go",
+		"+++ b/main.go",
+		"@@ -3,0 +4,2 @@ func main() {",
+		"+--- a/fake.go",
+		"+@@ -1 +1 @@",
+		"@@ -5 +7 @@",
+		"-}",
+		`\ No newline at end of file`,
+		"+}",
+		"diff --git a/other.go b/other.go",
+		"--- a/other.go",
+		"+++ b/other.go",
+		"@@ -1 +1 @@",
+		"-package other",
+		"+package another",
+		"\x00c2\x00alice@example.com\x001700000100",
+		"",
+		"diff --git a/main.go b/main.go",
+		"new file mode 100644",
+		"--- /dev/null",
+		"+++ b/main.go",
+		"@@ -0,0 +1,5 @@",
+		"+package main",
+		"+",
+		"+func main() {",
+		"+",
+		"+}",
+		"\x00c1\x00carol@example.com\x001700000000",
+		"",
+		"diff --git a/main.go b/main.go",
+		"deleted file mode 100644",
+		"--- a/main.go",
+		"+++ /dev/null",
+		"@@ -1 +0,0 @@",
+		"-package old",
+		"",
+	}, "\n")
+
+	history := make(map[string][]valueobjects.FileRevision)
+	files := map[string]string{"main.go": "src/main.go"}
+	if err := parseGitLog(strings.NewReader(log), files, history); err != nil {
+		t.Fatalf("parseGitLog failed: %v", err)
+	}
+
+	if len(history) != 1 {
+		t.Fatalf("Expected history for main.go only, got %v", history)
+	}
+	revisions := history["src/main.go"]
+	if len(revisions) != 2 {
+		t.Fatalf("Expected 2 revisions after the deletion, got %v", revisions)
+	}
+
+	edit, creation := revisions[0], revisions[1]
+	if edit.Commit() != "c3" || edit.Author() != "bob@example.com" || !edit.Time().Equal(time.Unix(1700000200, 0)) || edit.Created() {
+		t.Errorf("Unexpected edit revision: %s by %s at %v", edit.Commit(), edit.Author(), edit.Time())
+	}
+	if got := hunkHeaders(edit.Hunks()); !reflect.DeepEqual(got, [][4]int{{3, 0, 4, 2}, {5, 1, 7, 1}}) {
+		t.Errorf("Unexpected hunks %v", got)
+	}
+	if creation.Commit() != "c2" || creation.Author() != "alice@example.com" || !creation.Created() {
+		t.Errorf("Unexpected creation revision: %s by %s", creation.Commit(), creation.Author())
+	}
+	if got := hunkHeaders(creation.Hunks()); !reflect.DeepEqual(got, [][4]int{{0, 0, 1, 5}}) {
+		t.Errorf("Unexpected hunks %v", got)
+	}
+}
+
+func TestParseGitLog_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		log           string
+		expectedError string
+	}{
+		{name: "Missing header field", log: "\x00c1\x00alice@example.com\n", expectedError: "unexpected git log header"},
+		{name: "Invalid commit time", log: "\x00c1\x00alice@example.com\x00yesterday\n", expectedError: "unexpected commit time"},
+		{name: "Invalid hunk header", log: "\x00c1\x00a\x001\n--- a/main.go\n+++ b/main.go\n@@ -x +1 @@\n", expectedError: "unexpected hunk header"},
+		{name: "Truncated hunk header", log: "\x00c1\x00a\x001\n--- a/main.go\n+++ b/main.go\n@@ -1\n", expectedError: "unexpected hunk header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := make(map[string][]valueobjects.FileRevision)
+			err := parseGitLog(strings.NewReader(tt.log), map[string]string{"main.go": "main.go"}, history)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestGitHistoryReader_ReadHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	parse := filepath.Join(dir, "parse.go")
+	format := filepath.Join(dir, "format.go")
+	old := filepath.Join(dir, "old.go")
+
+	writeFile(t, parse, "package main\n\nfunc parse() {}\n")
+	writeFile(t, old, "package main\n\nfunc format() {}\n")
+	commitAs(t, dir, "alice", "2020-01-01T12:00:00Z", "initial")
+
+	writeFile(t, parse, "package main\n\nfunc parse() {\n\tprintln()\n}\n")
+	commitAs(t, dir, "bob", "2024-01-01T12:00:00Z", "print")
+
+	runGit(t, dir, "mv", "old.go", "format.go")
+	commitAs(t, dir, "bob", "2024-02-01T12:00:00Z", "rename")
+
+	writeFile(t, parse, "package main\n\nfunc parse() {\n\tprintln(1)\n}\n")
+	writeFile(t, format, "package main\n\nfunc format() {\n\tprintln()\n}\n")
+	commitAs(t, dir, "carol", "2024-03-01T12:00:00Z", "edit both")
+
+	writeFile(t, parse, "package main\n\nfunc parse() {\n\tprintln(2)\n}\n")
+	commitAs(t, dir, "bob", "2024-04-01T12:00:00Z", "edit parse")
+
+	reader := NewGitHistoryReader()
+	tests := []struct {
+		name            string
+		since           string
+		expectedAuthors map[string][]string
+	}{
+		{
+			name:  "Whole history",
+			since: "",
+			expectedAuthors: map[string][]string{
+				parse: {"bob", "carol", "bob", "alice"},
+				// The rename creates format.go, so the history of old.go is not followed
+				format: {"carol", "bob"},
+			},
+		},
+		{
+			name:  "Time window",
+			since: "2023-06-01",
+			expectedAuthors: map[string][]string{
+				parse:  {"bob", "carol", "bob"},
+				format: {"carol", "bob"},
+			},
+		},
+		{
+			name:            "Window without commits",
+			since:           "2025-01-01",
+			expectedAuthors: map[string][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history, err := reader.ReadHistory([]string{parse, format}, tt.since)
+			if err != nil {
+				t.Fatalf("ReadHistory failed: %v", err)
+			}
+
+			authors := make(map[string][]string)
+			for path, revisions := range history {
+				for _, revision := range revisions {
+					authors[path] = append(authors[path], strings.TrimSuffix(revision.Author(), "@example.com"))
+				}
+			}
+			if !reflect.DeepEqual(authors, tt.expectedAuthors) {
+				t.Errorf("Expected authors %v, got %v", tt.expectedAuthors, authors)
+			}
+		})
+	}
+
+	// parse changed more often and is more complex, so it ranks above format
+	history, err := reader.ReadHistory([]string{parse, format}, "2023-06-01")
+	if err != nil {
+		t.Fatalf("ReadHistory failed: %v", err)
+	}
+	functions := []valueobjects.FunctionMetrics{
+		testHotspotMetrics(t, "parse", parse, 4),
+		testHotspotMetrics(t, "format", format, 1),
+	}
+	hotspots, err := services.NewChurnHotspotRanker().RankHotspots(history, functions)
+	if err != nil {
+		t.Fatalf("RankHotspots failed: %v", err)
+	}
+
+	var ranking []string
+	for _, hotspot := range hotspots {
+		if hotspot.Kind() == valueobjects.HotspotKindFunction {
+			ranking = append(ranking, hotspot.String())
+		}
+	}
+	expected := []string{
+		"function parse: score=12 (revisions=3, authors=2, complexity=4)",
+		"function format: score=2 (revisions=2, authors=2, complexity=1)",
+	}
+	if !reflect.DeepEqual(ranking, expected) {
+		t.Errorf("Expected ranking %v, got %v", expected, ranking)
+	}
+
+	outside := filepath.Join(t.TempDir(), "main.go")
+	writeFile(t, outside, "package main\n")
+	if history, err := reader.ReadHistory([]string{outside}, ""); err != nil || len(history) != 0 {
+		t.Errorf("Expected no history outside a repository, got %v, %v", history, err)
+	}
+}
+
+// commitAs stages everything in dir and commits it as author at the given time
+func commitAs(t *testing.T, dir, author, when, message string) {
+	t.Helper()
+
+	runGit(t, dir, "add", "-A")
+	cmd := exec.Command("git", "-C", dir, "-c", "user.name="+author, "-c", "user.email="+author+"@example.com",
+		"commit", "-q", "-m", message)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+when, "GIT_COMMITTER_DATE="+when)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit failed: %v: %s", err, output)
+	}
+}
+
+// hunkHeaders returns hunks as {oldStart, oldLines, newStart, newLines}
+func hunkHeaders(hunks []valueobjects.DiffHunk) [][4]int {
+	headers := make([][4]int, 0, len(hunks))
+	for _, hunk := range hunks {
+		headers = append(headers, [4]int{hunk.OldStart(), hunk.OldLines(), hunk.NewStart(), hunk.NewLines()})
+	}
+	return headers
+}
+
+// testHotspotMetrics creates metrics for a function spanning lines 3-5 of file
+func testHotspotMetrics(t *testing.T, name, file string, cyclomatic int) valueobjects.FunctionMetrics {
+	t.Helper()
+
+	location, _ := valueobjects.NewSourceLocation(file, 3, 1)
+	complexity, _ := valueobjects.NewComplexityScore(cyclomatic, 0)
+	halstead, _ := valueobjects.NewHalsteadMetrics(1, 1, 1, 1)
+	lines, _ := valueobjects.NewLinesOfCode(3, 3, 0)
+
+	metrics, err := valueobjects.NewFunctionMetrics(name, location, complexity, halstead, lines)
+	if err != nil {
+		t.Fatalf("Failed to create function metrics: %v", err)
+	}
+	return metrics
+}
+
+// runGit runs a git command in dir with a fixed identity
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, output)
+	}
+}
+
+// writeFile writes content to path
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
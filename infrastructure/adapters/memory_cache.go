@@ -0,0 +1,56 @@
+package adapters
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+
+	"goastanalyzer/application/usecases"
+)
+
+// MemoryCache implements the AnalysisCache interface in memory, keeping analyses incremental
+// within one process when no cache directory may be written. Only the entries of the last run
+// are kept, so edits that change every key do not grow the cache
+type MemoryCache struct {
+	entries  map[string]*usecases.CachedAnalysis
+	previous map[string]*usecases.CachedAnalysis
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]*usecases.CachedAnalysis)}
+}
+
+// StartRun begins another analysis, dropping the entries the previous run neither loaded nor stored
+func (c *MemoryCache) StartRun() {
+	c.previous = c.entries
+	c.entries = make(map[string]*usecases.CachedAnalysis)
+}
+
+// HashFile returns the SHA-256 of a file's content
+func (c *MemoryCache) HashFile(filePath string) (string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Load returns the entry stored under key, keeping it for the next run
+func (c *MemoryCache) Load(key string) (*usecases.CachedAnalysis, bool) {
+	if entry, ok := c.entries[key]; ok {
+		return entry, true
+	}
+	entry, ok := c.previous[key]
+	if ok {
+		c.entries[key] = entry
+	}
+	return entry, ok
+}
+
+// Store keeps an entry under key
+func (c *MemoryCache) Store(key string, entry *usecases.CachedAnalysis) error {
+	c.entries[key] = entry
+	return nil
+}
@@ -0,0 +1,50 @@
+package adapters
+
+import (
+	"testing"
+
+	"goastanalyzer/application/usecases"
+)
+
+func TestMemoryCache_StartRun(t *testing.T) {
+	cache := NewMemoryCache()
+	entry := &usecases.CachedAnalysis{PackageName: "main"}
+
+	cache.StartRun()
+	for _, key := range []string{"loaded", "stored", "untouched"} {
+		if err := cache.Store(key, entry); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+	if loaded, ok := cache.Load("stored"); !ok || loaded != entry {
+		t.Errorf("Expected an entry stored in this run to load")
+	}
+
+	// The second run touches only two of the entries
+	cache.StartRun()
+	if _, ok := cache.Load("loaded"); !ok {
+		t.Errorf("Expected an entry of the previous run to load")
+	}
+	if err := cache.Store("stored", entry); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	cache.StartRun()
+	if len(cache.previous) != 2 {
+		t.Errorf("Expected the 2 entries of the last run to be held, got %d", len(cache.previous))
+	}
+	for key, expected := range map[string]bool{"loaded": true, "stored": true, "untouched": false} {
+		if _, ok := cache.Load(key); ok != expected {
+			t.Errorf("Expected %s to be kept: %v", key, expected)
+		}
+	}
+
+	// Entries stored before the first run starts load too
+	unbounded := NewMemoryCache()
+	if err := unbounded.Store("key", entry); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if _, ok := unbounded.Load("key"); !ok {
+		t.Errorf("Expected a stored entry to load")
+	}
+}
@@ -0,0 +1,292 @@
+package adapters
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"goastanalyzer/domain/services"
+)
+
+// GoPackageLoader implements the PackageLoader interface using go/types.
+// Imports that belong to the module being analyzed are type-checked from
+// source so detectors can follow calls into them; all other imports are
+// resolved from compiler export data.
+type GoPackageLoader struct {
+	exportImporter types.Importer
+	fset           *token.FileSet
+	modules        map[string]*moduleInfo
+	sources        map[string]*sourcePackage
+	builds         map[string]*build.Package
+}
+
+// moduleInfo describes the Go module enclosing an analyzed directory
+type moduleInfo struct {
+	root      string
+	path      string
+	goVersion string
+}
+
+// sourcePackage is a dependency package loaded from source
+type sourcePackage struct {
+	pkg        *types.Package
+	files      []*ast.File
+	info       *types.Info
+	typeErrors bool
+}
+
+// NewGoPackageLoader creates a new package loader
+func NewGoPackageLoader() *GoPackageLoader {
+	return &GoPackageLoader{
+		exportImporter: importer.Default(),
+		modules:        make(map[string]*moduleInfo),
+		sources:        make(map[string]*sourcePackage),
+		builds:         make(map[string]*build.Package),
+	}
+}
+
+// LoadPackage type-checks the files of one package and returns its context.
+// Type errors are tolerated: detectors fall back to syntax where information is missing.
+func (l *GoPackageLoader) LoadPackage(fset *token.FileSet, files []*ast.File) (*services.PackageContext, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files to load")
+	}
+
+	// Cached dependency syntax is only valid for the file set it was parsed into
+	if l.fset != fset {
+		l.fset = fset
+		l.sources = make(map[string]*sourcePackage)
+	}
+
+	dir := filepath.Dir(fset.Position(files[0].Pos()).Filename)
+	module := l.findModule(dir)
+
+	info := newTypesInfo()
+	config := types.Config{
+		Importer:    &moduleImporter{loader: l, module: module},
+		Error:       func(error) {},
+		FakeImportC: true,
+	}
+	pkg, err := config.Check(l.importPath(module, dir, files[0].Name.Name), fset, files, info)
+
+	context := services.NewPackageContext(fset, files, info)
+	context.SetTypeErrors(err != nil)
+	if module != nil {
+		context.SetGoVersion(module.goVersion)
+	}
+	if pkg != nil {
+		for _, imported := range pkg.Imports() {
+			if source := l.sources[imported.Path()]; source != nil {
+				context.AddDependency(source.files, source.info, source.typeErrors)
+			}
+		}
+	}
+
+	return context, nil
+}
+
+// DependencyFiles returns the go.mod of the module enclosing dir and the source files of the
+// module-local packages that the package in dir and its tests import, directly or through each
+// other; these are the files LoadPackage type-checks from source
+func (l *GoPackageLoader) DependencyFiles(dir string) ([]string, error) {
+	module := l.findModule(dir)
+	if module == nil {
+		return nil, nil
+	}
+
+	root, err := l.buildPackage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{filepath.Join(module.root, "go.mod")}
+	visited := make(map[string]bool)
+	var visit func(imports []string) error
+	visit = func(imports []string) error {
+		for _, importPath := range imports {
+			if visited[importPath] || (importPath != module.path && !strings.HasPrefix(importPath, module.path+"/")) {
+				continue
+			}
+			visited[importPath] = true
+
+			rel := strings.TrimPrefix(strings.TrimPrefix(importPath, module.path), "/")
+			depDir := filepath.Join(module.root, filepath.FromSlash(rel))
+			buildPkg, err := l.buildPackage(depDir)
+			if err != nil {
+				return err
+			}
+			for _, name := range buildPkg.GoFiles {
+				files = append(files, filepath.Join(depDir, name))
+			}
+			if err := visit(buildPkg.Imports); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	imports := append(append(append([]string(nil), root.Imports...), root.TestImports...), root.XTestImports...)
+	if err := visit(imports); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// buildPackage reads the file list and imports of the package in dir, caching the result per directory
+func (l *GoPackageLoader) buildPackage(dir string) (*build.Package, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if buildPkg, ok := l.builds[absDir]; ok {
+		return buildPkg, nil
+	}
+
+	buildPkg, err := build.Default.ImportDir(absDir, 0)
+	if err != nil {
+		return nil, err
+	}
+	l.builds[absDir] = buildPkg
+	return buildPkg, nil
+}
+
+// importPath derives the import path of a directory from its enclosing module
+func (l *GoPackageLoader) importPath(module *moduleInfo, dir, name string) string {
+	if module == nil {
+		return name
+	}
+
+	rel, err := filepath.Rel(module.root, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return name
+	}
+	if rel == "." {
+		return module.path
+	}
+	return path.Join(module.path, filepath.ToSlash(rel))
+}
+
+// findModule locates the go.mod enclosing dir, caching the result per directory
+func (l *GoPackageLoader) findModule(dir string) *moduleInfo {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+
+	if module, ok := l.modules[absDir]; ok {
+		return module
+	}
+
+	var module *moduleInfo
+	if content, err := os.Open(filepath.Join(absDir, "go.mod")); err == nil {
+		module = parseModuleFile(absDir, content)
+		content.Close()
+	} else if parent := filepath.Dir(absDir); parent != absDir {
+		module = l.findModule(parent)
+	}
+
+	l.modules[absDir] = module
+	return module
+}
+
+// parseModuleFile reads the module path and go version from a go.mod file
+func parseModuleFile(root string, content io.Reader) *moduleInfo {
+	module := &moduleInfo{root: root}
+
+	scanner := bufio.NewScanner(content)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			module.path = strings.Trim(fields[1], `"`)
+		case "go":
+			module.goVersion = fields[1]
+		}
+	}
+
+	if module.path == "" {
+		return nil
+	}
+	return module
+}
+
+// loadSource parses and type-checks a module-local package from its directory
+func (l *GoPackageLoader) loadSource(module *moduleInfo, importPath string) (*types.Package, error) {
+	if source, ok := l.sources[importPath]; ok {
+		if source == nil {
+			return nil, fmt.Errorf("import cycle through %s", importPath)
+		}
+		return source.pkg, nil
+	}
+	l.sources[importPath] = nil
+
+	rel := strings.TrimPrefix(strings.TrimPrefix(importPath, module.path), "/")
+	dir := filepath.Join(module.root, filepath.FromSlash(rel))
+
+	buildPkg, err := build.Default.ImportDir(dir, 0)
+	if err != nil {
+		delete(l.sources, importPath)
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, name := range buildPkg.GoFiles {
+		file, err := parser.ParseFile(l.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			delete(l.sources, importPath)
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	info := newTypesInfo()
+	config := types.Config{
+		Importer:    &moduleImporter{loader: l, module: module},
+		Error:       func(error) {},
+		FakeImportC: true,
+	}
+	pkg, err := config.Check(importPath, l.fset, files, info)
+
+	l.sources[importPath] = &sourcePackage{pkg: pkg, files: files, info: info, typeErrors: err != nil}
+	return pkg, nil
+}
+
+// moduleImporter resolves imports for packages of a single module
+type moduleImporter struct {
+	loader *GoPackageLoader
+	module *moduleInfo
+}
+
+// Import loads module-local packages from source and everything else from export data
+func (mi *moduleImporter) Import(importPath string) (*types.Package, error) {
+	if mi.module != nil && (importPath == mi.module.path || strings.HasPrefix(importPath, mi.module.path+"/")) {
+		return mi.loader.loadSource(mi.module, importPath)
+	}
+	return mi.loader.exportImporter.Import(importPath)
+}
+
+// newTypesInfo allocates a types.Info recording everything detectors may query
+func newTypesInfo() *types.Info {
+	return &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
+		Instances:  make(map[*ast.Ident]types.Instance),
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
 
 	"goastanalyzer/domain/valueobjects"
@@ -10,20 +11,42 @@ import (
 // Config holds application configuration
 type Config struct {
 	Analysis valueobjects.AnalysisConfiguration
+	// CacheDir is where analysis results are cached between runs; empty disables the cache
+	CacheDir string
 }
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() Config {
 	return Config{
 		Analysis: loadAnalysisConfig(),
+		CacheDir: loadCacheDir(),
 	}
 }
 
+// loadCacheDir returns GOAST_CACHE_DIR, or a directory in the user's cache directory.
+// As with GOCACHE, the value "off" disables the cache
+func loadCacheDir() string {
+	if dir := os.Getenv("GOAST_CACHE_DIR"); dir != "" {
+		if dir == "off" {
+			return ""
+		}
+		return dir
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "goastanalyzer")
+}
+
 // loadAnalysisConfig loads analysis configuration from environment
 func loadAnalysisConfig() valueobjects.AnalysisConfiguration {
-	config := valueobjects.DefaultAnalysisConfiguration()
+	return applyEnvironment(valueobjects.DefaultAnalysisConfiguration())
+}
 
-	// Override with environment variables if present
+// applyEnvironment overrides config with environment variables if present
+func applyEnvironment(config valueobjects.AnalysisConfiguration) valueobjects.AnalysisConfiguration {
 	if maxCyclo := getEnvInt("GOAST_MAX_CYCLOMATIC", 0); maxCyclo > 0 {
 		config = config.WithMaxCyclomaticComplexity(maxCyclo)
 	}
@@ -36,6 +59,34 @@ func loadAnalysisConfig() valueobjects.AnalysisConfiguration {
 		config = config.WithMaxFunctionLength(maxLen)
 	}
 
+	if minTokens := getEnvInt("GOAST_MIN_CLONE_TOKENS", 0); minTokens > 0 {
+		config = config.WithMinCloneTokens(minTokens)
+	}
+
+	if maxNPath := getEnvInt("GOAST_MAX_NPATH", -1); maxNPath >= 0 {
+		config = config.WithMaxNPath(maxNPath)
+	}
+
+	if maxEssential := getEnvInt("GOAST_MAX_ESSENTIAL", -1); maxEssential >= 0 {
+		config = config.WithMaxEssentialComplexity(maxEssential)
+	}
+
+	if maxFields := getEnvInt("GOAST_MAX_STRUCT_FIELDS", 0); maxFields > 0 {
+		config = config.WithMaxStructFields(maxFields)
+	}
+
+	if maxVolume := getEnvInt("GOAST_MAX_HALSTEAD_VOLUME", -1); maxVolume >= 0 {
+		config = config.WithMaxHalsteadVolume(maxVolume)
+	}
+
+	if maxEffort := getEnvInt("GOAST_MAX_HALSTEAD_EFFORT", -1); maxEffort >= 0 {
+		config = config.WithMaxHalsteadEffort(maxEffort)
+	}
+
+	if minIndex := getEnvInt("GOAST_MIN_MAINTAINABILITY", -1); minIndex >= 0 {
+		config = config.WithMinMaintainabilityIndex(minIndex)
+	}
+
 	if smellDetect := getEnvBool("GOAST_ENABLE_SMELL_DETECTION", true); !smellDetect {
 		config = config.WithSmellDetection(smellDetect)
 	}
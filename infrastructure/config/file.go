@@ -0,0 +1,173 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+// DefaultConfigFile is read from the working directory when no configuration file is given
+const DefaultConfigFile = ".goastanalyzer.json"
+
+// fileConfig mirrors the JSON configuration file
+type fileConfig struct {
+	MaxCyclomatic      *int             `json:"max_cyclomatic"`
+	MaxCognitive       *int             `json:"max_cognitive"`
+	MaxFunctionLength  *int             `json:"max_function_length"`
+	MaxNPath           *int             `json:"max_npath"`
+	MaxEssential       *int             `json:"max_essential"`
+	MaxStructFields    *int             `json:"max_struct_fields"`
+	MinCloneTokens     *int             `json:"min_clone_tokens"`
+	MaxHalsteadVolume  *int             `json:"max_halstead_volume"`
+	MaxHalsteadEffort  *int             `json:"max_halstead_effort"`
+	MinMaintainability *int             `json:"min_maintainability"`
+	SmellDetection     *bool            `json:"smell_detection"`
+	Taint              *taintFileConfig `json:"taint"`
+}
+
+// taintFileConfig lists taint rules; they extend the built-in rules unless replace_defaults is set
+type taintFileConfig struct {
+	ReplaceDefaults bool              `json:"replace_defaults"`
+	Sources         []taintRuleConfig `json:"sources"`
+	Sanitizers      []string          `json:"sanitizers"`
+	Sinks           []taintRuleConfig `json:"sinks"`
+}
+
+// taintRuleConfig describes a single source or sink
+type taintRuleConfig struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Args []int  `json:"args"`
+}
+
+// LoadConfigFile loads configuration from a JSON file; environment variables still take precedence
+func LoadConfigFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var file fileConfig
+	if err := decoder.Decode(&file); err != nil {
+		return Config{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	analysis, err := file.apply(valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return Config{
+		Analysis: applyEnvironment(analysis),
+		CacheDir: loadCacheDir(),
+	}, nil
+}
+
+// apply overrides config with the settings present in the file
+func (f fileConfig) apply(config valueobjects.AnalysisConfiguration) (valueobjects.AnalysisConfiguration, error) {
+	if f.MaxCyclomatic != nil {
+		if *f.MaxCyclomatic < 1 {
+			return config, fmt.Errorf("max_cyclomatic must be >= 1, got %d", *f.MaxCyclomatic)
+		}
+		config = config.WithMaxCyclomaticComplexity(*f.MaxCyclomatic)
+	}
+	if f.MaxCognitive != nil {
+		if *f.MaxCognitive < 0 {
+			return config, fmt.Errorf("max_cognitive must be >= 0, got %d", *f.MaxCognitive)
+		}
+		config = config.WithMaxCognitiveComplexity(*f.MaxCognitive)
+	}
+	if f.MaxFunctionLength != nil {
+		if *f.MaxFunctionLength < 1 {
+			return config, fmt.Errorf("max_function_length must be >= 1, got %d", *f.MaxFunctionLength)
+		}
+		config = config.WithMaxFunctionLength(*f.MaxFunctionLength)
+	}
+	if f.MaxNPath != nil {
+		if *f.MaxNPath < 0 {
+			return config, fmt.Errorf("max_npath must be >= 0, got %d", *f.MaxNPath)
+		}
+		config = config.WithMaxNPath(*f.MaxNPath)
+	}
+	if f.MaxEssential != nil {
+		if *f.MaxEssential < 0 {
+			return config, fmt.Errorf("max_essential must be >= 0, got %d", *f.MaxEssential)
+		}
+		config = config.WithMaxEssentialComplexity(*f.MaxEssential)
+	}
+	if f.MaxStructFields != nil {
+		if *f.MaxStructFields < 1 {
+			return config, fmt.Errorf("max_struct_fields must be >= 1, got %d", *f.MaxStructFields)
+		}
+		config = config.WithMaxStructFields(*f.MaxStructFields)
+	}
+	if f.MinCloneTokens != nil {
+		if *f.MinCloneTokens < 1 {
+			return config, fmt.Errorf("min_clone_tokens must be >= 1, got %d", *f.MinCloneTokens)
+		}
+		config = config.WithMinCloneTokens(*f.MinCloneTokens)
+	}
+	if f.MaxHalsteadVolume != nil {
+		if *f.MaxHalsteadVolume < 0 {
+			return config, fmt.Errorf("max_halstead_volume must be >= 0, got %d", *f.MaxHalsteadVolume)
+		}
+		config = config.WithMaxHalsteadVolume(*f.MaxHalsteadVolume)
+	}
+	if f.MaxHalsteadEffort != nil {
+		if *f.MaxHalsteadEffort < 0 {
+			return config, fmt.Errorf("max_halstead_effort must be >= 0, got %d", *f.MaxHalsteadEffort)
+		}
+		config = config.WithMaxHalsteadEffort(*f.MaxHalsteadEffort)
+	}
+	if f.MinMaintainability != nil {
+		if *f.MinMaintainability < 0 || *f.MinMaintainability > 100 {
+			return config, fmt.Errorf("min_maintainability must be between 0 and 100, got %d", *f.MinMaintainability)
+		}
+		config = config.WithMinMaintainabilityIndex(*f.MinMaintainability)
+	}
+	if f.SmellDetection != nil {
+		config = config.WithSmellDetection(*f.SmellDetection)
+	}
+
+	if f.Taint != nil {
+		taint, err := f.Taint.build()
+		if err != nil {
+			return config, err
+		}
+		if !f.Taint.ReplaceDefaults {
+			taint = config.TaintConfiguration().Merge(taint)
+		}
+		config = config.WithTaintConfiguration(taint)
+	}
+
+	return config, nil
+}
+
+// build converts the taint section into a taint configuration
+func (t taintFileConfig) build() (valueobjects.TaintConfiguration, error) {
+	var sources []valueobjects.TaintSource
+	for _, rule := range t.Sources {
+		source, err := valueobjects.NewTaintSource(rule.Name, rule.Kind)
+		if err != nil {
+			return valueobjects.TaintConfiguration{}, err
+		}
+		sources = append(sources, source)
+	}
+
+	var sinks []valueobjects.TaintSink
+	for _, rule := range t.Sinks {
+		sink, err := valueobjects.NewTaintSink(rule.Name, rule.Kind, rule.Args)
+		if err != nil {
+			return valueobjects.TaintConfiguration{}, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return valueobjects.NewTaintConfiguration(sources, t.Sanitizers, sinks), nil
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goastanalyzer/domain/valueobjects"
+)
+
+func TestLoadConfigFile_TaintRules(t *testing.T) {
+	defaults := valueobjects.DefaultTaintConfiguration()
+
+	tests := []struct {
+		name               string
+		content            string
+		expectedSources    int
+		expectedSinks      int
+		expectedSanitizers int
+		expectedSource     string
+		expectedSink       string
+		expectedSanitizer  string
+	}{
+		{
+			name: "Custom rules extend the defaults",
+			content: `{
+	"taint": {
+		"sources": [{"name": "example.com/app.Request.Param", "kind": "request"}],
+		"sanitizers": ["(*example.com/app.Escaper).Escape"],
+		"sinks": [{"name": "example.com/app.Store.Raw", "kind": "sql", "args": [1]}]
+	}
+}`,
+			expectedSources:    len(defaults.Sources()) + 1,
+			expectedSinks:      len(defaults.Sinks()) + 1,
+			expectedSanitizers: len(defaults.Sanitizers()) + 1,
+			expectedSource:     "example.com/app.Request.Param",
+			expectedSink:       "example.com/app.Store.Raw",
+			expectedSanitizer:  "example.com/app.Escaper.Escape",
+		},
+		{
+			name: "Replacing the defaults keeps only custom rules",
+			content: `{
+	"taint": {
+		"replace_defaults": true,
+		"sources": [{"name": "os.Getenv", "kind": "env"}],
+		"sinks": [{"name": "os/exec.Command", "kind": "exec"}]
+	}
+}`,
+			expectedSources:    1,
+			expectedSinks:      1,
+			expectedSanitizers: 0,
+			expectedSource:     "os.Getenv",
+			expectedSink:       "os/exec.Command",
+		},
+		{
+			name:               "Missing taint section keeps the defaults",
+			content:            `{"max_cyclomatic": 12}`,
+			expectedSources:    len(defaults.Sources()),
+			expectedSinks:      len(defaults.Sinks()),
+			expectedSanitizers: len(defaults.Sanitizers()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadConfigFile(writeConfigFile(t, tt.content))
+			if err != nil {
+				t.Fatalf("LoadConfigFile failed: %v", err)
+			}
+			taint := config.Analysis.TaintConfiguration()
+
+			if len(taint.Sources()) != tt.expectedSources {
+				t.Errorf("Expected %d sources, got %d", tt.expectedSources, len(taint.Sources()))
+			}
+			if len(taint.Sinks()) != tt.expectedSinks {
+				t.Errorf("Expected %d sinks, got %d", tt.expectedSinks, len(taint.Sinks()))
+			}
+			if len(taint.Sanitizers()) != tt.expectedSanitizers {
+				t.Errorf("Expected %d sanitizers, got %d", tt.expectedSanitizers, len(taint.Sanitizers()))
+			}
+
+			if tt.expectedSource != "" {
+				found := false
+				for _, source := range taint.Sources() {
+					found = found || source.Name() == tt.expectedSource
+				}
+				if !found {
+					t.Errorf("Expected source %s, but didn't find it", tt.expectedSource)
+				}
+			}
+			if tt.expectedSink != "" {
+				found := false
+				for _, sink := range taint.Sinks() {
+					found = found || sink.Name() == tt.expectedSink
+				}
+				if !found {
+					t.Errorf("Expected sink %s, but didn't find it", tt.expectedSink)
+				}
+			}
+			if tt.expectedSanitizer != "" {
+				found := false
+				for _, sanitizer := range taint.Sanitizers() {
+					found = found || sanitizer == tt.expectedSanitizer
+				}
+				if !found {
+					t.Errorf("Expected sanitizer %s, but didn't find it", tt.expectedSanitizer)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigFile_SinkArguments(t *testing.T) {
+	config, err := LoadConfigFile(writeConfigFile(t, `{
+	"taint": {
+		"replace_defaults": true,
+		"sinks": [{"name": "example.com/app.Store.Raw", "kind": "sql", "args": [1]}]
+	}
+}`))
+	if err != nil {
+		t.Fatalf("LoadConfigFile failed: %v", err)
+	}
+
+	sinks := config.Analysis.TaintConfiguration().Sinks()
+	if len(sinks) != 1 {
+		t.Fatalf("Expected 1 sink, got %d", len(sinks))
+	}
+	if sinks[0].Kind() != "sql" || sinks[0].Accepts(0) || !sinks[0].Accepts(1) {
+		t.Errorf("Expected sql sink checking only argument 1, got kind %s and args %v", sinks[0].Kind(), sinks[0].Args())
+	}
+}
+
+func TestLoadConfigFile_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name:          "Source without kind",
+			content:       `{"taint": {"sources": [{"name": "os.Getenv"}]}}`,
+			expectedError: "taint source os.Getenv needs a kind",
+		},
+		{
+			name:          "Sink without name",
+			content:       `{"taint": {"sinks": [{"kind": "sql"}]}}`,
+			expectedError: "taint sink name cannot be empty",
+		},
+		{
+			name:          "Negative sink argument",
+			content:       `{"taint": {"sinks": [{"name": "os/exec.Command", "kind": "exec", "args": [-1]}]}}`,
+			expectedError: "negative argument index -1",
+		},
+		{
+			name:          "Unknown taint setting",
+			content:       `{"taint": {"source": []}}`,
+			expectedError: "unknown field",
+		},
+		{
+			name:          "Struct field threshold below one",
+			content:       `{"max_struct_fields": 0}`,
+			expectedError: "max_struct_fields must be >= 1",
+		},
+		{
+			name:          "Malformed JSON",
+			content:       `{"taint": `,
+			expectedError: "failed to parse config file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfigFile(writeConfigFile(t, tt.content))
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got none", tt.expectedError)
+			}
+			if !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+// writeConfigFile writes content to a configuration file in a temporary directory
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), DefaultConfigFile)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
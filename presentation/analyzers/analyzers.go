@@ -0,0 +1,323 @@
+// Package analyzers exposes the detectors as go/analysis analyzers, so they run under go vet,
+// gopls and any other driver of the analysis framework
+package analyzers
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/services"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// All returns a fresh instance of every analyzer
+func All() []*analysis.Analyzer {
+	return []*analysis.Analyzer{
+		NewComplexityAnalyzer(),
+		NewSmellAnalyzer(),
+		NewGoroutineLeakAnalyzer(),
+		NewConcurrencyBugAnalyzer(),
+	}
+}
+
+// NewComplexityAnalyzer creates an analyzer reporting functions whose cyclomatic, cognitive,
+// NPath or essential complexity exceeds its flags
+func NewComplexityAnalyzer() *analysis.Analyzer {
+	defaults := valueobjects.DefaultAnalysisConfiguration()
+
+	analyzer := &analysis.Analyzer{
+		Name: "complexity",
+		Doc:  "report functions whose cyclomatic, cognitive, NPath or essential complexity exceeds a threshold",
+	}
+	maxCyclomatic := analyzer.Flags.Int("max-cyclomatic", defaults.MaxCyclomaticComplexity(), "maximum cyclomatic complexity of a function")
+	maxCognitive := analyzer.Flags.Int("max-cognitive", defaults.MaxCognitiveComplexity(), "maximum cognitive complexity of a function")
+	maxNPath := analyzer.Flags.Int("max-npath", defaults.MaxNPath(), "maximum NPath complexity of a function, 0 to disable")
+	maxEssential := analyzer.Flags.Int("max-essential", defaults.MaxEssentialComplexity(), "maximum essential complexity of a function, 0 to disable")
+
+	analyzer.Run = func(pass *analysis.Pass) (interface{}, error) {
+		calculator := services.NewASTComplexityCalculator()
+		return nil, report(pass, nil, func() ([]entities.AnalysisFinding, error) {
+			var findings []entities.AnalysisFinding
+			for _, file := range pass.Files {
+				for _, decl := range file.Decls {
+					funcDecl, ok := decl.(*ast.FuncDecl)
+					if !ok || funcDecl.Body == nil {
+						continue
+					}
+					complexity, err := calculator.CalculateComplexity(funcDecl, pass.Fset)
+					if err != nil {
+						return nil, err
+					}
+					findings = append(findings, complexityFindings(funcDecl, complexity, pass.Fset, complexityLimits{
+						cyclomatic: *maxCyclomatic,
+						cognitive:  *maxCognitive,
+						npath:      *maxNPath,
+						essential:  *maxEssential,
+					})...)
+				}
+			}
+			return findings, nil
+		})
+	}
+	return analyzer
+}
+
+// NewSmellAnalyzer creates an analyzer reporting architectural smells, resource misuse, security
+// and performance issues. Goroutine leaks and concurrency bugs are left to their own analyzers
+func NewSmellAnalyzer() *analysis.Analyzer {
+	defaults := valueobjects.DefaultAnalysisConfiguration()
+
+	analyzer := &analysis.Analyzer{
+		Name: "smells",
+		Doc:  "report architectural smells, resource misuse, security and performance issues",
+	}
+	maxLength := analyzer.Flags.Int("max-function-length", defaults.MaxFunctionLength(), "maximum number of lines of a function")
+	severity := newSeverityFlag(&analyzer.Flags)
+
+	analyzer.Run = func(pass *analysis.Pass) (interface{}, error) {
+		detector := services.NewASTSmellDetector().WithoutConcurrencyDetectors()
+		config := defaults.WithMaxFunctionLength(*maxLength)
+		return nil, report(pass, severity, func() ([]entities.AnalysisFinding, error) {
+			return detector.DetectPackageSmells(packageContext(pass), config)
+		})
+	}
+	return analyzer
+}
+
+// NewGoroutineLeakAnalyzer creates an analyzer reporting goroutines that can block forever. The
+// detector reads no threshold of the configuration, so -min-severity is its only flag
+func NewGoroutineLeakAnalyzer() *analysis.Analyzer {
+	analyzer := &analysis.Analyzer{
+		Name: "goroutineleak",
+		Doc:  "report goroutines that can block forever on channels, selects or wait groups",
+	}
+	severity := newSeverityFlag(&analyzer.Flags)
+
+	analyzer.Run = func(pass *analysis.Pass) (interface{}, error) {
+		return nil, report(pass, severity, func() ([]entities.AnalysisFinding, error) {
+			pkg := packageContext(pass)
+			findings, err := services.NewASTGoroutineLeakDetector().DetectPackageLeaks(pkg, valueobjects.DefaultAnalysisConfiguration())
+			if err != nil {
+				return nil, err
+			}
+			return services.NewASTFixSuggester().SuggestFixes(findings, pkg), nil
+		})
+	}
+	return analyzer
+}
+
+// NewConcurrencyBugAnalyzer creates an analyzer reporting data races, lock misuse and other
+// concurrency bugs. Like the goroutine leak analyzer it has no thresholds to expose as flags
+func NewConcurrencyBugAnalyzer() *analysis.Analyzer {
+	analyzer := &analysis.Analyzer{
+		Name: "concurrencybug",
+		Doc:  "report data races, lock misuse and other concurrency bugs",
+	}
+	severity := newSeverityFlag(&analyzer.Flags)
+
+	analyzer.Run = func(pass *analysis.Pass) (interface{}, error) {
+		return nil, report(pass, severity, func() ([]entities.AnalysisFinding, error) {
+			detector := services.NewASTConcurrencyBugDetector()
+			var findings []entities.AnalysisFinding
+			for _, file := range pass.Files {
+				fileFindings, err := detector.DetectBugs(file, pass.Fset, valueobjects.DefaultAnalysisConfiguration())
+				if err != nil {
+					return nil, err
+				}
+				findings = append(findings, fileFindings...)
+			}
+			return services.NewASTFixSuggester().SuggestFixes(findings, packageContext(pass)), nil
+		})
+	}
+	return analyzer
+}
+
+// complexityLimits are the thresholds of the complexity analyzer; zero disables NPath and essential
+type complexityLimits struct {
+	cyclomatic int
+	cognitive  int
+	npath      int
+	essential  int
+}
+
+// complexityFindings reports the limits a function exceeds, with the rule names the CLI uses
+func complexityFindings(funcDecl *ast.FuncDecl, complexity valueobjects.ComplexityScore, fset *token.FileSet, limits complexityLimits) []entities.AnalysisFinding {
+	pos := fset.Position(funcDecl.Pos())
+	location, _ := valueobjects.NewSourceLocation(pos.Filename, pos.Line, pos.Column)
+	name := funcDecl.Name.Name
+
+	var findings []entities.AnalysisFinding
+	add := func(rule, message string) {
+		finding, err := entities.NewAnalysisFinding(
+			fmt.Sprintf("%s_%s_%d", rule, name, pos.Line),
+			entities.FindingTypeComplexity,
+			location,
+			message,
+			valueobjects.SeverityWarning,
+		)
+		if err == nil {
+			findings = append(findings, finding)
+		}
+	}
+
+	if complexity.Cyclomatic() > limits.cyclomatic || complexity.Cognitive() > limits.cognitive {
+		add("complexity", fmt.Sprintf("Function %s: %s exceeds cyclomatic=%d or cognitive=%d",
+			name, complexity.String(), limits.cyclomatic, limits.cognitive))
+	}
+	if limits.npath > 0 && complexity.NPath() > int64(limits.npath) {
+		paths := fmt.Sprintf("%d", complexity.NPath())
+		if complexity.IsNPathSaturated() {
+			paths = "more than " + paths
+		}
+		add("npath", fmt.Sprintf("Function %s: NPath complexity %s exceeds %d", name, paths, limits.npath))
+	}
+	if limits.essential > 0 && complexity.Essential() > limits.essential {
+		add("essential", fmt.Sprintf("Function %s: essential complexity %d exceeds %d", name, complexity.Essential(), limits.essential))
+	}
+	return findings
+}
+
+// packageContext builds the detectors' view of the package under analysis
+func packageContext(pass *analysis.Pass) *services.PackageContext {
+	pkg := services.NewPackageContext(pass.Fset, pass.Files, pass.TypesInfo)
+	if pass.Pkg != nil {
+		pkg.SetGoVersion(pass.Pkg.GoVersion())
+	}
+	return pkg
+}
+
+// severityFlag is a flag value holding the minimum severity an analyzer reports
+type severityFlag struct {
+	level valueobjects.SeverityLevel
+}
+
+// newSeverityFlag registers the -min-severity flag, which defaults to reporting everything
+func newSeverityFlag(flags *flag.FlagSet) *severityFlag {
+	severity := &severityFlag{level: valueobjects.SeverityInfo}
+	flags.Var(severity, "min-severity", "minimum severity to report: info, warning, error or critical")
+	return severity
+}
+
+// String returns the name of the severity
+func (f *severityFlag) String() string {
+	if f == nil {
+		return valueobjects.SeverityInfo.String()
+	}
+	return f.level.String()
+}
+
+// Set parses a severity name
+func (f *severityFlag) Set(value string) error {
+	for _, level := range []valueobjects.SeverityLevel{
+		valueobjects.SeverityInfo,
+		valueobjects.SeverityWarning,
+		valueobjects.SeverityError,
+		valueobjects.SeverityCritical,
+	} {
+		if level.String() == value {
+			f.level = level
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", value)
+}
+
+// report runs detect and reports as diagnostics the findings that no //goast:ignore comment
+// suppresses and, given a severity flag, that are at least that severe
+func report(pass *analysis.Pass, severity *severityFlag, detect func() ([]entities.AnalysisFinding, error)) error {
+	findings, err := detect()
+	if err != nil {
+		return err
+	}
+
+	scanner := services.NewCommentSuppressionScanner()
+	var suppressions []valueobjects.Suppression
+	for _, file := range pass.Files {
+		suppressions = append(suppressions, scanner.ScanSuppressions(file, pass.Fset)...)
+	}
+
+	for _, finding := range findings {
+		if severity != nil && finding.Severity() < severity.level {
+			continue
+		}
+		if services.IsSuppressed(finding, suppressions) {
+			continue
+		}
+
+		pos := position(pass, finding.Location())
+		if !pos.IsValid() {
+			continue
+		}
+		diagnostic := analysis.Diagnostic{
+			Pos:      pos,
+			Category: finding.Type().String(),
+			Message:  finding.Message(),
+		}
+		for i, step := range finding.Trace() {
+			if stepPos := position(pass, step); stepPos.IsValid() {
+				diagnostic.Related = append(diagnostic.Related, analysis.RelatedInformation{
+					Pos:     stepPos,
+					Message: fmt.Sprintf("step %d of the data flow", i+1),
+				})
+			}
+		}
+		diagnostic.SuggestedFixes = suggestedFixes(pass, finding)
+		pass.Report(diagnostic)
+	}
+	return nil
+}
+
+// suggestedFixes converts the fixes of a finding to the analysis framework's, dropping any fix
+// with an edit outside the pass's files
+func suggestedFixes(pass *analysis.Pass, finding entities.AnalysisFinding) []analysis.SuggestedFix {
+	var fixes []analysis.SuggestedFix
+	for _, fix := range finding.Fixes() {
+		converted := analysis.SuggestedFix{Message: fix.Description()}
+		for _, edit := range fix.Edits() {
+			tokenFile := passFile(pass, edit.FilePath())
+			if tokenFile == nil || edit.End() > tokenFile.Size() {
+				converted.TextEdits = nil
+				break
+			}
+			converted.TextEdits = append(converted.TextEdits, analysis.TextEdit{
+				Pos:     tokenFile.Pos(edit.Offset()),
+				End:     tokenFile.Pos(edit.End()),
+				NewText: []byte(edit.NewText()),
+			})
+		}
+		if len(converted.TextEdits) > 0 {
+			fixes = append(fixes, converted)
+		}
+	}
+	return fixes
+}
+
+// passFile returns the token file of the pass's file at path
+func passFile(pass *analysis.Pass, path string) *token.File {
+	for _, file := range pass.Files {
+		if tokenFile := pass.Fset.File(file.Pos()); tokenFile != nil && tokenFile.Name() == path {
+			return tokenFile
+		}
+	}
+	return nil
+}
+
+// position converts a finding's location back to a position in the pass's files
+func position(pass *analysis.Pass, location valueobjects.SourceLocation) token.Pos {
+	tokenFile := passFile(pass, location.FilePath())
+	if tokenFile == nil || location.Line() < 1 || location.Line() > tokenFile.LineCount() {
+		return token.NoPos
+	}
+
+	pos := tokenFile.LineStart(location.Line())
+	offset := tokenFile.Offset(pos) + location.Column() - 1
+	if location.Column() < 1 || offset >= tokenFile.Size() {
+		return pos
+	}
+	return tokenFile.Pos(offset)
+}
@@ -0,0 +1,52 @@
+package analyzers
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzers(t *testing.T) {
+	tests := []struct {
+		name        string
+		newAnalyzer func() *analysis.Analyzer
+		flags       map[string]string
+		pkg         string
+	}{
+		{"Complexity with default limits", NewComplexityAnalyzer, nil, "complexity"},
+		{"Complexity with lowered limits", NewComplexityAnalyzer, map[string]string{"max-cyclomatic": "3"}, "complexitylimits"},
+		{"Smells without concurrency findings", NewSmellAnalyzer, nil, "smells"},
+		{"Goroutine leaks", NewGoroutineLeakAnalyzer, nil, "goroutineleak"},
+		{"Concurrency bugs", NewConcurrencyBugAnalyzer, nil, "concurrencybug"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Flags are set on an instance of this case only, so no case sees another's flags
+			analyzer := tt.newAnalyzer()
+			for name, value := range tt.flags {
+				if err := analyzer.Flags.Set(name, value); err != nil {
+					t.Fatalf("Failed to set -%s: %v", name, err)
+				}
+			}
+			analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer, tt.pkg)
+		})
+	}
+}
+
+func TestAll_Validate(t *testing.T) {
+	if err := analysis.Validate(All()); err != nil {
+		t.Errorf("Expected valid analyzers, got %v", err)
+	}
+}
+
+func TestSeverityFlag(t *testing.T) {
+	analyzer := NewSmellAnalyzer()
+	if err := analyzer.Flags.Set("min-severity", "error"); err != nil {
+		t.Errorf("Expected error to be a valid severity, got %v", err)
+	}
+	if err := analyzer.Flags.Set("min-severity", "fatal"); err == nil {
+		t.Error("Expected fatal to be rejected")
+	}
+}
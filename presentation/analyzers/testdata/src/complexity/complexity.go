@@ -0,0 +1,43 @@
+package complexity
+
+func simple(n int) int {
+	if n > 0 {
+		return n
+	}
+	return -n
+}
+
+func nested(values []int) int { // want `Function nested: cyclomatic=7, cognitive=33 exceeds`
+	total := 0
+	for _, v := range values {
+		if v > 0 {
+			for i := 0; i < v; i++ {
+				if i%2 == 0 {
+					if i%3 == 0 {
+						total++
+					} else if i%5 == 0 {
+						total--
+					}
+				}
+			}
+		}
+	}
+	return total
+}
+
+//goast:ignore complexity generated lookup
+func suppressed(values []int) int {
+	total := 0
+	for _, v := range values {
+		if v > 0 {
+			for i := 0; i < v; i++ {
+				if i%2 == 0 {
+					if i%3 == 0 {
+						total++
+					}
+				}
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,18 @@
+package complexitylimits
+
+func simple(n int) int {
+	return n
+}
+
+func branches(n int) int { // want `Function branches: cyclomatic=4, cognitive=9 exceeds cyclomatic=3 or cognitive=20`
+	if n > 0 {
+		return 1
+	}
+	if n < 0 {
+		return -1
+	}
+	if n == 0 {
+		return 0
+	}
+	return n
+}
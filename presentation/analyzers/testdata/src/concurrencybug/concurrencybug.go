@@ -0,0 +1,14 @@
+package concurrencybug
+
+import "sync"
+
+func lockWithoutUnlock() { // want `mutex 'mu' usage pattern may cause deadlock`
+	var mu sync.Mutex
+	mu.Lock()
+}
+
+func lockWithUnlock() {
+	var mu sync.Mutex
+	mu.Lock()
+	defer mu.Unlock()
+}
@@ -0,0 +1,22 @@
+package goroutineleak
+
+import "context"
+
+func leaks() {
+	ch := make(chan int)
+	go func() { // want `channel_receive_leak detected`
+		<-ch
+	}()
+}
+
+func cancellable(ctx context.Context) {
+	ch := make(chan int)
+	go func() {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return
+		}
+	}()
+	ch <- 1
+}
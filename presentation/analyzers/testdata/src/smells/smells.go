@@ -0,0 +1,47 @@
+package smells
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+func deep(values []int) int { // want `Function deep has deep nesting`
+	total := 0
+	for _, v := range values {
+		if v > 0 {
+			for i := 0; i < v; i++ {
+				if i%2 == 0 {
+					if i%3 == 0 {
+						total++
+					}
+				}
+			}
+		}
+	}
+	return total
+}
+
+func readConfig(path string) []byte {
+	f, _ := os.Open(path) // want `'f' from os.Open is never closed`
+	buf := make([]byte, 10)
+	f.Read(buf)
+	return buf
+}
+
+func parsePort(value string) (int, error) {
+	var port int
+	if _, err := fmt.Sscanf(value, "%d", &port); err != nil {
+		return 0, fmt.Errorf("parse port %q: %v", value, err) // want `fmt.Errorf formats 'err' with %v`
+	}
+	return port, nil
+}
+
+func leaks() {
+	var mu sync.Mutex
+	mu.Lock()
+	ch := make(chan int)
+	go func() {
+		<-ch
+	}()
+}
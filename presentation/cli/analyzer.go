@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +12,7 @@ import (
 	"goastanalyzer/application/usecases"
 	"goastanalyzer/domain/entities"
 	"goastanalyzer/domain/services"
+	"goastanalyzer/domain/valueobjects"
 	"goastanalyzer/infrastructure/adapters"
 	"goastanalyzer/infrastructure/config"
 )
@@ -18,8 +21,11 @@ import (
 type AnalyzerCLI struct {
 	config     config.Config
 	useCase    usecases.AnalyzeCodeUseCase
+	hotspots   usecases.FindHotspotsUseCase
+	cache      *adapters.FileCache
 	outputMode OutputMode
 	recursive  bool
+	fixMode    fixMode
 }
 
 // OutputMode defines how results should be displayed
@@ -29,47 +35,100 @@ const (
 	OutputModeText OutputMode = iota
 	OutputModeJSON
 	OutputModeTable
+	OutputModeHTML
 )
 
 // NewAnalyzerCLI creates a new CLI instance
 func NewAnalyzerCLI() *AnalyzerCLI {
 	cfg := config.LoadConfig()
 
+	// Create use case
+	cache := adapters.NewFileCache(cfg.CacheDir)
+	useCase := newAnalyzeCodeUseCase(cache)
+
+	hotspots := usecases.NewFindHotspotsUseCase(
+		useCase,
+		adapters.NewGitHistoryReader(),
+		services.NewChurnHotspotRanker(),
+	)
+
+	return &AnalyzerCLI{
+		config:     cfg,
+		useCase:    useCase,
+		hotspots:   hotspots,
+		cache:      cache,
+		outputMode: OutputModeText,
+	}
+}
+
+// newAnalyzeCodeUseCase creates an analyze code use case with fresh parser and loader state
+func newAnalyzeCodeUseCase(cache usecases.AnalysisCache) usecases.AnalyzeCodeUseCase {
 	// Create dependencies
 	complexityCalculator := services.NewASTComplexityCalculator()
 	smellDetector := services.NewASTSmellDetector()
+	cloneDetector := services.NewASTCloneDetector()
+	suppressionScanner := services.NewCommentSuppressionScanner()
 	fileParser := adapters.NewGoFileParser()
+	packageLoader := adapters.NewGoPackageLoader()
 	idGenerator := adapters.NewUUIDGenerator()
 
-	// Create use case
-	useCase := usecases.NewAnalyzeCodeUseCase(
+	return usecases.NewAnalyzeCodeUseCase(
 		complexityCalculator,
 		smellDetector,
+		cloneDetector,
+		suppressionScanner,
 		fileParser,
+		packageLoader,
 		idGenerator,
+		cache,
 	)
-
-	return &AnalyzerCLI{
-		config:     cfg,
-		useCase:    useCase,
-		outputMode: OutputModeText,
-	}
 }
 
 // Run executes the CLI application
 func (cli *AnalyzerCLI) Run(args []string) int {
+	if len(args) > 0 && args[0] == "hotspots" {
+		return cli.runHotspots(args[1:])
+	}
+	if len(args) > 0 && args[0] == "cache" {
+		return cli.runCache(args[1:])
+	}
+	if len(args) > 0 && args[0] == "watch" {
+		return cli.runWatch(args[1:])
+	}
+	if len(args) > 0 && args[0] == "lsp" {
+		return cli.runLsp(args[1:])
+	}
+
 	var (
 		files        = flag.String("files", "", "Comma-separated list of Go files to analyze")
-		outputMode   = flag.String("output", "text", "Output mode: text, json, table")
+		outputMode   = flag.String("output", "text", "Output mode: text, json, table, html")
 		showConfig   = flag.Bool("config", false, "Show current configuration")
 		help         = flag.Bool("help", false, "Show help")
 		recursive    = flag.Bool("recursive", false, "Recursively analyze directories for Go files")
+		configFile   = flag.String("config-file", "", "Path to a JSON configuration file (default: "+config.DefaultConfigFile+" if present)")
+		diffRef      = flag.String("diff", "", "Analyze only packages changed since the merge base of this git ref and report only findings in changed lines")
+		diffFile     = flag.String("diff-file", "", "Like -diff, with the changes read from a unified diff file; - reads stdin")
+		cacheDir     = flag.String("cache-dir", "", "Directory caching results between runs, off to disable (default: $GOAST_CACHE_DIR or the user cache directory)")
+		fix          = flag.Bool("fix", false, "Apply the suggested fixes of the findings to the analyzed files")
+		fixDiff      = flag.Bool("fix-diff", false, "Print the suggested fixes as a unified diff instead of the results")
 	)
 
 	flag.BoolVar(recursive, "r", false, "Recursively analyze directories for Go files (short for -recursive)")
 	flag.Parse()
 
 	cli.recursive = *recursive
+	switch {
+	case *fixDiff:
+		cli.fixMode = fixModeDiff
+	case *fix:
+		cli.fixMode = fixModeApply
+	}
+
+	if err := cli.loadConfigFile(*configFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	cli.setCacheDir(*cacheDir)
 
 	if *help {
 		cli.showHelp()
@@ -87,12 +146,36 @@ func (cli *AnalyzerCLI) Run(args []string) int {
 		cli.outputMode = OutputModeJSON
 	case "table":
 		cli.outputMode = OutputModeTable
+	case "html":
+		cli.outputMode = OutputModeHTML
 	default:
 		cli.outputMode = OutputModeText
 	}
 
 	// Get files to analyze
 	fileList := cli.parseFileList(*files, flag.Args())
+
+	// In diff mode, analyze the changed packages within the given paths
+	var changes *valueobjects.ChangeSet
+	if *diffRef != "" || *diffFile != "" {
+		changeSet, err := cli.readChanges(*diffRef, *diffFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		changes = &changeSet
+
+		fileList, err = changedPackageFiles(changeSet, fileList)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if len(fileList) == 0 {
+			fmt.Println("No changed Go packages to analyze")
+			return 0
+		}
+	}
+
 	if len(fileList) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: No files specified for analysis\n")
 		cli.showUsage()
@@ -100,7 +183,99 @@ func (cli *AnalyzerCLI) Run(args []string) int {
 	}
 
 	// Execute analysis
-	return cli.analyzeFiles(fileList)
+	return cli.analyzeFiles(fileList, changes)
+}
+
+// readChanges reads the changed lines from git or from a unified diff file
+func (cli *AnalyzerCLI) readChanges(ref, diffFile string) (valueobjects.ChangeSet, error) {
+	reader := adapters.NewGitDiffReader()
+	switch {
+	case ref != "" && diffFile != "":
+		return valueobjects.ChangeSet{}, fmt.Errorf("-diff and -diff-file cannot be combined")
+	case ref != "":
+		return reader.ReadChanges(ref)
+	case diffFile == "-":
+		return reader.ParseDiff(os.Stdin)
+	}
+
+	file, err := os.Open(diffFile)
+	if err != nil {
+		return valueobjects.ChangeSet{}, err
+	}
+	defer file.Close()
+	return reader.ParseDiff(file)
+}
+
+// changedPackageFiles returns every Go file of the packages holding a changed Go file. When
+// paths are given, only changes to those files or to files below those directories count
+func changedPackageFiles(changes valueobjects.ChangeSet, paths []string) ([]string, error) {
+	scope := make([]string, 0, len(paths))
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		scope = append(scope, abs)
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, changed := range changes.Files() {
+		if !strings.HasSuffix(changed, ".go") || !inScope(changed, scope) {
+			continue
+		}
+		dir := filepath.Dir(changed)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+				files = append(files, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+// inScope reports whether a path is one of the scope paths or lies below one; an empty scope holds everything
+func inScope(path string, scope []string) bool {
+	if len(scope) == 0 {
+		return true
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, root := range scope {
+		if abs == root || strings.HasPrefix(abs, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadConfigFile replaces the environment configuration with the given file, or with
+// the default configuration file when it exists in the working directory
+func (cli *AnalyzerCLI) loadConfigFile(path string) error {
+	if path == "" {
+		if _, err := os.Stat(config.DefaultConfigFile); err != nil {
+			return nil
+		}
+		path = config.DefaultConfigFile
+	}
+
+	cfg, err := config.LoadConfigFile(path)
+	if err != nil {
+		return err
+	}
+	cli.config = cfg
+	return nil
 }
 
 // parseFileList parses the file list from command line arguments
@@ -162,12 +337,13 @@ func (cli *AnalyzerCLI) findGoFilesRecursively(rootPath string) ([]string, error
 	return goFiles, err
 }
 
-// analyzeFiles performs the analysis on the specified files
-func (cli *AnalyzerCLI) analyzeFiles(files []string) int {
+// analyzeFiles performs the analysis on the specified files, reporting only changed lines when changes is set
+func (cli *AnalyzerCLI) analyzeFiles(files []string, changes *valueobjects.ChangeSet) int {
 	request := usecases.AnalyzeCodeRequest{
 		FilePaths:             files,
 		Configuration:         cli.config.Analysis,
 		IncludeSmellDetection: cli.config.Analysis.IsSmellDetectionEnabled(),
+		ChangedLines:          changes,
 	}
 
 	response, err := cli.useCase.Execute(request)
@@ -181,20 +357,35 @@ func (cli *AnalyzerCLI) analyzeFiles(files []string) int {
 		return 1
 	}
 
-	cli.displayResults(response)
+	if cli.fixMode == fixModeDiff {
+		return cli.handleFixes(response)
+	}
+	if err := cli.displayResults(response); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+		return 1
+	}
+	if cli.fixMode == fixModeApply {
+		return cli.handleFixes(response)
+	}
 	return 0
 }
 
-// displayResults displays the analysis results based on output mode
-func (cli *AnalyzerCLI) displayResults(response *usecases.AnalyzeCodeResponse) {
+// displayResults displays the analysis results based on output mode, returning an error when the
+// JSON or HTML report cannot be rendered or written
+func (cli *AnalyzerCLI) displayResults(response *usecases.AnalyzeCodeResponse) error {
 	switch cli.outputMode {
 	case OutputModeJSON:
-		cli.displayJSON(response)
+		return cli.displayJSON(response)
 	case OutputModeTable:
 		cli.displayTable(response)
+	case OutputModeHTML:
+		if err := cli.displayHTML(os.Stdout, response); err != nil {
+			return fmt.Errorf("failed to render HTML: %w", err)
+		}
 	default:
 		cli.displayText(response)
 	}
+	return nil
 }
 
 // displayText displays results in human-readable text format
@@ -218,7 +409,7 @@ func (cli *AnalyzerCLI) displayText(response *usecases.AnalyzeCodeResponse) {
 	if complexityFindings := findingsByType[entities.FindingTypeComplexity]; len(complexityFindings) > 0 {
 		fmt.Println("🔍 Complexity Issues:")
 		for _, finding := range complexityFindings {
-			fmt.Printf("  %s\n", finding.String())
+			printFinding(finding)
 		}
 		fmt.Println()
 	}
@@ -227,7 +418,7 @@ func (cli *AnalyzerCLI) displayText(response *usecases.AnalyzeCodeResponse) {
 	if smellFindings := findingsByType[entities.FindingTypeSmell]; len(smellFindings) > 0 {
 		fmt.Println("👃 Code Smells:")
 		for _, finding := range smellFindings {
-			fmt.Printf("  %s\n", finding.String())
+			printFinding(finding)
 		}
 		fmt.Println()
 	}
@@ -240,28 +431,112 @@ func (cli *AnalyzerCLI) displayText(response *usecases.AnalyzeCodeResponse) {
 		if len(findings) > 0 {
 			fmt.Printf("%s Issues:\n", strings.Title(findingType.String()))
 			for _, finding := range findings {
-				fmt.Printf("  %s\n", finding.String())
+				printFinding(finding)
 			}
 			fmt.Println()
 		}
 	}
 }
 
+// printFinding prints one finding in text format, followed by its data flow trace and fixes if any
+func printFinding(finding entities.AnalysisFinding) {
+	fmt.Printf("  %s\n", finding.String())
+	for i, step := range finding.Trace() {
+		fmt.Printf("      %d. %s\n", i+1, step.String())
+	}
+	for _, fix := range finding.Fixes() {
+		fmt.Printf("      fix: %s\n", fix.Description())
+	}
+}
+
+// jsonReport is the document written by the JSON output mode
+type jsonReport struct {
+	Success           bool           `json:"success"`
+	Summary           string         `json:"summary"`
+	TotalFindings     int            `json:"total_findings"`
+	HighSeverityCount int            `json:"high_severity_count"`
+	Functions         []jsonFunction `json:"functions"`
+	Types             []jsonType     `json:"types"`
+}
+
+// jsonFunction carries the metrics of one function in JSON output
+type jsonFunction struct {
+	File                 string  `json:"file"`
+	Line                 int     `json:"line"`
+	Name                 string  `json:"name"`
+	Cyclomatic           int     `json:"cyclomatic"`
+	Cognitive            int     `json:"cognitive"`
+	NPath                int64   `json:"npath"`
+	Essential            int     `json:"essential"`
+	HalsteadVolume       float64 `json:"halstead_volume"`
+	HalsteadDifficulty   float64 `json:"halstead_difficulty"`
+	HalsteadEffort       float64 `json:"halstead_effort"`
+	PhysicalLines        int     `json:"loc_physical"`
+	LogicalLines         int     `json:"loc_logical"`
+	CommentLines         int     `json:"loc_comment"`
+	MaintainabilityIndex float64 `json:"maintainability_index"`
+}
+
+// jsonType carries the design metrics of one struct type in JSON output
+type jsonType struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Name    string `json:"name"`
+	Fields  int    `json:"fields"`
+	Methods int    `json:"methods"`
+	LCOM4   int    `json:"lcom4"`
+}
+
 // displayJSON displays results in JSON format
-func (cli *AnalyzerCLI) displayJSON(response *usecases.AnalyzeCodeResponse) {
-	// Simplified JSON output - in production, use proper JSON marshaling
-	fmt.Printf(`{
-  "success": %t,
-  "summary": "%s",
-  "total_findings": %d,
-  "high_severity_count": %d
-}`,
-		response.Success,
-		strings.ReplaceAll(response.Summary, `"`, `\"`),
-		len(response.AnalysisResult.Findings()),
-		len(response.AnalysisResult.HighSeverityFindings()),
-	)
-	fmt.Println()
+func (cli *AnalyzerCLI) displayJSON(response *usecases.AnalyzeCodeResponse) error {
+	report := jsonReport{
+		Success:           response.Success,
+		Summary:           response.Summary,
+		TotalFindings:     len(response.AnalysisResult.Findings()),
+		HighSeverityCount: len(response.AnalysisResult.HighSeverityFindings()),
+		Functions:         []jsonFunction{},
+		Types:             []jsonType{},
+	}
+
+	for _, metrics := range response.AnalysisResult.FunctionMetrics() {
+		halstead := metrics.Halstead()
+		report.Functions = append(report.Functions, jsonFunction{
+			File:                 metrics.Location().FilePath(),
+			Line:                 metrics.Location().Line(),
+			Name:                 metrics.Name(),
+			Cyclomatic:           metrics.Complexity().Cyclomatic(),
+			Cognitive:            metrics.Complexity().Cognitive(),
+			NPath:                metrics.Complexity().NPath(),
+			Essential:            metrics.Complexity().Essential(),
+			HalsteadVolume:       round(halstead.Volume()),
+			HalsteadDifficulty:   round(halstead.Difficulty()),
+			HalsteadEffort:       round(halstead.Effort()),
+			PhysicalLines:        metrics.Lines().Physical(),
+			LogicalLines:         metrics.Lines().Logical(),
+			CommentLines:         metrics.Lines().Comment(),
+			MaintainabilityIndex: round(metrics.MaintainabilityIndex()),
+		})
+	}
+
+	for _, metrics := range response.AnalysisResult.TypeMetrics() {
+		report.Types = append(report.Types, jsonType{
+			File:    metrics.Location().FilePath(),
+			Line:    metrics.Location().Line(),
+			Name:    metrics.Name(),
+			Fields:  metrics.Fields(),
+			Methods: metrics.Methods(),
+			LCOM4:   metrics.LCOM4(),
+		})
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	if _, err := fmt.Println(string(data)); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
 }
 
 // displayTable displays results in a tabular format
@@ -279,6 +554,62 @@ func (cli *AnalyzerCLI) displayTable(response *usecases.AnalyzeCodeResponse) {
 			finding.Message(),
 		)
 	}
+
+	printFunctionMetrics(response.AnalysisResult.FunctionMetrics())
+	printTypeMetrics(response.AnalysisResult.TypeMetrics())
+}
+
+// printFunctionMetrics prints the per-function metrics table
+func printFunctionMetrics(metrics []valueobjects.FunctionMetrics) {
+	if len(metrics) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("FILE                 | LINE | FUNCTION                 |  CYC |  COG |    NPATH |  EV |   VOLUME |  DIFF |    EFFORT |  LOC | LLOC | CLOC |    MI")
+	fmt.Println("---------------------|------|--------------------------|------|------|----------|-----|----------|-------|-----------|------|------|------|------")
+
+	for _, function := range metrics {
+		halstead := function.Halstead()
+		fmt.Printf("%-20s | %4d | %-24s | %4d | %4d | %8s | %3d | %8.1f | %5.1f | %9.0f | %4d | %4d | %4d | %5.1f\n",
+			truncate(function.Location().FilePath(), 20),
+			function.Location().Line(),
+			truncate(function.Name(), 24),
+			function.Complexity().Cyclomatic(),
+			function.Complexity().Cognitive(),
+			formatNPath(function.Complexity()),
+			function.Complexity().Essential(),
+			halstead.Volume(),
+			halstead.Difficulty(),
+			halstead.Effort(),
+			function.Lines().Physical(),
+			function.Lines().Logical(),
+			function.Lines().Comment(),
+			function.MaintainabilityIndex(),
+		)
+	}
+}
+
+// printTypeMetrics prints the per-type field, method and cohesion table
+func printTypeMetrics(metrics []valueobjects.TypeMetrics) {
+	if len(metrics) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("FILE                 | LINE | TYPE                     | FIELDS | METHODS | LCOM4")
+	fmt.Println("---------------------|------|--------------------------|--------|---------|------")
+
+	for _, typeMetrics := range metrics {
+		fmt.Printf("%-20s | %4d | %-24s | %6d | %7d | %5d\n",
+			truncate(typeMetrics.Location().FilePath(), 20),
+			typeMetrics.Location().Line(),
+			truncate(typeMetrics.Name(), 24),
+			typeMetrics.Fields(),
+			typeMetrics.Methods(),
+			typeMetrics.LCOM4(),
+		)
+	}
 }
 
 // showConfiguration displays the current configuration
@@ -287,13 +618,29 @@ func (cli *AnalyzerCLI) showConfiguration() {
 	fmt.Printf("Max Cyclomatic Complexity: %d\n", cli.config.Analysis.MaxCyclomaticComplexity())
 	fmt.Printf("Max Cognitive Complexity:  %d\n", cli.config.Analysis.MaxCognitiveComplexity())
 	fmt.Printf("Max Function Length:       %d\n", cli.config.Analysis.MaxFunctionLength())
+	fmt.Printf("Max NPath Complexity:      %d\n", cli.config.Analysis.MaxNPath())
+	fmt.Printf("Max Essential Complexity:  %d\n", cli.config.Analysis.MaxEssentialComplexity())
+	fmt.Printf("Max Struct Fields:         %d\n", cli.config.Analysis.MaxStructFields())
+	fmt.Printf("Max Halstead Volume:       %d\n", cli.config.Analysis.MaxHalsteadVolume())
+	fmt.Printf("Max Halstead Effort:       %d\n", cli.config.Analysis.MaxHalsteadEffort())
+	fmt.Printf("Min Maintainability Index: %d\n", cli.config.Analysis.MinMaintainabilityIndex())
+	fmt.Printf("Min Clone Tokens:          %d\n", cli.config.Analysis.MinCloneTokens())
 	fmt.Printf("Smell Detection Enabled:   %t\n", cli.config.Analysis.IsSmellDetectionEnabled())
 	fmt.Printf("Severity Threshold:        %s\n", cli.config.Analysis.SeverityThreshold().String())
+
+	taint := cli.config.Analysis.TaintConfiguration()
+	fmt.Printf("Taint Sources:             %d\n", len(taint.Sources()))
+	fmt.Printf("Taint Sanitizers:          %d\n", len(taint.Sanitizers()))
+	fmt.Printf("Taint Sinks:               %d\n", len(taint.Sinks()))
 }
 
 // showUsage displays usage information
 func (cli *AnalyzerCLI) showUsage() {
 	fmt.Println("Usage: goastanalyzer [options] <files...>")
+	fmt.Println("       goastanalyzer hotspots [options] <files...>")
+	fmt.Println("       goastanalyzer watch [options] <files or directories...>")
+	fmt.Println("       goastanalyzer lsp [options]")
+	fmt.Println("       goastanalyzer cache clean|stats")
 	fmt.Println()
 	fmt.Println("Options:")
 	flag.PrintDefaults()
@@ -304,6 +651,13 @@ func (cli *AnalyzerCLI) showUsage() {
 	fmt.Println("  goastanalyzer -recursive ./src")
 	fmt.Println("  goastanalyzer -r /path/to/project")
 	fmt.Println("  goastanalyzer -config")
+	fmt.Println("  goastanalyzer -diff origin/main -output table")
+	fmt.Println("  goastanalyzer -r -output html . > report.html")
+	fmt.Println("  git diff HEAD~3 | goastanalyzer -diff-file - -r ./domain")
+	fmt.Println("  goastanalyzer hotspots -since \"6 months ago\" -output html -r . > hotspots.html")
+	fmt.Println("  goastanalyzer -cache-dir off -r .")
+	fmt.Println("  goastanalyzer -fix-diff -r ./service && goastanalyzer -fix -r ./service")
+	fmt.Println("  goastanalyzer watch ./...")
 }
 
 // showHelp displays detailed help information
@@ -318,12 +672,52 @@ func (cli *AnalyzerCLI) showHelp() {
 	fmt.Println("  When using -recursive or -r flag, directories will be scanned recursively")
 	fmt.Println("  for .go files. Individual files can still be specified alongside directories.")
 	fmt.Println()
+	fmt.Println("Caching:")
+	fmt.Println("  Results are cached per file content, analyzer build and configuration, so")
+	fmt.Println("  repeat runs only analyze modified files and the packages depending on them.")
+	fmt.Println("  The cache lives in $GOAST_CACHE_DIR or the user cache directory; -cache-dir")
+	fmt.Println("  overrides it and off disables it. \"cache stats\" and \"cache clean\" manage it.")
+	fmt.Println()
 	fmt.Println("Configuration:")
 	fmt.Println("  Set environment variables to override defaults:")
 	fmt.Println("  - GOAST_MAX_CYCLOMATIC: Maximum cyclomatic complexity (default: 15)")
 	fmt.Println("  - GOAST_MAX_COGNITIVE:  Maximum cognitive complexity (default: 20)")
 	fmt.Println("  - GOAST_MAX_FUNCTION_LENGTH: Maximum function length (default: 80)")
+	fmt.Println("  - GOAST_MAX_NPATH: Maximum NPath complexity per function, 0 disables (default: 200)")
+	fmt.Println("  - GOAST_MAX_ESSENTIAL: Maximum essential complexity per function, 0 disables (default: 4)")
+	fmt.Println("  - GOAST_MAX_STRUCT_FIELDS: Fields above which a struct is a god struct (default: 10)")
+	fmt.Println("  - GOAST_MAX_HALSTEAD_VOLUME: Maximum Halstead volume per function, 0 disables (default: 3000)")
+	fmt.Println("  - GOAST_MAX_HALSTEAD_EFFORT: Maximum Halstead effort per function, 0 disables (default: 150000)")
+	fmt.Println("  - GOAST_MIN_MAINTAINABILITY: Minimum maintainability index 0-100, 0 disables (default: 20)")
+	fmt.Println("  - GOAST_MIN_CLONE_TOKENS: Minimum size of reported code clones in tokens (default: 50)")
 	fmt.Println("  - GOAST_ENABLE_SMELL_DETECTION: Enable smell detection (default: true)")
+	fmt.Println()
+	fmt.Println("  Settings can also be read from a JSON file given with -config-file or found")
+	fmt.Println("  as " + config.DefaultConfigFile + " in the working directory. Its taint section adds")
+	fmt.Println("  sources, sanitizers and sinks to the built-in rules:")
+	fmt.Println(`    {"max_cyclomatic": 12,`)
+	fmt.Println(`     "taint": {"sources": [{"name": "os.ReadFile", "kind": "file"}],`)
+	fmt.Println(`               "sanitizers": ["app/internal/safe.Path"],`)
+	fmt.Println(`               "sinks": [{"name": "app/internal/db.Raw", "kind": "sql", "args": [0]}]}}`)
+	fmt.Println("  Set \"replace_defaults\": true in the taint section to drop the built-in rules.")
+}
+
+// formatNPath formats an NPath count to fit its table column, abbreviating large values
+func formatNPath(complexity valueobjects.ComplexityScore) string {
+	npath := complexity.NPath()
+	switch {
+	case complexity.IsNPathSaturated():
+		return "overflow"
+	case npath >= 100000000:
+		return fmt.Sprintf("%.1e", float64(npath))
+	default:
+		return fmt.Sprintf("%d", npath)
+	}
+}
+
+// round rounds a metric to two decimals for output
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
 }
 
 // truncate truncates a string to the specified length
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runCache executes the cache command, which reports on or empties the result cache
+func (cli *AnalyzerCLI) runCache(args []string) int {
+	flags := flag.NewFlagSet("cache", flag.ContinueOnError)
+	cacheDir := flags.String("cache-dir", "", "Cache directory (default: $GOAST_CACHE_DIR or the user cache directory)")
+	flags.Usage = func() {
+		fmt.Println("Usage: goastanalyzer cache [options] clean|stats")
+		fmt.Println()
+		fmt.Println("  stats  shows the number and total size of cached results")
+		fmt.Println("  clean  removes every cached result")
+		fmt.Println()
+		fmt.Println("Options:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 1
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	cli.setCacheDir(*cacheDir)
+	if cli.cache.Dir() == "" {
+		fmt.Fprintf(os.Stderr, "Error: the cache is disabled\n")
+		return 1
+	}
+
+	switch flags.Arg(0) {
+	case "stats":
+		stats, err := cli.cache.Stats()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Cache directory: %s\n", stats.Dir)
+		fmt.Printf("Entries: %d\n", stats.Entries)
+		fmt.Printf("Size: %s\n", formatBytes(stats.Bytes))
+	case "clean":
+		stats, err := cli.cache.Stats()
+		if err == nil {
+			err = cli.cache.Clean()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Removed %d entries (%s) from %s\n", stats.Entries, formatBytes(stats.Bytes), stats.Dir)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown cache command %q\n", flags.Arg(0))
+		flags.Usage()
+		return 1
+	}
+	return 0
+}
+
+// setCacheDir points the result cache at dir; an empty dir keeps the configured directory and
+// off disables caching
+func (cli *AnalyzerCLI) setCacheDir(dir string) {
+	switch dir {
+	case "":
+		cli.cache.SetDir(cli.config.CacheDir)
+	case "off":
+		cli.cache.SetDir("")
+	default:
+		cli.cache.SetDir(dir)
+	}
+}
+
+// formatBytes formats a size with a binary unit
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exponent := float64(size)/unit, 0
+	for value >= unit && exponent < 3 {
+		value /= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[exponent])
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goastanalyzer/application/usecases"
+	"goastanalyzer/infrastructure/adapters"
+	"goastanalyzer/infrastructure/config"
+)
+
+func TestRunCache(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		entries        int
+		expectedCode   int
+		expectedOutput string
+		expectedLeft   int
+	}{
+		{name: "Stats", args: []string{"stats"}, entries: 2, expectedOutput: "Entries: 2\n", expectedLeft: 2},
+		{name: "Stats of an empty cache", args: []string{"stats"}, expectedOutput: "Entries: 0\nSize: 0 B\n"},
+		{name: "Clean", args: []string{"clean"}, entries: 3, expectedOutput: "Removed 3 entries", expectedLeft: 0},
+		{name: "Unknown command", args: []string{"purge"}, entries: 1, expectedCode: 1, expectedLeft: 1},
+		{name: "Missing command", args: nil, entries: 1, expectedCode: 1, expectedLeft: 1},
+		{name: "Disabled cache", args: []string{"-cache-dir", "off", "stats"}, entries: 1, expectedCode: 1, expectedLeft: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cache := adapters.NewFileCache(dir)
+			for i := 0; i < tt.entries; i++ {
+				if err := cache.Store(strings.Repeat("k", i+1), &usecases.CachedAnalysis{PackageName: "main"}); err != nil {
+					t.Fatalf("Store failed: %v", err)
+				}
+			}
+
+			cli := &AnalyzerCLI{config: config.Config{CacheDir: dir}, cache: adapters.NewFileCache("")}
+			var code int
+			output := captureStdout(t, func() { code = cli.runCache(tt.args) })
+
+			if code != tt.expectedCode {
+				t.Errorf("Expected exit code %d, got %d", tt.expectedCode, code)
+			}
+			if !strings.Contains(output, tt.expectedOutput) {
+				t.Errorf("Expected output containing %q, got %q", tt.expectedOutput, output)
+			}
+			if stats, _ := adapters.NewFileCache(dir).Stats(); stats.Entries != tt.expectedLeft {
+				t.Errorf("Expected %d entries left, got %d", tt.expectedLeft, stats.Entries)
+			}
+		})
+	}
+}
+
+func TestRunCache_CacheDirFlag(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	cache := adapters.NewFileCache(dir)
+	if err := cache.Store("key", &usecases.CachedAnalysis{PackageName: "main"}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	cli := &AnalyzerCLI{config: config.Config{CacheDir: t.TempDir()}, cache: adapters.NewFileCache("")}
+	output := captureStdout(t, func() {
+		if code := cli.runCache([]string{"-cache-dir", dir, "stats"}); code != 0 {
+			t.Errorf("Expected exit code 0, got %d", code)
+		}
+	})
+	if !strings.Contains(output, "Cache directory: "+dir+"\nEntries: 1\n") {
+		t.Errorf("Expected the flag to select the cache directory, got %q", output)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		size     int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatBytes(tt.size); got != tt.expected {
+			t.Errorf("formatBytes(%d): expected %q, got %q", tt.size, tt.expected, got)
+		}
+	}
+}
+
+// captureStdout returns what fn writes to the standard output
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		output <- string(data)
+	}()
+
+	fn()
+	writer.Close()
+	return <-output
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"goastanalyzer/application/usecases"
+	"goastanalyzer/domain/services"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// fixMode selects what the analyzer does with the suggested fixes of its findings
+type fixMode int
+
+const (
+	fixModeNone fixMode = iota
+	fixModeApply
+	fixModeDiff
+)
+
+// diffContext is the number of unchanged lines around each hunk of a fix diff
+const diffContext = 3
+
+// handleFixes applies the suggested fixes of the findings, writing the fixed files or printing
+// them as a unified diff
+func (cli *AnalyzerCLI) handleFixes(response *usecases.AnalyzeCodeResponse) int {
+	var fixes []valueobjects.SuggestedFix
+	sources := make(map[string][]byte)
+	for _, finding := range response.AnalysisResult.Findings() {
+		for _, fix := range finding.Fixes() {
+			fixes = append(fixes, fix)
+			for _, edit := range fix.Edits() {
+				if _, ok := sources[edit.FilePath()]; ok {
+					continue
+				}
+				source, err := os.ReadFile(edit.FilePath())
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", edit.FilePath(), err)
+					return 1
+				}
+				sources[edit.FilePath()] = source
+			}
+		}
+	}
+
+	result, err := services.NewGoFixApplier().ApplyFixes(sources, fixes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error applying fixes: %v\n", err)
+		return 1
+	}
+
+	paths := make([]string, 0, len(result.Files))
+	for path := range result.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if cli.fixMode == fixModeDiff {
+			fmt.Print(unifiedDiff(path, sources[path], result.Files[path]))
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+			return 1
+		}
+		if err := os.WriteFile(path, result.Files[path], info.Mode().Perm()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+			return 1
+		}
+	}
+
+	verb := "Applied"
+	if cli.fixMode == fixModeDiff {
+		verb = "Suggested"
+	}
+	fmt.Fprintf(os.Stderr, "%s %d fixes to %d files", verb, len(result.Applied), len(paths))
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(os.Stderr, "; skipped %d overlapping fixes, run again to apply them", len(result.Skipped))
+	}
+	fmt.Fprintln(os.Stderr)
+	return 0
+}
+
+// diffOp is one line of a line diff: ' ' kept, '-' removed or '+' added
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns the changes from before to after as a unified diff, in the format of gofmt -d
+func unifiedDiff(path string, before, after []byte) string {
+	ops := diffLines(splitLines(string(before)), splitLines(string(after)))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "diff -u %s.orig %s\n--- %s.orig\n+++ %s\n", path, path, path, path)
+
+	for start := 0; start < len(ops); {
+		// Find the next change and extend its hunk while at most twice the context separates
+		// changes, so that the contexts of two hunks never touch
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		last := first
+		for i := first; i < len(ops) && i <= last+2*diffContext+1; i++ {
+			if ops[i].kind != ' ' {
+				last = i
+			}
+		}
+
+		from := max(first-diffContext, start)
+		to := min(last+diffContext+1, len(ops))
+
+		oldLine, newLine := 1, 1
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				oldLine++
+			}
+			if op.kind != '-' {
+				newLine++
+			}
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		if oldCount == 0 {
+			oldLine--
+		}
+		if newCount == 0 {
+			newLine--
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for _, op := range ops[from:to] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = to
+	}
+
+	return out.String()
+}
+
+// splitLines splits text into lines that keep their newlines
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a shortest line diff with Myers' algorithm. Fixes change few lines, so the
+// common prefix and suffix are trimmed first and the search stays small
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// myersDiff returns the edit script turning a into b
+func myersDiff(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+
+	// trace[d] holds the furthest reaching paths before round d
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		done := false
+		for k := -d; k <= d && !done; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			done = x >= n && y >= m
+		}
+		if done {
+			break
+		}
+	}
+
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{'+', b[y-1]})
+			y--
+		} else {
+			ops = append(ops, diffOp{'-', a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, diffOp{' ', a[x-1]})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
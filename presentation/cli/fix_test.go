@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	base := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	header := "diff -u x.go.orig x.go\n--- x.go.orig\n+++ x.go\n"
+
+	tests := []struct {
+		name     string
+		before   string
+		after    string
+		expected string
+	}{
+		{
+			name:   "Insertion",
+			before: base,
+			after:  strings.Replace(base, "f\n", "f\nnew\n", 1),
+			expected: `@@ -4,6 +4,7 @@
+ d
+ e
+ f
++new
+ g
+ h
+ i
+`,
+		},
+		{
+			name:   "Deletion",
+			before: base,
+			after:  strings.Replace(base, "f\n", "", 1),
+			expected: `@@ -3,7 +3,6 @@
+ c
+ d
+ e
+-f
+ g
+ h
+ i
+`,
+		},
+		{
+			name:   "Replacement",
+			before: base,
+			after:  strings.Replace(base, "f\n", "F\n", 1),
+			expected: `@@ -3,7 +3,7 @@
+ c
+ d
+ e
+-f
++F
+ g
+ h
+ i
+`,
+		},
+		{
+			name:   "Changes twice the context apart share a hunk",
+			before: base,
+			after:  strings.Replace(strings.Replace(base, "b\n", "B\n", 1), "i\n", "I\n", 1),
+			expected: `@@ -1,12 +1,12 @@
+ a
+-b
++B
+ c
+ d
+ e
+ f
+ g
+ h
+-i
++I
+ j
+ k
+ l
+`,
+		},
+		{
+			name:   "Changes further apart get separate hunks",
+			before: base,
+			after:  strings.Replace(strings.Replace(base, "b\n", "B\n", 1), "j\n", "J\n", 1),
+			expected: `@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -7,6 +7,6 @@
+ g
+ h
+ i
+-j
++J
+ k
+ l
+`,
+		},
+		{
+			name:   "Change at the start of the file",
+			before: base,
+			after:  "package\n" + base,
+			expected: `@@ -1,3 +1,4 @@
++package
+ a
+ b
+ c
+`,
+		},
+		{
+			name:   "Change at the end of a file without a trailing newline",
+			before: "a\nb\nc",
+			after:  "a\nb\nd",
+			expected: `@@ -1,3 +1,3 @@
+ a
+ b
+-c
+\ No newline at end of file
++d
+\ No newline at end of file
+`,
+		},
+		{
+			name:   "Trailing newline added",
+			before: "a\nb",
+			after:  "a\nb\n",
+			expected: `@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++b
+`,
+		},
+		{
+			name:   "Empty file",
+			before: "",
+			after:  "a\nb\n",
+			expected: `@@ -0,0 +1,2 @@
++a
++b
+`,
+		},
+		{
+			name:   "File emptied",
+			before: "a\nb\n",
+			after:  "",
+			expected: `@@ -1,2 +0,0 @@
+-a
+-b
+`,
+		},
+		{
+			name:     "No change",
+			before:   base,
+			after:    base,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unifiedDiff("x.go", []byte(tt.before), []byte(tt.after))
+			if got != header+tt.expected {
+				t.Errorf("Unexpected diff:\n%s\nexpected:\n%s", got, header+tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   []string
+		after    []string
+		expected string
+	}{
+		{name: "Identical", before: []string{"a", "b"}, after: []string{"a", "b"}, expected: " a b"},
+		{name: "Both empty", expected: ""},
+		{name: "Interleaved changes", before: []string{"a", "b", "c", "d"}, after: []string{"a", "x", "c", "y"}, expected: " a-b+x c-d+y"},
+		{name: "Moved line", before: []string{"a", "b", "c"}, after: []string{"b", "c", "a"}, expected: "-a b c+a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got strings.Builder
+			for _, op := range diffLines(tt.before, tt.after) {
+				got.WriteByte(op.kind)
+				got.WriteString(op.line)
+			}
+			if got.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+
+	"goastanalyzer/application/usecases"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// runHotspots executes the hotspots command, which ranks code by change frequency times complexity
+func (cli *AnalyzerCLI) runHotspots(args []string) int {
+	flags := flag.NewFlagSet("hotspots", flag.ContinueOnError)
+	var (
+		since      = flags.String("since", "1 year ago", "Time window passed to git log --since, e.g. \"6 months ago\" or 2025-01-01")
+		limit      = flags.Int("limit", 20, "Number of file and function hotspots to show, 0 for all")
+		outputMode = flags.String("output", "table", "Output mode: table, json, html")
+		recursive  = flags.Bool("recursive", false, "Recursively analyze directories for Go files")
+		configFile = flags.String("config-file", "", "Path to a JSON configuration file")
+		cacheDir   = flags.String("cache-dir", "", "Directory caching analysis results between runs, off to disable")
+	)
+	flags.BoolVar(recursive, "r", false, "Recursively analyze directories for Go files (short for -recursive)")
+	flags.Usage = func() {
+		fmt.Println("Usage: goastanalyzer hotspots [options] <files or directories...>")
+		fmt.Println()
+		fmt.Println("Ranks files and functions by the number of commits that changed them in the time")
+		fmt.Println("window times their cyclomatic complexity, using the local git history.")
+		fmt.Println()
+		fmt.Println("Options:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 1
+	}
+
+	cli.recursive = *recursive
+	if err := cli.loadConfigFile(*configFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	cli.setCacheDir(*cacheDir)
+
+	fileList := cli.parseFileList("", flags.Args())
+	if len(fileList) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: No files specified for analysis\n")
+		flags.Usage()
+		return 1
+	}
+
+	response, err := cli.hotspots.Execute(usecases.FindHotspotsRequest{
+		FilePaths:     fileList,
+		Configuration: cli.config.Analysis,
+		Since:         *since,
+		Limit:         *limit,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error executing hotspot analysis: %v\n", err)
+		return 1
+	}
+	if !response.Success {
+		fmt.Fprintf(os.Stderr, "Hotspot analysis failed: %v\n", response.Error)
+		return 1
+	}
+
+	switch strings.ToLower(*outputMode) {
+	case "json":
+		displayHotspotsJSON(response)
+	case "html":
+		if err := displayHotspotsHTML(response); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering HTML: %v\n", err)
+			return 1
+		}
+	default:
+		displayHotspotsTable(response)
+	}
+	return 0
+}
+
+// displayHotspotsTable displays file and function hotspots as tables
+func displayHotspotsTable(response *usecases.FindHotspotsResponse) {
+	fmt.Println(response.Summary)
+	fmt.Println()
+
+	fmt.Println("FILE                                     | REVS | AUTHORS |  CYC |   SCORE")
+	fmt.Println("-----------------------------------------|------|---------|------|--------")
+	for _, hotspot := range response.Files {
+		fmt.Printf("%-40s | %4d | %7d | %4d | %7d\n",
+			truncate(hotspot.Name(), 40),
+			hotspot.Revisions(),
+			hotspot.Authors(),
+			hotspot.Complexity(),
+			hotspot.Score(),
+		)
+	}
+
+	fmt.Println()
+	fmt.Println("FILE                 | LINE | FUNCTION                 | REVS | AUTHORS |  CYC |   SCORE")
+	fmt.Println("---------------------|------|--------------------------|------|---------|------|--------")
+	for _, hotspot := range response.Functions {
+		fmt.Printf("%-20s | %4d | %-24s | %4d | %7d | %4d | %7d\n",
+			truncate(hotspot.Location().FilePath(), 20),
+			hotspot.Location().Line(),
+			truncate(hotspot.Name(), 24),
+			hotspot.Revisions(),
+			hotspot.Authors(),
+			hotspot.Complexity(),
+			hotspot.Score(),
+		)
+	}
+}
+
+// jsonHotspotReport is the document written by the hotspots JSON output mode
+type jsonHotspotReport struct {
+	Summary   string        `json:"summary"`
+	Files     []jsonHotspot `json:"files"`
+	Functions []jsonHotspot `json:"functions"`
+}
+
+// jsonHotspot carries one ranked hotspot in JSON output
+type jsonHotspot struct {
+	File       string `json:"file"`
+	Line       int    `json:"line,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Revisions  int    `json:"revisions"`
+	Authors    int    `json:"authors"`
+	Complexity int    `json:"complexity"`
+	Score      int    `json:"score"`
+}
+
+// displayHotspotsJSON displays file and function hotspots in JSON format
+func displayHotspotsJSON(response *usecases.FindHotspotsResponse) {
+	report := jsonHotspotReport{
+		Summary:   response.Summary,
+		Files:     toJSONHotspots(response.Files),
+		Functions: toJSONHotspots(response.Functions),
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// toJSONHotspots converts hotspots for JSON output; file hotspots carry no line or name
+func toJSONHotspots(hotspots []valueobjects.Hotspot) []jsonHotspot {
+	converted := make([]jsonHotspot, 0, len(hotspots))
+	for _, hotspot := range hotspots {
+		entry := jsonHotspot{
+			File:       hotspot.Location().FilePath(),
+			Revisions:  hotspot.Revisions(),
+			Authors:    hotspot.Authors(),
+			Complexity: hotspot.Complexity(),
+			Score:      hotspot.Score(),
+		}
+		if hotspot.Kind() == valueobjects.HotspotKindFunction {
+			entry.Line = hotspot.Location().Line()
+			entry.Name = hotspot.Name()
+		}
+		converted = append(converted, entry)
+	}
+	return converted
+}
+
+// hotspotBar is one bar of the HTML hotspot chart
+type hotspotBar struct {
+	Label   string
+	Detail  string
+	Score   int
+	Percent float64
+}
+
+// displayHotspotsHTML writes a self-contained HTML page with a bar chart of the hotspots
+func displayHotspotsHTML(response *usecases.FindHotspotsResponse) error {
+	return hotspotsTemplate.Execute(os.Stdout, struct {
+		Summary   string
+		Files     []hotspotBar
+		Functions []hotspotBar
+	}{
+		Summary:   response.Summary,
+		Files:     hotspotBars(response.Files),
+		Functions: hotspotBars(response.Functions),
+	})
+}
+
+// hotspotBars scales hotspot scores to bar widths relative to the highest score
+func hotspotBars(hotspots []valueobjects.Hotspot) []hotspotBar {
+	bars := make([]hotspotBar, 0, len(hotspots))
+	for _, hotspot := range hotspots {
+		bar := hotspotBar{
+			Label:  hotspot.Name(),
+			Detail: fmt.Sprintf("%d revisions, %d authors, complexity %d", hotspot.Revisions(), hotspot.Authors(), hotspot.Complexity()),
+			Score:  hotspot.Score(),
+		}
+		if hotspot.Kind() == valueobjects.HotspotKindFunction {
+			bar.Label = fmt.Sprintf("%s (%s:%d)", hotspot.Name(), hotspot.Location().FilePath(), hotspot.Location().Line())
+		}
+		if top := hotspots[0].Score(); top > 0 {
+			bar.Percent = float64(hotspot.Score()) * 100 / float64(top)
+		}
+		bars = append(bars, bar)
+	}
+	return bars
+}
+
+// hotspotsTemplate renders the hotspot chart; hotspots arrive sorted, so the first bar is the widest
+var hotspotsTemplate = template.Must(template.New("hotspots").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Hotspots</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+td { padding: 2px 8px; font-size: 13px; white-space: nowrap; }
+td.bar { width: 60%; }
+div.bar { background: #d9534f; height: 14px; }
+span.detail { color: #777; }
+</style>
+</head>
+<body>
+<h1>Hotspots</h1>
+<p>{{.Summary}}</p>
+{{define "chart"}}<table>
+{{range .}}<tr><td>{{.Label}}<br><span class="detail">{{.Detail}}</span></td><td class="bar"><div class="bar" style="width: {{printf "%.1f" .Percent}}%"></div></td><td>{{.Score}}</td></tr>
+{{end}}</table>{{end}}
+<h2>Files</h2>
+{{template "chart" .Files}}
+<h2>Functions</h2>
+{{template "chart" .Functions}}
+</body>
+</html>
+`))
@@ -0,0 +1,511 @@
+package cli
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"html"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"goastanalyzer/application/usecases"
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// htmlReport is the data of the HTML output mode
+type htmlReport struct {
+	Summary       string
+	Cards         []htmlCard
+	Duplication   []valueobjects.PackageDuplication
+	Types         []string
+	Severities    []string
+	Findings      []htmlFinding
+	Heatmap       []htmlHeatFile
+	Files         []htmlFile
+	MaxCyclomatic int
+	MaxCognitive  int
+}
+
+// htmlCard is one figure of the summary dashboard
+type htmlCard struct {
+	Label string
+	Value string
+	Alert bool
+}
+
+// htmlFinding is one finding in the findings table or annotated on a source line
+type htmlFinding struct {
+	Link         string
+	File         string
+	Line         int
+	Column       int
+	Type         string
+	Severity     string
+	SeverityRank int
+	Message      string
+	Fixes        []string
+}
+
+// htmlHeatFile holds the heatmap cells of the functions of one file
+type htmlHeatFile struct {
+	Path      string
+	Link      string
+	Functions []htmlHeatCell
+}
+
+// htmlHeatCell is one function of the complexity heatmap, colored from green to red as its
+// complexity approaches and passes the configured limits
+type htmlHeatCell struct {
+	Name       string
+	Link       string
+	Line       int
+	Cyclomatic int
+	Cognitive  int
+	Hue        int
+}
+
+// htmlFile is the page of one analyzed file
+type htmlFile struct {
+	ID       string
+	Path     string
+	Findings int
+	Lines    []htmlLine
+	Error    string
+}
+
+// htmlLine is one highlighted source line with the findings and function metrics reported on it
+type htmlLine struct {
+	ID       string
+	Number   int
+	Code     template.HTML
+	Function *htmlHeatCell
+	Findings []htmlFinding
+}
+
+// displayHTML writes a self-contained HTML report: a dashboard, a sortable findings table, a
+// complexity heatmap and a page per file with its highlighted source and findings inline
+func (cli *AnalyzerCLI) displayHTML(w io.Writer, response *usecases.AnalyzeCodeResponse) error {
+	return reportTemplate.Execute(w, cli.htmlReport(response))
+}
+
+// htmlReport gathers the data of the HTML report
+func (cli *AnalyzerCLI) htmlReport(response *usecases.AnalyzeCodeResponse) htmlReport {
+	result := response.AnalysisResult
+	summary := result.Summary()
+	report := htmlReport{
+		Summary:       response.Summary,
+		Duplication:   summary.Duplication,
+		MaxCyclomatic: cli.config.Analysis.MaxCyclomaticComplexity(),
+		MaxCognitive:  cli.config.Analysis.MaxCognitiveComplexity(),
+	}
+	report.Cards = []htmlCard{
+		{Label: "Files", Value: fmt.Sprint(summary.TotalFiles)},
+		{Label: "Functions", Value: fmt.Sprint(summary.TotalFunctions)},
+		{Label: "Findings", Value: fmt.Sprint(summary.TotalFindings), Alert: summary.TotalFindings > 0},
+		{Label: "High severity", Value: fmt.Sprint(summary.HighSeverityCount), Alert: summary.HighSeverityCount > 0},
+		{Label: "Complexity", Value: fmt.Sprint(summary.ComplexityFindings)},
+		{Label: "Smells", Value: fmt.Sprint(summary.SmellFindings)},
+		{Label: "Security", Value: fmt.Sprint(summary.SecurityFindings)},
+		{Label: "Performance", Value: fmt.Sprint(summary.PerformanceFindings)},
+		{Label: "Duplication", Value: fmt.Sprintf("%.1f%%", summary.DuplicationPercentage)},
+		{Label: "Duration", Value: summary.Duration.Round(time.Millisecond).String()},
+	}
+
+	paths := append([]string(nil), result.AnalyzedFiles()...)
+	sort.Strings(paths)
+	fileIDs := make(map[string]string, len(paths))
+	for i, path := range paths {
+		fileIDs[path] = fmt.Sprintf("f%d", i+1)
+	}
+
+	// Findings, in file and line order, linked to their line on the file's page
+	findings := result.Findings()
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i].Location(), findings[j].Location()
+		if a.FilePath() != b.FilePath() {
+			return a.FilePath() < b.FilePath()
+		}
+		return a.Line() < b.Line()
+	})
+	findingTypes, severities := make(map[string]bool), make(map[valueobjects.SeverityLevel]bool)
+	findingsByLine := make(map[string]map[int][]htmlFinding)
+	for _, finding := range findings {
+		converted := newHTMLFinding(finding, fileIDs)
+		report.Findings = append(report.Findings, converted)
+		findingTypes[converted.Type] = true
+		severities[finding.Severity()] = true
+
+		path := finding.Location().FilePath()
+		if findingsByLine[path] == nil {
+			findingsByLine[path] = make(map[int][]htmlFinding)
+		}
+		findingsByLine[path][converted.Line] = append(findingsByLine[path][converted.Line], converted)
+	}
+	for name := range findingTypes {
+		report.Types = append(report.Types, name)
+	}
+	sort.Strings(report.Types)
+	for _, level := range []valueobjects.SeverityLevel{
+		valueobjects.SeverityInfo,
+		valueobjects.SeverityWarning,
+		valueobjects.SeverityError,
+		valueobjects.SeverityCritical,
+	} {
+		if severities[level] {
+			report.Severities = append(report.Severities, level.String())
+		}
+	}
+
+	// Heatmap cells, grouped by file in line order
+	functions := make(map[string]map[int]htmlHeatCell)
+	heatmap := make(map[string]*htmlHeatFile)
+	for _, metrics := range result.FunctionMetrics() {
+		path := metrics.Location().FilePath()
+		cell := htmlHeatCell{
+			Name:       metrics.Name(),
+			Link:       "#" + fileIDs[path] + fmt.Sprintf("-L%d", metrics.Location().Line()),
+			Line:       metrics.Location().Line(),
+			Cyclomatic: metrics.Complexity().Cyclomatic(),
+			Cognitive:  metrics.Complexity().Cognitive(),
+			Hue:        heatHue(metrics.Complexity(), report.MaxCyclomatic, report.MaxCognitive),
+		}
+		if heatmap[path] == nil {
+			heatmap[path] = &htmlHeatFile{Path: path, Link: "#" + fileIDs[path]}
+			functions[path] = make(map[int]htmlHeatCell)
+		}
+		heatmap[path].Functions = append(heatmap[path].Functions, cell)
+		functions[path][cell.Line] = cell
+	}
+	for _, path := range paths {
+		if file := heatmap[path]; file != nil {
+			sort.SliceStable(file.Functions, func(i, j int) bool { return file.Functions[i].Line < file.Functions[j].Line })
+			report.Heatmap = append(report.Heatmap, *file)
+		}
+	}
+
+	for _, path := range paths {
+		page := htmlFile{ID: fileIDs[path], Path: path}
+		for _, lineFindings := range findingsByLine[path] {
+			page.Findings += len(lineFindings)
+		}
+
+		source, err := os.ReadFile(path)
+		if err != nil {
+			page.Error = err.Error()
+			report.Files = append(report.Files, page)
+			continue
+		}
+		for i, code := range highlightGo(source) {
+			number := i + 1
+			line := htmlLine{
+				ID:       fmt.Sprintf("%s-L%d", page.ID, number),
+				Number:   number,
+				Code:     code,
+				Findings: findingsByLine[path][number],
+			}
+			if cell, ok := functions[path][number]; ok {
+				line.Function = &cell
+			}
+			page.Lines = append(page.Lines, line)
+		}
+		report.Files = append(report.Files, page)
+	}
+
+	return report
+}
+
+// newHTMLFinding converts a finding, linking it to its line when its file has a page
+func newHTMLFinding(finding entities.AnalysisFinding, fileIDs map[string]string) htmlFinding {
+	location := finding.Location()
+	converted := htmlFinding{
+		File:         location.FilePath(),
+		Line:         location.Line(),
+		Column:       location.Column(),
+		Type:         finding.Type().String(),
+		Severity:     finding.Severity().String(),
+		SeverityRank: int(finding.Severity()),
+		Message:      finding.Message(),
+	}
+	if id, ok := fileIDs[location.FilePath()]; ok {
+		converted.Link = fmt.Sprintf("#%s-L%d", id, location.Line())
+	}
+	for _, fix := range finding.Fixes() {
+		converted.Fixes = append(converted.Fixes, fix.Description())
+	}
+	return converted
+}
+
+// heatHue maps a function's complexity relative to the limits to a hue: green well below the
+// limits, orange at the limit and red at one and a half times the limit or more
+func heatHue(complexity valueobjects.ComplexityScore, maxCyclomatic, maxCognitive int) int {
+	ratio := 0.0
+	if maxCyclomatic > 0 {
+		ratio = float64(complexity.Cyclomatic()) / float64(maxCyclomatic)
+	}
+	if maxCognitive > 0 {
+		ratio = math.Max(ratio, float64(complexity.Cognitive())/float64(maxCognitive))
+	}
+	ratio = math.Min(ratio, 1.5)
+	return int(math.Round(120 * (1 - ratio/1.5)))
+}
+
+// highlightGo splits Go source into lines of escaped HTML, wrapping keywords, literals, comments
+// and predeclared identifiers in spans. Tokens spanning lines, such as raw strings and block
+// comments, are wrapped on each line
+func highlightGo(source []byte) []template.HTML {
+	classes := make([]string, len(source))
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(source))
+	var s scanner.Scanner
+	s.Init(file, source, nil, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		class := ""
+		switch {
+		case tok == token.COMMENT:
+			class = "com"
+		case tok == token.STRING || tok == token.CHAR:
+			class = "str"
+		case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+			class = "num"
+		case tok.IsKeyword():
+			class = "kw"
+		case tok == token.IDENT && types.Universe.Lookup(lit) != nil:
+			class = "builtin"
+		}
+		if class == "" {
+			continue
+		}
+
+		start := file.Offset(pos)
+		for i := start; i < start+len(lit) && i < len(source); i++ {
+			classes[i] = class
+		}
+	}
+
+	var lines []template.HTML
+	var line strings.Builder
+	for start := 0; start < len(source); {
+		if source[start] == '\n' {
+			lines = append(lines, template.HTML(line.String()))
+			line.Reset()
+			start++
+			continue
+		}
+
+		end := start + 1
+		for end < len(source) && source[end] != '\n' && classes[end] == classes[start] {
+			end++
+		}
+		text := html.EscapeString(string(source[start:end]))
+		if classes[start] == "" {
+			line.WriteString(text)
+		} else {
+			fmt.Fprintf(&line, `<span class="%s">%s</span>`, classes[start], text)
+		}
+		start = end
+	}
+	if line.Len() > 0 {
+		lines = append(lines, template.HTML(line.String()))
+	}
+	return lines
+}
+
+// reportTemplate renders the HTML report. Each file page is a section shown in place of the
+// dashboard when the location hash points into it, so the report stays a single file
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Go AST Analyzer Report</title>
+<style>
+body { font-family: sans-serif; margin: 0; color: #222; }
+header { background: #2c3e50; color: #fff; padding: 1em 2em; }
+header a { color: #fff; }
+main { padding: 1em 2em; }
+section.page:not(.active) { display: none; }
+.cards { display: flex; flex-wrap: wrap; gap: 10px; }
+.card { border: 1px solid #ddd; border-radius: 4px; padding: 8px 14px; min-width: 100px; }
+.card .value { font-size: 22px; font-weight: bold; }
+.card.alert .value { color: #c0392b; }
+.card .label { color: #777; font-size: 12px; }
+.filters { margin: 1em 0; font-size: 13px; }
+.filters label { margin-right: 10px; }
+table { border-collapse: collapse; width: 100%; font-size: 13px; }
+th, td { padding: 3px 8px; text-align: left; vertical-align: top; border-bottom: 1px solid #eee; }
+th { cursor: pointer; user-select: none; background: #f5f5f5; }
+th.asc::after { content: " \25B2"; }
+th.desc::after { content: " \25BC"; }
+.severity-info { color: #2980b9; }
+.severity-warning { color: #d68910; }
+.severity-error, .severity-critical { color: #c0392b; font-weight: bold; }
+.fix { color: #27ae60; font-size: 12px; }
+.heatmap h3 { font-size: 14px; margin: 10px 0 4px; }
+.cells { display: flex; flex-wrap: wrap; gap: 3px; }
+.cell { display: block; width: 110px; padding: 3px 5px; font-size: 11px; color: #222; text-decoration: none; overflow: hidden; white-space: nowrap; text-overflow: ellipsis; }
+.legend { font-size: 12px; color: #777; }
+div.source { font-family: monospace; font-size: 12px; line-height: 1.45; }
+.source .line { display: flex; min-height: 1.45em; }
+.source .line:target { background: #fff3c4; }
+.source .number { color: #aaa; width: 4em; text-align: right; padding-right: 1em; flex-shrink: 0; text-decoration: none; }
+.source .code { white-space: pre; }
+.source .annotation { margin: 2px 0 2px 5em; padding: 3px 8px; border-left: 3px solid #d68910; background: #fdf6e3; white-space: normal; font-family: sans-serif; }
+.source .annotation.severity-error, .source .annotation.severity-critical { border-color: #c0392b; }
+.source .annotation.severity-info { border-color: #2980b9; }
+.source .metrics { margin: 2px 0 2px 5em; font-family: sans-serif; font-size: 11px; color: #555; }
+.kw { color: #8e44ad; font-weight: bold; }
+.str { color: #27ae60; }
+.num { color: #d35400; }
+.com { color: #999; font-style: italic; }
+.builtin { color: #2980b9; }
+</style>
+</head>
+<body>
+<header>
+<h1>Go AST Analyzer Report</h1>
+<p>{{.Summary}}</p>
+<a href="#dashboard">Dashboard</a>
+</header>
+<main>
+<section class="page active" id="dashboard">
+<div class="cards">
+{{range .Cards}}<div class="card{{if .Alert}} alert{{end}}"><div class="value">{{.Value}}</div><div class="label">{{.Label}}</div></div>
+{{end}}</div>
+{{if .Duplication}}<h2>Duplication</h2>
+<table>
+<tr><th>Package</th><th>Duplicated tokens</th><th>Total tokens</th><th>Duplication</th></tr>
+{{range .Duplication}}<tr><td>{{.Package}}</td><td>{{.DuplicatedTokens}}</td><td>{{.TotalTokens}}</td><td>{{printf "%.1f" .Percentage}}%</td></tr>
+{{end}}</table>{{end}}
+<h2>Findings</h2>
+<div class="filters">
+Type: {{range .Types}}<label><input type="checkbox" class="filter" data-filter="type" value="{{.}}" checked> {{.}}</label>{{end}}
+Severity: {{range .Severities}}<label><input type="checkbox" class="filter" data-filter="severity" value="{{.}}" checked> {{.}}</label>{{end}}
+<span id="shown"></span>
+</div>
+<table class="sortable" id="findings">
+<thead><tr><th>File</th><th>Line</th><th>Type</th><th>Severity</th><th>Message</th></tr></thead>
+<tbody>
+{{range .Findings}}<tr class="finding" data-type="{{.Type}}" data-severity="{{.Severity}}">
+<td>{{if .Link}}<a href="{{.Link}}">{{.File}}</a>{{else}}{{.File}}{{end}}</td>
+<td data-sort="{{.Line}}">{{.Line}}:{{.Column}}</td>
+<td>{{.Type}}</td>
+<td data-sort="{{.SeverityRank}}" class="severity-{{.Severity}}">{{.Severity}}</td>
+<td>{{.Message}}{{range .Fixes}}<div class="fix">fix: {{.}}</div>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+<h2>Complexity Heatmap</h2>
+<p class="legend">Each function is colored by the larger of its cyclomatic complexity over {{.MaxCyclomatic}} and its cognitive complexity over {{.MaxCognitive}}: green is well below the limits, orange at them and red far above.</p>
+<div class="heatmap">
+{{range .Heatmap}}<h3><a href="{{.Link}}">{{.Path}}</a></h3>
+<div class="cells">
+{{range .Functions}}<a class="cell" href="{{.Link}}" style="background: hsl({{.Hue}}, 70%, 75%)" title="{{.Name}}: cyclomatic {{.Cyclomatic}}, cognitive {{.Cognitive}}">{{.Name}}<br>{{.Cyclomatic}} / {{.Cognitive}}</a>
+{{end}}</div>
+{{end}}</div>
+<h2>Files</h2>
+<table class="sortable">
+<thead><tr><th>File</th><th>Findings</th></tr></thead>
+<tbody>
+{{range .Files}}<tr><td><a href="#{{.ID}}">{{.Path}}</a></td><td>{{.Findings}}</td></tr>
+{{end}}</tbody>
+</table>
+</section>
+{{range .Files}}<section class="page" id="{{.ID}}">
+<h2>{{.Path}}</h2>
+<p>{{.Findings}} findings</p>
+{{if .Error}}<p>Source unavailable: {{.Error}}</p>{{end}}
+<div class="source">
+{{range .Lines}}<div class="line" id="{{.ID}}"><a class="number" href="#{{.ID}}">{{.Number}}</a><span class="code">{{.Code}}</span></div>{{if .Function}}<div class="metrics">{{.Function.Name}}: cyclomatic {{.Function.Cyclomatic}}, cognitive {{.Function.Cognitive}}</div>{{end}}{{range .Findings}}<div class="annotation finding severity-{{.Severity}}" data-type="{{.Type}}" data-severity="{{.Severity}}">[{{.Severity}}] {{.Message}}{{range .Fixes}}<div class="fix">fix: {{.}}</div>{{end}}</div>{{end}}
+{{end}}</div>
+</section>
+{{end}}</main>
+<script>
+(function() {
+	// Show the page the location hash points into: a file page or one of its lines
+	function route() {
+		var id = location.hash.slice(1).split("-")[0] || "dashboard";
+		var page = document.getElementById(id);
+		if (!page || !page.classList.contains("page")) {
+			page = document.getElementById("dashboard");
+		}
+		document.querySelectorAll("section.page").forEach(function(section) {
+			section.classList.toggle("active", section === page);
+		});
+		var target = location.hash && document.getElementById(location.hash.slice(1));
+		if (target) {
+			target.scrollIntoView();
+		}
+	}
+	window.addEventListener("hashchange", route);
+	route();
+
+	// Hide findings whose type or severity is unchecked, in the table and on the file pages
+	function filter() {
+		var allowed = {type: {}, severity: {}};
+		document.querySelectorAll("input.filter").forEach(function(box) {
+			allowed[box.dataset.filter][box.value] = box.checked;
+		});
+		var shown = 0, total = 0;
+		document.querySelectorAll(".finding").forEach(function(finding) {
+			var visible = allowed.type[finding.dataset.type] && allowed.severity[finding.dataset.severity];
+			finding.style.display = visible ? "" : "none";
+			if (finding.tagName === "TR") {
+				total++;
+				if (visible) {
+					shown++;
+				}
+			}
+		});
+		document.getElementById("shown").textContent = shown + " of " + total + " shown";
+	}
+	document.querySelectorAll("input.filter").forEach(function(box) {
+		box.addEventListener("change", filter);
+	});
+	filter();
+
+	// Sort a table by a column, numerically when every cell is a number
+	document.querySelectorAll("table.sortable th").forEach(function(header) {
+		header.addEventListener("click", function() {
+			var table = header.closest("table");
+			var column = Array.prototype.indexOf.call(header.parentNode.children, header);
+			var ascending = !header.classList.contains("asc");
+			table.querySelectorAll("th").forEach(function(th) {
+				th.classList.remove("asc", "desc");
+			});
+			header.classList.add(ascending ? "asc" : "desc");
+
+			var body = table.tBodies[0];
+			var rows = Array.prototype.slice.call(body.rows);
+			var key = function(row) {
+				var cell = row.cells[column];
+				return cell.dataset.sort !== undefined ? cell.dataset.sort : cell.textContent.trim();
+			};
+			var numeric = rows.every(function(row) {
+				return key(row) !== "" && !isNaN(key(row));
+			});
+			rows.sort(function(a, b) {
+				var x = key(a), y = key(b);
+				var order = numeric ? x - y : x.localeCompare(y);
+				return ascending ? order : -order;
+			});
+			rows.forEach(function(row) {
+				body.appendChild(row);
+			});
+		});
+	});
+})();
+</script>
+</body>
+</html>
+`))
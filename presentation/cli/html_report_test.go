@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goastanalyzer/application/usecases"
+	"goastanalyzer/domain/aggregates"
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/valueobjects"
+	"goastanalyzer/infrastructure/adapters"
+	"goastanalyzer/infrastructure/config"
+)
+
+func TestHighlightGo(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected []template.HTML
+	}{
+		{
+			name:     "Keywords",
+			source:   "package main\n",
+			expected: []template.HTML{`<span class="kw">package</span> main`},
+		},
+		{
+			name:   "Strings and comments are escaped",
+			source: "x := \"a<b>\" // c&d\n",
+			expected: []template.HTML{
+				`x := <span class="str">&#34;a&lt;b&gt;&#34;</span> <span class="com">// c&amp;d</span>`,
+			},
+		},
+		{
+			name:   "Numbers and predeclared identifiers",
+			source: "n := len(s) + 42 + 'c'\n",
+			expected: []template.HTML{
+				`n := <span class="builtin">len</span>(s) + <span class="num">42</span> + <span class="str">&#39;c&#39;</span>`,
+			},
+		},
+		{
+			name:     "Operators outside tokens are escaped",
+			source:   "ok := a < b && c > d",
+			expected: []template.HTML{`ok := a &lt; b &amp;&amp; c &gt; d`},
+		},
+		{
+			name:   "Raw string spanning lines is wrapped on each line",
+			source: "s := `a\n<b>`\n",
+			expected: []template.HTML{
+				"s := <span class=\"str\">`a</span>",
+				"<span class=\"str\">&lt;b&gt;`</span>",
+			},
+		},
+		{
+			name:   "Block comment spanning lines is wrapped on each line",
+			source: "/* a\nb */\nvar x int\n",
+			expected: []template.HTML{
+				`<span class="com">/* a</span>`,
+				`<span class="com">b */</span>`,
+				`<span class="kw">var</span> x <span class="builtin">int</span>`,
+			},
+		},
+		{
+			name:     "Empty lines are kept",
+			source:   "a\n\nb\n",
+			expected: []template.HTML{"a", "", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := highlightGo([]byte(tt.source))
+			if len(lines) != len(tt.expected) {
+				t.Fatalf("Expected %d lines, got %d: %q", len(tt.expected), len(lines), lines)
+			}
+			for i := range lines {
+				if lines[i] != tt.expected[i] {
+					t.Errorf("Line %d: expected %q, got %q", i+1, tt.expected[i], lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDisplayHTML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	source := "package main\n\nfunc run() {\n\tprintln(\"<hi>\")\n}\n"
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	missing := filepath.Join(dir, "missing.go")
+
+	cli := &AnalyzerCLI{config: config.Config{Analysis: valueobjects.DefaultAnalysisConfiguration()}}
+	response := newHTMLTestResponse(t, path, missing)
+
+	var output bytes.Buffer
+	if err := cli.displayHTML(&output, response); err != nil {
+		t.Fatalf("displayHTML failed: %v", err)
+	}
+	report := output.String()
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "Summary", expected: "<p>2 findings</p>"},
+		{name: "Findings card", expected: `<div class="card alert"><div class="value">2</div><div class="label">Findings</div></div>`},
+		{name: "High severity card", expected: `<div class="card alert"><div class="value">1</div><div class="label">High severity</div></div>`},
+		{name: "Type filter", expected: `data-filter="type" value="smell" checked`},
+		{name: "Severity filter", expected: `data-filter="severity" value="error" checked`},
+		{name: "Findings table row links to the line", expected: `<td><a href="#f1-L3">` + path + `</a></td>`},
+		{name: "Findings table message is escaped", expected: `<td>Function &lt;run&gt; is too complex<div class="fix">fix: Split run</div></td>`},
+		{name: "Heatmap cell", expected: `title="run: cyclomatic 12, cognitive 3"`},
+		{name: "File page", expected: `<section class="page" id="f1">`},
+		{name: "Highlighted source line", expected: `<div class="line" id="f1-L3"><a class="number" href="#f1-L3">3</a><span class="code"><span class="kw">func</span> run() {</span></div>`},
+		{name: "Escaped source", expected: `<span class="str">&#34;&lt;hi&gt;&#34;</span>`},
+		{name: "Function metrics on the declaration line", expected: `<div class="metrics">run: cyclomatic 12, cognitive 3</div>`},
+		{name: "Annotation on the finding line", expected: `<div class="annotation finding severity-error" data-type="smell" data-severity="error">[error] Function &lt;run&gt; is too complex`},
+		{name: "Unreadable file page", expected: `<section class="page" id="f2">`},
+		{name: "Unreadable file reason", expected: "<p>Source unavailable: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(report, tt.expected) {
+				t.Errorf("Expected report to contain %q", tt.expected)
+			}
+		})
+	}
+
+	if strings.Contains(report, "Function <run>") {
+		t.Errorf("Expected finding messages to be escaped")
+	}
+}
+
+func TestDisplayHTML_WriteError(t *testing.T) {
+	cli := &AnalyzerCLI{config: config.Config{Analysis: valueobjects.DefaultAnalysisConfiguration()}}
+	response := newHTMLTestResponse(t, filepath.Join(t.TempDir(), "main.go"))
+
+	if err := cli.displayHTML(failingWriter{}, response); err == nil {
+		t.Errorf("Expected an error when the report cannot be written")
+	}
+}
+
+func TestAnalyzeFiles_OutputError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		mode OutputMode
+	}{
+		{name: "JSON", mode: OutputModeJSON},
+		{name: "HTML", mode: OutputModeHTML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &AnalyzerCLI{
+				config:     config.Config{Analysis: valueobjects.DefaultAnalysisConfiguration()},
+				useCase:    newAnalyzeCodeUseCase(adapters.NewFileCache("")),
+				outputMode: tt.mode,
+			}
+
+			// Writing to a closed standard output fails like a full disk or a closed pipe
+			closed, err := os.CreateTemp(t.TempDir(), "stdout")
+			if err != nil {
+				t.Fatalf("Failed to create file: %v", err)
+			}
+			closed.Close()
+			stdout := os.Stdout
+			os.Stdout = closed
+			code := cli.analyzeFiles([]string{path}, nil)
+			os.Stdout = stdout
+
+			if code == 0 {
+				t.Errorf("Expected a non-zero exit code when the results cannot be written")
+			}
+		})
+	}
+}
+
+// newHTMLTestResponse builds a response with a complex function and two findings in the first
+// path; the other paths are analyzed files without findings
+func newHTMLTestResponse(t *testing.T, path string, others ...string) *usecases.AnalyzeCodeResponse {
+	t.Helper()
+
+	result, err := aggregates.NewAnalysisResult("report", valueobjects.DefaultAnalysisConfiguration())
+	if err != nil {
+		t.Fatalf("Failed to create result: %v", err)
+	}
+	result.AddAnalyzedFile(path)
+	for _, other := range others {
+		result.AddAnalyzedFile(other)
+	}
+	result.SetTotalFunctions(1)
+
+	location, _ := valueobjects.NewSourceLocation(path, 3, 1)
+	complexity, _ := valueobjects.NewComplexityScore(12, 3)
+	halstead, _ := valueobjects.NewHalsteadMetrics(1, 1, 1, 1)
+	lines, _ := valueobjects.NewLinesOfCode(3, 1, 0)
+	metrics, _ := valueobjects.NewFunctionMetrics("run", location, complexity, halstead, lines)
+	result.AddFunctionMetrics(metrics)
+
+	complex, _ := entities.NewAnalysisFinding("complex_run", entities.FindingTypeSmell, location, "Function <run> is too complex", valueobjects.SeverityError)
+	edit, _ := valueobjects.NewTextEdit(path, 14, 17, "runAll")
+	fix, err := valueobjects.NewSuggestedFix("Split run", edit)
+	if err != nil {
+		t.Fatalf("Failed to create fix: %v", err)
+	}
+	complex.AddFix(fix)
+	if err := result.AddFinding(complex); err != nil {
+		t.Fatalf("Failed to add finding: %v", err)
+	}
+	printLocation, _ := valueobjects.NewSourceLocation(path, 4, 2)
+	print, _ := entities.NewAnalysisFinding("print_run", entities.FindingTypePerformance, printLocation, "println in run", valueobjects.SeverityInfo)
+	if err := result.AddFinding(print); err != nil {
+		t.Fatalf("Failed to add finding: %v", err)
+	}
+
+	return &usecases.AnalyzeCodeResponse{
+		AnalysisResult: result,
+		Summary:        "2 findings",
+		Success:        true,
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"goastanalyzer/presentation/lsp"
+)
+
+// runLsp executes the lsp command, which serves the Language Server Protocol over stdin and stdout
+func (cli *AnalyzerCLI) runLsp(args []string) int {
+	flags := flag.NewFlagSet("lsp", flag.ContinueOnError)
+	var (
+		configFile = flags.String("config-file", "", "Path to a JSON configuration file")
+		cacheDir   = flags.String("cache-dir", "", "Directory caching analysis results between runs, off to keep them in memory")
+	)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: goastanalyzer lsp [options]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Serves the Language Server Protocol over stdin and stdout. Findings are published as")
+		fmt.Fprintln(os.Stderr, "diagnostics when a Go file is opened or saved; hovers show complexity breakdowns, code")
+		fmt.Fprintln(os.Stderr, "lenses show the complexity of each function, and code actions insert //goast:ignore.")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 1
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: lsp takes no arguments\n")
+		flags.Usage()
+		return 1
+	}
+
+	if err := cli.loadConfigFile(*configFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	cli.setCacheDir(*cacheDir)
+
+	server := lsp.NewServer(cli.incrementalAnalyzers(), cli.config.Analysis)
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
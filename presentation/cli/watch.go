@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"goastanalyzer/application/usecases"
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/services"
+	"goastanalyzer/infrastructure/adapters"
+)
+
+// runWatch executes the watch command, which re-analyzes the code on every change and prints the
+// findings that appeared, disappeared or changed
+func (cli *AnalyzerCLI) runWatch(args []string) int {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	var (
+		interval   = flags.Duration("interval", 500*time.Millisecond, "How often to poll the watched files for changes")
+		recursive  = flags.Bool("recursive", false, "Recursively watch directories for Go files")
+		configFile = flags.String("config-file", "", "Path to a JSON configuration file")
+		cacheDir   = flags.String("cache-dir", "", "Directory caching analysis results between runs, off to keep them in memory")
+	)
+	flags.BoolVar(recursive, "r", false, "Recursively watch directories for Go files (short for -recursive)")
+	flags.Usage = func() {
+		fmt.Println("Usage: goastanalyzer watch [options] <files or directories...>")
+		fmt.Println()
+		fmt.Println("Analyzes the files, then re-analyzes them whenever a Go file is added, modified or")
+		fmt.Println("removed, printing the findings that appeared (+), disappeared (-) or changed (~).")
+		fmt.Println("A directory given as dir/... is watched recursively, like a go command pattern.")
+		fmt.Println()
+		fmt.Println("Options:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 1
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: -interval must be positive, got %v\n", *interval)
+		return 1
+	}
+
+	if err := cli.loadConfigFile(*configFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	cli.setCacheDir(*cacheDir)
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: No files specified for watching\n")
+		flags.Usage()
+		return 1
+	}
+
+	watch := usecases.NewWatchCodeUseCase(
+		cli.incrementalAnalyzers(),
+		adapters.NewPollingFileWatcher(paths, *recursive, *interval),
+		services.NewEditFindingDiffer(),
+	)
+
+	stop := make(chan struct{})
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		<-interrupt
+		close(stop)
+	}()
+
+	err := watch.Execute(usecases.WatchCodeRequest{
+		Configuration:         cli.config.Analysis,
+		IncludeSmellDetection: cli.config.Analysis.IsSmellDetectionEnabled(),
+		OnUpdate:              printWatchUpdate,
+	}, stop)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// incrementalAnalyzers returns a factory of analyzers sharing the cache. Without a cache directory,
+// results are still reused between runs of this process
+func (cli *AnalyzerCLI) incrementalAnalyzers() func() usecases.AnalyzeCodeUseCase {
+	if cli.cache.Dir() != "" {
+		return func() usecases.AnalyzeCodeUseCase { return newAnalyzeCodeUseCase(cli.cache) }
+	}
+
+	cache := adapters.NewMemoryCache()
+	return func() usecases.AnalyzeCodeUseCase {
+		cache.StartRun()
+		return newAnalyzeCodeUseCase(cache)
+	}
+}
+
+// printWatchUpdate prints one analysis of the watch command as a timestamped diff of findings
+func printWatchUpdate(update usecases.WatchUpdate) {
+	stamp := time.Now().Format("15:04:05")
+	trigger := "Initial analysis"
+	if len(update.Changed) > 0 {
+		trigger = "Changed " + describeChangedFiles(update.Changed)
+	}
+
+	if !update.Response.Success {
+		fmt.Printf("[%s] %s: analysis failed: %v\n", stamp, trigger, update.Response.Error)
+		return
+	}
+
+	diff := update.Diff
+	fmt.Printf("[%s] %s: %d appeared, %d disappeared, %d changed, %d findings in total\n", stamp, trigger,
+		len(diff.Appeared), len(diff.Disappeared), len(diff.Changed), len(update.Response.AnalysisResult.Findings()))
+	printWatchFindings("+", diff.Appeared)
+	printWatchFindings("-", diff.Disappeared)
+	printWatchFindings("~", diff.Changed)
+}
+
+// printWatchFindings prints findings of one kind of change behind its marker
+func printWatchFindings(marker string, findings []entities.AnalysisFinding) {
+	for _, finding := range findings {
+		fmt.Printf("  %s %s\n", marker, finding.String())
+	}
+}
+
+// describeChangedFiles lists the first few changed files
+func describeChangedFiles(files []string) string {
+	const shown = 3
+	if len(files) <= shown {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:shown], ", "), len(files)-shown)
+}
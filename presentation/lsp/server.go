@@ -0,0 +1,545 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"goastanalyzer/application/usecases"
+	"goastanalyzer/domain/entities"
+	"goastanalyzer/domain/services"
+	"goastanalyzer/domain/valueobjects"
+)
+
+// diagnosticSource names the server in diagnostics
+const diagnosticSource = "goastanalyzer"
+
+// Server is a Language Server Protocol server that publishes findings as diagnostics when a Go
+// file is opened or saved, and offers metric hovers, complexity code lenses and suppression fixes.
+// Requests are handled one at a time in the order they arrive
+type Server struct {
+	newAnalyzer func() usecases.AnalyzeCodeUseCase
+	config      valueobjects.AnalysisConfiguration
+
+	conn      *connection
+	documents map[string]string
+	packages  map[string]*packageAnalysis
+	shutdown  bool
+}
+
+// packageAnalysis holds the last analysis of the Go files in one directory, keyed by file path
+type packageAnalysis struct {
+	files     []string
+	findings  map[string][]entities.AnalysisFinding
+	functions map[string][]valueobjects.FunctionMetrics
+	lines     map[string][]string
+}
+
+// NewServer creates a server that analyzes each package with a fresh analyzer from newAnalyzer
+func NewServer(newAnalyzer func() usecases.AnalyzeCodeUseCase, config valueobjects.AnalysisConfiguration) *Server {
+	return &Server{
+		newAnalyzer: newAnalyzer,
+		config:      config,
+		documents:   make(map[string]string),
+		packages:    make(map[string]*packageAnalysis),
+	}
+}
+
+// Serve handles messages from in and writes responses and notifications to out until the client
+// sends exit or closes in
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.conn = newConnection(in, out)
+
+	for {
+		msg, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Error != nil && msg.Method == "" && msg.ID == nil {
+			if err := s.conn.replyError(nil, msg.Error.Code, msg.Error.Message); err != nil {
+				return err
+			}
+			continue
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches one request or notification; only failures to write end the session
+func (s *Server) handle(msg *message) error {
+	if msg.ID != nil && s.shutdown {
+		return s.conn.replyError(msg.ID, codeInvalidRequest, "server is shut down")
+	}
+
+	var result interface{}
+	var err error
+	switch msg.Method {
+	case "initialize":
+		result = s.initialize()
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			s.documents[params.TextDocument.URI] = params.TextDocument.Text
+			err = s.analyze(params.TextDocument.URI)
+		}
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil && len(params.ContentChanges) > 0 {
+			s.documents[params.TextDocument.URI] = params.ContentChanges[len(params.ContentChanges)-1].Text
+		}
+	case "textDocument/didSave":
+		var params DidSaveTextDocumentParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			err = s.analyze(params.TextDocument.URI)
+		}
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			delete(s.documents, params.TextDocument.URI)
+		}
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.hover(params)
+		}
+	case "textDocument/codeLens":
+		var params CodeLensParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.codeLenses(params)
+		}
+	case "textDocument/codeAction":
+		var params CodeActionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.codeActions(params)
+		}
+	default:
+		if msg.ID != nil {
+			return s.conn.replyError(msg.ID, codeMethodNotFound, "method not supported: "+msg.Method)
+		}
+		return nil
+	}
+
+	if msg.ID == nil {
+		// Notifications have no response, so their failures are logged
+		if err != nil {
+			return s.conn.notify("window/logMessage", map[string]interface{}{"type": 1, "message": err.Error()})
+		}
+		return nil
+	}
+	if _, ok := err.(*json.UnmarshalTypeError); ok {
+		return s.conn.replyError(msg.ID, codeInvalidParams, err.Error())
+	}
+	if err != nil {
+		return s.conn.replyError(msg.ID, codeInternalError, err.Error())
+	}
+	return s.conn.reply(msg.ID, result)
+}
+
+// initialize describes the server's capabilities
+func (s *Server) initialize() InitializeResult {
+	return InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    TextDocumentSyncKindFull,
+				Save:      SaveOptions{IncludeText: false},
+			},
+			HoverProvider:      true,
+			CodeLensProvider:   &CodeLensOptions{},
+			CodeActionProvider: true,
+		},
+		ServerInfo: ServerInfo{Name: diagnosticSource},
+	}
+}
+
+// analyze analyzes the saved package of a document and publishes the diagnostics of its files
+func (s *Server) analyze(uri string) error {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(path, ".go") {
+		return nil
+	}
+	dir := filepath.Dir(path)
+
+	files, err := packageFiles(dir)
+	if err != nil {
+		return err
+	}
+	response, err := s.newAnalyzer().Execute(usecases.AnalyzeCodeRequest{
+		FilePaths:             files,
+		Configuration:         s.config,
+		IncludeSmellDetection: s.config.IsSmellDetectionEnabled(),
+	})
+	if err != nil {
+		return err
+	}
+	if !response.Success {
+		// Diagnostics are kept until the package parses again
+		return fmt.Errorf("analysis of %s failed: %v", dir, response.Error)
+	}
+
+	analysis := &packageAnalysis{
+		files:     files,
+		findings:  make(map[string][]entities.AnalysisFinding),
+		functions: make(map[string][]valueobjects.FunctionMetrics),
+		lines:     make(map[string][]string),
+	}
+	for _, finding := range response.AnalysisResult.Findings() {
+		file := finding.Location().FilePath()
+		analysis.findings[file] = append(analysis.findings[file], finding)
+	}
+	for _, metrics := range response.AnalysisResult.FunctionMetrics() {
+		file := metrics.Location().FilePath()
+		analysis.functions[file] = append(analysis.functions[file], metrics)
+	}
+	for _, file := range files {
+		if content, err := os.ReadFile(file); err == nil {
+			analysis.lines[file] = strings.Split(string(content), "\n")
+		}
+	}
+
+	// Files that vanished from the package lose their diagnostics too
+	published := append([]string(nil), files...)
+	if previous := s.packages[dir]; previous != nil {
+		published = append(published, previous.files...)
+	}
+	s.packages[dir] = analysis
+
+	seen := make(map[string]bool)
+	for _, file := range published {
+		if seen[file] {
+			continue
+		}
+		seen[file] = true
+		if err := s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI:         pathToURI(file),
+			Diagnostics: analysis.diagnostics(file),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diagnostics converts the findings of a file, each covering the rest of its line
+func (a *packageAnalysis) diagnostics(file string) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(a.findings[file]))
+	for _, finding := range a.findings[file] {
+		diagnostic := Diagnostic{
+			Range:    a.lineRange(finding.Location()),
+			Severity: diagnosticSeverity(finding.Severity()),
+			Code:     finding.Type().String(),
+			Source:   diagnosticSource,
+			Message:  finding.Message(),
+		}
+		for i, step := range finding.Trace() {
+			diagnostic.RelatedInformation = append(diagnostic.RelatedInformation, DiagnosticRelatedInformation{
+				Location: Location{URI: pathToURI(step.FilePath()), Range: a.lineRange(step)},
+				Message:  fmt.Sprintf("step %d of the data flow", i+1),
+			})
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// lineRange returns the range from a location to the end of its line
+func (a *packageAnalysis) lineRange(location valueobjects.SourceLocation) Range {
+	line := location.Line() - 1
+	if line < 0 {
+		return Range{}
+	}
+
+	text := ""
+	if lines := a.lines[location.FilePath()]; line < len(lines) {
+		text = lines[line]
+	}
+	start := utf16Column(text, location.Column()-1)
+	end := utf16Column(text, len(text))
+	if end < start {
+		end = start
+	}
+	return Range{Start: Position{Line: line, Character: start}, End: Position{Line: line, Character: end}}
+}
+
+// hover describes the metrics of the function at a position and the findings on its line
+func (s *Server) hover(params TextDocumentPositionParams) *Hover {
+	path, analysis := s.lookup(params.TextDocument.URI)
+	if analysis == nil {
+		return nil
+	}
+	line := params.Position.Line + 1
+
+	var text strings.Builder
+	var hoverRange *Range
+	if function, ok := enclosingFunction(analysis.functions[path], line); ok {
+		writeFunctionMetrics(&text, function)
+		lineRange := analysis.lineRange(function.Location())
+		hoverRange = &lineRange
+	}
+
+	var onLine []entities.AnalysisFinding
+	for _, finding := range analysis.findings[path] {
+		if finding.Location().Line() == line {
+			onLine = append(onLine, finding)
+		}
+	}
+	if len(onLine) > 0 {
+		if text.Len() > 0 {
+			text.WriteString("\n")
+		}
+		text.WriteString("**Findings**\n\n")
+		for _, finding := range onLine {
+			fmt.Fprintf(&text, "- %s %s: %s\n", finding.Severity(), finding.Type(), finding.Message())
+		}
+	}
+
+	if text.Len() == 0 {
+		return nil
+	}
+	return &Hover{Contents: MarkupContent{Kind: "markdown", Value: text.String()}, Range: hoverRange}
+}
+
+// enclosingFunction returns the innermost function whose lines contain line
+func enclosingFunction(functions []valueobjects.FunctionMetrics, line int) (valueobjects.FunctionMetrics, bool) {
+	var found valueobjects.FunctionMetrics
+	ok := false
+	for _, function := range functions {
+		start := function.Location().Line()
+		end := start + function.Lines().Physical() - 1
+		if line >= start && line <= end && (!ok || start > found.Location().Line()) {
+			found, ok = function, true
+		}
+	}
+	return found, ok
+}
+
+// writeFunctionMetrics writes the complexity breakdown of a function as a Markdown table
+func writeFunctionMetrics(text *strings.Builder, function valueobjects.FunctionMetrics) {
+	complexity, halstead, lines := function.Complexity(), function.Halstead(), function.Lines()
+
+	npath := fmt.Sprintf("%d", complexity.NPath())
+	if complexity.IsNPathSaturated() {
+		npath = "overflow"
+	}
+
+	fmt.Fprintf(text, "**%s**\n\n", function.Name())
+	text.WriteString("| Metric | Value |\n|---|---|\n")
+	fmt.Fprintf(text, "| Cyclomatic complexity | %d |\n", complexity.Cyclomatic())
+	fmt.Fprintf(text, "| Cognitive complexity | %d |\n", complexity.Cognitive())
+	fmt.Fprintf(text, "| NPath complexity | %s |\n", npath)
+	fmt.Fprintf(text, "| Essential complexity | %d |\n", complexity.Essential())
+	fmt.Fprintf(text, "| Halstead volume | %.1f |\n", halstead.Volume())
+	fmt.Fprintf(text, "| Halstead difficulty | %.1f |\n", halstead.Difficulty())
+	fmt.Fprintf(text, "| Halstead effort | %.0f |\n", halstead.Effort())
+	fmt.Fprintf(text, "| Lines (physical / logical / comment) | %d / %d / %d |\n", lines.Physical(), lines.Logical(), lines.Comment())
+	fmt.Fprintf(text, "| Maintainability index | %.1f |\n", function.MaintainabilityIndex())
+}
+
+// codeLenses shows the cyclomatic and cognitive complexity above each function
+func (s *Server) codeLenses(params CodeLensParams) []CodeLens {
+	path, analysis := s.lookup(params.TextDocument.URI)
+	lenses := []CodeLens{}
+	if analysis == nil {
+		return lenses
+	}
+
+	for _, function := range analysis.functions[path] {
+		complexity := function.Complexity()
+		line := function.Location().Line() - 1
+		lenses = append(lenses, CodeLens{
+			Range: Range{Start: Position{Line: line}, End: Position{Line: line}},
+			Command: &Command{
+				Title: fmt.Sprintf("cyclomatic %d · cognitive %d", complexity.Cyclomatic(), complexity.Cognitive()),
+			},
+		})
+	}
+	return lenses
+}
+
+// codeActions offers the suggested fixes of the findings on the lines of the requested range, and
+// to suppress each kind of finding there with a //goast:ignore comment above the finding's line
+func (s *Server) codeActions(params CodeActionParams) []CodeAction {
+	path, analysis := s.lookup(params.TextDocument.URI)
+	actions := []CodeAction{}
+	if analysis == nil {
+		return actions
+	}
+
+	first, last := params.Range.Start.Line+1, params.Range.End.Line+1
+	offered := make(map[string]bool)
+	var findings []entities.AnalysisFinding
+	for _, finding := range analysis.findings[path] {
+		line := finding.Location().Line()
+		if line < first || line > last {
+			continue
+		}
+		for _, fix := range finding.Fixes() {
+			actions = append(actions, CodeAction{
+				Title:       "Fix: " + fix.Description(),
+				Kind:        CodeActionKindQuickFix,
+				Diagnostics: coveredDiagnostics(params.Context.Diagnostics, finding),
+				Edit:        analysis.workspaceEdit(fix),
+			})
+		}
+
+		key := fmt.Sprintf("%d|%s", line, finding.Type())
+		if offered[key] {
+			continue
+		}
+		offered[key] = true
+		findings = append(findings, finding)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Location().Line() < findings[j].Location().Line()
+	})
+
+	for _, finding := range findings {
+		line := finding.Location().Line() - 1
+		indent := ""
+		if lines := analysis.lines[path]; line < len(lines) {
+			indent = lines[line][:len(lines[line])-len(strings.TrimLeft(lines[line], " \t"))]
+		}
+		directive := fmt.Sprintf("%s %s", services.SuppressionDirective, finding.Type())
+
+		actions = append(actions, CodeAction{
+			Title:       fmt.Sprintf("Suppress %s findings on line %d with %s", finding.Type(), line+1, directive),
+			Kind:        CodeActionKindQuickFix,
+			Diagnostics: coveredDiagnostics(params.Context.Diagnostics, finding),
+			Edit: &WorkspaceEdit{Changes: map[string][]TextEdit{
+				params.TextDocument.URI: {{
+					Range:   Range{Start: Position{Line: line}, End: Position{Line: line}},
+					NewText: indent + directive + "\n",
+				}},
+			}},
+		})
+	}
+	return actions
+}
+
+// coveredDiagnostics returns the diagnostics of the analyzer reporting a finding's type on its line
+func coveredDiagnostics(diagnostics []Diagnostic, finding entities.AnalysisFinding) []Diagnostic {
+	var covered []Diagnostic
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Source == diagnosticSource && diagnostic.Range.Start.Line == finding.Location().Line()-1 && diagnostic.Code == finding.Type().String() {
+			covered = append(covered, diagnostic)
+		}
+	}
+	return covered
+}
+
+// workspaceEdit converts the byte offsets of a fix's edits to positions in the analyzed files
+func (a *packageAnalysis) workspaceEdit(fix valueobjects.SuggestedFix) *WorkspaceEdit {
+	edit := &WorkspaceEdit{Changes: make(map[string][]TextEdit)}
+	for _, textEdit := range fix.Edits() {
+		uri := pathToURI(textEdit.FilePath())
+		lines := a.lines[textEdit.FilePath()]
+		edit.Changes[uri] = append(edit.Changes[uri], TextEdit{
+			Range: Range{
+				Start: offsetPosition(lines, textEdit.Offset()),
+				End:   offsetPosition(lines, textEdit.End()),
+			},
+			NewText: textEdit.NewText(),
+		})
+	}
+	return edit
+}
+
+// offsetPosition converts a byte offset into a file split into lines to an LSP position
+func offsetPosition(lines []string, offset int) Position {
+	for line, text := range lines {
+		if offset <= len(text) {
+			return Position{Line: line, Character: utf16Column(text, offset)}
+		}
+		offset -= len(text) + 1
+	}
+	if len(lines) == 0 {
+		return Position{}
+	}
+	last := len(lines) - 1
+	return Position{Line: last, Character: utf16Column(lines[last], len(lines[last]))}
+}
+
+// lookup returns the path of a document and the last analysis of its package, if any
+func (s *Server) lookup(uri string) (string, *packageAnalysis) {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return "", nil
+	}
+	return path, s.packages[filepath.Dir(path)]
+}
+
+// packageFiles returns the Go files of a directory
+func packageFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// diagnosticSeverity maps a finding's severity to the diagnostic severity shown by the editor
+func diagnosticSeverity(severity valueobjects.SeverityLevel) DiagnosticSeverity {
+	switch {
+	case severity >= valueobjects.SeverityError:
+		return DiagnosticSeverityError
+	case severity == valueobjects.SeverityWarning:
+		return DiagnosticSeverityWarning
+	default:
+		return DiagnosticSeverityInformation
+	}
+}
+
+// utf16Column converts a byte offset within a line to the UTF-16 offset LSP positions use
+func utf16Column(line string, offset int) int {
+	if offset > len(line) {
+		offset = len(line)
+	}
+	if offset < 0 {
+		return 0
+	}
+	return len(utf16.Encode([]rune(line[:offset])))
+}
+
+// uriToPath converts a file URI to a clean absolute path
+func uriToPath(uri string) (string, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URI scheme %q", parsed.Scheme)
+	}
+	return filepath.Clean(filepath.FromSlash(parsed.Path)), nil
+}
+
+// pathToURI converts an absolute path to a file URI
+func pathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
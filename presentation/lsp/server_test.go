@@ -0,0 +1,309 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"goastanalyzer/application/usecases"
+	"goastanalyzer/domain/services"
+	"goastanalyzer/domain/valueobjects"
+	"goastanalyzer/infrastructure/adapters"
+)
+
+const testSource = `package main
+
+func classify(values []int) int {
+	total := 0
+	for _, v := range values {
+		if v > 0 {
+			for i := 0; i < v; i++ {
+				if i%2 == 0 {
+					if i%3 == 0 {
+						total++
+					} else if i%5 == 0 {
+						total--
+					}
+					if i%7 == 0 && v > 10 {
+						total += 2
+					}
+				}
+			}
+		}
+	}
+	return total
+}
+
+func main() {}
+`
+
+// testClient is an in-process LSP client talking to a server over pipes
+type testClient struct {
+	t             *testing.T
+	conn          *connection
+	nextID        int
+	messages      chan *message
+	notifications []*message
+	done          chan error
+}
+
+func newTestClient(t *testing.T) *testClient {
+	config, err := valueobjects.NewAnalysisConfiguration(3, 3, 80, false, valueobjects.SeverityInfo)
+	if err != nil {
+		t.Fatalf("Failed to create configuration: %v", err)
+	}
+	server := NewServer(func() usecases.AnalyzeCodeUseCase {
+		return usecases.NewAnalyzeCodeUseCase(
+			services.NewASTComplexityCalculator(),
+			services.NewASTSmellDetector(),
+			services.NewASTCloneDetector(),
+			services.NewCommentSuppressionScanner(),
+			adapters.NewGoFileParser(),
+			adapters.NewGoPackageLoader(),
+			adapters.NewUUIDGenerator(),
+			nil,
+		)
+	}, config)
+
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	client := &testClient{
+		t:        t,
+		conn:     newConnection(clientIn, clientOut),
+		messages: make(chan *message, 64),
+		done:     make(chan error, 1),
+	}
+	go func() {
+		client.done <- server.Serve(serverIn, serverOut)
+		serverOut.Close()
+	}()
+
+	// Pipes are unbuffered, so the client reads concurrently with its writes
+	go func() {
+		defer close(client.messages)
+		for {
+			msg, err := client.conn.read()
+			if err != nil {
+				return
+			}
+			client.messages <- msg
+		}
+	}()
+	return client
+}
+
+// call sends a request and returns its result, collecting the notifications sent before it
+func (c *testClient) call(method string, params, result interface{}) {
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	raw, _ := json.Marshal(params)
+	if err := c.conn.write(&message{ID: &id, Method: method, Params: raw}); err != nil {
+		c.t.Fatalf("Failed to send %s: %v", method, err)
+	}
+
+	for {
+		msg, ok := <-c.messages
+		if !ok {
+			c.t.Fatalf("Connection closed before the response to %s", method)
+		}
+		if msg.ID == nil {
+			c.notifications = append(c.notifications, msg)
+			continue
+		}
+		if msg.Error != nil {
+			c.t.Fatalf("Request %s failed: %v", method, msg.Error)
+		}
+		if result != nil {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				c.t.Fatalf("Failed to decode result of %s: %v", method, err)
+			}
+		}
+		return
+	}
+}
+
+// notify sends a notification
+func (c *testClient) notify(method string, params interface{}) {
+	if err := c.conn.notify(method, params); err != nil {
+		c.t.Fatalf("Failed to send %s: %v", method, err)
+	}
+}
+
+// diagnostics returns the last diagnostics published for a document
+func (c *testClient) diagnostics(uri string) ([]Diagnostic, bool) {
+	for i := len(c.notifications) - 1; i >= 0; i-- {
+		msg := c.notifications[i]
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			c.t.Fatalf("Failed to decode diagnostics: %v", err)
+		}
+		if params.URI == uri {
+			return params.Diagnostics, true
+		}
+	}
+	return nil, false
+}
+
+func TestServer_Session(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(testSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	uri := pathToURI(path)
+	document := TextDocumentIdentifier{URI: uri}
+
+	client := newTestClient(t)
+
+	var initialized InitializeResult
+	client.call("initialize", map[string]interface{}{"processId": nil, "rootUri": pathToURI(dir)}, &initialized)
+	if !initialized.Capabilities.HoverProvider || initialized.Capabilities.CodeLensProvider == nil || !initialized.Capabilities.CodeActionProvider {
+		t.Errorf("Expected hover, code lens and code action capabilities, got %+v", initialized.Capabilities)
+	}
+	client.notify("initialized", struct{}{})
+
+	client.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "go", Version: 1, Text: testSource},
+	})
+
+	// The server handles messages in order, so diagnostics arrive before this response
+	var lenses []CodeLens
+	client.call("textDocument/codeLens", CodeLensParams{TextDocument: document}, &lenses)
+
+	diagnostics, ok := client.diagnostics(uri)
+	if !ok {
+		t.Fatalf("Expected diagnostics for %s", uri)
+	}
+	var complexity *Diagnostic
+	for i := range diagnostics {
+		if diagnostics[i].Code == "complexity" && diagnostics[i].Range.Start.Line == 2 {
+			complexity = &diagnostics[i]
+		}
+	}
+	if complexity == nil {
+		t.Fatalf("Expected a complexity diagnostic on line 3, got %+v", diagnostics)
+	}
+	if complexity.Source != diagnosticSource || !strings.Contains(complexity.Message, "classify") {
+		t.Errorf("Unexpected complexity diagnostic %+v", complexity)
+	}
+
+	if len(lenses) != 2 {
+		t.Fatalf("Expected a code lens per function, got %+v", lenses)
+	}
+	if lenses[0].Range.Start.Line != 2 || lenses[0].Command == nil || !strings.HasPrefix(lenses[0].Command.Title, "cyclomatic 9 · cognitive 49") {
+		t.Errorf("Unexpected code lens %+v", lenses[0])
+	}
+
+	var hover Hover
+	client.call("textDocument/hover", TextDocumentPositionParams{TextDocument: document, Position: Position{Line: 9, Character: 6}}, &hover)
+	for _, expected := range []string{"**classify**", "| Cyclomatic complexity | 9 |", "NPath complexity", "Maintainability index"} {
+		if !strings.Contains(hover.Contents.Value, expected) {
+			t.Errorf("Expected hover to contain %q, got:\n%s", expected, hover.Contents.Value)
+		}
+	}
+
+	var actions []CodeAction
+	client.call("textDocument/codeAction", CodeActionParams{
+		TextDocument: document,
+		Range:        complexity.Range,
+		Context:      CodeActionContext{Diagnostics: []Diagnostic{*complexity}},
+	}, &actions)
+	if len(actions) != 1 || actions[0].Kind != CodeActionKindQuickFix || actions[0].Edit == nil {
+		t.Fatalf("Expected one suppression quick fix, got %+v", actions)
+	}
+	edits := actions[0].Edit.Changes[uri]
+	if len(edits) != 1 || edits[0].NewText != "//goast:ignore complexity\n" || edits[0].Range.Start.Line != 2 {
+		t.Errorf("Unexpected suppression edit %+v", edits)
+	}
+
+	// Applying the fix and saving clears the diagnostic
+	fixed := strings.Replace(testSource, "func classify", "//goast:ignore complexity\nfunc classify", 1)
+	if err := os.WriteFile(path, []byte(fixed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	client.notify("textDocument/didSave", DidSaveTextDocumentParams{TextDocument: document})
+	client.call("textDocument/codeLens", CodeLensParams{TextDocument: document}, &lenses)
+	diagnostics, _ = client.diagnostics(uri)
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Code == "complexity" && strings.Contains(diagnostic.Message, "classify") {
+			t.Errorf("Expected the suppressed diagnostic to be cleared, got %+v", diagnostic)
+		}
+	}
+
+	client.call("shutdown", nil, nil)
+	client.notify("exit", nil)
+	if err := <-client.done; err != nil {
+		t.Errorf("Expected the server to exit cleanly, got %v", err)
+	}
+}
+
+func TestServer_UnknownRequest(t *testing.T) {
+	client := newTestClient(t)
+
+	id := json.RawMessage("1")
+	if err := client.conn.write(&message{ID: &id, Method: "workspace/unknown"}); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-client.messages
+	if msg == nil {
+		t.Fatal("Connection closed before the response")
+	}
+	if msg.Error == nil || msg.Error.Code != codeMethodNotFound {
+		t.Errorf("Expected a method not found error, got %+v", msg)
+	}
+
+	client.notify("exit", nil)
+	if err := <-client.done; err != nil {
+		t.Errorf("Expected the server to exit cleanly, got %v", err)
+	}
+}
+
+func TestUTF16Column(t *testing.T) {
+	tests := []struct {
+		line     string
+		offset   int
+		expected int
+	}{
+		{"x := 1", 2, 2},
+		{`s := "é" + x`, 11, 10},
+		{`s := "𝄞" + x`, 13, 11},
+		{"short", 10, 5},
+	}
+
+	for _, tt := range tests {
+		if got := utf16Column(tt.line, tt.offset); got != tt.expected {
+			t.Errorf("utf16Column(%q, %d) = %d, expected %d", tt.line, tt.offset, got, tt.expected)
+		}
+	}
+}
+
+func TestOffsetPosition(t *testing.T) {
+	lines := []string{"package main", "", `var s = "é" + x`}
+
+	tests := []struct {
+		offset   int
+		expected Position
+	}{
+		{0, Position{Line: 0, Character: 0}},
+		{12, Position{Line: 0, Character: 12}},
+		{13, Position{Line: 1, Character: 0}},
+		{27, Position{Line: 2, Character: 12}},
+		{100, Position{Line: 2, Character: 15}},
+	}
+
+	for _, tt := range tests {
+		if got := offsetPosition(lines, tt.offset); got != tt.expected {
+			t.Errorf("offsetPosition(%d) = %+v, expected %+v", tt.offset, got, tt.expected)
+		}
+	}
+}